@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package aitesting provides evaluators and other helpers for testing code
+// built on the ai package, such as evaluation pipelines, without calling
+// any models.
+package aitesting
+
+import (
+	"context"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// DefineNoOpEvaluator registers an [ai.Evaluator] that immediately passes
+// every example with a score of 1.
+func DefineNoOpEvaluator(r *registry.Registry, provider, name string) (ai.Evaluator, error) {
+	return defineConstantEvaluator(r, provider, name, "No-op", "Passes every example", ai.ScoreStatusPass)
+}
+
+// DefineAlwaysFailEvaluator registers an [ai.Evaluator] that immediately
+// fails every example with a score of 0.
+func DefineAlwaysFailEvaluator(r *registry.Registry, provider, name string) (ai.Evaluator, error) {
+	return defineConstantEvaluator(r, provider, name, "Always fail", "Fails every example", ai.ScoreStatusFail)
+}
+
+func defineConstantEvaluator(r *registry.Registry, provider, name, displayName, definition string, status ai.ScoreStatus) (ai.Evaluator, error) {
+	score := 0.0
+	if status == ai.ScoreStatusPass {
+		score = 1
+	}
+	opts := &ai.EvaluatorOptions{DisplayName: displayName, Definition: definition}
+	return ai.DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *ai.EvaluatorCallbackRequest) (*ai.EvaluatorCallbackResponse, error) {
+		return &ai.EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []ai.Score{{Id: name, Score: score, Status: status.String()}},
+		}, nil
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aitesting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestConstantEvaluators(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop, err := DefineNoOpEvaluator(r, "test", "noop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail, err := DefineAlwaysFailEvaluator(r, "test", "fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := ai.Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "y"}}
+	for _, test := range []struct {
+		e          ai.Evaluator
+		wantScore  float64
+		wantStatus ai.ScoreStatus
+	}{
+		{noop, 1, ai.ScoreStatusPass},
+		{fail, 0, ai.ScoreStatusFail},
+	} {
+		resp, err := ai.Evaluate(context.Background(), test.e, ai.WithEvaluateDataset(&ds))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(*resp), len(ds); got != want {
+			t.Fatalf("%s: got %d results, want %d", test.e.Name(), got, want)
+		}
+		for _, res := range *resp {
+			s := res.Evaluation[0]
+			if s.Score != test.wantScore || s.Status != test.wantStatus.String() {
+				t.Errorf("%s: got score %v (%s), want %v (%s)", test.e.Name(), s.Score, s.Status, test.wantScore, test.wantStatus)
+			}
+		}
+	}
+}
+
+func TestRunEvaluatorTestCases(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop, err := DefineNoOpEvaluator(r, "test", "noop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	RunEvaluatorTestCases(t, noop, []TestCase{
+		{Input: ai.Example{Input: "x"}, ExpectedScores: []ai.Score{{Score: 1, Status: "pass"}}},
+		{Input: ai.Example{TestCaseId: "b", Input: "y"}, ExpectedScores: []ai.Score{{Id: "noop", Score: 0.99}}, Tolerance: 0.05},
+	})
+}
+
+func TestScoreMismatches(t *testing.T) {
+	got := []ai.Score{
+		{Id: "a", Score: 0.5, Status: "pass", Details: map[string]any{"reasoning": "ok", "n": 2.0}},
+		{Id: "b", Score: "good"},
+	}
+	tests := []struct {
+		name      string
+		want      []ai.Score
+		tolerance float64
+		mismatch  bool
+	}{
+		{"only set fields", []ai.Score{{Score: 0.5}, {}}, 0, false},
+		{"by id", []ai.Score{{Id: "b", Score: "good"}, {Id: "a", Status: "pass"}}, 0, false},
+		{"within tolerance", []ai.Score{{Score: 0.55, Details: map[string]any{"n": 2.1}}, {}}, 0.15, false},
+		{"score differs", []ai.Score{{Score: 0.6}, {}}, 0.05, true},
+		{"label differs", []ai.Score{{}, {Score: "bad"}}, 0, true},
+		{"status differs", []ai.Score{{Status: "fail"}, {}}, 0, true},
+		{"details differ", []ai.Score{{Details: map[string]any{"reasoning": "no"}}, {}}, 0, true},
+		{"missing id", []ai.Score{{Id: "c"}, {}}, 0, true},
+		{"extra score", []ai.Score{{}}, 0, true},
+	}
+	for _, test := range tests {
+		ms := scoreMismatches(test.want, got, test.tolerance)
+		if (len(ms) > 0) != test.mismatch {
+			t.Errorf("%s: got mismatches %q, want mismatch %v", test.name, ms, test.mismatch)
+		}
+	}
+}
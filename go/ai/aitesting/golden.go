@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aitesting
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to "1", makes
+// [AssertEvaluatorResponse] write golden files instead of comparing with
+// them.
+const UpdateGoldenEnv = "GENKIT_UPDATE_GOLDEN"
+
+// nondeterministicFields are the JSON fields that [AssertEvaluatorResponse]
+// always ignores.
+var nondeterministicFields = []string{"traceId", "spanId", "timestamp"}
+
+// AssertOptions configures [AssertEvaluatorResponse].
+type AssertOptions struct {
+	// IgnoreFields names additional JSON fields to ignore wherever they
+	// appear, such as "reasoning" in the details of LLM-judge scores.
+	IgnoreFields []string
+	// IgnoreTestCaseIds ignores the TestCaseId of results, for datasets
+	// whose examples are assigned random IDs.
+	IgnoreTestCaseIds bool
+	// Tolerance is the largest difference between numbers that are
+	// considered equal.
+	Tolerance float64
+	// Update makes [AssertEvaluatorResponse] write the golden file instead
+	// of comparing with it.
+	Update bool
+}
+
+// updateGolden reports whether golden files should be written: if
+// opts.Update is set, if [UpdateGoldenEnv] is "1", or if the test binary
+// defines an -update flag, as is conventional, and it is set. The flag is
+// looked up rather than defined, so that packages importing aitesting can
+// define their own.
+func updateGolden(opts AssertOptions) bool {
+	if opts.Update || os.Getenv(UpdateGoldenEnv) == "1" {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			if v, ok := g.Get().(bool); ok {
+				return v
+			}
+		}
+	}
+	return false
+}
+
+// AssertEvaluatorResponse compares resp with the snapshot stored in
+// goldenFile and fails t with a diff if they differ. Trace and span IDs and
+// timestamps are ignored. In update mode, resp is written to goldenFile
+// instead; see [AssertOptions.Update] and [UpdateGoldenEnv]. A missing
+// goldenFile is created on the first run, which is logged so that the new
+// file is not left out of version control.
+func AssertEvaluatorResponse(t testing.TB, resp *ai.EvaluatorResponse, goldenFile string, opts AssertOptions) {
+	t.Helper()
+	got, err := goldenValue(resp, opts)
+	if err != nil {
+		t.Fatalf("AssertEvaluatorResponse: %v", err)
+	}
+	if updateGolden(opts) {
+		if err := writeGolden(goldenFile, got); err != nil {
+			t.Fatalf("AssertEvaluatorResponse: %v", err)
+		}
+		t.Logf("AssertEvaluatorResponse: wrote %s", goldenFile)
+		return
+	}
+	data, err := os.ReadFile(goldenFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := writeGolden(goldenFile, got); err != nil {
+			t.Fatalf("AssertEvaluatorResponse: %v", err)
+		}
+		t.Logf("AssertEvaluatorResponse: golden file %s did not exist and was created", goldenFile)
+		return
+	}
+	if err != nil {
+		t.Fatalf("AssertEvaluatorResponse: %v", err)
+	}
+	var want any
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("AssertEvaluatorResponse: %s: %v", goldenFile, err)
+	}
+	if diff := diffGolden(want, got, opts.Tolerance); diff != "" {
+		t.Errorf("evaluator response does not match %s (-want +got):\n%s\nSet %s=1 to update the golden file.", goldenFile, diff, UpdateGoldenEnv)
+	}
+}
+
+// goldenValue returns resp decoded from JSON, without the ignored fields.
+func goldenValue(resp *ai.EvaluatorResponse, opts AssertOptions) (any, error) {
+	results := ai.EvaluatorResponse{}
+	if resp != nil {
+		results = *resp
+	}
+	b, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	ignore := map[string]bool{}
+	for _, f := range append(nondeterministicFields, opts.IgnoreFields...) {
+		ignore[f] = true
+	}
+	if opts.IgnoreTestCaseIds {
+		ignore["testCaseId"] = true
+	}
+	return dropFields(v, ignore), nil
+}
+
+// dropFields removes the fields named in ignore from the objects in v.
+func dropFields(v any, ignore map[string]bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if ignore[k] {
+				delete(v, k)
+			} else {
+				v[k] = dropFields(e, ignore)
+			}
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = dropFields(e, ignore)
+		}
+	}
+	return v
+}
+
+// diffGolden returns the difference between the decoded JSON values want
+// and got, treating numbers within tolerance of each other as equal.
+func diffGolden(want, got any, tolerance float64) string {
+	return cmp.Diff(want, got, cmpopts.EquateApprox(0, tolerance))
+}
+
+func writeGolden(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aitesting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestAssertEvaluatorResponse(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop, err := DefineNoOpEvaluator(r, "test", "noop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := ai.Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "y"}}
+	resp, err := ai.Evaluate(context.Background(), noop, ai.WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stored snapshot matches although trace and span IDs differ.
+	AssertEvaluatorResponse(t, resp, filepath.Join("testdata", "noop.json"), AssertOptions{})
+
+	// A missing golden file is created on the first run.
+	golden := filepath.Join(t.TempDir(), "new", "noop.json")
+	if assertFails(resp, golden, AssertOptions{}) {
+		t.Error("missing golden file: got failure, want success")
+	}
+	if _, err := os.Stat(golden); err != nil {
+		t.Fatalf("golden file was not created: %v", err)
+	}
+	AssertEvaluatorResponse(t, resp, golden, AssertOptions{})
+
+	// In update mode, an existing golden file is overwritten.
+	other, err := ai.Evaluate(context.Background(), noop, ai.WithEvaluateDataset(&ai.Dataset{{TestCaseId: "c", Input: "z"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assertFails(other, golden, AssertOptions{}) {
+		t.Error("different response: got success, want failure")
+	}
+	AssertEvaluatorResponse(t, other, golden, AssertOptions{Update: true})
+	AssertEvaluatorResponse(t, other, golden, AssertOptions{})
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertEvaluatorResponse(t, resp, golden, AssertOptions{})
+	t.Setenv(UpdateGoldenEnv, "")
+	if assertFails(resp, golden, AssertOptions{}) {
+		t.Errorf("golden file was not updated with %s set", UpdateGoldenEnv)
+	}
+}
+
+// recordingTB is a [testing.TB] that records failures instead of reporting
+// them.
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (t *recordingTB) Helper()               {}
+func (t *recordingTB) Logf(string, ...any)   {}
+func (t *recordingTB) Errorf(string, ...any) { t.failed = true }
+func (t *recordingTB) Fatalf(string, ...any) {
+	t.failed = true
+	runtime.Goexit()
+}
+
+// assertFails reports whether [AssertEvaluatorResponse] fails.
+func assertFails(resp *ai.EvaluatorResponse, goldenFile string, opts AssertOptions) bool {
+	tb := &recordingTB{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		AssertEvaluatorResponse(tb, resp, goldenFile, opts)
+	}()
+	<-done
+	return tb.failed
+}
+
+func TestGoldenDiff(t *testing.T) {
+	resp := func(score float64, reasoning string) *ai.EvaluatorResponse {
+		return &ai.EvaluatorResponse{{
+			TestCaseId: "a",
+			TraceID:    reasoning,
+			Evaluation: []ai.Score{{Id: "s", Score: score, Details: map[string]any{"reasoning": reasoning}}},
+		}}
+	}
+	tests := []struct {
+		name      string
+		want, got *ai.EvaluatorResponse
+		opts      AssertOptions
+		same      bool
+	}{
+		{"identical", resp(0.5, "r"), resp(0.5, "r"), AssertOptions{}, true},
+		{"score changed", resp(0.5, "r"), resp(0.6, "r"), AssertOptions{}, false},
+		{"within tolerance", resp(0.5, "r"), resp(0.501, "r"), AssertOptions{Tolerance: 0.01}, true},
+		{"details changed", resp(0.5, "r"), resp(0.5, "other"), AssertOptions{}, false},
+		{"details ignored", resp(0.5, "r"), resp(0.5, "other"), AssertOptions{IgnoreFields: []string{"reasoning"}}, true},
+	}
+	for _, test := range tests {
+		want, err := goldenValue(test.want, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := goldenValue(test.got, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := diffGolden(want, got, test.opts.Tolerance); (diff == "") != test.same {
+			t.Errorf("%s: got diff %q, want same %v", test.name, diff, test.same)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aitesting
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// A TestCase is an input for an evaluator under test together with the
+// scores the evaluator is expected to produce for it.
+type TestCase struct {
+	// Input is the example to evaluate. If it has no TestCaseId, one is
+	// derived from the position of the test case.
+	Input ai.Example
+	// ExpectedScores are the scores the evaluator should return, in order.
+	// A score with an Id is matched with the returned score of the same Id
+	// instead. Only the fields that are set are compared: the Status and
+	// Error if not empty, the Score if not nil, and each key of Details.
+	ExpectedScores []ai.Score
+	// Tolerance is the largest difference between numeric scores, and
+	// numbers in details, that are considered equal.
+	Tolerance float64
+}
+
+// RunEvaluatorTestCases evaluates the input of each test case with eval in
+// a subtest of t, and fails the subtest if the returned scores do not match
+// the expected ones.
+func RunEvaluatorTestCases(t *testing.T, eval ai.Evaluator, cases []TestCase) {
+	t.Helper()
+	for i, tc := range cases {
+		ex := tc.Input
+		if ex.TestCaseId == "" {
+			ex.TestCaseId = fmt.Sprintf("case%d", i)
+		}
+		t.Run(ex.TestCaseId, func(t *testing.T) {
+			ds := ai.Dataset{ex}
+			resp, err := ai.Evaluate(context.Background(), eval, ai.WithEvaluateDataset(&ds))
+			if resp == nil {
+				t.Fatalf("%s: %v", eval.Name(), err)
+			}
+			if len(*resp) != 1 {
+				t.Fatalf("%s: got %d results, want 1", eval.Name(), len(*resp))
+			}
+			for _, m := range scoreMismatches(tc.ExpectedScores, (*resp)[0].Evaluation, tc.Tolerance) {
+				t.Errorf("%s: %s", eval.Name(), m)
+			}
+		})
+	}
+}
+
+// scoreMismatches returns a description of each difference between the
+// expected scores want and the returned scores got.
+func scoreMismatches(want, got []ai.Score, tolerance float64) []string {
+	var ms []string
+	if len(got) != len(want) {
+		ms = append(ms, fmt.Sprintf("got %d scores, want %d", len(got), len(want)))
+	}
+	for i, w := range want {
+		name := fmt.Sprintf("score %d", i)
+		var g *ai.Score
+		if w.Id != "" {
+			name = fmt.Sprintf("score %q", w.Id)
+			for j := range got {
+				if got[j].Id == w.Id {
+					g = &got[j]
+					break
+				}
+			}
+		} else if i < len(got) {
+			g = &got[i]
+		}
+		if g == nil {
+			ms = append(ms, name+": missing")
+			continue
+		}
+		if w.Score != nil && !scoreEqual(w, *g, tolerance) {
+			ms = append(ms, fmt.Sprintf("%s: got score %v, want %v", name, g.Score, w.Score))
+		}
+		if w.Status != "" && g.Status != w.Status {
+			ms = append(ms, fmt.Sprintf("%s: got status %q, want %q", name, g.Status, w.Status))
+		}
+		if w.Error != "" && g.Error != w.Error {
+			ms = append(ms, fmt.Sprintf("%s: got error %q, want %q", name, g.Error, w.Error))
+		}
+		for k, v := range w.Details {
+			if diff := cmp.Diff(v, g.Details[k], cmpopts.EquateApprox(0, tolerance)); diff != "" {
+				ms = append(ms, fmt.Sprintf("%s: details[%q] mismatch (-want +got):\n%s", name, k, strings.TrimSpace(diff)))
+			}
+		}
+	}
+	return ms
+}
+
+// scoreEqual reports whether the score values of want and got are equal,
+// comparing numeric values within tolerance.
+func scoreEqual(want, got ai.Score, tolerance float64) bool {
+	wv, werr := want.Normalize()
+	gv, gerr := got.Normalize()
+	if werr == nil && gerr == nil {
+		return math.Abs(wv-gv) <= tolerance
+	}
+	return cmp.Equal(want.Score, got.Score)
+}
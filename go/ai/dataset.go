@@ -0,0 +1,232 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// MergeStrategy is an enum that selects how [MergeDatasets] handles an
+// example whose TestCaseId appears in both datasets.
+type MergeStrategy int
+
+const (
+	// MergeKeepFirst keeps the example from the first dataset.
+	MergeKeepFirst MergeStrategy = iota
+	// MergeKeepLast replaces the example from the first dataset with the
+	// one from the second, keeping its position.
+	MergeKeepLast
+	// MergeConflict makes [MergeDatasets] return an error.
+	MergeConflict
+)
+
+var mergeStrategyName = map[MergeStrategy]string{
+	MergeKeepFirst: "keepFirst",
+	MergeKeepLast:  "keepLast",
+	MergeConflict:  "conflict",
+}
+
+func (m MergeStrategy) String() string {
+	if n, ok := mergeStrategyName[m]; ok {
+		return n
+	}
+	return "unknown"
+}
+
+// MergeDatasets returns a new [Dataset] with the examples of d followed by
+// the examples of other whose TestCaseId does not appear in d. Examples that
+// appear in both are handled according to strategy. Examples without a
+// TestCaseId are always included.
+func MergeDatasets(d, other Dataset, strategy MergeStrategy) (Dataset, error) {
+	out := slices.Clone(d)
+	pos := map[string]int{}
+	for i, ex := range d {
+		if ex.TestCaseId != "" {
+			if _, ok := pos[ex.TestCaseId]; !ok {
+				pos[ex.TestCaseId] = i
+			}
+		}
+	}
+	for _, ex := range other {
+		i, ok := pos[ex.TestCaseId]
+		if ex.TestCaseId == "" || !ok {
+			out = append(out, ex)
+			continue
+		}
+		switch strategy {
+		case MergeKeepFirst:
+		case MergeKeepLast:
+			out[i] = ex
+		case MergeConflict:
+			return nil, fmt.Errorf("ai.MergeDatasets: test case %q is in both datasets", ex.TestCaseId)
+		default:
+			return nil, fmt.Errorf("ai.MergeDatasets: unknown merge strategy %v", strategy)
+		}
+	}
+	return out, nil
+}
+
+// GroupBy splits ds into groups of examples that share a value, for
+// analyzing results per category such as difficulty or domain. Each example
+// is placed in the group named by extract(ex). If extract is nil, the group
+// is the value of the key field in the example's Input, which must then be a
+// map; examples without it are placed in the group "". Examples keep their
+// order within each group.
+func GroupBy(ds Dataset, field string, extract func(Example) string) map[string]Dataset {
+	if extract == nil {
+		extract = func(ex Example) string { return inputField(ex, field) }
+	}
+	groups := map[string]Dataset{}
+	for _, ex := range ds {
+		k := extract(ex)
+		groups[k] = append(groups[k], ex)
+	}
+	return groups
+}
+
+// inputField returns the value of the key field in the Input of ex as a
+// string, or "" if Input is not a map or has no such key.
+func inputField(ex Example, field string) string {
+	m, ok := ex.Input.(map[string]any)
+	if !ok {
+		return ""
+	}
+	v, ok := m[field]
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// DatasetMutator builds a [Dataset] incrementally. Every example it holds
+// is valid and has a unique TestCaseId. The zero value is an empty mutator
+// ready to use.
+type DatasetMutator struct {
+	examples []Example
+}
+
+// NewDatasetMutator returns a DatasetMutator holding the examples of ds.
+func NewDatasetMutator(ds Dataset) (*DatasetMutator, error) {
+	m := &DatasetMutator{}
+	for _, ex := range ds {
+		if err := m.Add(ex); err != nil {
+			return nil, fmt.Errorf("ai.NewDatasetMutator: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// Add validates ex and appends it. An example without a TestCaseId is
+// assigned one. Add fails if another example has the same TestCaseId.
+func (m *DatasetMutator) Add(ex Example) error {
+	if err := ex.Validate(); err != nil {
+		return err
+	}
+	if ex.TestCaseId == "" {
+		ex.TestCaseId = uuid.New().String()
+	} else if m.index(ex.TestCaseId) >= 0 {
+		return fmt.Errorf("duplicate test case %q", ex.TestCaseId)
+	}
+	m.examples = append(m.examples, ex)
+	return nil
+}
+
+// Remove removes the example with the given TestCaseId and reports whether
+// it was present.
+func (m *DatasetMutator) Remove(testCaseId string) bool {
+	i := m.index(testCaseId)
+	if i < 0 {
+		return false
+	}
+	m.examples = slices.Delete(m.examples, i, i+1)
+	return true
+}
+
+// Update calls fn on the example with the given TestCaseId and reports
+// whether it was present. If the updated example is invalid or its
+// TestCaseId now collides with another example, the update is discarded and
+// Update returns false.
+func (m *DatasetMutator) Update(testCaseId string, fn func(*Example)) bool {
+	i := m.index(testCaseId)
+	if i < 0 {
+		return false
+	}
+	ex := m.examples[i]
+	fn(&ex)
+	if ex.Validate() != nil || ex.TestCaseId == "" {
+		return false
+	}
+	if j := m.index(ex.TestCaseId); j >= 0 && j != i {
+		return false
+	}
+	m.examples[i] = ex
+	return true
+}
+
+// Build returns the examples as a new [Dataset]. Later changes to m do not
+// affect it.
+func (m *DatasetMutator) Build() Dataset {
+	return slices.Clone(m.examples)
+}
+
+func (m *DatasetMutator) index(testCaseId string) int {
+	return slices.IndexFunc(m.examples, func(ex Example) bool {
+		return ex.TestCaseId == testCaseId
+	})
+}
+
+// Fold is one split of a dataset made by [KFoldDataset].
+type Fold struct {
+	// Train holds the examples of all the other folds.
+	Train Dataset `json:"train"`
+	// Test holds the examples of this fold.
+	Test Dataset `json:"test"`
+}
+
+// KFoldDataset splits ds into k folds of nearly equal size for
+// cross-validation. Examples are assigned to folds at random, using seed so
+// that the split can be reproduced, and keep their dataset order within
+// each fold's Train and Test. k must be between 2 and the number of
+// examples.
+func KFoldDataset(ds Dataset, k int, seed int64) ([]Fold, error) {
+	if k < 2 || k > len(ds) {
+		return nil, fmt.Errorf("ai.KFoldDataset: k must be between 2 and the dataset size %d, got %d", len(ds), k)
+	}
+	r := rand.New(rand.NewPCG(uint64(seed), 0))
+	fold := make([]int, len(ds))
+	for i, j := range r.Perm(len(ds)) {
+		fold[j] = i % k
+	}
+	folds := make([]Fold, k)
+	for i, ex := range ds {
+		for f := range folds {
+			if fold[i] == f {
+				folds[f].Test = append(folds[f].Test, ex)
+			} else {
+				folds[f].Train = append(folds[f].Train, ex)
+			}
+		}
+	}
+	return folds, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// AnnotationSchema describes the annotations that the examples of a dataset
+// must have. See [ValidateAnnotations].
+type AnnotationSchema struct {
+	Fields []FieldSpec `json:"fields"`
+}
+
+// FieldSpec constrains one field of the examples of a dataset.
+type FieldSpec struct {
+	// Name is the path of the field in the JSON encoding of the [Example]:
+	// a top-level field such as "reference" or "context", optionally
+	// followed by dot-separated object keys, as in "reference.label".
+	Name string `json:"name"`
+	// Type, if set, is the JSON type the value must have.
+	Type JSONType `json:"type,omitempty"`
+	// Required makes a missing or null value a violation. Otherwise the
+	// other constraints only apply to values that are present.
+	Required bool `json:"required,omitempty"`
+	// Min and Max, if set, bound numbers, and the length of strings and
+	// arrays, inclusively.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// Enum, if not empty, lists the values allowed.
+	Enum []any `json:"enum,omitempty"`
+	// Validate, if set, is called with the decoded JSON value and returns
+	// an error describing any other violation.
+	Validate func(value any) error `json:"-"`
+}
+
+// ValidationError is a violation of an [AnnotationSchema] by one field of
+// one example.
+type ValidationError struct {
+	Index      int    `json:"index"` // Position of the example in the dataset.
+	TestCaseId string `json:"testCaseId,omitempty"`
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("example %d: %s: %s", e.Index, e.Field, e.Message)
+}
+
+// ValidateAnnotations checks every example of ds against schema and returns
+// one [ValidationError] for each field of each example that violates it,
+// ordered by example and then by field. It returns an error if the schema
+// itself is invalid or an example cannot be encoded as JSON.
+func ValidateAnnotations(ds Dataset, schema AnnotationSchema) ([]ValidationError, error) {
+	fields := slices.Clone(schema.Fields)
+	for i := range fields {
+		if err := fields[i].check(); err != nil {
+			return nil, fmt.Errorf("ai.ValidateAnnotations: %w", err)
+		}
+	}
+	var violations []ValidationError
+	for i, ex := range ds {
+		doc, err := jsonValue(ex)
+		if err != nil {
+			return nil, fmt.Errorf("ai.ValidateAnnotations: example %d: %w", i, err)
+		}
+		for _, f := range fields {
+			value, present := lookupJSONPath(doc, f.Name)
+			if msg := f.violation(value, present); msg != "" {
+				violations = append(violations, ValidationError{
+					Index:      i,
+					TestCaseId: ex.TestCaseId,
+					Field:      f.Name,
+					Message:    msg,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// check reports whether the spec is well formed, and replaces Enum with a
+// copy holding the values as decoded from JSON.
+func (f *FieldSpec) check() error {
+	if f.Name == "" {
+		return errors.New("field spec has no name")
+	}
+	switch f.Type {
+	case "", JSONTypeObject, JSONTypeArray, JSONTypeString, JSONTypeNumber, JSONTypeBoolean, JSONTypeNull:
+	default:
+		return fmt.Errorf("field %q: unknown type %q", f.Name, f.Type)
+	}
+	if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+		return fmt.Errorf("field %q: min %v is greater than max %v", f.Name, *f.Min, *f.Max)
+	}
+	enum := make([]any, len(f.Enum))
+	for i, e := range f.Enum {
+		v, err := jsonValue(e)
+		if err != nil {
+			return fmt.Errorf("field %q: enum value %d: %w", f.Name, i, err)
+		}
+		enum[i] = v
+	}
+	f.Enum = enum
+	return nil
+}
+
+// violation returns a description of how value violates the spec, or "" if
+// it does not.
+func (f *FieldSpec) violation(value any, present bool) string {
+	if !present || value == nil {
+		if f.Required {
+			return "required field is missing"
+		}
+		return ""
+	}
+	if f.Type != "" {
+		if got := jsonTypeOf(value); got != f.Type {
+			return fmt.Sprintf("value is %s, want %s", got, f.Type)
+		}
+	}
+	var n float64
+	var what string // What n measures; empty if the value is not bounded.
+	switch v := value.(type) {
+	case float64:
+		n, what = v, "value"
+	case string:
+		n, what = float64(utf8.RuneCountInString(v)), "length"
+	case []any:
+		n, what = float64(len(v)), "length"
+	}
+	if what != "" {
+		if f.Min != nil && n < *f.Min {
+			return fmt.Sprintf("%s %v is less than %v", what, n, *f.Min)
+		}
+		if f.Max != nil && n > *f.Max {
+			return fmt.Sprintf("%s %v is greater than %v", what, n, *f.Max)
+		}
+	}
+	if len(f.Enum) > 0 {
+		allowed := false
+		for _, e := range f.Enum {
+			if jsonValuesEqual(e, value) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("value %v is not one of %v", value, f.Enum)
+		}
+	}
+	if f.Validate != nil {
+		if err := f.Validate(value); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// lookupJSONPath returns the value at the dot-separated path of object keys
+// in the decoded JSON value doc, and whether it is present.
+func lookupJSONPath(doc any, path string) (any, bool) {
+	v := doc
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
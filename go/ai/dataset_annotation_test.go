@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateAnnotations(t *testing.T) {
+	zero, one, two := 0.0, 1.0, 2.0
+	schema := AnnotationSchema{Fields: []FieldSpec{
+		{Name: "reference.score", Type: JSONTypeNumber, Required: true, Min: &zero, Max: &one},
+		{Name: "reference.label", Enum: []any{"positive", "negative"}},
+		{Name: "context", Type: JSONTypeArray, Min: &one, Max: &two},
+		{Name: "input", Validate: func(v any) error {
+			if s, _ := v.(string); s == "" {
+				return errors.New("input must be a non-empty string")
+			}
+			return nil
+		}},
+	}}
+	ds := Dataset{
+		{TestCaseId: "ok", Input: "q", Reference: map[string]any{"score": 0.5, "label": "positive"}, Context: []any{"c"}},
+		{TestCaseId: "range", Input: "q", Reference: map[string]any{"score": 1.5}},
+		{TestCaseId: "missing", Input: 3, Reference: "unstructured", Context: []any{"a", "b", "c"}},
+		{TestCaseId: "type", Input: "q", Reference: map[string]any{"score": "high", "label": "neutral"}},
+	}
+
+	got, err := ValidateAnnotations(ds, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ValidationError{
+		{Index: 1, TestCaseId: "range", Field: "reference.score", Message: "value 1.5 is greater than 1"},
+		{Index: 2, TestCaseId: "missing", Field: "reference.score", Message: "required field is missing"},
+		{Index: 2, TestCaseId: "missing", Field: "context", Message: "length 3 is greater than 2"},
+		{Index: 2, TestCaseId: "missing", Field: "input", Message: "input must be a non-empty string"},
+		{Index: 3, TestCaseId: "type", Field: "reference.score", Message: "value is string, want number"},
+		{Index: 3, TestCaseId: "type", Field: "reference.label", Message: "value neutral is not one of [positive negative]"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, bad := range []FieldSpec{
+		{},
+		{Name: "input", Type: "integer"},
+		{Name: "input", Min: &two, Max: &one},
+	} {
+		if _, err := ValidateAnnotations(ds, AnnotationSchema{Fields: []FieldSpec{bad}}); err == nil {
+			t.Errorf("field spec %+v: got nil error, want error", bad)
+		}
+	}
+}
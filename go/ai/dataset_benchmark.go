@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// TruthfulQAURL is the location of the TruthfulQA dataset published by its
+// authors.
+const TruthfulQAURL = "https://raw.githubusercontent.com/sylinrl/TruthfulQA/main/TruthfulQA.csv"
+
+// DatasetLoader loads a [Dataset], such as a standard benchmark, on demand.
+// Loaders are registered with [RegisterBenchmarkDataset] and looked up with
+// [LookupBenchmarkDataset].
+type DatasetLoader interface {
+	Load(ctx context.Context) (Dataset, error)
+}
+
+// DatasetLoaderFunc adapts a function to the [DatasetLoader] interface.
+type DatasetLoaderFunc func(ctx context.Context) (Dataset, error)
+
+// Load implements [DatasetLoader.Load].
+func (f DatasetLoaderFunc) Load(ctx context.Context) (Dataset, error) {
+	return f(ctx)
+}
+
+// builtinBenchmarks holds the loaders available from every registry.
+var builtinBenchmarks = map[string]DatasetLoader{
+	"truthfulqa": &TruthfulQALoader{},
+}
+
+// RegisterBenchmarkDataset registers loader under name for use with
+// [LookupBenchmarkDataset]. A registered loader takes precedence over a
+// built-in one with the same name. It panics if a loader with the same name
+// is already registered.
+func RegisterBenchmarkDataset(r *registry.Registry, name string, loader DatasetLoader) {
+	r.RegisterValue(benchmarkDatasetKey(name), loader)
+}
+
+// LookupBenchmarkDataset returns the loader registered under name or, if
+// there is none, the built-in loader with that name. The built-in loaders
+// are:
+//
+//   - "truthfulqa": a [TruthfulQALoader] reading from [TruthfulQAURL].
+//
+// It returns nil if there is no loader with that name.
+func LookupBenchmarkDataset(r *registry.Registry, name string) DatasetLoader {
+	if loader, ok := r.LookupValue(benchmarkDatasetKey(name)).(DatasetLoader); ok {
+		return loader
+	}
+	return builtinBenchmarks[name]
+}
+
+func benchmarkDatasetKey(name string) string {
+	return "benchmarkDataset/" + name
+}
+
+// TruthfulQALoader loads the TruthfulQA benchmark, whose questions probe
+// whether a model repeats common misconceptions. Each [Example] has the
+// question as its Input and the best answer as its Reference. Its
+// TestCaseId is "truthfulqa-" followed by the question's row number.
+type TruthfulQALoader struct {
+	// URL is the CSV file to load. It defaults to [TruthfulQAURL].
+	URL string
+	// Client makes the request. It defaults to [http.DefaultClient].
+	Client *http.Client
+}
+
+// Load implements [DatasetLoader.Load].
+func (l *TruthfulQALoader) Load(ctx context.Context) (Dataset, error) {
+	url, client := l.URL, l.Client
+	if url == "" {
+		url = TruthfulQAURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ai.TruthfulQALoader: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ai.TruthfulQALoader: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ai.TruthfulQALoader: GET %s: %s", url, resp.Status)
+	}
+	ds, err := parseTruthfulQA(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ai.TruthfulQALoader: %w", err)
+	}
+	return ds, nil
+}
+
+// parseTruthfulQA reads the TruthfulQA CSV file, which has a header row
+// naming its columns.
+func parseTruthfulQA(r io.Reader) (Dataset, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.TrimSpace(name)] = i
+	}
+	question, ok := col["Question"]
+	if !ok {
+		return nil, errors.New(`missing "Question" column`)
+	}
+	best, ok := col["Best Answer"]
+	if !ok {
+		return nil, errors.New(`missing "Best Answer" column`)
+	}
+	var ds Dataset
+	for row := 1; ; row++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return ds, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, Example{
+			TestCaseId: "truthfulqa-" + strconv.Itoa(row),
+			Input:      rec[question],
+			Reference:  rec[best],
+		})
+	}
+}
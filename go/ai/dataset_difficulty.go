@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// difficultyRating is the structured output requested from the model.
+type difficultyRating struct {
+	Rating int `json:"rating" jsonschema:"minimum=1,maximum=10"`
+}
+
+// EstimateExampleDifficulty asks model to rate from 1 to 10 how hard it is
+// to answer the Input of example correctly, and returns the rating
+// normalized to [0, 1], where 1 is the hardest. The Reference, if any, is
+// shown to the model as the expected answer. The result can be stored in
+// [Example.Difficulty] for use with [StratifiedSampler].
+func EstimateExampleDifficulty(ctx context.Context, r *registry.Registry, model Model, example Example) (float64, error) {
+	if model == nil {
+		return 0, errors.New("ai.EstimateExampleDifficulty: model is required")
+	}
+	prompt, err := difficultyPrompt(&example)
+	if err != nil {
+		return 0, fmt.Errorf("ai.EstimateExampleDifficulty: %w", err)
+	}
+	var v difficultyRating
+	if _, err := GenerateData(ctx, r, &v, WithModel(model), WithPromptText(prompt)); err != nil {
+		return 0, fmt.Errorf("ai.EstimateExampleDifficulty: model %q: %w", model.Name(), err)
+	}
+	if v.Rating < 1 || v.Rating > 10 {
+		return 0, fmt.Errorf("ai.EstimateExampleDifficulty: model %q gave rating %d, want 1 to 10", model.Name(), v.Rating)
+	}
+	return float64(v.Rating-1) / 9, nil
+}
+
+// difficultyPrompt returns the prompt asking a model to rate the
+// difficulty of ex.
+func difficultyPrompt(ex *Example) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("Rate the difficulty of correctly answering the following input on a scale from 1 to 10, where 1 is trivial and 10 is extremely hard.\n\n")
+	if err := writeJudgeExample(&sb, &Example{Input: ex.Input, Reference: ex.Reference}); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
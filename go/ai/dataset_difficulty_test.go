@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestEstimateExampleDifficulty(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prompt string
+	model := defineJudgeModel(r, "difficulty", func(p string) any {
+		prompt = p
+		switch {
+		case strings.Contains(p, "2+2"):
+			return difficultyRating{Rating: 1}
+		case strings.Contains(p, "Riemann"):
+			return difficultyRating{Rating: 10}
+		case strings.Contains(p, "capital"):
+			return difficultyRating{Rating: 4}
+		}
+		return difficultyRating{Rating: 11}
+	})
+
+	ctx := context.Background()
+	for input, want := range map[string]float64{
+		"What is 2+2?":                    0,
+		"Prove the Riemann hypothesis.":   1,
+		"What is the capital of Burkina?": 1.0 / 3,
+	} {
+		got, err := EstimateExampleDifficulty(ctx, r, model, Example{Input: input, Reference: "answer"})
+		if err != nil {
+			t.Fatalf("%q: %v", input, err)
+		}
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%q: got difficulty %v, want %v", input, got, want)
+		}
+	}
+	if !strings.Contains(prompt, "Reference:\nanswer") {
+		t.Errorf("prompt %q does not contain the reference", prompt)
+	}
+	if _, err := EstimateExampleDifficulty(ctx, r, model, Example{Input: "hmm"}); err == nil {
+		t.Error("got nil, want error for a rating out of range")
+	}
+}
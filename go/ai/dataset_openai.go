@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// openAIRecord is one line of an OpenAI evals dataset in JSONL format. The
+// prompt is either a string or a list of chat messages. The "id" and
+// "context" fields are not part of the OpenAI format; they are written only
+// when set so that datasets survive a round trip.
+type openAIRecord struct {
+	Id         string          `json:"id,omitempty"`
+	Prompt     json.RawMessage `json:"prompt"`
+	Completion string          `json:"completion,omitempty"`
+	Ideal      any             `json:"ideal,omitempty"`
+	Context    []any           `json:"context,omitempty"`
+}
+
+// openAIMessage is a chat message in an OpenAI evals prompt.
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIRoles maps OpenAI chat roles to genkit roles.
+var openAIRoles = map[string]Role{
+	"system":    RoleSystem,
+	"user":      RoleUser,
+	"assistant": RoleModel,
+	"tool":      RoleTool,
+}
+
+// FromOpenAIDataset reads an OpenAI evals dataset in JSONL format, with one
+// record per line, and converts it to a [Dataset].
+//
+// The "prompt", "completion" and "ideal" fields map to the Input, Output and
+// Reference of each [Example]. A prompt that is a list of chat messages
+// becomes an Input of type []*Message.
+func FromOpenAIDataset(r io.Reader) (Dataset, error) {
+	dec := json.NewDecoder(r)
+	var ds Dataset
+	for n := 1; ; n++ {
+		var rec openAIRecord
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return ds, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ai.FromOpenAIDataset: record %d: %w", n, err)
+		}
+		ex := Example{
+			TestCaseId: rec.Id,
+			Reference:  rec.Ideal,
+			Context:    rec.Context,
+		}
+		if rec.Completion != "" {
+			ex.Output = rec.Completion
+		}
+		if ex.Input, err = openAIPromptToInput(rec.Prompt); err != nil {
+			return nil, fmt.Errorf("ai.FromOpenAIDataset: record %d: %w", n, err)
+		}
+		ds = append(ds, ex)
+	}
+}
+
+// ToOpenAIDataset writes ds to w as an OpenAI evals dataset in JSONL format.
+// An Input of type []*Message or []Message is written as a list of chat
+// messages using the text of each message; other inputs and outputs that are
+// not strings are encoded as JSON text.
+func ToOpenAIDataset(ds Dataset, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for i, ex := range ds {
+		rec := openAIRecord{
+			Id:      ex.TestCaseId,
+			Ideal:   ex.Reference,
+			Context: ex.Context,
+		}
+		prompt, err := inputToOpenAIPrompt(ex.Input)
+		if err != nil {
+			return fmt.Errorf("ai.ToOpenAIDataset: example %d input: %w", i, err)
+		}
+		if rec.Prompt, err = json.Marshal(prompt); err != nil {
+			return fmt.Errorf("ai.ToOpenAIDataset: example %d input: %w", i, err)
+		}
+		if ex.Output != nil {
+			if rec.Completion, err = exampleText(ex.Output); err != nil {
+				return fmt.Errorf("ai.ToOpenAIDataset: example %d output: %w", i, err)
+			}
+		}
+		if err := enc.Encode(&rec); err != nil {
+			return fmt.Errorf("ai.ToOpenAIDataset: %w", err)
+		}
+	}
+	return nil
+}
+
+// openAIPromptToInput converts an OpenAI prompt to an [Example] Input.
+func openAIPromptToInput(prompt json.RawMessage) (any, error) {
+	if len(prompt) == 0 || string(prompt) == "null" {
+		return nil, nil
+	}
+	var text string
+	if err := json.Unmarshal(prompt, &text); err == nil {
+		return text, nil
+	}
+	var chat []openAIMessage
+	if err := json.Unmarshal(prompt, &chat); err != nil {
+		return nil, errors.New("prompt must be a string or a list of chat messages")
+	}
+	msgs := make([]*Message, len(chat))
+	for i, m := range chat {
+		role, ok := openAIRoles[m.Role]
+		if !ok {
+			return nil, fmt.Errorf("unknown chat role %q", m.Role)
+		}
+		msgs[i] = NewTextMessage(role, m.Content)
+	}
+	return msgs, nil
+}
+
+// inputToOpenAIPrompt converts an [Example] Input to an OpenAI prompt.
+func inputToOpenAIPrompt(input any) (any, error) {
+	var msgs []*Message
+	switch v := input.(type) {
+	case nil:
+		return "", nil
+	case []*Message:
+		msgs = v
+	case []Message:
+		for i := range v {
+			msgs = append(msgs, &v[i])
+		}
+	default:
+		return exampleText(input)
+	}
+	chat := make([]openAIMessage, len(msgs))
+	for i, m := range msgs {
+		chat[i] = openAIMessage{Role: string(m.Role), Content: m.Text()}
+		for name, role := range openAIRoles {
+			if role == m.Role {
+				chat[i].Role = name
+			}
+		}
+	}
+	return chat, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIDatasetRoundTrip(t *testing.T) {
+	ds := Dataset{
+		{
+			TestCaseId: "chat",
+			Input: []*Message{
+				NewTextMessage(RoleSystem, "You are terse."),
+				NewTextMessage(RoleUser, "Capital of France?"),
+				NewTextMessage(RoleModel, "Paris."),
+				NewTextMessage(RoleUser, "And Spain?"),
+			},
+			Output:    "Madrid.",
+			Reference: "Madrid",
+		},
+		{
+			Input:     "2+2=",
+			Output:    "4",
+			Reference: []any{"4", "four"},
+			Context:   []any{"arithmetic"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ToOpenAIDataset(ds, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"role":"assistant","content":"Paris."`) {
+		t.Errorf("model turn was not written with the assistant role: %s", buf.String())
+	}
+	got, err := FromOpenAIDataset(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, ds) {
+		t.Errorf("round trip changed the dataset:\ngot  %#v\nwant %#v", got, ds)
+	}
+}
+
+func TestFromOpenAIDataset(t *testing.T) {
+	const sample = `{"prompt": "Say hi", "completion": "hi", "ideal": "hi"}
+{"prompt": [{"role": "narrator", "content": "x"}]}
+`
+	_, err := FromOpenAIDataset(strings.NewReader(sample))
+	if err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("got %v, want error for unknown role in record 2", err)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+)
+
+// RedactedValue replaces string values suppressed by [AnonymizeDataset].
+const RedactedValue = "[REDACTED]"
+
+// ErrPrivacyBudgetExhausted is returned when spending from a
+// [PrivacyBudget] would exceed its total.
+var ErrPrivacyBudgetExhausted = errors.New("privacy budget exhausted")
+
+// DPConfig configures [AnonymizeDataset].
+type DPConfig struct {
+	// Epsilon is the privacy parameter of the Laplace mechanism for all the
+	// numbers of an example together. Smaller values add more noise.
+	Epsilon float64
+	// Sensitivity is the largest amount by which one individual can change
+	// a numeric value.
+	Sensitivity float64
+	// Fields names the example fields to anonymize: any of "Input",
+	// "Output", "Context" and "Reference".
+	Fields []string
+	// K is the smallest number of examples that must share a string value
+	// for it to be kept. Zero means 5.
+	K int
+	// Budget, if set, is charged Epsilon for each call to
+	// [AnonymizeDataset], which is split among the numbers of each example.
+	Budget *PrivacyBudget
+	// Rand is the source of noise. If nil, a randomly seeded source is used.
+	Rand *rand.Rand
+}
+
+// anonymizableFields gives access to the fields [DPConfig.Fields] may name,
+// except Context, which is a list and handled separately.
+var anonymizableFields = map[string]func(*Example) *any{
+	"Input":     func(ex *Example) *any { return &ex.Input },
+	"Output":    func(ex *Example) *any { return &ex.Output },
+	"Reference": func(ex *Example) *any { return &ex.Reference },
+}
+
+// AnonymizeDataset returns a copy of ds in which the configured fields are
+// anonymized. Values are treated as JSON: objects and arrays are traversed,
+// and their leaves anonymized independently.
+//
+//   - Numbers get Laplace noise. Epsilon is split evenly among the numbers
+//     of an example: if no example has more than m numbers in the
+//     configured fields, each gets noise with scale m*Sensitivity/Epsilon.
+//     By sequential composition, releasing the noised numbers of an example
+//     is Epsilon-differentially private with respect to changes of up to
+//     Sensitivity in each of its numbers, for instance when the example
+//     holds the data of one individual. The noise has mean zero, so means
+//     over many examples are preserved in expectation.
+//   - Strings are k-anonymized by suppression: a string is kept only if at
+//     least K examples have the same string at the same place in the same
+//     field, and is replaced by [RedactedValue] otherwise. This hides rare,
+//     identifying values but is not differential privacy, and the guarantee
+//     above does not cover the strings that are kept.
+//
+// Other values, and TestCaseId, Weight and the fields not named in
+// dp.Fields, are copied unchanged. If dp.Budget is set and cannot cover
+// dp.Epsilon, AnonymizeDataset returns an error wrapping
+// [ErrPrivacyBudgetExhausted].
+func AnonymizeDataset(ds Dataset, dp DPConfig) (Dataset, error) {
+	if !(dp.Epsilon > 0) {
+		return nil, fmt.Errorf("ai.AnonymizeDataset: epsilon must be positive, got %v", dp.Epsilon)
+	}
+	if dp.Sensitivity < 0 {
+		return nil, fmt.Errorf("ai.AnonymizeDataset: sensitivity must not be negative, got %v", dp.Sensitivity)
+	}
+	for _, field := range dp.Fields {
+		if _, ok := anonymizableFields[field]; !ok && field != "Context" {
+			return nil, fmt.Errorf("ai.AnonymizeDataset: cannot anonymize field %q", field)
+		}
+	}
+	k := dp.K
+	if k == 0 {
+		k = 5
+	}
+
+	out := make(Dataset, len(ds))
+	for i, ex := range ds {
+		ex.Context = slices.Clone(ex.Context)
+		for _, field := range dp.Fields {
+			if field == "Context" {
+				for j, c := range ex.Context {
+					v, err := jsonValue(c)
+					if err != nil {
+						return nil, fmt.Errorf("ai.AnonymizeDataset: example %d: context: %w", i, err)
+					}
+					ex.Context[j] = v
+				}
+				continue
+			}
+			p := anonymizableFields[field](&ex)
+			v, err := jsonValue(*p)
+			if err != nil {
+				return nil, fmt.Errorf("ai.AnonymizeDataset: example %d: %s: %w", i, field, err)
+			}
+			*p = v
+		}
+		out[i] = ex
+	}
+
+	// Count the strings at each place so that rare ones can be suppressed,
+	// and the numbers of each example so that epsilon can be split among them.
+	counts := map[[2]string]int{}
+	maxNumbers := 0
+	for i := range out {
+		numbers := 0
+		visitFields(out[i:i+1], dp.Fields, func(path string, v any) any {
+			switch v := v.(type) {
+			case float64:
+				numbers++
+			case string:
+				counts[[2]string{path, v}]++
+			}
+			return v
+		})
+		maxNumbers = max(maxNumbers, numbers)
+	}
+
+	if dp.Budget != nil {
+		if err := dp.Budget.Spend(dp.Epsilon); err != nil {
+			return nil, fmt.Errorf("ai.AnonymizeDataset: %w", err)
+		}
+	}
+	r := samplerRand(dp.Rand)
+	scale := dp.Sensitivity * float64(max(maxNumbers, 1)) / dp.Epsilon
+	visitFields(out, dp.Fields, func(path string, v any) any {
+		switch v := v.(type) {
+		case float64:
+			return v + laplaceNoise(r, scale)
+		case string:
+			if counts[[2]string{path, v}] < k {
+				return RedactedValue
+			}
+		}
+		return v
+	})
+	return out, nil
+}
+
+// visitFields replaces each leaf of the named fields of the examples in ds
+// with the result of fn, which is passed the leaf's path and value.
+func visitFields(ds Dataset, fields []string, fn func(path string, v any) any) {
+	for i := range ds {
+		ex := &ds[i]
+		for _, field := range fields {
+			if field == "Context" {
+				for j := range ex.Context {
+					ex.Context[j] = visitLeaves("Context[]", ex.Context[j], fn)
+				}
+				continue
+			}
+			p := anonymizableFields[field](ex)
+			*p = visitLeaves(field, *p, fn)
+		}
+	}
+}
+
+// visitLeaves returns v with each leaf replaced by the result of fn.
+func visitLeaves(path string, v any, fn func(path string, v any) any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = visitLeaves(path+"."+key, elem, fn)
+		}
+		return v
+	case []any:
+		for i, elem := range v {
+			v[i] = visitLeaves(path+"[]", elem, fn)
+		}
+		return v
+	}
+	return fn(path, v)
+}
+
+// laplaceNoise returns a sample of the Laplace distribution with mean zero
+// and the given scale.
+func laplaceNoise(r *rand.Rand, scale float64) float64 {
+	if scale == 0 {
+		return 0
+	}
+	u := r.Float64() - 0.5
+	return -scale * math.Copysign(1, u) * math.Log(1-2*math.Abs(u))
+}
+
+// PrivacyBudget tracks the total privacy loss, as a sum of epsilons, of a
+// series of differentially private releases. By sequential composition, the
+// releases together are differentially private with the sum of their
+// epsilons. It is safe for concurrent use.
+type PrivacyBudget struct {
+	mu    sync.Mutex
+	total float64
+	spent float64
+}
+
+// NewPrivacyBudget returns a [PrivacyBudget] that allows spending up to
+// total.
+func NewPrivacyBudget(total float64) *PrivacyBudget {
+	return &PrivacyBudget{total: total}
+}
+
+// Spend records a release with privacy parameter epsilon. It returns an
+// error wrapping [ErrPrivacyBudgetExhausted], and records nothing, if the
+// release would exceed the budget.
+func (b *PrivacyBudget) Spend(epsilon float64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.spent+epsilon > b.total {
+		return fmt.Errorf("%w: spending %v would exceed the total of %v (%v spent)", ErrPrivacyBudgetExhausted, epsilon, b.total, b.spent)
+	}
+	b.spent += epsilon
+	return nil
+}
+
+// Spent returns the sum of the epsilons spent so far.
+func (b *PrivacyBudget) Spent() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+// Remaining returns the part of the budget that has not been spent.
+func (b *PrivacyBudget) Remaining() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.total - b.spent
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestAnonymizeDataset(t *testing.T) {
+	var ds Dataset
+	for i := range 200 {
+		city := "Paris"
+		if i == 0 {
+			city = "Smallville"
+		}
+		ds = append(ds, Example{
+			TestCaseId: "t",
+			Input:      "q",
+			Output:     map[string]any{"score": 10, "city": city},
+			Context:    []any{city},
+		})
+	}
+	budget := NewPrivacyBudget(1.5)
+	dp := DPConfig{
+		Epsilon:     1,
+		Sensitivity: 1,
+		Fields:      []string{"Output", "Context"},
+		Budget:      budget,
+		Rand:        rand.New(rand.NewPCG(1, 2)),
+	}
+	out, err := AnonymizeDataset(ds, dp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sum float64
+	changed := 0
+	for _, ex := range out {
+		v := ex.Output.(map[string]any)["score"].(float64)
+		sum += v
+		if v != 10 {
+			changed++
+		}
+	}
+	// The noise has standard deviation sqrt(2), so the mean of 200 values is
+	// within 0.5 of the true mean with overwhelming probability.
+	if mean := sum / float64(len(out)); math.Abs(mean-10) > 0.5 {
+		t.Errorf("got mean %v, want about 10", mean)
+	}
+	if changed != len(out) {
+		t.Errorf("%d of %d scores have no noise", len(out)-changed, len(out))
+	}
+	if got, want := out[0].Output.(map[string]any)["city"], RedactedValue; got != want {
+		t.Errorf("got rare city %v, want %v", got, want)
+	}
+	if got, want := out[0].Context[0], RedactedValue; got != want {
+		t.Errorf("got rare context %v, want %v", got, want)
+	}
+	if got, want := out[1].Output.(map[string]any)["city"], "Paris"; got != want {
+		t.Errorf("got common city %v, want %v", got, want)
+	}
+	if out[1].Input != "q" || out[1].TestCaseId != "t" {
+		t.Errorf("unlisted fields changed: %+v", out[1])
+	}
+	if got := ds[0].Output.(map[string]any)["city"]; got != "Smallville" {
+		t.Errorf("input dataset was modified: got city %v", got)
+	}
+
+	if _, err := AnonymizeDataset(ds, dp); !errors.Is(err, ErrPrivacyBudgetExhausted) {
+		t.Errorf("got %v, want ErrPrivacyBudgetExhausted", err)
+	}
+	if got, want := budget.Remaining(), 0.5; got != want {
+		t.Errorf("got remaining budget %v, want %v", got, want)
+	}
+
+	for name, bad := range map[string]DPConfig{
+		"epsilon": {Fields: []string{"Output"}},
+		"field":   {Epsilon: 1, Fields: []string{"Weight"}},
+	} {
+		if _, err := AnonymizeDataset(ds, bad); err == nil {
+			t.Errorf("%s: got nil, want error", name)
+		}
+	}
+}
+
+func TestAnonymizeDatasetSplitsEpsilon(t *testing.T) {
+	var ds Dataset
+	for range 2000 {
+		ds = append(ds, Example{Output: map[string]any{"a": 0, "b": 0}})
+	}
+	out, err := AnonymizeDataset(ds, DPConfig{
+		Epsilon:     1,
+		Sensitivity: 1,
+		Fields:      []string{"Output"},
+		Rand:        rand.New(rand.NewPCG(3, 4)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each example has two numbers, so each gets half of epsilon and noise
+	// with scale 2, whose mean absolute value is 2.
+	var sum float64
+	for _, ex := range out {
+		for _, v := range ex.Output.(map[string]any) {
+			sum += math.Abs(v.(float64))
+		}
+	}
+	if got := sum / float64(2*len(out)); math.Abs(got-2) > 0.2 {
+		t.Errorf("got mean absolute noise %v, want about 2", got)
+	}
+}
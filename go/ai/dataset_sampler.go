@@ -0,0 +1,264 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// DatasetSampler selects the examples to evaluate from a pool. See
+// [WithEvaluateDatasetSampler].
+type DatasetSampler interface {
+	// Sample returns at most n examples of pool, in pool order. It returns
+	// an error if n is negative.
+	Sample(ctx context.Context, pool Dataset, n int) (Dataset, error)
+}
+
+// checkSampleSize returns an error if n is not a valid sample size.
+func checkSampleSize(sampler string, n int) error {
+	if n < 0 {
+		return fmt.Errorf("ai.%s: sample size %d is negative", sampler, n)
+	}
+	return nil
+}
+
+// RandomSampler samples examples uniformly at random without replacement.
+type RandomSampler struct {
+	// Rand is the source of randomness. If nil, a randomly seeded source is
+	// used. A sampler with a non-nil Rand must not be used concurrently.
+	Rand *rand.Rand
+}
+
+// Sample implements [DatasetSampler.Sample].
+func (s *RandomSampler) Sample(ctx context.Context, pool Dataset, n int) (Dataset, error) {
+	if err := checkSampleSize("RandomSampler", n); err != nil {
+		return nil, err
+	}
+	weights := make([]float64, len(pool))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weightedSample(samplerRand(s.Rand), pool, weights, n), nil
+}
+
+// StratifiedSampler samples examples so that each value of Field is
+// represented in proportion to its share of the pool. Within each group,
+// examples are drawn uniformly at random.
+type StratifiedSampler struct {
+	// Field is the example field to group by: "TestCaseId", "Input",
+	// "Output", "Reference", "Language" or "Difficulty". Values that are not
+	// strings are compared by their JSON encoding, except for difficulties,
+	// which are grouped into DifficultyBins ranges of equal width. Examples
+	// without a difficulty form a group of their own.
+	Field string
+	// DifficultyBins is the number of difficulty ranges when grouping by
+	// "Difficulty". Zero means 3, for easy, medium and hard examples.
+	DifficultyBins int
+	// Rand is the source of randomness, as in [RandomSampler].
+	Rand *rand.Rand
+}
+
+// exampleFieldValue returns the value of each field that
+// [StratifiedSampler] can group by.
+var exampleFieldValue = map[string]func(*Example) any{
+	"TestCaseId": func(ex *Example) any { return ex.TestCaseId },
+	"Input":      func(ex *Example) any { return ex.Input },
+	"Output":     func(ex *Example) any { return ex.Output },
+	"Reference":  func(ex *Example) any { return ex.Reference },
+	"Language":   func(ex *Example) any { return ex.Language },
+	"Difficulty": func(ex *Example) any { return ex.Difficulty },
+}
+
+// difficultyBin returns the index of the range of equal width, out of
+// bins ranges covering [0, 1], that contains difficulty.
+func difficultyBin(difficulty float64, bins int) int {
+	return min(max(int(difficulty*float64(bins)), 0), bins-1)
+}
+
+// Sample implements [DatasetSampler.Sample]. Group sizes are rounded by
+// the largest remainder method, so the sample has exactly n examples when
+// the pool has at least n.
+func (s *StratifiedSampler) Sample(ctx context.Context, pool Dataset, n int) (Dataset, error) {
+	if err := checkSampleSize("StratifiedSampler", n); err != nil {
+		return nil, err
+	}
+	value, ok := exampleFieldValue[s.Field]
+	if !ok {
+		return nil, fmt.Errorf("ai.StratifiedSampler: cannot group by field %q", s.Field)
+	}
+	if n >= len(pool) {
+		return slices.Clone(pool), nil
+	}
+
+	bins := s.DifficultyBins
+	if bins == 0 {
+		bins = 3
+	}
+	if bins < 0 {
+		return nil, fmt.Errorf("ai.StratifiedSampler: %d difficulty bins", bins)
+	}
+
+	var keys []string
+	groups := map[string][]int{}
+	for i := range pool {
+		v := value(&pool[i])
+		if d := pool[i].Difficulty; s.Field == "Difficulty" && d != nil {
+			v = difficultyBin(*d, bins)
+		}
+		key, err := exampleText(v)
+		if err != nil {
+			return nil, fmt.Errorf("ai.StratifiedSampler: example %d: %w", i, err)
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	// Allocate the floor of each group's share, then hand out the rest to
+	// the groups with the largest remainders.
+	type share struct {
+		key       string
+		size      int
+		remainder float64
+	}
+	shares := make([]share, len(keys))
+	allocated := 0
+	for i, key := range keys {
+		exact := float64(n) * float64(len(groups[key])) / float64(len(pool))
+		shares[i] = share{key: key, size: int(exact), remainder: exact - math.Floor(exact)}
+		allocated += shares[i].size
+	}
+	slices.SortStableFunc(shares, func(a, b share) int { return cmp.Compare(b.remainder, a.remainder) })
+	for i := 0; allocated < n; i++ {
+		shares[i].size++
+		allocated++
+	}
+
+	r := samplerRand(s.Rand)
+	var picked []int
+	for _, sh := range shares {
+		group := groups[sh.key]
+		r.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+		picked = append(picked, group[:sh.size]...)
+	}
+	slices.Sort(picked)
+	sample := make(Dataset, len(picked))
+	for i, idx := range picked {
+		sample[i] = pool[idx]
+	}
+	return sample, nil
+}
+
+// FailureOversampleSampler samples examples at random, favoring those that
+// failed in recent evaluations stored in Store. Examples are matched to
+// stored results by TestCaseId.
+type FailureOversampleSampler struct {
+	Store StoreEvaluatorResponse
+	// Evaluator restricts the history to runs of the named evaluator. If
+	// empty, runs of all evaluators are considered.
+	Evaluator string
+	// Runs is the number of most recent runs to consider. Zero means 1.
+	Runs int
+	// Factor is how many times more likely a failed example is to be drawn
+	// than one that did not fail. Zero means 3.
+	Factor float64
+	// Rand is the source of randomness, as in [RandomSampler].
+	Rand *rand.Rand
+}
+
+// Sample implements [DatasetSampler.Sample].
+func (s *FailureOversampleSampler) Sample(ctx context.Context, pool Dataset, n int) (Dataset, error) {
+	if err := checkSampleSize("FailureOversampleSampler", n); err != nil {
+		return nil, err
+	}
+	if s.Store == nil {
+		return nil, errors.New("ai.FailureOversampleSampler: store is required")
+	}
+	factor := s.Factor
+	if factor == 0 {
+		factor = 3
+	}
+	if factor < 1 {
+		return nil, fmt.Errorf("ai.FailureOversampleSampler: factor %v is less than 1", factor)
+	}
+	runs := max(s.Runs, 1)
+
+	history, err := LoadEvalHistory(ctx, s.Store, EvalFilter{Evaluator: s.Evaluator})
+	if err != nil {
+		return nil, fmt.Errorf("ai.FailureOversampleSampler: %w", err)
+	}
+	failed := map[string]bool{}
+	for _, run := range history[max(len(history)-runs, 0):] {
+		resp, err := s.Store.Load(ctx, run.EvalId)
+		if err != nil {
+			return nil, fmt.Errorf("ai.FailureOversampleSampler: %w", err)
+		}
+		for _, res := range *resp {
+			if resultStatus(res) == ScoreStatusFail {
+				failed[res.TestCaseId] = true
+			}
+		}
+	}
+
+	weights := make([]float64, len(pool))
+	for i, ex := range pool {
+		weights[i] = 1
+		if ex.TestCaseId != "" && failed[ex.TestCaseId] {
+			weights[i] = factor
+		}
+	}
+	return weightedSample(samplerRand(s.Rand), pool, weights, n), nil
+}
+
+// weightedSample draws n examples of pool without replacement, each with
+// probability proportional to its weight, and returns them in pool order.
+// It uses the Efraimidis-Spirakis method: each example gets the key
+// u^(1/weight) for a uniform random u, and the n largest keys are chosen.
+func weightedSample(r *rand.Rand, pool Dataset, weights []float64, n int) Dataset {
+	if n >= len(pool) {
+		return slices.Clone(pool)
+	}
+	keys := make([]float64, len(pool))
+	idx := make([]int, len(pool))
+	for i, w := range weights {
+		keys[i] = math.Pow(r.Float64(), 1/w)
+		idx[i] = i
+	}
+	slices.SortFunc(idx, func(a, b int) int { return cmp.Compare(keys[b], keys[a]) })
+	idx = idx[:n]
+	slices.Sort(idx)
+	sample := make(Dataset, n)
+	for i, j := range idx {
+		sample[i] = pool[j]
+	}
+	return sample
+}
+
+// samplerRand returns r, or a randomly seeded source if r is nil.
+func samplerRand(r *rand.Rand) *rand.Rand {
+	if r != nil {
+		return r
+	}
+	return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// numberedDataset returns n examples with TestCaseIds "0" to "n-1".
+func numberedDataset(n int) Dataset {
+	ds := make(Dataset, n)
+	for i := range ds {
+		ds[i] = Example{TestCaseId: fmt.Sprint(i), Input: fmt.Sprint("q", i)}
+	}
+	return ds
+}
+
+func testCaseIds(ds Dataset) []string {
+	ids := make([]string, len(ds))
+	for i, ex := range ds {
+		ids[i] = ex.TestCaseId
+	}
+	return ids
+}
+
+func TestRandomSampler(t *testing.T) {
+	ctx := context.Background()
+	pool := numberedDataset(20)
+	s := &RandomSampler{Rand: rand.New(rand.NewPCG(1, 2))}
+	sample, err := s.Sample(ctx, pool, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(sample), 5; got != want {
+		t.Fatalf("got %d examples, want %d", got, want)
+	}
+	ids := testCaseIds(sample)
+	if !slices.IsSortedFunc(ids, func(a, b string) int { return slices.Index(testCaseIds(pool), a) - slices.Index(testCaseIds(pool), b) }) {
+		t.Errorf("sample %v is not in pool order", ids)
+	}
+	if got := len(slices.Compact(slices.Clone(ids))); got != 5 {
+		t.Errorf("sample %v has duplicates", ids)
+	}
+
+	all, err := s.Sample(ctx, pool, 50)
+	if err != nil || len(all) != len(pool) {
+		t.Errorf("got %d examples (err %v), want the whole pool", len(all), err)
+	}
+}
+
+func TestStratifiedSampler(t *testing.T) {
+	var pool Dataset
+	for lang, n := range map[string]int{"en": 6, "fr": 3, "de": 1} {
+		for range n {
+			pool = append(pool, Example{Input: "q", Language: lang})
+		}
+	}
+	s := &StratifiedSampler{Field: "Language", Rand: rand.New(rand.NewPCG(1, 2))}
+	sample, err := s.Sample(context.Background(), pool, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for _, ex := range sample {
+		counts[ex.Language]++
+	}
+	// Exact shares are 3, 1.5 and 0.5; one of the halves is rounded up.
+	if len(sample) != 5 || counts["en"] != 3 || counts["fr"]+counts["de"] != 2 || counts["fr"] < 1 {
+		t.Errorf("got counts %v, want 3 en and 2 others including at least 1 fr", counts)
+	}
+
+	if _, err := (&StratifiedSampler{Field: "Weight"}).Sample(context.Background(), pool, 5); err == nil {
+		t.Error("got nil, want error for unsupported field")
+	}
+}
+
+func TestStratifiedSamplerDifficulty(t *testing.T) {
+	var pool Dataset
+	for difficulty, n := range map[float64]int{0.1: 6, 0.5: 3, 1: 3, -1: 3} {
+		for range n {
+			ex := Example{Input: "q"}
+			if difficulty >= 0 {
+				ex.Difficulty = &difficulty
+			}
+			pool = append(pool, ex)
+		}
+	}
+	s := &StratifiedSampler{Field: "Difficulty", Rand: rand.New(rand.NewPCG(1, 2))}
+	sample, err := s.Sample(context.Background(), pool, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unrated examples are counted under -1.
+	counts := map[float64]int{}
+	for _, ex := range sample {
+		if ex.Difficulty == nil {
+			counts[-1]++
+		} else {
+			counts[*ex.Difficulty]++
+		}
+	}
+	if want := map[float64]int{0.1: 2, 0.5: 1, 1: 1, -1: 1}; !maps.Equal(counts, want) {
+		t.Errorf("got counts %v, want %v", counts, want)
+	}
+
+	// With a single bin, every rated example is in the same group.
+	s = &StratifiedSampler{Field: "Difficulty", DifficultyBins: 1, Rand: rand.New(rand.NewPCG(1, 2))}
+	if sample, err := s.Sample(context.Background(), pool, 4); err != nil || len(sample) != 4 {
+		t.Errorf("got %d examples (%v), want 4", len(sample), err)
+	}
+}
+
+func TestFailureOversampleSampler(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryEvaluationStore()
+	resp := EvaluatorResponse{passFail("3", false, 0)}
+	for i := range 10 {
+		if i != 3 {
+			resp = append(resp, passFail(fmt.Sprint(i), true, 1))
+		}
+	}
+	if err := store.Save(ctx, "", "eval1", &resp); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordEvalRun(ctx, store, EvalRun{EvalId: "eval1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := numberedDataset(10)
+	picked := 0
+	for seed := range uint64(20) {
+		s := &FailureOversampleSampler{Store: store, Factor: 1000, Rand: rand.New(rand.NewPCG(seed, seed))}
+		sample, err := s.Sample(ctx, pool, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sample[0].TestCaseId == "3" {
+			picked++
+		}
+	}
+	if picked < 18 {
+		t.Errorf("failed example picked in %d of 20 samples, want nearly all", picked)
+	}
+}
+
+func TestSamplerNegativeSize(t *testing.T) {
+	samplers := map[string]DatasetSampler{
+		"random":     &RandomSampler{},
+		"stratified": &StratifiedSampler{Field: "Input"},
+		"failures":   &FailureOversampleSampler{Store: NewInMemoryEvaluationStore()},
+	}
+	for name, s := range samplers {
+		if _, err := s.Sample(context.Background(), numberedDataset(5), -1); err == nil {
+			t.Errorf("%s: got nil, want error for a negative sample size", name)
+		}
+	}
+}
+
+func TestWithEvaluateDatasetSampler(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := DefineEvaluator(r, "test", "sampled", &evalOptions, testEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := numberedDataset(10)
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDatasetSampler(&RandomSampler{}, 4), WithEvaluateDataset(&pool))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(*resp), 4; got != want {
+		t.Errorf("got %d results, want %d", got, want)
+	}
+	if _, err := Evaluate(context.Background(), e, WithEvaluateDatasetSampler(&RandomSampler{}, 0)); err == nil {
+		t.Error("got nil, want error for zero sample size")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+// ErrDatasetNotFound is returned by a [DatasetStore] when no dataset is
+// stored under the requested name.
+var ErrDatasetNotFound = errors.New("dataset not found")
+
+// DatasetStore persists datasets by name. Names ending in ".jsonl" are
+// stored as JSONL, with one example per line, and other names as a JSON
+// list of examples.
+type DatasetStore interface {
+	// Load returns the dataset stored under name, or an error wrapping
+	// [ErrDatasetNotFound].
+	Load(ctx context.Context, name string) (Dataset, error)
+	// Save stores ds under name, replacing any previous dataset.
+	Save(ctx context.Context, name string, ds Dataset) error
+}
+
+// isJSONL reports whether the dataset named name is stored as JSONL.
+func isJSONL(name string) bool {
+	return path.Ext(name) == ".jsonl"
+}
+
+// ReadDataset reads a [Dataset] from r in the format selected by the
+// extension of name, as described in [DatasetStore]. JSONL datasets are
+// decoded one example at a time.
+func ReadDataset(r io.Reader, name string) (Dataset, error) {
+	dec := json.NewDecoder(r)
+	var ds Dataset
+	if !isJSONL(name) {
+		if err := dec.Decode(&ds); err != nil {
+			return nil, fmt.Errorf("reading dataset %q: %w", name, err)
+		}
+		return ds, nil
+	}
+	for {
+		var ex Example
+		err := dec.Decode(&ex)
+		if errors.Is(err, io.EOF) {
+			return ds, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading dataset %q: %w", name, err)
+		}
+		ds = append(ds, ex)
+	}
+}
+
+// WriteDataset writes ds to w in the format selected by the extension of
+// name, as described in [DatasetStore]. Examples are encoded one at a time,
+// so that large datasets can be streamed.
+func WriteDataset(w io.Writer, name string, ds Dataset) error {
+	enc := json.NewEncoder(w)
+	if isJSONL(name) {
+		for i, ex := range ds {
+			if err := enc.Encode(ex); err != nil {
+				return fmt.Errorf("writing dataset %q: example %d: %w", name, i, err)
+			}
+		}
+		return nil
+	}
+	if _, err := io.WriteString(w, "["); err != nil {
+		return fmt.Errorf("writing dataset %q: %w", name, err)
+	}
+	for i, ex := range ds {
+		b, err := json.Marshal(ex)
+		if err != nil {
+			return fmt.Errorf("writing dataset %q: example %d: %w", name, i, err)
+		}
+		if i > 0 {
+			b = append([]byte(",\n"), b...)
+		}
+		if _, err := w.Write(b); err != nil {
+			return fmt.Errorf("writing dataset %q: %w", name, err)
+		}
+	}
+	if _, err := io.WriteString(w, "]\n"); err != nil {
+		return fmt.Errorf("writing dataset %q: %w", name, err)
+	}
+	return nil
+}
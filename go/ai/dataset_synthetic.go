@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/uuid"
+)
+
+// DatasetSpec describes the examples that [GenerateSyntheticDataset] asks a
+// model to write.
+type DatasetSpec struct {
+	// Domain is the subject of the examples, such as "questions about a
+	// bank's savings accounts". It is required.
+	Domain string
+	// InputTemplate describes the form of each input, such as "a customer
+	// question of one or two sentences".
+	InputTemplate string
+	// Difficulties are the difficulty levels to cover, from easiest to
+	// hardest, such as "easy" and "hard". Examples are spread evenly across
+	// them.
+	Difficulties []string
+	// Constraints are additional requirements for the variety of the
+	// examples, such as "vary the tone from polite to angry".
+	Constraints []string
+	// BatchSize is the number of examples requested per model call. It
+	// defaults to 10.
+	BatchSize int
+}
+
+// syntheticBatch is the structured output requested from the model.
+type syntheticBatch struct {
+	Examples []struct {
+		Input      string `json:"input"`
+		Reference  string `json:"reference"`
+		Difficulty string `json:"difficulty,omitempty"`
+	} `json:"examples"`
+}
+
+// maxSyntheticAvoid is the number of earlier inputs listed in each prompt
+// so that the model does not repeat them.
+const maxSyntheticAvoid = 50
+
+// GenerateSyntheticDataset asks model to write n examples matching spec, in
+// batches of spec.BatchSize. Each example has an Input, a Reference answer
+// and a TestCaseId. Inputs that differ only in case or white space are
+// considered duplicates and dropped, and more examples are requested until
+// there are n unique ones. It returns an error if the model does not
+// produce enough unique examples within a few extra calls.
+//
+// Each batch asks for a mix of spec.Difficulties that keeps the dataset
+// balanced, and the model labels every example with its level. The
+// Difficulty of an example is the position of its level among
+// spec.Difficulties, scaled to [0, 1]; it is left unset if there is only
+// one level or the model's label is not one of them.
+func GenerateSyntheticDataset(ctx context.Context, r *registry.Registry, model Model, spec DatasetSpec, n int) (Dataset, error) {
+	if spec.Domain == "" {
+		return nil, errors.New("ai.GenerateSyntheticDataset: domain is required")
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("ai.GenerateSyntheticDataset: invalid number of examples %d", n)
+	}
+	batchSize := spec.BatchSize
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+	levels := map[string]int{}
+	for i, d := range spec.Difficulties {
+		levels[strings.ToLower(d)] = i
+	}
+	have := make([]int, len(spec.Difficulties))
+
+	ds := Dataset{}
+	seen := map[string]bool{}
+	var inputs []string
+	maxCalls := 2*((n+batchSize-1)/batchSize) + 2
+	for call := 0; len(ds) < n; call++ {
+		if call == maxCalls {
+			return nil, fmt.Errorf("ai.GenerateSyntheticDataset: got only %d unique examples after %d model calls", len(ds), call)
+		}
+		count := min(batchSize, n-len(ds))
+		prompt := syntheticPrompt(spec, batchDifficulties(have, n, count), count, inputs[max(0, len(inputs)-maxSyntheticAvoid):])
+		var batch syntheticBatch
+		if _, err := GenerateData(ctx, r, &batch, WithModel(model), WithPromptText(prompt)); err != nil {
+			return nil, fmt.Errorf("ai.GenerateSyntheticDataset: model %q: %w", model.Name(), err)
+		}
+		for _, ex := range batch.Examples {
+			key := strings.Join(strings.Fields(strings.ToLower(ex.Input)), " ")
+			if key == "" || seen[key] || len(ds) == n {
+				continue
+			}
+			seen[key] = true
+			inputs = append(inputs, ex.Input)
+			example := Example{TestCaseId: uuid.New().String(), Input: ex.Input}
+			if ex.Reference != "" {
+				example.Reference = ex.Reference
+			}
+			if level, ok := levels[strings.ToLower(strings.TrimSpace(ex.Difficulty))]; ok {
+				have[level]++
+				if len(have) > 1 {
+					difficulty := float64(level) / float64(len(have)-1)
+					example.Difficulty = &difficulty
+				}
+			}
+			ds = append(ds, example)
+		}
+	}
+	return ds, nil
+}
+
+// batchDifficulties returns how many of the next count examples to request
+// at each difficulty level, given how many examples of each level the
+// dataset already has out of n. Levels furthest below an even share of n
+// come first, and earlier levels win ties.
+func batchDifficulties(have []int, n, count int) []int {
+	if len(have) == 0 {
+		return nil
+	}
+	want := make([]int, len(have))
+	for i := range want {
+		want[i] = n/len(have) - have[i]
+		if i < n%len(have) {
+			want[i]++
+		}
+	}
+	alloc := make([]int, len(have))
+	for range count {
+		i := 0
+		for j := range want {
+			if want[j] > want[i] {
+				i = j
+			}
+		}
+		alloc[i]++
+		want[i]--
+	}
+	return alloc
+}
+
+// syntheticPrompt returns the prompt asking for count examples matching
+// spec, alloc[i] of which at spec.Difficulties[i], none of which repeats an
+// input in avoid.
+func syntheticPrompt(spec DatasetSpec, alloc []int, count int, avoid []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Write %d diverse examples for evaluating an AI system.\n\n", count)
+	fmt.Fprintf(&sb, "Domain: %s\n", spec.Domain)
+	if spec.InputTemplate != "" {
+		fmt.Fprintf(&sb, "Each input should be: %s\n", spec.InputTemplate)
+	}
+	var mix []string
+	for i, k := range alloc {
+		if k > 0 {
+			mix = append(mix, fmt.Sprintf("%d %s", k, spec.Difficulties[i]))
+		}
+	}
+	if len(mix) > 0 {
+		fmt.Fprintf(&sb, "Difficulty: %s\n", strings.Join(mix, ", "))
+	}
+	if len(spec.Constraints) > 0 {
+		sb.WriteString("\nRequirements:\n")
+		for _, c := range spec.Constraints {
+			fmt.Fprintf(&sb, "- %s\n", c)
+		}
+	}
+	if len(avoid) > 0 {
+		sb.WriteString("\nDo not repeat or paraphrase these existing inputs:\n")
+		for _, in := range avoid {
+			fmt.Fprintf(&sb, "- %s\n", in)
+		}
+	}
+	sb.WriteString("\nFor each example, give the input and a correct reference answer")
+	if len(spec.Difficulties) > 0 {
+		fmt.Fprintf(&sb, ", and its difficulty as one of: %s", strings.Join(spec.Difficulties, ", "))
+	}
+	sb.WriteString(".")
+	return sb.String()
+}
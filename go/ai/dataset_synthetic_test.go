@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestBatchDifficulties(t *testing.T) {
+	for _, test := range []struct {
+		have     []int
+		n, count int
+		want     []int
+	}{
+		{nil, 5, 3, nil},
+		{[]int{0, 0}, 5, 3, []int{2, 1}},
+		{[]int{0, 0, 0}, 6, 6, []int{2, 2, 2}},
+		{[]int{3, 0}, 6, 3, []int{0, 3}},
+		// Levels that already have more than their share get no more
+		// until the others catch up.
+		{[]int{4, 0}, 6, 4, []int{0, 4}},
+	} {
+		if got := batchDifficulties(test.have, test.n, test.count); !slices.Equal(got, test.want) {
+			t.Errorf("batchDifficulties(%v, %d, %d) = %v, want %v", test.have, test.n, test.count, got, test.want)
+		}
+	}
+}
+
+func TestGenerateSyntheticDataset(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prompts []string
+	model := defineJudgeModel(r, "writer", func(prompt string) any {
+		prompts = append(prompts, prompt)
+		call := len(prompts)
+		// Every batch repeats its first input with different case and
+		// spacing.
+		return map[string]any{"examples": []map[string]any{
+			{"input": fmt.Sprintf("Question %d?", call), "reference": "answer", "difficulty": "easy"},
+			{"input": fmt.Sprintf(" question  %d? ", call), "reference": "answer", "difficulty": "easy"},
+			{"input": fmt.Sprintf("Other question %d?", call), "reference": "answer", "difficulty": "Hard"},
+		}}
+	})
+
+	spec := DatasetSpec{
+		Domain:       "savings accounts",
+		Difficulties: []string{"easy", "hard"},
+		Constraints:  []string{"vary the tone"},
+		BatchSize:    3,
+	}
+	ds, err := GenerateSyntheticDataset(context.Background(), r, model, spec, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(ds), 5; got != want {
+		t.Fatalf("got %d examples, want %d", got, want)
+	}
+	seen := map[any]bool{}
+	for _, ex := range ds {
+		if seen[ex.Input] || ex.TestCaseId == "" || ex.Reference != "answer" {
+			t.Errorf("unexpected example %+v", ex)
+		}
+		seen[ex.Input] = true
+		want := 0.0
+		if strings.HasPrefix(ex.Input.(string), "Other") {
+			want = 1
+		}
+		if ex.Difficulty == nil || *ex.Difficulty != want {
+			t.Errorf("%q: got difficulty %v, want %v", ex.Input, ex.Difficulty, want)
+		}
+	}
+	if got, want := len(prompts), 3; got != want {
+		t.Errorf("got %d model calls, want %d", got, want)
+	}
+	for _, want := range []string{"Domain: savings accounts", "Difficulty: 2 easy, 1 hard", "- vary the tone", "one of: easy, hard"} {
+		if !strings.Contains(prompts[0], want) {
+			t.Errorf("first prompt %q does not contain %q", prompts[0], want)
+		}
+	}
+	if !strings.Contains(prompts[1], "Difficulty: 2 easy, 1 hard") || !strings.Contains(prompts[1], "- Question 1?") {
+		t.Errorf("second prompt %q does not ask for a mix of examples avoiding earlier inputs", prompts[1])
+	}
+
+	// A model that keeps repeating itself eventually fails.
+	repeater := defineJudgeModel(r, "repeater", func(string) any {
+		return map[string]any{"examples": []map[string]any{{"input": "same"}}}
+	})
+	if _, err := GenerateSyntheticDataset(context.Background(), r, repeater, spec, 2); err == nil {
+		t.Error("got nil, want error when the model produces too few unique examples")
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDatasetMutator(t *testing.T) {
+	m, err := NewDatasetMutator(Dataset{{TestCaseId: "a", Input: "qa"}, {TestCaseId: "b", Input: "qb"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(Example{TestCaseId: "a", Input: "again"}); err == nil {
+		t.Error("got nil, want error for duplicate test case")
+	}
+	if err := m.Add(Example{TestCaseId: "c"}); err == nil {
+		t.Error("got nil, want error for missing input")
+	}
+	if err := m.Add(Example{Input: "qc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Update("b", func(ex *Example) { ex.Output = "ob" }) {
+		t.Error("update of b failed")
+	}
+	if m.Update("b", func(ex *Example) { ex.TestCaseId = "a" }) {
+		t.Error("update renaming b to a succeeded, want failure")
+	}
+	if m.Update("missing", func(*Example) {}) {
+		t.Error("update of missing test case succeeded")
+	}
+	if !m.Remove("a") || m.Remove("a") {
+		t.Error("got unexpected result removing a")
+	}
+
+	ds := m.Build()
+	if got, want := len(ds), 2; got != want {
+		t.Fatalf("got %d examples, want %d", got, want)
+	}
+	if got, want := ds[0].Output, "ob"; got != want {
+		t.Errorf("got output %v, want %v", got, want)
+	}
+	if ds[1].TestCaseId == "" {
+		t.Error("added example was not assigned a TestCaseId")
+	}
+
+	ds[0].Output = "changed"
+	if got := m.Build()[0].Output; got != "ob" {
+		t.Errorf("Build result shares storage with the mutator: got output %v", got)
+	}
+}
+
+func TestExampleValidate(t *testing.T) {
+	if err := (&Example{Input: "q"}).Validate(); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	if err := (&Example{Input: "q", Weight: -1}).Validate(); err == nil {
+		t.Error("got nil, want error for negative weight")
+	}
+	difficulty := 1.5
+	if err := (&Example{Input: "q", Difficulty: &difficulty}).Validate(); err == nil {
+		t.Error("got nil, want error for difficulty above 1")
+	}
+}
+
+func TestMergeDatasets(t *testing.T) {
+	d := Dataset{{TestCaseId: "a", Input: "a1"}, {TestCaseId: "b", Input: "b1"}}
+	other := Dataset{{TestCaseId: "b", Input: "b2"}, {TestCaseId: "c", Input: "c2"}, {Input: "anonymous"}}
+
+	inputs := func(ds Dataset) []any {
+		var in []any
+		for _, ex := range ds {
+			in = append(in, ex.Input)
+		}
+		return in
+	}
+	for _, test := range []struct {
+		strategy MergeStrategy
+		want     []any
+	}{
+		{MergeKeepFirst, []any{"a1", "b1", "c2", "anonymous"}},
+		{MergeKeepLast, []any{"a1", "b2", "c2", "anonymous"}},
+	} {
+		t.Run(test.strategy.String(), func(t *testing.T) {
+			got, err := MergeDatasets(d, other, test.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(inputs(got), test.want) {
+				t.Errorf("got %v, want %v", inputs(got), test.want)
+			}
+		})
+	}
+	if got := d[1].Input; got != "b1" {
+		t.Errorf("MergeDatasets modified its input: got %v", got)
+	}
+
+	if _, err := MergeDatasets(d, other, MergeConflict); err == nil {
+		t.Error("got nil, want error for conflicting test case")
+	}
+	if _, err := MergeDatasets(d, other[1:], MergeConflict); err != nil {
+		t.Errorf("got %v, want nil without conflicts", err)
+	}
+}
+
+func TestKFoldDataset(t *testing.T) {
+	var ds Dataset
+	for i := range 10 {
+		ds = append(ds, Example{TestCaseId: fmt.Sprint(i)})
+	}
+	folds, err := KFoldDataset(ds, 3, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]int{}
+	for i, f := range folds {
+		if n := len(f.Test); n < 3 || n > 4 {
+			t.Errorf("fold %d: got %d test examples, want 3 or 4", i, n)
+		}
+		if got, want := len(f.Train)+len(f.Test), len(ds); got != want {
+			t.Errorf("fold %d: got %d examples, want %d", i, got, want)
+		}
+		for _, ex := range f.Test {
+			seen[ex.TestCaseId]++
+		}
+	}
+	for _, ex := range ds {
+		if seen[ex.TestCaseId] != 1 {
+			t.Errorf("example %s is in %d test folds, want 1", ex.TestCaseId, seen[ex.TestCaseId])
+		}
+	}
+
+	again, err := KFoldDataset(ds, 3, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(folds, again); diff != "" {
+		t.Errorf("split with the same seed differs (-first +second):\n%s", diff)
+	}
+
+	for _, k := range []int{1, 11} {
+		if _, err := KFoldDataset(ds, k, 42); err == nil {
+			t.Errorf("k=%d: got nil, want error", k)
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	ds := Dataset{
+		{TestCaseId: "a", Input: map[string]any{"difficulty": "easy"}},
+		{TestCaseId: "b", Input: map[string]any{"difficulty": "hard"}},
+		{TestCaseId: "c", Input: map[string]any{"difficulty": "easy"}},
+		{TestCaseId: "d", Input: map[string]any{"level": 2}},
+		{TestCaseId: "e", Input: "plain text"},
+	}
+	ids := func(groups map[string]Dataset) map[string][]string {
+		m := map[string][]string{}
+		for k, exs := range groups {
+			for _, ex := range exs {
+				m[k] = append(m[k], ex.TestCaseId)
+			}
+		}
+		return m
+	}
+
+	got := ids(GroupBy(ds, "difficulty", nil))
+	want := map[string][]string{"easy": {"a", "c"}, "hard": {"b"}, "": {"d", "e"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GroupBy field mismatch (-want +got):\n%s", diff)
+	}
+
+	got = ids(GroupBy(ds, "", func(ex Example) string {
+		return fmt.Sprint(slices.Index([]string{"a", "b", "c", "d", "e"}, ex.TestCaseId) % 2)
+	}))
+	want = map[string][]string{"0": {"a", "c", "e"}, "1": {"b", "d"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GroupBy extract mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadWriteDataset(t *testing.T) {
+	ds := Dataset{
+		{TestCaseId: "a", Input: "question", Output: "answer"},
+		{TestCaseId: "b", Input: map[string]any{"q": "x"}, Context: []any{"c"}},
+	}
+	for _, name := range []string{"set.json", "set.jsonl"} {
+		var buf bytes.Buffer
+		if err := WriteDataset(&buf, name, ds); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := strings.Count(buf.String(), "\n"), 2; name == "set.jsonl" && got != want {
+			t.Errorf("%s: got %d lines, want %d", name, got, want)
+		}
+		got, err := ReadDataset(&buf, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ds, got); diff != "" {
+			t.Errorf("%s: round trip mismatch (-want +got):\n%s", name, diff)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDataset(&buf, "empty.json", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadDataset(&buf, "empty.json"); err != nil || len(got) != 0 {
+		t.Errorf("got %v, %v, want an empty dataset", got, err)
+	}
+	if _, err := ReadDataset(strings.NewReader("{not json"), "bad.jsonl"); err == nil {
+		t.Error("got nil, want error for invalid JSONL")
+	}
+}
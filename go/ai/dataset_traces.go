@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/firebase/genkit/go/core/tracing"
+)
+
+// A TraceStore holds the traces recorded by a running Genkit application,
+// for example those exported to the Genkit telemetry server.
+type TraceStore interface {
+	// ListTraces returns the traces that started at or after since.
+	ListTraces(ctx context.Context, since time.Time) ([]*tracing.Data, error)
+}
+
+// FlowTraceOption configures [LoadDatasetFromFlowTraces].
+type FlowTraceOption func(opts *flowTraceOptions)
+
+type flowTraceOptions struct {
+	until         time.Time
+	includeFailed bool
+}
+
+// WithFlowTracesUntil only loads flow runs that started before t.
+func WithFlowTracesUntil(t time.Time) FlowTraceOption {
+	return func(opts *flowTraceOptions) {
+		opts.until = t
+	}
+}
+
+// WithFailedFlowTraces also loads flow runs that ended with an error. Their
+// examples have no Output.
+func WithFailedFlowTraces() FlowTraceOption {
+	return func(opts *flowTraceOptions) {
+		opts.includeFailed = true
+	}
+}
+
+// LoadDatasetFromFlowTraces builds a [Dataset] from the recorded runs of the
+// flow named flowName that started at or after since. Each flow span becomes
+// one [Example] whose Input and Output are the flow's input and output, and
+// whose TraceIds hold the ID of the trace it was found in. Its TestCaseId is
+// the trace ID and the span ID separated by a slash, so that runs recorded
+// in the same trace get different IDs.
+//
+// Only runs that succeeded are loaded unless [WithFailedFlowTraces] is given.
+// Examples are ordered by the start time of their flow span.
+func LoadDatasetFromFlowTraces(ctx context.Context, store TraceStore, flowName string, since time.Time, opts ...FlowTraceOption) (Dataset, error) {
+	var o flowTraceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	traces, err := store.ListTraces(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("ai.LoadDatasetFromFlowTraces: %w", err)
+	}
+
+	var spans []*tracing.SpanData
+	for _, td := range traces {
+		for _, span := range td.Spans {
+			if !isFlowSpan(span, flowName) {
+				continue
+			}
+			start := span.StartTime.Time()
+			if start.Before(since) || (!o.until.IsZero() && !start.Before(o.until)) {
+				continue
+			}
+			if span.Attributes["genkit:state"] != "success" && !o.includeFailed {
+				continue
+			}
+			if span.TraceID == "" {
+				span.TraceID = td.TraceID
+			}
+			spans = append(spans, span)
+		}
+	}
+	slices.SortStableFunc(spans, func(a, b *tracing.SpanData) int {
+		return cmp.Compare(a.StartTime, b.StartTime)
+	})
+
+	ds := make(Dataset, 0, len(spans))
+	for _, span := range spans {
+		ex := Example{
+			TestCaseId: flowTestCaseId(span),
+			Input:      spanValue(span.Attributes["genkit:input"]),
+			TraceIds:   []string{span.TraceID},
+		}
+		if span.Attributes["genkit:state"] == "success" {
+			ex.Output = spanValue(span.Attributes["genkit:output"])
+		}
+		ds = append(ds, ex)
+	}
+	return ds, nil
+}
+
+// flowTestCaseId returns the TestCaseId of the example loaded from span.
+func flowTestCaseId(span *tracing.SpanData) string {
+	if span.SpanID == "" {
+		return span.TraceID
+	}
+	return span.TraceID + "/" + span.SpanID
+}
+
+// isFlowSpan reports whether span records a run of the flow named flowName.
+func isFlowSpan(span *tracing.SpanData, flowName string) bool {
+	return span.Attributes["genkit:name"] == flowName &&
+		span.Attributes["genkit:metadata:subtype"] == "flow"
+}
+
+// spanValue decodes a JSON-encoded span input or output attribute. Values
+// that are not valid JSON are returned as is.
+func spanValue(attr any) any {
+	s, ok := attr.(string)
+	if !ok {
+		return attr
+	}
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/core/tracing"
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recorderTraceStore is a [TraceStore] backed by the spans of a
+// [tracetest.SpanRecorder].
+type recorderTraceStore struct {
+	rec *tracetest.SpanRecorder
+}
+
+func (s recorderTraceStore) ListTraces(ctx context.Context, since time.Time) ([]*tracing.Data, error) {
+	byID := map[string]*tracing.Data{}
+	var traces []*tracing.Data
+	for _, span := range s.rec.Ended() {
+		id := span.SpanContext().TraceID().String()
+		td := byID[id]
+		if td == nil {
+			td = &tracing.Data{TraceID: id, Spans: map[string]*tracing.SpanData{}}
+			byID[id] = td
+			traces = append(traces, td)
+		}
+		attrs := map[string]any{}
+		for _, kv := range span.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		td.Spans[span.SpanContext().SpanID().String()] = &tracing.SpanData{
+			SpanID:     span.SpanContext().SpanID().String(),
+			StartTime:  tracing.ToMilliseconds(span.StartTime()),
+			Attributes: attrs,
+		}
+	}
+	return traces, nil
+}
+
+func TestLoadDatasetFromFlowTraces(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	r.RegisterSpanProcessor(recorder)
+
+	upper := core.DefineFlow(r, "upper", func(ctx context.Context, in string) (string, error) {
+		if in == "" {
+			return "", errors.New("empty input")
+		}
+		return in + "!", nil
+	})
+	other := core.DefineFlow(r, "other", func(ctx context.Context, in string) (string, error) {
+		return in, nil
+	})
+
+	ctx := context.Background()
+	start := time.Now()
+	for _, in := range []string{"a", "", "b"} {
+		upper.Run(ctx, in)
+	}
+	other.Run(ctx, "c")
+	store := recorderTraceStore{recorder}
+
+	ds, err := LoadDatasetFromFlowTraces(ctx, store, "upper", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Example
+	for _, ex := range ds {
+		if len(ex.TraceIds) != 1 || ex.TraceIds[0] == "" || !strings.HasPrefix(ex.TestCaseId, ex.TraceIds[0]+"/") {
+			t.Errorf("example %+v: want a TestCaseId made of its trace ID and span ID", ex)
+		}
+		got = append(got, Example{Input: ex.Input, Output: ex.Output})
+	}
+	want := []Example{{Input: "a", Output: "a!"}, {Input: "b", Output: "b!"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	ds, err = LoadDatasetFromFlowTraces(ctx, store, "upper", start, WithFailedFlowTraces())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(ds), 3; got != want {
+		t.Fatalf("got %d examples including failed runs, want %d", got, want)
+	}
+	if ds[1].Input != "" || ds[1].Output != nil {
+		t.Errorf("failed run: got %+v, want empty input and no output", ds[1])
+	}
+
+	// Runs recorded in the same trace get different TestCaseIds.
+	tracing.RunInNewSpan(ctx, r.TracingState(), "batch", "test", false, "", func(ctx context.Context, _ string) (string, error) {
+		upper.Run(ctx, "d")
+		upper.Run(ctx, "e")
+		return "", nil
+	})
+	ds, err = LoadDatasetFromFlowTraces(ctx, store, "upper", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(ds), 4; got != want {
+		t.Fatalf("got %d examples, want %d", got, want)
+	}
+	if ds[2].TraceIds[0] != ds[3].TraceIds[0] || ds[2].TestCaseId == ds[3].TestCaseId {
+		t.Errorf("got examples %+v and %+v, want the same trace and different TestCaseIds", ds[2], ds[3])
+	}
+
+	ds, err = LoadDatasetFromFlowTraces(ctx, store, "upper", start, WithFlowTracesUntil(start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 0 {
+		t.Errorf("got %d examples before %v, want none", len(ds), start)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// vertexAIRecord is one line of a Vertex AI evaluation dataset in JSONL
+// format. Vertex AI also accepts "prompt" and "response" for the input and
+// output columns.
+type vertexAIRecord struct {
+	Id         string          `json:"id,omitempty"`
+	InputText  string          `json:"input_text,omitempty"`
+	Prompt     string          `json:"prompt,omitempty"`
+	OutputText string          `json:"output_text,omitempty"`
+	Response   string          `json:"response,omitempty"`
+	Reference  string          `json:"reference,omitempty"`
+	Context    json.RawMessage `json:"context,omitempty"`
+}
+
+// FromVertexAIDataset reads a Vertex AI evaluation dataset in JSONL format,
+// with one record per line, and converts it to a [Dataset].
+//
+// The "input_text" (or "prompt"), "output_text" (or "response"), "reference"
+// and "id" columns map to the Input, Output, Reference and TestCaseId of each
+// [Example]. The "context" column may be a string or a list of strings.
+func FromVertexAIDataset(r io.Reader) (Dataset, error) {
+	dec := json.NewDecoder(r)
+	var ds Dataset
+	for n := 1; ; n++ {
+		var rec vertexAIRecord
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return ds, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ai.FromVertexAIDataset: record %d: %w", n, err)
+		}
+		ex := Example{
+			TestCaseId: rec.Id,
+			Input:      firstNonEmpty(rec.InputText, rec.Prompt),
+		}
+		if out := firstNonEmpty(rec.OutputText, rec.Response); out != "" {
+			ex.Output = out
+		}
+		if rec.Reference != "" {
+			ex.Reference = rec.Reference
+		}
+		if len(rec.Context) > 0 && string(rec.Context) != "null" {
+			var one string
+			var many []string
+			if err := json.Unmarshal(rec.Context, &one); err == nil {
+				ex.Context = []any{one}
+			} else if err := json.Unmarshal(rec.Context, &many); err == nil {
+				for _, c := range many {
+					ex.Context = append(ex.Context, c)
+				}
+			} else {
+				return nil, fmt.Errorf("ai.FromVertexAIDataset: record %d: context must be a string or a list of strings", n)
+			}
+		}
+		ds = append(ds, ex)
+	}
+}
+
+// ToVertexAIDataset writes ds to w as a Vertex AI evaluation dataset in JSONL
+// format, using the "input_text", "output_text", "reference", "context" and
+// "id" columns. Values that are not strings are encoded as JSON text. A
+// single context is written as a string and several as a list of strings.
+func ToVertexAIDataset(ds Dataset, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for i, ex := range ds {
+		rec := vertexAIRecord{Id: ex.TestCaseId}
+		var err error
+		if rec.InputText, err = vertexAIText(ex.Input); err != nil {
+			return fmt.Errorf("ai.ToVertexAIDataset: example %d input: %w", i, err)
+		}
+		if rec.OutputText, err = vertexAIText(ex.Output); err != nil {
+			return fmt.Errorf("ai.ToVertexAIDataset: example %d output: %w", i, err)
+		}
+		if rec.Reference, err = vertexAIText(ex.Reference); err != nil {
+			return fmt.Errorf("ai.ToVertexAIDataset: example %d reference: %w", i, err)
+		}
+		if len(ex.Context) > 0 {
+			contexts := make([]string, len(ex.Context))
+			for j, c := range ex.Context {
+				if contexts[j], err = vertexAIText(c); err != nil {
+					return fmt.Errorf("ai.ToVertexAIDataset: example %d context: %w", i, err)
+				}
+			}
+			var v any = contexts
+			if len(contexts) == 1 {
+				v = contexts[0]
+			}
+			if rec.Context, err = json.Marshal(v); err != nil {
+				return fmt.Errorf("ai.ToVertexAIDataset: example %d context: %w", i, err)
+			}
+		}
+		if err := enc.Encode(&rec); err != nil {
+			return fmt.Errorf("ai.ToVertexAIDataset: %w", err)
+		}
+	}
+	return nil
+}
+
+// vertexAIText returns v as a Vertex AI text column.
+func vertexAIText(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	return exampleText(v)
+}
+
+// firstNonEmpty returns the first of ss that is not empty.
+func firstNonEmpty(ss ...string) string {
+	for _, s := range ss {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
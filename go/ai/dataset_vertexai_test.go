@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const vertexAISample = `{"id": "1", "input_text": "What is the capital of France?", "output_text": "Paris", "reference": "Paris", "context": "France is a country in Europe."}
+{"id": "2", "input_text": "Summarize the text.", "output_text": "A short summary.", "context": ["First passage.", "Second passage."]}
+
+{"prompt": "Say hi", "response": "hi"}
+`
+
+func TestVertexAIDatasetRoundTrip(t *testing.T) {
+	ds, err := FromVertexAIDataset(strings.NewReader(vertexAISample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Dataset{
+		{TestCaseId: "1", Input: "What is the capital of France?", Output: "Paris", Reference: "Paris", Context: []any{"France is a country in Europe."}},
+		{TestCaseId: "2", Input: "Summarize the text.", Output: "A short summary.", Context: []any{"First passage.", "Second passage."}},
+		{Input: "Say hi", Output: "hi"},
+	}
+	if !reflect.DeepEqual(ds, want) {
+		t.Fatalf("got %#v, want %#v", ds, want)
+	}
+
+	var buf bytes.Buffer
+	if err := ToVertexAIDataset(ds, &buf); err != nil {
+		t.Fatal(err)
+	}
+	again, err := FromVertexAIDataset(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, ds) {
+		t.Errorf("round trip changed the dataset: got %#v, want %#v", again, ds)
+	}
+}
+
+func TestToVertexAIDatasetEncodesValues(t *testing.T) {
+	var buf bytes.Buffer
+	ds := Dataset{{Input: map[string]any{"q": 1}, Output: 42}}
+	if err := ToVertexAIDataset(ds, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rec["input_text"], `{"q":1}`; got != want {
+		t.Errorf("got input_text %v, want %v", got, want)
+	}
+	if got, want := rec["output_text"], "42"; got != want {
+		t.Errorf("got output_text %v, want %v", got, want)
+	}
+}
+
+func TestFromVertexAIDatasetInvalidContext(t *testing.T) {
+	if _, err := FromVertexAIDataset(strings.NewReader(`{"input_text": "q", "context": 3}`)); err == nil {
+		t.Error("got nil, want error for numeric context")
+	}
+}
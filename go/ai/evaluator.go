@@ -18,15 +18,22 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/firebase/genkit/go/core"
 	"github.com/firebase/genkit/go/core/logger"
 	"github.com/firebase/genkit/go/core/tracing"
 	"github.com/firebase/genkit/go/internal/atype"
+	"github.com/firebase/genkit/go/internal/base"
 	"github.com/firebase/genkit/go/internal/registry"
 	"github.com/google/uuid"
+	"github.com/invopop/jsonschema"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -52,6 +59,43 @@ type Example struct {
 	Context    []any    `json:"context,omitempty"`
 	Reference  any      `json:"reference,omitempty"`
 	TraceIds   []string `json:"traceIds,omitempty"`
+	// Weight is the relative importance of this example when computing
+	// weighted aggregates. Zero means the default weight of 1.
+	Weight float64 `json:"weight,omitempty"`
+	// ExpectedToolCalls are the tool calls a model is expected to make for
+	// this example. See [DefineToolCallAccuracyEvaluator].
+	ExpectedToolCalls []*ToolRequest `json:"expectedToolCalls,omitempty"`
+	// Language is the language of the example as a BCP 47 code such as
+	// "en" or "pt-BR". See [DefineMultilingualEvaluator].
+	Language string `json:"language,omitempty"`
+	// Difficulty is how hard the example is to answer correctly, from 0
+	// for the easiest to 1 for the hardest, as estimated by
+	// [EstimateExampleDifficulty]. It is nil if the difficulty is unknown.
+	Difficulty *float64 `json:"difficulty,omitempty"`
+}
+
+// EffectiveWeight returns the weight of the example, defaulting to 1 when
+// Weight is unset.
+func (e *Example) EffectiveWeight() float64 {
+	if e.Weight == 0 {
+		return 1
+	}
+	return e.Weight
+}
+
+// Validate reports whether e is well formed: it must have an Input, a
+// non-negative Weight and, if it has one, a Difficulty between 0 and 1.
+func (e *Example) Validate() error {
+	if e.Input == nil {
+		return fmt.Errorf("example %q: input is required", e.TestCaseId)
+	}
+	if e.Weight < 0 {
+		return fmt.Errorf("example %q: weight %v is negative", e.TestCaseId, e.Weight)
+	}
+	if d := e.Difficulty; d != nil && (*d < 0 || *d > 1) {
+		return fmt.Errorf("example %q: difficulty %v is not between 0 and 1", e.TestCaseId, *d)
+	}
+	return nil
 }
 
 // Dataset is a collection of [Example]
@@ -62,7 +106,71 @@ type Dataset = []Example
 type EvaluatorRequest struct {
 	Dataset      *Dataset `json:"dataset"`
 	EvaluationId string   `json:"evalRunId"`
-	Options      any      `json:"options,omitempty"`
+	// RunId groups several evaluations (for example, of different models)
+	// under a single run.
+	RunId   string `json:"runId,omitempty"`
+	Options any    `json:"options,omitempty"`
+	// DryRun requests a validation-only run. Evaluators defined with
+	// [DefineEvaluator] validate the request and return an empty result for
+	// each example without calling the evaluator function. Batch evaluators
+	// receive the flag and decide for themselves.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Observer, if set, receives events as the evaluation progresses.
+	Observer EvalObserver `json:"-"`
+	// DedupStrategy controls how evaluators defined with [DefineEvaluator]
+	// treat examples that share a TestCaseId.
+	DedupStrategy DedupStrategy `json:"dedupStrategy,omitempty"`
+	// StopAfterFailures, if positive, makes evaluators defined with
+	// [DefineEvaluator] stop once this many examples have failed.
+	// [Evaluator.Evaluate] then returns the results so far together with
+	// [ErrStoppedEarly].
+	StopAfterFailures int `json:"stopAfterFailures,omitempty"`
+	// ExcludeTestCaseIds lists examples that evaluators defined with
+	// [DefineEvaluator] skip.
+	ExcludeTestCaseIds []string `json:"excludeTestCaseIds,omitempty"`
+	// RequireAllExamples makes evaluators defined with [DefineEvaluator]
+	// stop at the first example that fails with an error, rather than
+	// recording the failure and continuing. [Evaluator.Evaluate] then
+	// returns a [RequiredExampleError] holding the results so far.
+	RequireAllExamples bool `json:"requireAllExamples,omitempty"`
+	// Annotations holds metadata about the run, such as the hardware or
+	// model endpoint used, that applies to every evaluator. Evaluators
+	// defined with [DefineEvaluator] or [DefineBatchEvaluator] record them
+	// on their trace span and in [EvaluatorRunSummary.Annotations].
+	Annotations map[string]any `json:"annotations,omitempty"`
+
+	// sampler and sampleSize are set by [WithEvaluateDatasetSampler].
+	sampler    DatasetSampler
+	sampleSize int
+	// jobStore is set by [WithEvaluateJobStore].
+	jobStore EvalJobStore
+}
+
+// DedupStrategy is an enum that selects which of several examples with the
+// same TestCaseId are evaluated. Examples without a TestCaseId are never
+// considered duplicates.
+type DedupStrategy int
+
+const (
+	// DedupStrategyNone evaluates every example.
+	DedupStrategyNone DedupStrategy = iota
+	// DedupStrategyKeepFirst evaluates the first example with each TestCaseId.
+	DedupStrategyKeepFirst
+	// DedupStrategyKeepLast evaluates the last example with each TestCaseId.
+	DedupStrategyKeepLast
+)
+
+var dedupStrategyName = map[DedupStrategy]string{
+	DedupStrategyNone:      "none",
+	DedupStrategyKeepFirst: "keepFirst",
+	DedupStrategyKeepLast:  "keepLast",
+}
+
+func (d DedupStrategy) String() string {
+	if n, ok := dedupStrategyName[d]; ok {
+		return n
+	}
+	return "unknown"
 }
 
 // ScoreStatus is an enum used to indicate if a Score has passed or failed. This
@@ -95,6 +203,14 @@ type Score struct {
 	Status  string         `json:"status,omitempty" jsonschema:"enum=unknown,enum=fail,enum=pass"`
 	Error   string         `json:"error,omitempty"`
 	Details map[string]any `json:"details,omitempty"`
+	// Confidence is the evaluator's certainty in Score, in [0, 1], as
+	// reported for example by an LLM judge. It is nil if no confidence was
+	// reported.
+	Confidence *float64 `json:"confidence,omitempty"`
+	// ConfidenceInterval is the 95% confidence interval around a numeric
+	// Score, when it was estimated from repeated evaluations. See
+	// [RunWithRepetitions].
+	ConfidenceInterval *[2]float64 `json:"confidenceInterval,omitempty"`
 }
 
 // EvaluationResult is the result of running the evaluator on a single Example.
@@ -105,16 +221,242 @@ type EvaluationResult struct {
 	TraceID    string  `json:"traceId,omitempty"`
 	SpanID     string  `json:"spanId,omitempty"`
 	Evaluation []Score `json:"evaluation"`
+	// PartialCredit is the mean of the numeric scores in Evaluation, clamped
+	// to [0, 1]. It is set by evaluators defined with [DefineEvaluator].
+	PartialCredit float64 `json:"partialCredit,omitempty"`
+	// PartialCreditStatus is the overall status of the result, derived from
+	// PartialCredit by the [EvaluatorOptions.PartialCreditThresholds] of the
+	// evaluator. It is empty if the evaluator has no thresholds.
+	PartialCreditStatus string `json:"partialCreditStatus,omitempty"`
+	// HumanAnnotation holds reviews added by people after the automated
+	// evaluation. See [AnnotateResult].
+	HumanAnnotation []HumanScore `json:"humanAnnotation,omitempty"`
+	// EvaluatorVersion is the [EvaluatorOptions.ActionVersion] of the
+	// evaluator that produced the result, if any.
+	EvaluatorVersion string `json:"evaluatorVersion,omitempty"`
+
+	// err is the error returned by the evaluator callback, if any.
+	err error
+}
+
+// EvaluatorError is the error returned by [Evaluator.Evaluate] when the
+// evaluator fails on an example. Cause is the error returned by the
+// evaluator callback.
+type EvaluatorError struct {
+	EvaluatorName string
+	TestCaseId    string
+	Cause         error
+}
+
+func (e EvaluatorError) Error() string {
+	return fmt.Sprintf("evaluator %q failed on test case %q: %v", e.EvaluatorName, e.TestCaseId, e.Cause)
+}
+
+func (e EvaluatorError) Unwrap() error { return e.Cause }
+
+// MultiEvaluatorError is the error returned by [Evaluator.Evaluate] when
+// the evaluator fails on one or more examples. It holds an
+// [EvaluatorError] for each failed example, in dataset order.
+type MultiEvaluatorError struct {
+	Errors []EvaluatorError
+}
+
+func (e *MultiEvaluatorError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d examples failed; first error: %v", len(e.Errors), e.Errors[0])
+}
+
+// Unwrap returns the errors of the failed examples, so that [errors.As]
+// finds each [EvaluatorError].
+func (e *MultiEvaluatorError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
 }
 
 // EvaluatorResponse is a collection of [EvaluationResult] structs, it
 // represents the result on the entire input dataset.
 type EvaluatorResponse = []EvaluationResult
 
+// EvaluatorRunResponse is the result of [EvaluateRun]. It wraps the
+// [EvaluatorResponse] with information about the run itself.
+type EvaluatorRunResponse struct {
+	// IsDryRun reports whether the results come from a dry run, in which
+	// case they should not be persisted or acted upon.
+	IsDryRun bool                `json:"isDryRun,omitempty"`
+	Results  EvaluatorResponse   `json:"results"`
+	Summary  EvaluatorRunSummary `json:"summary"`
+}
+
+// EvaluatorRunSummary describes how an evaluator processed its dataset. It is
+// reported by evaluators defined with [DefineEvaluator].
+type EvaluatorRunSummary struct {
+	// DuplicatesRemoved is the number of examples skipped because of the
+	// request's DedupStrategy.
+	DuplicatesRemoved int `json:"duplicatesRemoved,omitempty"`
+	// StoppedEarly reports whether the evaluation stopped before the end of
+	// the dataset because of the request's StopAfterFailures.
+	StoppedEarly bool `json:"stoppedEarly,omitempty"`
+	// ExcludedExamples is the number of examples skipped because they were
+	// listed in the request's ExcludeTestCaseIds.
+	ExcludedExamples int `json:"excludedExamples,omitempty"`
+	// SkippedExamples is the number of examples not evaluated because of
+	// [EvaluatorOptions.SkipIf].
+	SkippedExamples int `json:"skippedExamples,omitempty"`
+	// Annotations are the request's [EvaluatorRequest.Annotations].
+	Annotations map[string]any `json:"annotations,omitempty"`
+}
+
+// RequiredExampleError is the error returned by [Evaluator.Evaluate] when an
+// example fails and [EvaluatorRequest.RequireAllExamples] is set. Cause is
+// the error returned by the evaluator callback.
+type RequiredExampleError struct {
+	EvaluatorName string
+	TestCaseId    string
+	Cause         error
+	// Partial holds the results of the examples evaluated before the
+	// failure.
+	Partial EvaluatorResponse
+}
+
+func (e *RequiredExampleError) Error() string {
+	return fmt.Sprintf("evaluator %q failed on required test case %q: %v", e.EvaluatorName, e.TestCaseId, e.Cause)
+}
+
+func (e *RequiredExampleError) Unwrap() error { return e.Cause }
+
+// ErrStoppedEarly is returned, along with the partial response, when an
+// evaluation stops because of [EvaluatorRequest.StopAfterFailures].
+var ErrStoppedEarly = errors.New("evaluation stopped after reaching the failure limit")
+
 type EvaluatorOptions struct {
 	DisplayName string `json:"displayName"`
 	Definition  string `json:"definition"`
 	IsBilled    bool   `json:"isBilled,omitempty"`
+	// MaxDatasetSize, if positive, is the largest dataset the evaluator
+	// accepts. Larger datasets are rejected before any example is evaluated.
+	MaxDatasetSize int `json:"maxDatasetSize,omitempty"`
+	// RequiredFields names the [Example] fields that must be set for
+	// evaluators defined with [DefineEvaluator]: any of "Input", "Output",
+	// "Context", "Reference" and "TraceIds". Examples missing one of them
+	// fail without calling the evaluator function.
+	RequiredFields []string `json:"requiredFields,omitempty"`
+	// UseChainOfThought makes LLM-judge evaluators, such as those defined
+	// with [DefineMultilingualEvaluator] and [DefineRubricEvaluator], ask the
+	// judge to reason step by step before scoring. The judge's reasoning is
+	// reported in the "rationale" key of [Score.Details].
+	UseChainOfThought bool `json:"useChainOfThought,omitempty"`
+	// SpanNameFormatter, if set, names the trace span of each example
+	// evaluated by evaluators defined with [DefineEvaluator]. By default
+	// spans are named "TestCase <TestCaseId>".
+	SpanNameFormatter func(example Example) string `json:"-"`
+	// SkipIf, if set, is called with each example before it is evaluated
+	// by evaluators defined with [DefineEvaluator]. Examples for which it
+	// returns true are not evaluated; their result has a single score with
+	// unknown status and "skipped" in the "reason" key of [Score.Details].
+	SkipIf func(example Example) bool `json:"-"`
+	// DatasetSchema, if set, is a JSON Schema that each [Example] must
+	// match, in its JSON form, to be evaluated by evaluators defined with
+	// [DefineEvaluator]. Examples that do not match fail without being
+	// evaluated, and every violation is reported in the "schemaViolations"
+	// key of [Score.Details].
+	DatasetSchema []byte `json:"datasetSchema,omitempty"`
+	// PartialCreditThresholds, if set, make evaluators defined with
+	// [DefineEvaluator] set the overall status of each result from its
+	// partial credit. See [EvaluatorOptions.SetPartialCreditThresholds].
+	PartialCreditThresholds *PartialCreditThresholds `json:"partialCreditThresholds,omitempty"`
+	// ActionVersion, if set, is the version of the evaluator. It is
+	// reported in the "evaluatorVersion" key of the action metadata and in
+	// [EvaluationResult.EvaluatorVersion], and lets several versions of an
+	// evaluator be defined with the same provider and name. The first one
+	// defined is registered under that name and later ones under
+	// "name@version"; use [LookupEvaluatorByVersion] to look them up.
+	ActionVersion string `json:"actionVersion,omitempty"`
+	// InputTransformer and OutputTransformer, if set, make evaluators
+	// defined with [DefineEvaluator] replace the Input and Output of each
+	// example with the result of the transformer before passing it to the
+	// callback, for example to decode base64 or to parse a JSON string.
+	// They run after the example is checked against DatasetSchema and
+	// RequiredFields. If a transformer fails, the example fails with its
+	// error.
+	InputTransformer  func(ctx context.Context, input any) (any, error)  `json:"-"`
+	OutputTransformer func(ctx context.Context, output any) (any, error) `json:"-"`
+}
+
+// EvaluatorOption configures an [EvaluatorOptions] built by
+// [NewEvaluatorOptions].
+type EvaluatorOption func(opts *EvaluatorOptions)
+
+// NewEvaluatorOptions returns an [EvaluatorOptions] with opts applied.
+func NewEvaluatorOptions(opts ...EvaluatorOption) *EvaluatorOptions {
+	o := &EvaluatorOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDisplayName sets the display name of the evaluator.
+func WithDisplayName(name string) EvaluatorOption {
+	return func(opts *EvaluatorOptions) {
+		opts.DisplayName = name
+	}
+}
+
+// WithDefinition sets the definition of the evaluator.
+func WithDefinition(definition string) EvaluatorOption {
+	return func(opts *EvaluatorOptions) {
+		opts.Definition = definition
+	}
+}
+
+// WithIsBilled marks whether running the evaluator incurs costs.
+func WithIsBilled(billed bool) EvaluatorOption {
+	return func(opts *EvaluatorOptions) {
+		opts.IsBilled = billed
+	}
+}
+
+// WithMaxDatasetSize sets the largest dataset the evaluator accepts.
+func WithMaxDatasetSize(n int) EvaluatorOption {
+	return func(opts *EvaluatorOptions) {
+		opts.MaxDatasetSize = n
+	}
+}
+
+// WithRequiredFields sets the [Example] fields the evaluator requires.
+func WithRequiredFields(fields ...string) EvaluatorOption {
+	return func(opts *EvaluatorOptions) {
+		opts.RequiredFields = fields
+	}
+}
+
+// WithChainOfThought sets whether LLM-judge evaluators reason step by step
+// before scoring.
+func WithChainOfThought(enabled bool) EvaluatorOption {
+	return func(opts *EvaluatorOptions) {
+		opts.UseChainOfThought = enabled
+	}
+}
+
+// WithSpanNameFormatter sets the function naming the trace span of each
+// example.
+func WithSpanNameFormatter(format func(example Example) string) EvaluatorOption {
+	return func(opts *EvaluatorOptions) {
+		opts.SpanNameFormatter = format
+	}
+}
+
+// WithSkipIf sets the predicate selecting the examples that are not
+// evaluated.
+func WithSkipIf(skip func(example Example) bool) EvaluatorOption {
+	return func(opts *EvaluatorOptions) {
+		opts.SkipIf = skip
+	}
 }
 
 // EvaluatorCallbackRequest is the data we pass to the callback function
@@ -123,6 +465,9 @@ type EvaluatorOptions struct {
 type EvaluatorCallbackRequest struct {
 	Input   Example `json:"input"`
 	Options any     `json:"options,omitempty"`
+	// Metrics records custom metrics using the meter of the registry's
+	// tracing state.
+	Metrics MetricsEmitter `json:"-"`
 }
 
 // EvaluatorCallbackResponse is the result on evaluating a single [Example]
@@ -132,42 +477,175 @@ type EvaluatorCallbackResponse = EvaluationResult
 // returns a [Evaluator] that runs it. This method process the input dataset
 // one-by-one.
 func DefineEvaluator(r *registry.Registry, provider, name string, options *EvaluatorOptions, eval func(context.Context, *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error)) (Evaluator, error) {
+	return defineEvaluator(r, provider, name, options, nil, eval)
+}
+
+// DefineEvaluatorTyped is like [DefineEvaluator] for evaluators whose
+// request options have the concrete type O. The options are decoded into an
+// O, which is passed to eval and set as the Options of its request. The
+// evaluator's input schema describes O, so that the Dev UI can render a form
+// for the options. Examples whose options cannot be decoded fail.
+func DefineEvaluatorTyped[O any](r *registry.Registry, provider, name string, options *EvaluatorOptions, eval func(context.Context, *EvaluatorCallbackRequest, O) (*EvaluatorCallbackResponse, error)) (Evaluator, error) {
+	return defineEvaluator(r, provider, name, options, evaluatorInputSchema[O](), func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		var opts O
+		if err := decodeEvaluatorOptions(req.Options, &opts); err != nil {
+			return nil, err
+		}
+		req.Options = opts
+		return eval(ctx, req, opts)
+	})
+}
+
+// evaluatorInputSchema returns the schema of an [EvaluatorRequest] whose
+// Options have type O.
+func evaluatorInputSchema[O any]() *jsonschema.Schema {
+	s := base.InferJSONSchema(&EvaluatorRequest{})
+	opts := base.InferJSONSchema(new(O))
+	opts.ID = "" // Only the root schema has an id.
+	s.Properties.Set("options", opts)
+	return s
+}
+
+// defineEvaluator implements [DefineEvaluator]. If inputSchema is nil, the
+// action's input schema is inferred from [EvaluatorRequest].
+func defineEvaluator(r *registry.Registry, provider, name string, options *EvaluatorOptions, inputSchema *jsonschema.Schema, eval func(context.Context, *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error)) (Evaluator, error) {
 	if options == nil {
 		return nil, errors.New("EvaluatorOptions must be provided")
 	}
+	for _, field := range options.RequiredFields {
+		if _, ok := exampleFieldIsSet[field]; !ok {
+			return nil, fmt.Errorf("ai.DefineEvaluator: unknown required field %q", field)
+		}
+	}
+	if err := options.PartialCreditThresholds.validate(); err != nil {
+		return nil, fmt.Errorf("ai.DefineEvaluator: %w", err)
+	}
+	if options.DatasetSchema != nil {
+		if _, err := base.SchemaViolations(json.RawMessage("{}"), options.DatasetSchema); err != nil {
+			return nil, fmt.Errorf("ai.DefineEvaluator: invalid dataset schema: %w", err)
+		}
+	}
 	// TODO(ssbushi): Set this on `evaluator` key on action metadata
 	metadataMap := map[string]any{}
 	metadataMap["evaluatorIsBilled"] = options.IsBilled
 	metadataMap["evaluatorDisplayName"] = options.DisplayName
 	metadataMap["evaluatorDefinition"] = options.Definition
+	if options.ActionVersion != "" {
+		metadataMap["evaluatorVersion"] = options.ActionVersion
+	}
+	actionName, err := versionedEvaluatorName(r, provider, name, options.ActionVersion)
+	if err != nil {
+		return nil, fmt.Errorf("ai.DefineEvaluator: %w", err)
+	}
 
-	actionDef := (*evaluatorActionDef)(core.DefineAction(r, provider, name, atype.Evaluator, metadataMap, func(ctx context.Context, req *EvaluatorRequest) (output *EvaluatorResponse, err error) {
+	var actionDef *evaluatorActionDef
+	actionDef = (*evaluatorActionDef)(core.DefineTypedActionWithInputSchema(r, provider, actionName, atype.Evaluator, metadataMap, inputSchema, func(ctx context.Context, req *EvaluatorRequest) (output *EvaluatorResponse, err error) {
+		setEvaluationSpanAttrs(ctx, req)
+		setAnnotationSpanAttrs(ctx, req)
+		if err := checkDatasetSize(options, req.Dataset); err != nil {
+			return nil, err
+		}
 		var evalResponses []EvaluationResult
+		summary := EvaluatorRunSummary{Annotations: req.Annotations}
+		failures := 0
 		dataset := *req.Dataset
+		evaluatorName := actionDef.Name()
+		if len(req.ExcludeTestCaseIds) > 0 {
+			dataset, summary.ExcludedExamples = excludeExamples(dataset, req.ExcludeTestCaseIds)
+			if summary.ExcludedExamples > 0 {
+				logger.FromContext(ctx).Info("excluded examples", "evaluator", evaluatorName, "excluded", summary.ExcludedExamples)
+			}
+		}
+		if req.DedupStrategy != DedupStrategyNone {
+			dataset, summary.DuplicatesRemoved = dedupDataset(dataset, req.DedupStrategy)
+			if summary.DuplicatesRemoved > 0 {
+				logger.FromContext(ctx).Info("removed duplicate examples", "evaluator", evaluatorName, "strategy", req.DedupStrategy, "removed", summary.DuplicatesRemoved)
+			}
+		}
+		notifyObserver(ctx, req, EvaluationStarted{Evaluator: evaluatorName, EvaluationId: req.EvaluationId, DatasetSize: len(dataset)})
+		defer func() {
+			failed := 0
+			for _, res := range evalResponses {
+				if res.err != nil {
+					failed++
+				}
+			}
+			notifyObserver(ctx, req, EvaluationCompleted{Evaluator: evaluatorName, EvaluationId: req.EvaluationId, Results: len(evalResponses), Failed: failed, Summary: summary})
+		}()
 		for i := 0; i < len(dataset); i++ {
+			if ctx.Err() != nil {
+				// Stop evaluating but return the results gathered so far.
+				break
+			}
 			datapoint := dataset[i]
 			if datapoint.TestCaseId == "" {
 				datapoint.TestCaseId = uuid.New().String()
 			}
-			_, err := tracing.RunInNewSpan(ctx, r.TracingState(), fmt.Sprintf("TestCase %s", datapoint.TestCaseId), "evaluator", false, datapoint,
+			if req.DryRun {
+				evalResponses = append(evalResponses, EvaluationResult{TestCaseId: datapoint.TestCaseId, Evaluation: []Score{}})
+				continue
+			}
+			spanName := fmt.Sprintf("TestCase %s", datapoint.TestCaseId)
+			if options.SpanNameFormatter != nil {
+				spanName = options.SpanNameFormatter(datapoint)
+			}
+			_, err := tracing.RunInNewSpan(ctx, r.TracingState(), spanName, "evaluator", false, datapoint,
 				func(ctx context.Context, input Example) (*EvaluatorCallbackResponse, error) {
+					setEvaluationSpanAttrs(ctx, req)
 					traceId := trace.SpanContextFromContext(ctx).TraceID().String()
 					spanId := trace.SpanContextFromContext(ctx).SpanID().String()
 					callbackRequest := EvaluatorCallbackRequest{
 						Input:   input,
 						Options: req.Options,
+						Metrics: newMetricsEmitter(ctx, r.TracingState().Meter(), evaluatorName),
+					}
+					notifyObserver(ctx, req, ExampleStarted{Evaluator: evaluatorName, TestCaseId: input.TestCaseId})
+					if options.SkipIf != nil && options.SkipIf(input) {
+						trace.SpanFromContext(ctx).AddEvent("skipped", trace.WithAttributes(attribute.String("reason", "SkipIf returned true")))
+						skipped := EvaluationResult{
+							TestCaseId: input.TestCaseId,
+							Evaluation: []Score{{
+								Status:  ScoreStatusUnknown.String(),
+								Details: map[string]any{"reason": "skipped"},
+							}},
+							TraceID: traceId,
+							SpanID:  spanId,
+						}
+						summary.SkippedExamples++
+						evalResponses = append(evalResponses, skipped)
+						notifyObserver(ctx, req, ExampleCompleted{Evaluator: evaluatorName, Result: skipped})
+						return &skipped, nil
+					}
+					var evaluatorResponse *EvaluatorCallbackResponse
+					var failureDetails map[string]any
+					violations, err := datasetSchemaViolations(input, options.DatasetSchema)
+					if len(violations) > 0 {
+						err = fmt.Errorf("example does not match dataset schema: %s", strings.Join(violations, "; "))
+						failureDetails = map[string]any{"schemaViolations": violations}
+					}
+					if err == nil {
+						if field := missingField(&input, options.RequiredFields); field != "" {
+							err = fmt.Errorf("missing required field: %s", field)
+						} else {
+							callbackRequest.Input, err = transformExample(ctx, input, options)
+							if err == nil {
+								evaluatorResponse, err = eval(ctx, &callbackRequest)
+							}
+						}
 					}
-					evaluatorResponse, err := eval(ctx, &callbackRequest)
 					if err != nil {
+						notifyObserver(ctx, req, ExampleFailed{Evaluator: evaluatorName, TestCaseId: input.TestCaseId, Err: err})
 						failedScore := Score{
-							Status: ScoreStatusFail.String(),
-							Error:  fmt.Sprintf("Evaluation of test case %s failed: \n %s", input.TestCaseId, err.Error()),
+							Status:  ScoreStatusFail.String(),
+							Error:   fmt.Sprintf("Evaluation of test case %s failed: \n %s", input.TestCaseId, err.Error()),
+							Details: failureDetails,
 						}
 						failedEvalResult := EvaluationResult{
 							TestCaseId: input.TestCaseId,
 							Evaluation: []Score{failedScore},
 							TraceID:    traceId,
 							SpanID:     spanId,
+							err:        err,
 						}
 						evalResponses = append(evalResponses, failedEvalResult)
 						// return error to mark span as failed
@@ -175,16 +653,34 @@ func DefineEvaluator(r *registry.Registry, provider, name string, options *Evalu
 					}
 					evaluatorResponse.TraceID = traceId
 					evaluatorResponse.SpanID = spanId
+					setPartialCredit(evaluatorResponse, options.PartialCreditThresholds)
 					evalResponses = append(evalResponses, *evaluatorResponse)
+					notifyObserver(ctx, req, ExampleCompleted{Evaluator: evaluatorName, Result: *evaluatorResponse})
 					return evaluatorResponse, nil
 				})
 			if err != nil {
 				logger.FromContext(ctx).Debug("EvaluatorAction", "err", err)
-				continue
+			}
+			if last := evalResponses[len(evalResponses)-1]; req.RequireAllExamples && last.err != nil {
+				return nil, &RequiredExampleError{
+					EvaluatorName: evaluatorName,
+					TestCaseId:    last.TestCaseId,
+					Cause:         last.err,
+					Partial:       evalResponses[:len(evalResponses)-1],
+				}
+			}
+			if req.StopAfterFailures > 0 && resultStatus(evalResponses[len(evalResponses)-1]) == ScoreStatusFail {
+				failures++
+				if failures >= req.StopAfterFailures && i < len(dataset)-1 {
+					summary.StoppedEarly = true
+					break
+				}
 			}
 		}
+		stampEvaluatorVersion(&evalResponses, options.ActionVersion)
 		return &evalResponses, nil
 	}))
+	registerEvaluatorVersion(r, provider, name, options.ActionVersion, actionName)
 	return actionDef, nil
 }
 
@@ -201,8 +697,169 @@ func DefineBatchEvaluator(r *registry.Registry, provider, name string, options *
 	metadataMap["evaluatorIsBilled"] = options.IsBilled
 	metadataMap["evaluatorDisplayName"] = options.DisplayName
 	metadataMap["evaluatorDefinition"] = options.Definition
+	if options.ActionVersion != "" {
+		metadataMap["evaluatorVersion"] = options.ActionVersion
+	}
+	actionName, err := versionedEvaluatorName(r, provider, name, options.ActionVersion)
+	if err != nil {
+		return nil, fmt.Errorf("ai.DefineBatchEvaluator: %w", err)
+	}
 
-	return (*evaluatorActionDef)(core.DefineAction(r, provider, name, atype.Evaluator, map[string]any{"evaluator": metadataMap}, batchEval)), nil
+	var actionDef *evaluatorActionDef
+	actionDef = (*evaluatorActionDef)(core.DefineAction(r, provider, actionName, atype.Evaluator, map[string]any{"evaluator": metadataMap}, func(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
+		setEvaluationSpanAttrs(ctx, req)
+		setAnnotationSpanAttrs(ctx, req)
+		if err := checkDatasetSize(options, req.Dataset); err != nil {
+			return nil, err
+		}
+		evaluatorName := actionDef.Name()
+		size := 0
+		if req.Dataset != nil {
+			size = len(*req.Dataset)
+		}
+		notifyObserver(ctx, req, EvaluationStarted{Evaluator: evaluatorName, EvaluationId: req.EvaluationId, DatasetSize: size})
+		resp, err := batchEval(ctx, req)
+		stampEvaluatorVersion(resp, options.ActionVersion)
+		completed := EvaluationCompleted{Evaluator: evaluatorName, EvaluationId: req.EvaluationId, Summary: EvaluatorRunSummary{Annotations: req.Annotations}}
+		if resp != nil {
+			completed.Results = len(*resp)
+		}
+		notifyObserver(ctx, req, completed)
+		return resp, err
+	}))
+	registerEvaluatorVersion(r, provider, name, options.ActionVersion, actionName)
+	return actionDef, nil
+}
+
+// transformExample returns ex with the InputTransformer and
+// OutputTransformer of options applied.
+func transformExample(ctx context.Context, ex Example, options *EvaluatorOptions) (Example, error) {
+	var err error
+	if options.InputTransformer != nil {
+		if ex.Input, err = options.InputTransformer(ctx, ex.Input); err != nil {
+			return ex, fmt.Errorf("transforming input: %w", err)
+		}
+	}
+	if options.OutputTransformer != nil {
+		if ex.Output, err = options.OutputTransformer(ctx, ex.Output); err != nil {
+			return ex, fmt.Errorf("transforming output: %w", err)
+		}
+	}
+	return ex, nil
+}
+
+// dedupDataset returns ds without the examples that the strategy discards,
+// and the number of examples removed.
+func dedupDataset(ds Dataset, strategy DedupStrategy) (Dataset, int) {
+	keep := map[string]int{}
+	for i, ex := range ds {
+		if ex.TestCaseId == "" {
+			continue
+		}
+		if _, seen := keep[ex.TestCaseId]; !seen || strategy == DedupStrategyKeepLast {
+			keep[ex.TestCaseId] = i
+		}
+	}
+	out := make(Dataset, 0, len(ds))
+	for i, ex := range ds {
+		if ex.TestCaseId == "" || keep[ex.TestCaseId] == i {
+			out = append(out, ex)
+		}
+	}
+	return out, len(ds) - len(out)
+}
+
+// datasetSchemaViolations returns the ways in which ex does not match the
+// JSON Schema schema, if any, in sorted order.
+func datasetSchemaViolations(ex Example, schema []byte) ([]string, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(ex)
+	if err != nil {
+		return nil, fmt.Errorf("example is not valid JSON: %w", err)
+	}
+	violations, err := base.SchemaViolations(data, schema)
+	// The validator reports violations in no particular order.
+	slices.Sort(violations)
+	return violations, err
+}
+
+// exampleFieldIsSet reports, for each field name allowed in
+// [EvaluatorOptions.RequiredFields], whether the field of an example is set.
+var exampleFieldIsSet = map[string]func(*Example) bool{
+	"Input":     func(ex *Example) bool { return !isEmptyValue(ex.Input) },
+	"Output":    func(ex *Example) bool { return !isEmptyValue(ex.Output) },
+	"Context":   func(ex *Example) bool { return len(ex.Context) > 0 },
+	"Reference": func(ex *Example) bool { return !isEmptyValue(ex.Reference) },
+	"TraceIds":  func(ex *Example) bool { return len(ex.TraceIds) > 0 },
+}
+
+// missingField returns the first of fields that is not set in ex, or "" if
+// they are all set.
+func missingField(ex *Example, fields []string) string {
+	for _, field := range fields {
+		if !exampleFieldIsSet[field](ex) {
+			return field
+		}
+	}
+	return ""
+}
+
+// isEmptyValue reports whether v is nil or the empty string.
+func isEmptyValue(v any) bool {
+	return v == nil || v == ""
+}
+
+// excludeExamples returns ds without the examples whose TestCaseId is in
+// ids, and the number of examples removed.
+func excludeExamples(ds Dataset, ids []string) (Dataset, int) {
+	excluded := map[string]bool{}
+	for _, id := range ids {
+		excluded[id] = true
+	}
+	out := make(Dataset, 0, len(ds))
+	for _, ex := range ds {
+		if ex.TestCaseId == "" || !excluded[ex.TestCaseId] {
+			out = append(out, ex)
+		}
+	}
+	return out, len(ds) - len(out)
+}
+
+// checkDatasetSize returns an error if ds is larger than the
+// MaxDatasetSize allowed by options.
+func checkDatasetSize(options *EvaluatorOptions, ds *Dataset) error {
+	if options.MaxDatasetSize <= 0 || ds == nil {
+		return nil
+	}
+	if n := len(*ds); n > options.MaxDatasetSize {
+		return fmt.Errorf("dataset has %d examples, which exceeds the maximum of %d", n, options.MaxDatasetSize)
+	}
+	return nil
+}
+
+// setEvaluationSpanAttrs records the evaluation and run IDs of req on the
+// current span so that spans from different evaluators can be correlated.
+func setEvaluationSpanAttrs(ctx context.Context, req *EvaluatorRequest) {
+	if req.EvaluationId != "" {
+		tracing.SetCustomMetadataAttr(ctx, "evaluator:evalRunId", req.EvaluationId)
+	}
+	if req.RunId != "" {
+		tracing.SetCustomMetadataAttr(ctx, "evaluator:runId", req.RunId)
+	}
+}
+
+// setAnnotationSpanAttrs records the annotations of req on the current span.
+// Values that are not strings are recorded as JSON.
+func setAnnotationSpanAttrs(ctx context.Context, req *EvaluatorRequest) {
+	for k, v := range req.Annotations {
+		s, ok := v.(string)
+		if !ok {
+			s = base.JSONString(v)
+		}
+		tracing.SetCustomMetadataAttr(ctx, "evaluator:annotation:"+k, s)
+	}
 }
 
 // IsDefinedEvaluator reports whether an [Evaluator] is defined.
@@ -213,7 +870,11 @@ func IsDefinedEvaluator(r *registry.Registry, provider, name string) bool {
 // LookupEvaluator looks up an [Evaluator] registered by [DefineEvaluator].
 // It returns nil if the evaluator was not defined.
 func LookupEvaluator(r *registry.Registry, provider, name string) Evaluator {
-	return (*evaluatorActionDef)(core.LookupActionFor[*EvaluatorRequest, *EvaluatorResponse, struct{}](r, atype.Evaluator, provider, name))
+	action := core.LookupActionFor[*EvaluatorRequest, *EvaluatorResponse, struct{}](r, atype.Evaluator, provider, name)
+	if action == nil {
+		return nil
+	}
+	return (*evaluatorActionDef)(action)
 }
 
 // EvaluateOption configures params of the Embed call.
@@ -235,6 +896,79 @@ func WithEvaluateId(evaluationId string) EvaluateOption {
 	}
 }
 
+// WithEvaluateRunId set the run ID on [EvaluatorRequest]
+func WithEvaluateRunId(runId string) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		req.RunId = runId
+		return nil
+	}
+}
+
+// WithEvaluateDryRun marks the [EvaluatorRequest] as a dry run.
+func WithEvaluateDryRun() EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		req.DryRun = true
+		return nil
+	}
+}
+
+// WithEvaluateDedupStrategy set the [DedupStrategy] on [EvaluatorRequest]
+func WithEvaluateDedupStrategy(strategy DedupStrategy) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		req.DedupStrategy = strategy
+		return nil
+	}
+}
+
+// WithEvaluateStopAfterFailures set the failure limit on [EvaluatorRequest]
+func WithEvaluateStopAfterFailures(n int) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		req.StopAfterFailures = n
+		return nil
+	}
+}
+
+// WithEvaluateRequireAllExamples sets RequireAllExamples on [EvaluatorRequest]
+func WithEvaluateRequireAllExamples(require bool) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		req.RequireAllExamples = require
+		return nil
+	}
+}
+
+// WithEvaluateExcludeIds adds test case IDs to skip to [EvaluatorRequest]
+func WithEvaluateExcludeIds(ids ...string) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		req.ExcludeTestCaseIds = append(req.ExcludeTestCaseIds, ids...)
+		return nil
+	}
+}
+
+// WithEvaluateAnnotations adds run-level metadata to [EvaluatorRequest].
+// Keys already set are replaced.
+func WithEvaluateAnnotations(ann map[string]any) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		if req.Annotations == nil {
+			req.Annotations = make(map[string]any, len(ann))
+		}
+		maps.Copy(req.Annotations, ann)
+		return nil
+	}
+}
+
+// WithEvaluateDatasetSampler makes [Evaluate] evaluate a sample of n
+// examples drawn by s instead of the whole dataset. The dataset given with
+// [WithEvaluateDataset] is the pool the sample is drawn from.
+func WithEvaluateDatasetSampler(s DatasetSampler, n int) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		if n <= 0 {
+			return fmt.Errorf("sample size must be positive, got %d", n)
+		}
+		req.sampler, req.sampleSize = s, n
+		return nil
+	}
+}
+
 // WithEvaluateOptions set evaluator options on [EvaluatorRequest]
 func WithEvaluateOptions(opts any) EvaluateOption {
 	return func(req *EvaluatorRequest) error {
@@ -245,23 +979,114 @@ func WithEvaluateOptions(opts any) EvaluateOption {
 
 // Evaluate calls the retrivers with provided options.
 func Evaluate(ctx context.Context, r Evaluator, opts ...EvaluateOption) (*EvaluatorResponse, error) {
+	req, err := newEvaluatorRequest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return r.Evaluate(ctx, req)
+}
+
+// EvaluateRun is like [Evaluate] but returns an [EvaluatorRunResponse],
+// which records whether the request was a dry run and summarizes the run.
+func EvaluateRun(ctx context.Context, r Evaluator, opts ...EvaluateOption) (*EvaluatorRunResponse, error) {
+	req, err := newEvaluatorRequest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	obs := &summaryObserver{next: req.Observer}
+	req.Observer = obs
+	resp, err := r.Evaluate(ctx, req)
+	if resp == nil {
+		return nil, err
+	}
+	return &EvaluatorRunResponse{IsDryRun: req.DryRun, Results: *resp, Summary: obs.summary}, err
+}
+
+// newEvaluatorRequest returns the request configured by opts. If a
+// [DatasetSampler] was given, the request's dataset is replaced by a sample.
+func newEvaluatorRequest(ctx context.Context, opts []EvaluateOption) (*EvaluatorRequest, error) {
 	req := &EvaluatorRequest{}
 	for _, with := range opts {
-		err := with(req)
-		if err != nil {
+		if err := with(req); err != nil {
 			return nil, err
 		}
 	}
-	return r.Evaluate(ctx, req)
+	if req.sampler != nil {
+		var pool Dataset
+		if req.Dataset != nil {
+			pool = *req.Dataset
+		}
+		sample, err := req.sampler.Sample(ctx, pool, req.sampleSize)
+		if err != nil {
+			return nil, fmt.Errorf("sampling dataset: %w", err)
+		}
+		req.Dataset = &sample
+	}
+	return req, nil
 }
 
 func (r *evaluatorActionDef) Name() string { return (*evaluatorAction)(r).Name() }
 
 // Evaluate runs the given [Evaluator].
+//
+// If the evaluator fails on some examples, Evaluate returns the response
+// together with a [*MultiEvaluatorError] holding an [EvaluatorError] for
+// each failed example. The response still holds a failed [Score] for those
+// examples.
+// Likewise, if ctx is cancelled, evaluators defined with [DefineEvaluator]
+// stop before the next example and Evaluate returns the partial response
+// with an error that includes ctx.Err().
 func (e *evaluatorActionDef) Evaluate(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
 	if e == nil {
 		return nil, errors.New("Evaluator called on a nil Evaluator; check that all evaluators are defined")
 	}
 	a := (*core.ActionDef[*EvaluatorRequest, *EvaluatorResponse, struct{}])(e)
-	return a.Run(ctx, req, nil)
+	obs := &summaryObserver{next: req.Observer}
+	observed := *req
+	observed.Observer = obs
+	resp, err := a.Run(ctx, &observed, nil)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	var errs []error
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	if obs.summary.StoppedEarly {
+		errs = append(errs, ErrStoppedEarly)
+	}
+	var exampleErrs []EvaluatorError
+	for _, res := range *resp {
+		if res.err != nil {
+			exampleErrs = append(exampleErrs, EvaluatorError{
+				EvaluatorName: e.Name(),
+				TestCaseId:    res.TestCaseId,
+				Cause:         res.err,
+			})
+		}
+	}
+	if len(exampleErrs) > 0 {
+		errs = append(errs, &MultiEvaluatorError{Errors: exampleErrs})
+	}
+	if len(errs) == 1 {
+		return resp, errs[0]
+	}
+	return resp, errors.Join(errs...)
+}
+
+// exampleText returns a textual representation of an [Example] field such as
+// Output or Reference. Strings are returned as-is and other values are
+// encoded as JSON.
+func exampleText(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case fmt.Stringer:
+		return t.String(), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
 }
@@ -0,0 +1,474 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// ScoreSummary aggregates the results in an [EvaluatorResponse].
+//
+// An [EvaluationResult] passes if all of its scores have status
+// [ScoreStatusPass], and fails if any of its scores has status
+// [ScoreStatusFail].
+type ScoreSummary struct {
+	Total            int     `json:"total"`
+	Passed           int     `json:"passed"`
+	Failed           int     `json:"failed"`
+	PassRate         float64 `json:"passRate"`
+	WeightedPassRate float64 `json:"weightedPassRate"`
+	// MeanPartialCredit is the mean partial credit of the results with
+	// numeric scores. See [EvaluationResult.PartialCredit].
+	MeanPartialCredit float64                `json:"meanPartialCredit"`
+	Scores            map[string]*ScoreStats `json:"scores,omitempty"`
+	// NeedsReview holds the TestCaseIds of the results with a score whose
+	// [Score.Confidence] is below the threshold set with
+	// [WithLowConfidenceThreshold], so that people can review them.
+	NeedsReview []string `json:"needsReview,omitempty"`
+}
+
+// ScoreStats holds statistics for all scores with the same [Score.Id].
+// Mean, Min and Max only consider scores that can be normalized to a number.
+type ScoreStats struct {
+	Count   int     `json:"count"`
+	Numeric int     `json:"numeric"`
+	Passed  int     `json:"passed"`
+	Failed  int     `json:"failed"`
+	Mean    float64 `json:"mean"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	// StdDev is the standard deviation of Mean propagated from the
+	// [ProbabilisticScore] values, assuming they are independent: the square
+	// root of the sum of their variances, divided by Numeric. Point scores
+	// contribute no variance.
+	StdDev float64 `json:"stdDev,omitempty"`
+	// ConfidenceWeightedMean is the mean of the numeric scores weighted by
+	// their [Score.Confidence]. Scores without a confidence are left out;
+	// it is zero if no score has one.
+	ConfidenceWeightedMean float64 `json:"confidenceWeightedMean,omitempty"`
+	// Aggregate is the score computed by the aggregator selected with
+	// [WithAggregationStrategy], if any.
+	Aggregate *Score `json:"aggregate,omitempty"`
+}
+
+// ProbabilisticScore is a [Score.Score] value for evaluators that produce a
+// distribution rather than a point estimate. In JSON it is an object with
+// "mean" and "stddev" fields; such objects are recognized after a [Score]
+// is decoded.
+type ProbabilisticScore struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+}
+
+// asProbabilisticScore returns v as a [ProbabilisticScore] if it is one or
+// has its JSON form: a map with exactly the numeric keys "mean" and
+// "stddev".
+func asProbabilisticScore(v any) (ProbabilisticScore, bool) {
+	switch v := v.(type) {
+	case ProbabilisticScore:
+		return v, true
+	case *ProbabilisticScore:
+		if v != nil {
+			return *v, true
+		}
+	case map[string]any:
+		if len(v) != 2 {
+			return ProbabilisticScore{}, false
+		}
+		mean, okMean := jsonFloat(v["mean"])
+		sd, okSD := jsonFloat(v["stddev"])
+		if okMean && okSD {
+			return ProbabilisticScore{Mean: mean, StdDev: sd}, true
+		}
+	}
+	return ProbabilisticScore{}, false
+}
+
+// jsonFloat returns v as a float64 if it is a number decoded from JSON.
+func jsonFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// AggregatorPlugin combines the scores with the same [Score.Id] into a
+// single score, for example their minimum or geometric mean. Aggregators are
+// registered with [RegisterScoreAggregator] and selected with
+// [WithAggregationStrategy].
+type AggregatorPlugin interface {
+	Aggregate(scores []Score) (*Score, error)
+}
+
+// AggregatorFunc adapts a function to the [AggregatorPlugin] interface.
+type AggregatorFunc func(scores []Score) (*Score, error)
+
+// Aggregate implements [AggregatorPlugin.Aggregate].
+func (f AggregatorFunc) Aggregate(scores []Score) (*Score, error) {
+	return f(scores)
+}
+
+// RegisterScoreAggregator registers agg under name for use with
+// [WithAggregationStrategy]. It panics if an aggregator with the same name
+// is already registered.
+func RegisterScoreAggregator(r *registry.Registry, name string, agg AggregatorPlugin) {
+	r.RegisterValue(scoreAggregatorKey(name), agg)
+}
+
+// LookupScoreAggregator returns the aggregator registered under name, or
+// nil if there is none.
+func LookupScoreAggregator(r *registry.Registry, name string) AggregatorPlugin {
+	agg, _ := r.LookupValue(scoreAggregatorKey(name)).(AggregatorPlugin)
+	return agg
+}
+
+func scoreAggregatorKey(name string) string {
+	return "scoreAggregator/" + name
+}
+
+// AggregateOption configures [AggregateScores] and [SortEvaluatorResponse].
+type AggregateOption func(opts *aggregateOptions) error
+
+type aggregateOptions struct {
+	weights       map[string]float64 // Example weights by TestCaseId.
+	strategy      string             // Name of the aggregator.
+	aggregator    AggregatorPlugin
+	lowConfidence float64 // Confidence below which results need review.
+}
+
+// DefaultLowConfidenceThreshold is the confidence below which
+// [AggregateScores] flags results for review, unless another threshold is
+// set with [WithLowConfidenceThreshold].
+const DefaultLowConfidenceThreshold = 0.5
+
+// WithAggregateDataset provides the dataset that was evaluated, so that
+// results can be weighted by [Example.Weight]. Results are matched to
+// examples by TestCaseId; results without a matching example have weight 1.
+func WithAggregateDataset(ds Dataset) AggregateOption {
+	return func(opts *aggregateOptions) error {
+		opts.weights = make(map[string]float64, len(ds))
+		for _, ex := range ds {
+			if ex.Weight < 0 {
+				return fmt.Errorf("example %q has negative weight %v", ex.TestCaseId, ex.Weight)
+			}
+			if ex.TestCaseId != "" {
+				opts.weights[ex.TestCaseId] = ex.EffectiveWeight()
+			}
+		}
+		return nil
+	}
+}
+
+// WithAggregationStrategy makes [AggregateScores] combine all scores with
+// the same [Score.Id] using the aggregator registered under name with
+// [RegisterScoreAggregator]. The result is reported in
+// [ScoreStats.Aggregate]. [SortEvaluatorResponse] ignores this option.
+func WithAggregationStrategy(r *registry.Registry, name string) AggregateOption {
+	return func(opts *aggregateOptions) error {
+		agg := LookupScoreAggregator(r, name)
+		if agg == nil {
+			return fmt.Errorf("no score aggregator named %q", name)
+		}
+		opts.strategy, opts.aggregator = name, agg
+		return nil
+	}
+}
+
+// WithLowConfidenceThreshold sets the confidence below which
+// [AggregateScores] lists results in [ScoreSummary.NeedsReview]. Scores
+// without a confidence are never flagged. [SortEvaluatorResponse] ignores
+// this option.
+func WithLowConfidenceThreshold(threshold float64) AggregateOption {
+	return func(opts *aggregateOptions) error {
+		if threshold < 0 || threshold > 1 {
+			return fmt.Errorf("low confidence threshold %v is not in [0, 1]", threshold)
+		}
+		opts.lowConfidence = threshold
+		return nil
+	}
+}
+
+func newAggregateOptions(opts []AggregateOption) (*aggregateOptions, error) {
+	o := &aggregateOptions{lowConfidence: DefaultLowConfidenceThreshold}
+	for _, with := range opts {
+		if err := with(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *aggregateOptions) weight(testCaseId string) float64 {
+	if w, ok := o.weights[testCaseId]; ok {
+		return w
+	}
+	return 1
+}
+
+// AggregateScores computes a [ScoreSummary] for resp. The WeightedPassRate is
+// sum(weight * isPass) / sum(weight); without [WithAggregateDataset] every
+// result has weight 1 and it equals PassRate. Results with a score whose
+// confidence is below [DefaultLowConfidenceThreshold], or the threshold set
+// with [WithLowConfidenceThreshold], are listed in NeedsReview.
+func AggregateScores(resp *EvaluatorResponse, opts ...AggregateOption) (*ScoreSummary, error) {
+	o, err := newAggregateOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("ai.AggregateScores: %w", err)
+	}
+
+	summary := &ScoreSummary{Scores: map[string]*ScoreStats{}}
+	if resp == nil {
+		return summary, nil
+	}
+	var totalWeight, passedWeight, totalCredit float64
+	credited := 0
+	byId := map[string][]Score{}
+	confidence := map[string]float64{} // Total confidence by score Id.
+	for _, res := range *resp {
+		summary.Total++
+		if credit, ok := partialCredit(res); ok {
+			totalCredit += credit
+			credited++
+		}
+		w := o.weight(res.TestCaseId)
+		totalWeight += w
+		switch resultStatus(res) {
+		case ScoreStatusPass:
+			summary.Passed++
+			passedWeight += w
+		case ScoreStatusFail:
+			summary.Failed++
+		}
+
+		needsReview := false
+		for _, s := range res.Evaluation {
+			if s.Confidence != nil && *s.Confidence < o.lowConfidence {
+				needsReview = true
+			}
+			if o.aggregator != nil {
+				byId[s.Id] = append(byId[s.Id], s)
+			}
+			stats, ok := summary.Scores[s.Id]
+			if !ok {
+				stats = &ScoreStats{Min: math.Inf(1), Max: math.Inf(-1)}
+				summary.Scores[s.Id] = stats
+			}
+			stats.Count++
+			switch s.Status {
+			case ScoreStatusPass.String():
+				stats.Passed++
+			case ScoreStatusFail.String():
+				stats.Failed++
+			}
+			if v, err := s.Normalize(); err == nil {
+				stats.Numeric++
+				stats.Mean += v
+				stats.Min = min(stats.Min, v)
+				stats.Max = max(stats.Max, v)
+				if p, ok := asProbabilisticScore(s.Score); ok {
+					// Accumulate the variance; converted to StdDev below.
+					stats.StdDev += p.StdDev * p.StdDev
+				}
+				if s.Confidence != nil {
+					// Accumulate the weighted sum; divided below.
+					stats.ConfidenceWeightedMean += *s.Confidence * v
+					confidence[s.Id] += *s.Confidence
+				}
+			}
+		}
+		if needsReview {
+			summary.NeedsReview = append(summary.NeedsReview, res.TestCaseId)
+		}
+	}
+
+	if summary.Total > 0 {
+		summary.PassRate = float64(summary.Passed) / float64(summary.Total)
+	}
+	if totalWeight > 0 {
+		summary.WeightedPassRate = passedWeight / totalWeight
+	}
+	if credited > 0 {
+		summary.MeanPartialCredit = totalCredit / float64(credited)
+	}
+	for id, stats := range summary.Scores {
+		if o.aggregator != nil {
+			agg, err := o.aggregator.Aggregate(byId[id])
+			if err != nil {
+				return nil, fmt.Errorf("ai.AggregateScores: aggregator %q on score %q: %w", o.strategy, id, err)
+			}
+			stats.Aggregate = agg
+		}
+		if stats.Numeric == 0 {
+			stats.Min, stats.Max = 0, 0
+			continue
+		}
+		stats.Mean /= float64(stats.Numeric)
+		if c := confidence[id]; c > 0 {
+			stats.ConfidenceWeightedMean /= c
+		}
+		stats.StdDev = math.Sqrt(stats.StdDev) / float64(stats.Numeric)
+	}
+	return summary, nil
+}
+
+// GroupEvaluatorResponseBy splits resp into the groups that [GroupBy] forms
+// from ds with the given field, matching results to examples by TestCaseId.
+// Results without a matching example are placed in the group "". Groups can
+// then be summarized separately with [AggregateScores].
+func GroupEvaluatorResponseBy(resp *EvaluatorResponse, ds Dataset, field string) map[string]*EvaluatorResponse {
+	group := make(map[string]string, len(ds))
+	for k, exs := range GroupBy(ds, field, nil) {
+		for _, ex := range exs {
+			group[ex.TestCaseId] = k
+		}
+	}
+	groups := map[string]*EvaluatorResponse{}
+	if resp == nil {
+		return groups
+	}
+	for _, res := range *resp {
+		k := group[res.TestCaseId]
+		g, ok := groups[k]
+		if !ok {
+			g = &EvaluatorResponse{}
+			groups[k] = g
+		}
+		*g = append(*g, res)
+	}
+	return groups
+}
+
+// SortEvaluatorResponse sorts resp in place by the numeric value of the score
+// with the given id, lowest first, so that the worst results come first.
+// Results without a numeric score with that id are placed last. If
+// [WithAggregateDataset] is given, each score is multiplied by the weight of
+// its example, so that important failures sort ahead of trivial ones.
+func SortEvaluatorResponse(resp *EvaluatorResponse, scoreId string, opts ...AggregateOption) error {
+	o, err := newAggregateOptions(opts)
+	if err != nil {
+		return fmt.Errorf("ai.SortEvaluatorResponse: %w", err)
+	}
+	if resp == nil {
+		return nil
+	}
+
+	key := func(res EvaluationResult) (float64, bool) {
+		for _, s := range res.Evaluation {
+			if s.Id != scoreId {
+				continue
+			}
+			v, err := s.Normalize()
+			if err != nil {
+				return 0, false
+			}
+			if o.weights != nil {
+				v *= o.weight(res.TestCaseId)
+			}
+			return v, true
+		}
+		return 0, false
+	}
+	slices.SortStableFunc(*resp, func(a, b EvaluationResult) int {
+		ka, okA := key(a)
+		kb, okB := key(b)
+		switch {
+		case !okA && !okB:
+			return 0
+		case !okA:
+			return 1
+		case !okB:
+			return -1
+		case ka < kb:
+			return -1
+		case ka > kb:
+			return 1
+		}
+		return 0
+	})
+	return nil
+}
+
+// resultStatus returns the overall status of a result: its
+// PartialCreditStatus if set, and otherwise pass if all of its scores
+// passed, fail if any of them failed, and unknown otherwise.
+func resultStatus(res EvaluationResult) ScoreStatus {
+	switch res.PartialCreditStatus {
+	case "":
+	case ScoreStatusPass.String():
+		return ScoreStatusPass
+	case ScoreStatusFail.String():
+		return ScoreStatusFail
+	default:
+		return ScoreStatusUnknown
+	}
+	if len(res.Evaluation) == 0 {
+		return ScoreStatusUnknown
+	}
+	status := ScoreStatusPass
+	for _, s := range res.Evaluation {
+		switch s.Status {
+		case ScoreStatusFail.String():
+			return ScoreStatusFail
+		case ScoreStatusPass.String():
+		default:
+			status = ScoreStatusUnknown
+		}
+	}
+	return status
+}
+
+// Normalize converts the value of the score to a float64. Booleans become 1
+// or 0, numeric types are converted directly, strings are parsed as numbers
+// and a [ProbabilisticScore] becomes its Mean. It returns an error for any
+// other value.
+func (s Score) Normalize() (float64, error) {
+	if p, ok := asProbabilisticScore(s.Score); ok {
+		return p.Mean, nil
+	}
+	switch v := s.Score.(type) {
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+	rv := reflect.ValueOf(s.Score)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return 0, fmt.Errorf("score %v of type %T is not numeric", s.Score, s.Score)
+}
@@ -0,0 +1,294 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+)
+
+func passFail(id string, pass bool, score any) EvaluationResult {
+	status := ScoreStatusFail
+	if pass {
+		status = ScoreStatusPass
+	}
+	return EvaluationResult{
+		TestCaseId: id,
+		Evaluation: []Score{{Id: "s", Score: score, Status: status.String()}},
+	}
+}
+
+func TestAggregateScores(t *testing.T) {
+	resp := EvaluatorResponse{
+		passFail("a", true, 1),
+		passFail("b", true, 0.8),
+		passFail("c", true, true),
+		passFail("safety", false, 0),
+	}
+
+	summary, err := AggregateScores(&resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := summary.PassRate, 0.75; got != want {
+		t.Errorf("got pass rate %v, want %v", got, want)
+	}
+	if got, want := summary.WeightedPassRate, summary.PassRate; got != want {
+		t.Errorf("got weighted pass rate %v, want %v", got, want)
+	}
+	if got, want := summary.MeanPartialCredit, 0.7; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got mean partial credit %v, want %v", got, want)
+	}
+	stats := summary.Scores["s"]
+	if got, want := stats.Mean, 0.7; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got mean %v, want %v", got, want)
+	}
+	if stats.Min != 0 || stats.Max != 1 {
+		t.Errorf("got min %v max %v, want 0 and 1", stats.Min, stats.Max)
+	}
+
+	t.Run("high weight failure dominates", func(t *testing.T) {
+		ds := Dataset{
+			{TestCaseId: "a"},
+			{TestCaseId: "b"},
+			{TestCaseId: "c"},
+			{TestCaseId: "safety", Weight: 10},
+		}
+		summary, err := AggregateScores(&resp, WithAggregateDataset(ds))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := summary.WeightedPassRate, 3.0/13.0; math.Abs(got-want) > 1e-9 {
+			t.Errorf("got weighted pass rate %v, want %v", got, want)
+		}
+		if summary.WeightedPassRate >= 0.5 {
+			t.Errorf("weighted pass rate %v should be dominated by the failure", summary.WeightedPassRate)
+		}
+	})
+
+	t.Run("negative weight", func(t *testing.T) {
+		if _, err := AggregateScores(&resp, WithAggregateDataset(Dataset{{TestCaseId: "a", Weight: -1}})); err == nil {
+			t.Error("got nil, want error")
+		}
+	})
+}
+
+func TestAggregateScoresConfidence(t *testing.T) {
+	scored := func(id string, score float64, confidence ...float64) EvaluationResult {
+		s := Score{Id: "s", Score: score, Status: ScoreStatusPass.String()}
+		if len(confidence) > 0 {
+			s.Confidence = &confidence[0]
+		}
+		return EvaluationResult{TestCaseId: id, Evaluation: []Score{s}}
+	}
+	resp := EvaluatorResponse{
+		scored("sure", 1, 0.9),
+		scored("unsure", 0, 0.3),
+		scored("unreported", 0.5),
+		scored("doubtful", 1, 0),
+	}
+
+	summary, err := AggregateScores(&resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := summary.Scores["s"]
+	// The score without a confidence is left out, and the one with a
+	// confidence of 0 has no weight: (0.9*1 + 0.3*0 + 0*1) / 1.2.
+	if got, want := stats.ConfidenceWeightedMean, 0.75; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got confidence-weighted mean %v, want %v", got, want)
+	}
+	if got, want := stats.Mean, 0.625; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got mean %v, want %v", got, want)
+	}
+	if want := []string{"unsure", "doubtful"}; !slices.Equal(summary.NeedsReview, want) {
+		t.Errorf("got results needing review %v, want %v", summary.NeedsReview, want)
+	}
+
+	summary, err = AggregateScores(&resp, WithLowConfidenceThreshold(0.95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"sure", "unsure", "doubtful"}; !slices.Equal(summary.NeedsReview, want) {
+		t.Errorf("threshold 0.95: got results needing review %v, want %v", summary.NeedsReview, want)
+	}
+
+	// Without confidences, nothing is weighted or flagged.
+	unreported := EvaluatorResponse{scored("a", 1), scored("b", 0)}
+	summary, err = AggregateScores(&unreported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := summary.Scores["s"]; stats.ConfidenceWeightedMean != 0 || stats.Mean != 0.5 || summary.NeedsReview != nil {
+		t.Errorf("got confidence-weighted mean %v, mean %v and review %v, want 0, 0.5 and none", stats.ConfidenceWeightedMean, stats.Mean, summary.NeedsReview)
+	}
+
+	if _, err := AggregateScores(&resp, WithLowConfidenceThreshold(2)); err == nil {
+		t.Error("got nil, want error for threshold above 1")
+	}
+}
+
+func TestSortEvaluatorResponse(t *testing.T) {
+	resp := EvaluatorResponse{
+		passFail("a", true, 0.9),
+		passFail("b", false, "n/a"),
+		passFail("c", true, 0.5),
+		passFail("d", true, 0.7),
+	}
+
+	if err := SortEvaluatorResponse(&resp, "s"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := order(resp), "cdab"; got != want {
+		t.Errorf("got order %q, want %q", got, want)
+	}
+
+	ds := Dataset{{TestCaseId: "c", Weight: 3}, {TestCaseId: "a", Weight: 0.1}}
+	if err := SortEvaluatorResponse(&resp, "s", WithAggregateDataset(ds)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := order(resp), "adcb"; got != want {
+		t.Errorf("got weighted order %q, want %q", got, want)
+	}
+}
+
+func order(resp EvaluatorResponse) string {
+	var s string
+	for _, res := range resp {
+		s += res.TestCaseId
+	}
+	return s
+}
+
+func TestScoreNormalize(t *testing.T) {
+	for _, v := range []any{1, int64(1), uint8(1), float32(1), 1.0, true, "1", ProbabilisticScore{Mean: 1, StdDev: 0.1}, map[string]any{"mean": 1.0, "stddev": 0.1}} {
+		got, err := Score{Score: v}.Normalize()
+		if err != nil || got != 1 {
+			t.Errorf("Normalize(%#v) = %v, %v; want 1, nil", v, got, err)
+		}
+	}
+	if _, err := (Score{Score: map[string]any{}}).Normalize(); err == nil {
+		t.Error("got nil, want error for non-numeric score")
+	}
+}
+
+func TestAggregateProbabilisticScores(t *testing.T) {
+	var resp EvaluatorResponse
+	data := `[
+		{"testCaseId": "a", "evaluation": [{"id": "s", "score": {"mean": 0.8, "stddev": 0.3}, "status": "pass"}]},
+		{"testCaseId": "b", "evaluation": [{"id": "s", "score": {"mean": 0.4, "stddev": 0.4}, "status": "fail"}]}
+	]`
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatal(err)
+	}
+	summary, err := AggregateScores(&resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := summary.Scores["s"]
+	if got, want := stats.Mean, 0.6; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got mean %v, want %v", got, want)
+	}
+	// sqrt(0.3² + 0.4²) / 2
+	if got, want := stats.StdDev, 0.25; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got stddev %v, want %v", got, want)
+	}
+
+	out, err := json.Marshal(Score{Score: ProbabilisticScore{Mean: 0.5, StdDev: 0.1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), `{"score":{"mean":0.5,"stddev":0.1}}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestAggregationStrategy(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterScoreAggregator(r, "min", AggregatorFunc(func(scores []Score) (*Score, error) {
+		lowest := math.Inf(1)
+		for _, s := range scores {
+			if v, err := s.Normalize(); err == nil {
+				lowest = min(lowest, v)
+			}
+		}
+		return &Score{Id: "min", Score: lowest}, nil
+	}))
+	RegisterScoreAggregator(r, "broken", AggregatorFunc(func(scores []Score) (*Score, error) {
+		return nil, errors.New("boom")
+	}))
+
+	resp := EvaluatorResponse{passFail("a", true, 0.9), passFail("b", false, 0.2), passFail("c", true, 0.7)}
+	summary, err := AggregateScores(&resp, WithAggregationStrategy(r, "min"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agg := summary.Scores["s"].Aggregate; agg == nil || agg.Score != 0.2 {
+		t.Errorf("got aggregate %+v, want score 0.2", agg)
+	}
+
+	if _, err := AggregateScores(&resp, WithAggregationStrategy(r, "broken")); err == nil {
+		t.Error("got nil, want error from aggregator")
+	}
+	if _, err := AggregateScores(&resp, WithAggregationStrategy(r, "missing")); err == nil {
+		t.Error("got nil, want error for unregistered aggregator")
+	}
+}
+
+func TestGroupEvaluatorResponseBy(t *testing.T) {
+	ds := Dataset{
+		{TestCaseId: "a", Input: map[string]any{"domain": "finance"}},
+		{TestCaseId: "b", Input: map[string]any{"domain": "medical"}},
+		{TestCaseId: "c", Input: map[string]any{"domain": "finance"}},
+	}
+	resp := EvaluatorResponse{
+		passFail("a", true, 1),
+		passFail("b", false, 0),
+		passFail("c", false, 0),
+		passFail("x", true, 1),
+	}
+
+	groups := GroupEvaluatorResponseBy(&resp, ds, "domain")
+	got := map[string]string{}
+	total := 0
+	for k, g := range groups {
+		got[k] = order(*g)
+		total += len(*g)
+	}
+	want := map[string]string{"finance": "ac", "medical": "b", "": "x"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("groups mismatch (-want +got):\n%s", diff)
+	}
+	if total != len(resp) {
+		t.Errorf("got %d results in groups, want %d", total, len(resp))
+	}
+
+	summary, err := AggregateScores(groups["finance"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := summary.PassRate, 0.5; got != want {
+		t.Errorf("finance pass rate: got %v, want %v", got, want)
+	}
+}
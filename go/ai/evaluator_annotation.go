@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HumanScore is a score given by a person reviewing an [EvaluationResult],
+// for example to correct an automated judgement.
+type HumanScore struct {
+	Annotator string    `json:"annotator"`
+	Score     Score     `json:"score"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AnnotateResult adds ann to the result for testCaseId in the evaluation
+// stored under evalId. If ann.Timestamp is zero, the current time is used.
+//
+// AnnotateResult loads and re-saves the whole evaluation, so concurrent
+// annotations of the same evaluation must be serialized by the caller.
+func AnnotateResult(ctx context.Context, store StoreEvaluatorResponse, evalId, testCaseId string, ann HumanScore) error {
+	if ann.Annotator == "" {
+		return errors.New("ai.AnnotateResult: annotator is required")
+	}
+	if ann.Timestamp.IsZero() {
+		ann.Timestamp = time.Now()
+	}
+
+	resp, err := store.Load(ctx, evalId)
+	if err != nil {
+		return fmt.Errorf("ai.AnnotateResult: %w", err)
+	}
+	found := false
+	for i := range *resp {
+		if (*resp)[i].TestCaseId == testCaseId {
+			(*resp)[i].HumanAnnotation = append((*resp)[i].HumanAnnotation, ann)
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("ai.AnnotateResult: no result for test case %q in evaluation %q", testCaseId, evalId)
+	}
+	return store.Save(ctx, "", evalId, resp)
+}
+
+// LoadAnnotations returns the human annotations of the evaluation stored
+// under evalId, keyed by TestCaseId. Results without annotations are omitted.
+func LoadAnnotations(ctx context.Context, store StoreEvaluatorResponse, evalId string) (map[string][]HumanScore, error) {
+	resp, err := store.Load(ctx, evalId)
+	if err != nil {
+		return nil, fmt.Errorf("ai.LoadAnnotations: %w", err)
+	}
+	anns := map[string][]HumanScore{}
+	for _, res := range *resp {
+		if len(res.HumanAnnotation) > 0 {
+			anns[res.TestCaseId] = append(anns[res.TestCaseId], res.HumanAnnotation...)
+		}
+	}
+	return anns, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAnnotateResult(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileEvaluationStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := EvaluatorResponse{passFail("a", true, 1), passFail("b", true, 1)}
+	if err := store.Save(ctx, "run1", "eval1", &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	correction := HumanScore{
+		Annotator: "reviewer@example.com",
+		Score:     Score{Id: "s", Score: 0, Status: ScoreStatusFail.String()},
+	}
+	if err := AnnotateResult(ctx, store, "eval1", "b", correction); err != nil {
+		t.Fatal(err)
+	}
+	if err := AnnotateResult(ctx, store, "eval1", "missing", correction); err == nil {
+		t.Error("got nil, want error for unknown test case")
+	}
+	if err := AnnotateResult(ctx, store, "eval1", "b", HumanScore{}); err == nil {
+		t.Error("got nil, want error for missing annotator")
+	}
+
+	anns, err := LoadAnnotations(ctx, store, "eval1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(anns), 1; got != want {
+		t.Fatalf("got annotations for %d results, want %d", got, want)
+	}
+	ann := anns["b"][0]
+	if got, want := ann.Score.Status, "fail"; got != want {
+		t.Errorf("got status %v, want %v", got, want)
+	}
+	if time.Since(ann.Timestamp) > time.Minute {
+		t.Errorf("timestamp %v was not defaulted to now", ann.Timestamp)
+	}
+
+	// Annotating keeps the evaluation in its run.
+	if run, err := store.LoadAllForRun(ctx, "run1"); err != nil || len(run) != 1 {
+		t.Errorf("got %d evaluations in run (err %v), want 1", len(run), err)
+	}
+}
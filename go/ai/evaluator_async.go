@@ -0,0 +1,436 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EvalJobState is an enum for the state of an evaluation started with
+// [StartEvaluationAsync].
+type EvalJobState int
+
+const (
+	// EvalJobStateUnknown means that the job is not known: it was started
+	// by another process and the handle has no [EvalJobStore], or it was
+	// waited for or released.
+	EvalJobStateUnknown EvalJobState = iota
+	// EvalJobStateRunning means that the evaluation is in progress.
+	EvalJobStateRunning
+	// EvalJobStateSucceeded means that the evaluation finished with a
+	// response. Some examples may still have failed; see EvalJobStatus.Err.
+	EvalJobStateSucceeded
+	// EvalJobStateFailed means that the evaluation finished without a
+	// response.
+	EvalJobStateFailed
+	// EvalJobStateCanceled means that the job was canceled.
+	EvalJobStateCanceled
+)
+
+var evalJobStateName = map[EvalJobState]string{
+	EvalJobStateUnknown:   "unknown",
+	EvalJobStateRunning:   "running",
+	EvalJobStateSucceeded: "succeeded",
+	EvalJobStateFailed:    "failed",
+	EvalJobStateCanceled:  "canceled",
+}
+
+func (s EvalJobState) String() string {
+	if n, ok := evalJobStateName[s]; ok {
+		return n
+	}
+	return "unknown"
+}
+
+func (s EvalJobState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *EvalJobState) UnmarshalText(text []byte) error {
+	for state, name := range evalJobStateName {
+		if name == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown evaluation job state %q", text)
+}
+
+// EvalJobStatus is the progress of an evaluation started with
+// [StartEvaluationAsync].
+type EvalJobStatus struct {
+	State EvalJobState
+	// Total is the number of examples to evaluate, once the evaluation has
+	// started, and Completed the number evaluated so far. Batch evaluators
+	// only report Completed when they finish.
+	Total     int
+	Completed int
+	// Err is the error returned by the evaluation once it finished, or
+	// [ErrEvalJobNotFound] if the state is unknown.
+	Err error
+}
+
+// ErrEvalJobNotFound is returned for an [EvalJobHandle] whose job is not
+// known to this process.
+var ErrEvalJobNotFound = errors.New("evaluation job not found")
+
+// EvalJobHandle refers to an evaluation started with [StartEvaluationAsync].
+//
+// A handle can be serialized, for example as JSON, and used again later.
+// Jobs run in the process that started them and end with it. If the job was
+// started with [WithEvaluateJobStore], its state and final response are
+// saved to that store, and a handle restored in another process, or after a
+// restart, can follow the job through [EvalJobHandle.WithStore]. Otherwise
+// the job is known only to the process that started it.
+//
+// A job is forgotten by the process running it once [EvalJobHandle.Wait]
+// has returned its results or [EvalJobHandle.Release] was called; its
+// stored state remains. Jobs that are neither waited for nor released stay
+// in memory until the process ends.
+type EvalJobHandle struct {
+	JobId        string `json:"jobId"`
+	Evaluator    string `json:"evaluator"`
+	EvaluationId string `json:"evaluationId"`
+
+	store EvalJobStore
+}
+
+// EvalJobStore is a [StoreEvaluatorResponse] that also stores the state of
+// the jobs started with [StartEvaluationAsync]. [FileEvaluationStore] and
+// [InMemoryEvaluationStore] implement it.
+type EvalJobStore interface {
+	StoreEvaluatorResponse
+	// SaveJob stores job under job.EvaluationId, replacing any previous
+	// job record. It does not change the response stored under that ID.
+	SaveJob(ctx context.Context, job *EvalJobRecord) error
+	// LoadJob returns the job record stored under evalId, or an error
+	// wrapping [ErrEvalJobNotFound].
+	LoadJob(ctx context.Context, evalId string) (*EvalJobRecord, error)
+}
+
+// EvalJobRecord is the state of a job saved to an [EvalJobStore].
+type EvalJobRecord struct {
+	JobId        string       `json:"jobId"`
+	Evaluator    string       `json:"evaluator"`
+	EvaluationId string       `json:"evaluationId"`
+	State        EvalJobState `json:"state"`
+	Total        int          `json:"total"`
+	Completed    int          `json:"completed"`
+	// Error is the message of the error the evaluation returned, if any.
+	Error string `json:"error,omitempty"`
+	// HasResponse reports whether the response of the finished job is
+	// stored under EvaluationId.
+	HasResponse bool `json:"hasResponse,omitempty"`
+}
+
+const (
+	// evalJobSaveInterval is the least time between saves of the progress
+	// of a running job.
+	evalJobSaveInterval = time.Second
+	// evalJobPollInterval is how often Wait reloads the state of a job
+	// running in another process.
+	evalJobPollInterval = 100 * time.Millisecond
+)
+
+// WithEvaluateJobStore makes [StartEvaluationAsync] save the state of the
+// job and its final response to store, so that the job can be followed
+// from other processes. It has no effect on [Evaluate]. The response is not
+// saved for dry runs.
+func WithEvaluateJobStore(store EvalJobStore) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		req.jobStore = store
+		return nil
+	}
+}
+
+// evalJob is an evaluation running in the background.
+type evalJob struct {
+	cancel context.CancelFunc
+	done   chan struct{} // closed when the evaluation finished
+	store  EvalJobStore  // nil if the job is not persisted
+
+	mu       sync.Mutex
+	record   EvalJobRecord // state of the job as last saved or to save
+	saved    time.Time     // when record was last saved
+	status   EvalJobStatus
+	canceled bool
+	resp     *EvaluatorResponse
+}
+
+// evalJobs holds the jobs started in this process, keyed by job ID.
+var evalJobs sync.Map
+
+// StartEvaluationAsync starts evaluating with e in the background and
+// returns a handle to follow the evaluation. If no evaluation ID is given,
+// one is assigned. The evaluation keeps the values of ctx but is not
+// canceled with it; use [EvalJobHandle.Cancel].
+func StartEvaluationAsync(ctx context.Context, e Evaluator, opts ...EvaluateOption) (EvalJobHandle, error) {
+	req, err := newEvaluatorRequest(ctx, opts)
+	if err != nil {
+		return EvalJobHandle{}, err
+	}
+	if req.EvaluationId == "" {
+		req.EvaluationId = uuid.New().String()
+	}
+
+	h := EvalJobHandle{JobId: uuid.New().String(), Evaluator: e.Name(), EvaluationId: req.EvaluationId, store: req.jobStore}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	job := &evalJob{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		store:  req.jobStore,
+		record: EvalJobRecord{JobId: h.JobId, Evaluator: h.Evaluator, EvaluationId: h.EvaluationId, State: EvalJobStateRunning},
+		status: EvalJobStatus{State: EvalJobStateRunning},
+	}
+	if err := job.save(ctx); err != nil {
+		cancel()
+		return EvalJobHandle{}, fmt.Errorf("ai.StartEvaluationAsync: %w", err)
+	}
+	req.Observer = &jobObserver{job: job, next: req.Observer}
+	evalJobs.Store(h.JobId, job)
+
+	go func() {
+		defer cancel()
+		resp, err := e.Evaluate(ctx, req)
+		job.mu.Lock()
+		defer job.mu.Unlock()
+		job.resp = resp
+		job.status.Err = err
+		switch {
+		case job.canceled:
+			job.status.State = EvalJobStateCanceled
+		case resp == nil:
+			job.status.State = EvalJobStateFailed
+		default:
+			job.status.State = EvalJobStateSucceeded
+		}
+		if err := job.saveResult(ctx, req); err != nil {
+			job.status.Err = errors.Join(job.status.Err, err)
+		}
+		close(job.done)
+	}()
+	return h, nil
+}
+
+// save saves the current record of the job, if it has a store. The caller
+// must hold job.mu or be the only user of job.
+func (job *evalJob) save(ctx context.Context) error {
+	if job.store == nil {
+		return nil
+	}
+	job.saved = time.Now()
+	rec := job.record
+	if err := job.store.SaveJob(ctx, &rec); err != nil {
+		return fmt.Errorf("saving evaluation job %q: %w", job.record.JobId, err)
+	}
+	return nil
+}
+
+// saveResult saves the response and final state of the finished job. The
+// caller must hold job.mu.
+func (job *evalJob) saveResult(ctx context.Context, req *EvaluatorRequest) error {
+	if job.store == nil {
+		return nil
+	}
+	// Saving must not fail because the job was canceled.
+	ctx = context.WithoutCancel(ctx)
+	job.record.State = job.status.State
+	job.record.Total = job.status.Total
+	job.record.Completed = job.status.Completed
+	if job.status.Err != nil {
+		job.record.Error = job.status.Err.Error()
+	}
+	if job.resp != nil && !req.DryRun {
+		if err := job.store.Save(ctx, req.RunId, req.EvaluationId, job.resp); err != nil {
+			return fmt.Errorf("saving evaluation %q: %w", req.EvaluationId, err)
+		}
+		job.record.HasResponse = true
+	}
+	return job.save(ctx)
+}
+
+// WithStore returns a copy of h that also looks for the job in store, such
+// as a handle restored after a restart. store must be the one the job was
+// started with using [WithEvaluateJobStore].
+func (h EvalJobHandle) WithStore(store EvalJobStore) EvalJobHandle {
+	h.store = store
+	return h
+}
+
+func (h EvalJobHandle) job() (*evalJob, bool) {
+	job, ok := evalJobs.Load(h.JobId)
+	if !ok {
+		return nil, false
+	}
+	return job.(*evalJob), true
+}
+
+// record loads the stored state of the job.
+func (h EvalJobHandle) record(ctx context.Context) (*EvalJobRecord, error) {
+	if h.store == nil {
+		return nil, ErrEvalJobNotFound
+	}
+	rec, err := h.store.LoadJob(ctx, h.EvaluationId)
+	if err != nil {
+		return nil, err
+	}
+	if rec.JobId != h.JobId {
+		return nil, fmt.Errorf("%w: evaluation %q belongs to job %q", ErrEvalJobNotFound, h.EvaluationId, rec.JobId)
+	}
+	return rec, nil
+}
+
+// Status returns the progress of the job. For a job running in another
+// process, it is the progress last saved to the store of h, which is
+// updated about every second.
+func (h EvalJobHandle) Status() EvalJobStatus {
+	if job, ok := h.job(); ok {
+		job.mu.Lock()
+		defer job.mu.Unlock()
+		return job.status
+	}
+	rec, err := h.record(context.Background())
+	if err != nil {
+		return EvalJobStatus{State: EvalJobStateUnknown, Err: err}
+	}
+	return rec.status()
+}
+
+// status returns the job status recorded in rec.
+func (rec *EvalJobRecord) status() EvalJobStatus {
+	s := EvalJobStatus{State: rec.State, Total: rec.Total, Completed: rec.Completed}
+	if rec.Error != "" {
+		s.Err = errors.New(rec.Error)
+	}
+	return s
+}
+
+// Cancel stops the job. Evaluators defined with [DefineEvaluator] stop
+// before the next example, and [EvalJobHandle.Wait] returns the results so
+// far. Canceling a finished job has no effect. Only the process running the
+// job can cancel it; in other processes Cancel returns [ErrEvalJobNotFound].
+func (h EvalJobHandle) Cancel() error {
+	job, ok := h.job()
+	if !ok {
+		return ErrEvalJobNotFound
+	}
+	job.mu.Lock()
+	if job.status.State == EvalJobStateRunning {
+		job.canceled = true
+	}
+	job.mu.Unlock()
+	job.cancel()
+	return nil
+}
+
+// Wait waits until the job finished or ctx is done, and returns what
+// [Evaluator.Evaluate] returned. Once the job finished, Wait forgets it in
+// the process running it, so later calls return [ErrEvalJobNotFound] unless
+// h has a store.
+//
+// For a job running in another process, Wait polls the store of h and
+// returns the stored response. Errors are then only known by their
+// message. If that process ended before the job finished, the stored state
+// stays [EvalJobStateRunning] and Wait returns only when ctx is done.
+func (h EvalJobHandle) Wait(ctx context.Context) (*EvaluatorResponse, error) {
+	job, ok := h.job()
+	if !ok {
+		return h.waitStored(ctx)
+	}
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	evalJobs.Delete(h.JobId)
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.resp, job.status.Err
+}
+
+// waitStored waits until the stored state of the job shows that it
+// finished, and returns its stored response.
+func (h EvalJobHandle) waitStored(ctx context.Context) (*EvaluatorResponse, error) {
+	ticker := time.NewTicker(evalJobPollInterval)
+	defer ticker.Stop()
+	for {
+		rec, err := h.record(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if rec.State != EvalJobStateRunning {
+			var resp *EvaluatorResponse
+			if rec.HasResponse {
+				if resp, err = h.store.Load(ctx, h.EvaluationId); err != nil {
+					return nil, err
+				}
+			}
+			return resp, rec.status().Err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Release cancels the job if it is still running and forgets it, freeing
+// its response. Its stored state, if any, remains.
+func (h EvalJobHandle) Release() {
+	if job, ok := h.job(); ok {
+		job.cancel()
+		evalJobs.Delete(h.JobId)
+	}
+}
+
+// jobObserver is an [EvalObserver] that records the progress of a job and
+// forwards all events to next.
+type jobObserver struct {
+	job  *evalJob
+	next EvalObserver
+}
+
+func (o *jobObserver) Observe(ctx context.Context, ev EvalEvent) {
+	job := o.job
+	job.mu.Lock()
+	switch ev := ev.(type) {
+	case EvaluationStarted:
+		job.status.Total = ev.DatasetSize
+	case ExampleCompleted, ExampleFailed:
+		job.status.Completed++
+	case EvaluationCompleted:
+		job.status.Completed = ev.Results
+	}
+	if job.store != nil && time.Since(job.saved) >= evalJobSaveInterval {
+		job.record.Total = job.status.Total
+		job.record.Completed = job.status.Completed
+		// Progress is saved on a best-effort basis; the final state is
+		// saved, and its errors reported, when the job finishes.
+		job.save(context.WithoutCancel(ctx))
+	}
+	job.mu.Unlock()
+	if o.next != nil {
+		o.next.Observe(ctx, ev)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// defineSteppedEvaluator defines an evaluator that waits for a value on
+// step before evaluating each example.
+func defineSteppedEvaluator(t *testing.T, r *registry.Registry, step <-chan struct{}) Evaluator {
+	t.Helper()
+	e, err := DefineEvaluator(r, "test", "stepped", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		select {
+		case <-step:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return testEvalFunc(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// waitForStatus polls the status of h until cond holds.
+func waitForStatus(t *testing.T, h EvalJobHandle, cond func(EvalJobStatus) bool) EvalJobStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s := h.Status()
+		if cond(s) {
+			return s
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for job status, last %+v", s)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStartEvaluationAsync(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := make(chan struct{})
+	e := defineSteppedEvaluator(t, r, step)
+
+	ds := Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "y"}, {TestCaseId: "c", Input: "z"}}
+	h, err := StartEvaluationAsync(context.Background(), e, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Release()
+	if h.EvaluationId == "" || h.Evaluator != "test/stepped" {
+		t.Errorf("got handle %+v, want an evaluation ID and evaluator name", h)
+	}
+
+	step <- struct{}{}
+	status := waitForStatus(t, h, func(s EvalJobStatus) bool { return s.Completed == 1 })
+	if status.State != EvalJobStateRunning || status.Total != 3 {
+		t.Errorf("got status %+v, want running with 3 examples", status)
+	}
+
+	// A handle restored from JSON refers to the same job.
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored EvalJobHandle
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	step <- struct{}{}
+	step <- struct{}{}
+	resp, err := restored.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(*resp), 3; got != want {
+		t.Errorf("got %d results, want %d", got, want)
+	}
+
+	// Jobs are forgotten once waited for.
+	if got := h.Status(); got.State != EvalJobStateUnknown {
+		t.Errorf("got state %v after Wait, want %v", got.State, EvalJobStateUnknown)
+	}
+	if _, err := h.Wait(context.Background()); !errors.Is(err, ErrEvalJobNotFound) {
+		t.Errorf("got error %v from a second Wait, want ErrEvalJobNotFound", err)
+	}
+}
+
+func TestEvalJobCancel(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := make(chan struct{})
+	e := defineSteppedEvaluator(t, r, step)
+
+	ds := Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "y"}, {TestCaseId: "c", Input: "z"}}
+	h, err := StartEvaluationAsync(context.Background(), e, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Release()
+	step <- struct{}{}
+	waitForStatus(t, h, func(s EvalJobStatus) bool { return s.Completed == 1 })
+	if err := h.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForStatus(t, h, func(s EvalJobStatus) bool { return s.State == EvalJobStateCanceled })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := h.Wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if resp == nil || len(*resp) == 3 {
+		t.Errorf("got response %v, want partial results", resp)
+	}
+}
+
+func TestUnknownEvalJob(t *testing.T) {
+	h := EvalJobHandle{JobId: "from-another-process"}
+	if got := h.Status(); got.State != EvalJobStateUnknown || !errors.Is(got.Err, ErrEvalJobNotFound) {
+		t.Errorf("got status %+v, want unknown", got)
+	}
+	if _, err := h.Wait(context.Background()); !errors.Is(err, ErrEvalJobNotFound) {
+		t.Errorf("got error %v, want ErrEvalJobNotFound", err)
+	}
+	if err := h.Cancel(); !errors.Is(err, ErrEvalJobNotFound) {
+		t.Errorf("got error %v, want ErrEvalJobNotFound", err)
+	}
+}
+
+func TestEvalJobStore(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := make(chan struct{})
+	e := defineSteppedEvaluator(t, r, step)
+	dir := t.TempDir()
+	store, err := NewFileEvaluationStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "y"}, {TestCaseId: "c", Input: "z"}}
+	h, err := StartEvaluationAsync(context.Background(), e, WithEvaluateDataset(&ds), WithEvaluateJobStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Release()
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate following the job from another process, which only knows
+	// the serialized handle and the store directory.
+	var restored EvalJobHandle
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	evalJobs.Delete(h.JobId)
+	if got := restored.Status(); got.State != EvalJobStateUnknown || !errors.Is(got.Err, ErrEvalJobNotFound) {
+		t.Errorf("got status %+v without a store, want unknown", got)
+	}
+	other, err := NewFileEvaluationStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored = restored.WithStore(other)
+	if got := restored.Status(); got.State != EvalJobStateRunning {
+		t.Errorf("got state %v from the store, want %v", got.State, EvalJobStateRunning)
+	}
+	if err := restored.Cancel(); !errors.Is(err, ErrEvalJobNotFound) {
+		t.Errorf("got error %v canceling from another process, want ErrEvalJobNotFound", err)
+	}
+
+	for range ds {
+		step <- struct{}{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := restored.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(*resp), 3; got != want {
+		t.Errorf("got %d results, want %d", got, want)
+	}
+	if got := restored.Status(); got.State != EvalJobStateSucceeded || got.Total != 3 || got.Completed != 3 {
+		t.Errorf("got status %+v, want succeeded with 3 examples completed", got)
+	}
+
+	// A handle for another job of the same evaluation is not confused with
+	// this one.
+	stale := restored
+	stale.JobId = "other"
+	if _, err := stale.Wait(ctx); !errors.Is(err, ErrEvalJobNotFound) {
+		t.Errorf("got error %v, want ErrEvalJobNotFound", err)
+	}
+}
+
+func TestEvalJobStoreFailure(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := DefineBatchEvaluator(r, "test", "broken", &evalOptions, func(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
+		return nil, errors.New("broken")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewInMemoryEvaluationStore()
+	ds := Dataset{{TestCaseId: "a", Input: "x"}}
+	h, err := StartEvaluationAsync(context.Background(), e, WithEvaluateDataset(&ds), WithEvaluateJobStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Wait(context.Background()); err == nil {
+		t.Fatal("got nil, want error")
+	}
+
+	// The job is forgotten in memory, but its stored state remains.
+	resp, err := h.Wait(context.Background())
+	if resp != nil || err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("got %v, %v, want the stored error", resp, err)
+	}
+	if got := h.Status(); got.State != EvalJobStateFailed {
+		t.Errorf("got state %v, want %v", got.State, EvalJobStateFailed)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// bertScorePassThreshold is the lowest BERTScore that passes.
+const bertScorePassThreshold = 0.8
+
+// DefineBERTScoreEvaluator registers an evaluator named "bertscore" that
+// compares the Output and Reference of each [Example] in the manner of
+// BERTScore, reporting "precision", "recall" and "f1" scores.
+//
+// BERTScore greedily matches each token of one text to the most similar
+// token of the other, using contextual token embeddings from a BERT model.
+// This evaluator is an approximation: it matches sentences instead of
+// tokens, and embeds each sentence on its own with embedder. Precision is
+// the mean similarity of each output sentence to its closest reference
+// sentence, recall is the same from the reference to the output, and F1 is
+// their harmonic mean. Similarities are cosine similarities clamped to
+// [0, 1]. Scores are therefore not comparable to those of the original
+// metric, and depend on the embedder. Each score passes from 0.8. If opts
+// is nil, default options are used.
+func DefineBERTScoreEvaluator(r *registry.Registry, provider string, embedder Embedder, opts *EvaluatorOptions) (Evaluator, error) {
+	if embedder == nil {
+		return nil, errors.New("ai.DefineBERTScoreEvaluator: embedder is required")
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName:    "BERTScore",
+			Definition:     "Approximates BERTScore between the output and reference with sentence embeddings",
+			RequiredFields: []string{"Output", "Reference"},
+		}
+	}
+
+	return DefineEvaluator(r, provider, "bertscore", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		if req.Input.Reference == nil {
+			return nil, errors.New("reference was not provided")
+		}
+		output, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, fmt.Errorf("output: %w", err)
+		}
+		reference, err := exampleText(req.Input.Reference)
+		if err != nil {
+			return nil, fmt.Errorf("reference: %w", err)
+		}
+		candidates, references := splitSentences(output), splitSentences(reference)
+		if len(candidates) == 0 || len(references) == 0 {
+			return nil, errors.New("output and reference must both contain text")
+		}
+
+		vectors, err := embedTexts(ctx, embedder, slices.Concat(candidates, references))
+		if err != nil {
+			return nil, err
+		}
+		sims := make([][]float64, len(candidates))
+		for i := range candidates {
+			sims[i] = make([]float64, len(references))
+			for j := range references {
+				sims[i][j] = min(max(cosineSimilarity(vectors[i], vectors[len(candidates)+j]), 0), 1)
+			}
+		}
+		precision, recall := greedyMatch(sims)
+		f1 := 0.0
+		if precision+recall > 0 {
+			f1 = 2 * precision * recall / (precision + recall)
+		}
+
+		var scores []Score
+		for _, s := range []struct {
+			id    string
+			value float64
+		}{
+			{"precision", precision},
+			{"recall", recall},
+			{"f1", f1},
+		} {
+			scores = append(scores, Score{
+				Id:     s.id,
+				Score:  s.value,
+				Status: passStatus(s.value >= bertScorePassThreshold).String(),
+			})
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: scores,
+		}, nil
+	})
+}
+
+// splitSentences returns the sentences of text, trimmed. Fragments without
+// letters or digits are dropped.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	add := func(s string) {
+		s = strings.TrimSpace(s)
+		if strings.IndexFunc(s, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			sentences = append(sentences, s)
+		}
+	}
+	for i, r := range text {
+		if isSentenceEnd(r) {
+			end := i + len(string(r))
+			add(text[start:end])
+			start = end
+		}
+	}
+	add(text[start:])
+	return sentences
+}
+
+// embedTexts returns the embeddings of texts from embedder, in order.
+func embedTexts(ctx context.Context, embedder Embedder, texts []string) ([][]float32, error) {
+	docs := make([]*Document, len(texts))
+	for i, text := range texts {
+		docs[i] = DocumentFromText(text, nil)
+	}
+	resp, err := embedder.Embed(ctx, &EmbedRequest{Documents: docs})
+	if err != nil {
+		return nil, fmt.Errorf("embedder %q: %w", embedder.Name(), err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedder %q returned %d embeddings for %d texts", embedder.Name(), len(resp.Embeddings), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for i, e := range resp.Embeddings {
+		vectors[i] = e.Embedding
+	}
+	return vectors, nil
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 if they
+// differ in length or either is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+// greedyMatch returns the mean of the row maxima and the mean of the column
+// maxima of the similarity matrix sims, which has a row per candidate and a
+// column per reference.
+func greedyMatch(sims [][]float64) (precision, recall float64) {
+	colMax := make([]float64, len(sims[0]))
+	for _, row := range sims {
+		rowMax := 0.0
+		for j, s := range row {
+			rowMax = max(rowMax, s)
+			colMax[j] = max(colMax[j], s)
+		}
+		precision += rowMax
+	}
+	for _, s := range colMax {
+		recall += s
+	}
+	return precision / float64(len(sims)), recall / float64(len(colMax))
+}
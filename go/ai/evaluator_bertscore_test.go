@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitSentences(t *testing.T) {
+	got := splitSentences("The cat sat. Did it?! Yes... 猫です。")
+	want := []string{"The cat sat.", "Did it?", "Yes.", "猫です。"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBERTScoreEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sentences about cats and dogs are orthogonal to each other and to
+	// everything else.
+	embedder := DefineEmbedder(r, "test", "animals", func(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+		resp := &EmbedResponse{}
+		for _, doc := range req.Documents {
+			text := doc.Content[0].Text
+			v := []float32{0, 0, 1}
+			switch {
+			case strings.Contains(text, "cat"):
+				v = []float32{1, 0, 0}
+			case strings.Contains(text, "dog"):
+				v = []float32{0, 2, 0}
+			}
+			resp.Embeddings = append(resp.Embeddings, &DocumentEmbedding{Embedding: v})
+		}
+		return resp, nil
+	})
+	e, err := DefineBERTScoreEvaluator(r, "test", embedder, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "same", Output: "A cat sat. A dog ran.", Reference: "The dog ran. The cat sat."},
+		{TestCaseId: "extra", Output: "The cat sat. The dog ran.", Reference: "A cat sat."},
+		{TestCaseId: "empty", Output: "...", Reference: "A cat sat."},
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err == nil {
+		t.Error("got nil, want error for output without text")
+	}
+	results := indexResults(resp)
+	for id, want := range map[string]map[string]float64{
+		"same":  {"precision": 1, "recall": 1, "f1": 1},
+		"extra": {"precision": 0.5, "recall": 1, "f1": 2.0 / 3},
+	} {
+		got := map[string]float64{}
+		for _, s := range results[id].Evaluation {
+			got[s.Id] = s.Score.(float64)
+		}
+		if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b float64) bool { return math.Abs(a-b) < 1e-9 })); diff != "" {
+			t.Errorf("%s: mismatch (-want +got):\n%s", id, diff)
+		}
+	}
+	if s := results["extra"].Evaluation; s[0].Status != "fail" || s[1].Status != "pass" {
+		t.Errorf("got statuses %q and %q, want precision to fail and recall to pass", s[0].Status, s[1].Status)
+	}
+	if results["empty"].Evaluation[0].Error == "" {
+		t.Error("got no error for output without text")
+	}
+}
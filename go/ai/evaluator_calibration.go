@@ -0,0 +1,278 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// BiasReport describes how the scores of a candidate evaluator differ from
+// those of a reference evaluator on the same dataset. Each evaluator's score
+// for an example is the mean of the numeric scores in its result.
+type BiasReport struct {
+	Reference string `json:"reference"`
+	Candidate string `json:"candidate"`
+	// Examples is the number of examples that both evaluators scored.
+	Examples int `json:"examples"`
+	// MeanBias is the mean of candidate minus reference score.
+	MeanBias float64 `json:"meanBias"`
+	// Variance is the sample variance of candidate minus reference score.
+	Variance float64 `json:"variance"`
+	// Correlation is the Pearson correlation between the two evaluators'
+	// scores. It is 0 if either evaluator gave every example the same score.
+	Correlation float64 `json:"correlation"`
+	// Factor is the ratio of the mean reference score to the mean candidate
+	// score. Multiplying candidate scores by Factor brings their mean in line
+	// with the reference; see [CalibratedEvaluator].
+	Factor float64 `json:"factor"`
+}
+
+// CalibrateBias runs reference and candidate concurrently on ds and measures
+// the bias of candidate relative to reference. Examples without a TestCaseId
+// are assigned one before evaluation. Examples that either evaluator failed
+// to score numerically are left out.
+func CalibrateBias(ctx context.Context, reference, candidate Evaluator, ds Dataset, opts ...EvaluateOption) (*BiasReport, error) {
+	if reference.Name() == candidate.Name() {
+		return nil, errors.New("ai.CalibrateBias: evaluators must be distinct")
+	}
+	ds = withTestCaseIds(ds)
+	resps, err := evaluateAll(ctx, ds, []Evaluator{reference, candidate}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("ai.CalibrateBias: %w", err)
+	}
+
+	refById, candById := indexResults(resps[0]), indexResults(resps[1])
+	var refScores, candScores, diffs []float64
+	for _, ex := range ds {
+		refRes, okRef := refById[ex.TestCaseId]
+		candRes, okCand := candById[ex.TestCaseId]
+		if !okRef || !okCand {
+			continue
+		}
+		ref, errRef := meanScore(refRes)
+		cand, errCand := meanScore(candRes)
+		if errRef != nil || errCand != nil {
+			continue
+		}
+		refScores = append(refScores, ref)
+		candScores = append(candScores, cand)
+		diffs = append(diffs, cand-ref)
+	}
+	if len(diffs) == 0 {
+		return nil, errors.New("ai.CalibrateBias: no examples were scored by both evaluators")
+	}
+
+	report := &BiasReport{
+		Reference:   reference.Name(),
+		Candidate:   candidate.Name(),
+		Examples:    len(diffs),
+		Correlation: pearson(candScores, refScores),
+		Factor:      1,
+	}
+	var sd float64
+	report.MeanBias, sd = meanStdDev(diffs)
+	report.Variance = sd * sd
+	refMean, _ := meanStdDev(refScores)
+	if candMean, _ := meanStdDev(candScores); candMean != 0 {
+		report.Factor = refMean / candMean
+	}
+	return report, nil
+}
+
+// CalibratedEvaluator returns an [Evaluator] that runs inner and multiplies
+// each numeric score in its results by factor, typically [BiasReport.Factor].
+// Scores that are not numeric, and score statuses, are left unchanged.
+func CalibratedEvaluator(inner Evaluator, factor float64) Evaluator {
+	return &calibratedEvaluator{inner: inner, factor: factor}
+}
+
+type calibratedEvaluator struct {
+	inner  Evaluator
+	factor float64
+}
+
+func (e *calibratedEvaluator) Name() string {
+	return e.inner.Name()
+}
+
+func (e *calibratedEvaluator) Evaluate(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
+	resp, err := e.inner.Evaluate(ctx, req)
+	if resp == nil {
+		return nil, err
+	}
+	out := make(EvaluatorResponse, len(*resp))
+	for i, res := range *resp {
+		scores := make([]Score, len(res.Evaluation))
+		for j, s := range res.Evaluation {
+			if v, nerr := s.Normalize(); nerr == nil {
+				s.Score = v * e.factor
+			}
+			scores[j] = s
+		}
+		res.Evaluation = scores
+		out[i] = res
+	}
+	return &out, err
+}
+
+// CalibrationData compares the predicted probability of passing with the
+// actual pass rate of the results of a probabilistic evaluator. See
+// [CalibrationCurve].
+type CalibrationData struct {
+	// Bins holds the upper edge of each equal-width probability bin; bin i
+	// covers (Bins[i-1], Bins[i]], and the first bin includes 0.
+	Bins []float64 `json:"bins"`
+	// ActualPassRates is the fraction of passing results in each bin.
+	ActualPassRates []float64 `json:"actualPassRates"`
+	// MeanConfidences is the mean predicted probability in each bin.
+	MeanConfidences []float64 `json:"meanConfidences"`
+	// Counts is the number of results in each bin. The rate and mean
+	// confidence of an empty bin are 0.
+	Counts []int `json:"counts"`
+	// ExpectedCalibrationError is the mean of |actual - confidence| over
+	// the bins, weighted by their counts. 0 means perfectly calibrated.
+	ExpectedCalibrationError float64 `json:"expectedCalibrationError"`
+}
+
+// CalibrationCurve groups the scores with Id scoreId in resp into bins of
+// equal width by their predicted probability of passing, and computes the
+// actual pass rate of each bin.
+//
+// The predicted probability is the score's [Score.Confidence] if set, and
+// otherwise its numeric value, which must be in [0, 1]. Whether the result
+// actually passed is taken from the most recent human annotation of the
+// score, if any, and otherwise from its status. Scores that are not numeric
+// or whose outcome is unknown are skipped.
+func CalibrationCurve(resp *EvaluatorResponse, scoreId string, bins int) (*CalibrationData, error) {
+	if bins < 1 {
+		return nil, fmt.Errorf("ai.CalibrationCurve: bins must be positive, got %d", bins)
+	}
+	if resp == nil {
+		return nil, errors.New("ai.CalibrationCurve: response is nil")
+	}
+	cd := &CalibrationData{
+		Bins:            make([]float64, bins),
+		ActualPassRates: make([]float64, bins),
+		MeanConfidences: make([]float64, bins),
+		Counts:          make([]int, bins),
+	}
+	for i := range bins {
+		cd.Bins[i] = float64(i+1) / float64(bins)
+	}
+	total := 0
+	for _, res := range *resp {
+		for _, s := range res.Evaluation {
+			if s.Id != scoreId {
+				continue
+			}
+			var p float64
+			if s.Confidence != nil {
+				p = *s.Confidence
+			} else {
+				v, err := s.Normalize()
+				if err != nil {
+					continue
+				}
+				p = v
+			}
+			if p < 0 || p > 1 {
+				return nil, fmt.Errorf("ai.CalibrationCurve: test case %q: probability %v is not in [0, 1]", res.TestCaseId, p)
+			}
+			status := s.Status
+			for _, ann := range res.HumanAnnotation {
+				if ann.Score.Id == scoreId && ann.Score.Status != "" {
+					status = ann.Score.Status
+				}
+			}
+			var pass float64
+			switch status {
+			case ScoreStatusPass.String():
+				pass = 1
+			case ScoreStatusFail.String():
+			default:
+				continue
+			}
+			b := max(int(math.Ceil(p*float64(bins)))-1, 0)
+			cd.Counts[b]++
+			cd.ActualPassRates[b] += pass
+			cd.MeanConfidences[b] += p
+			total++
+		}
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("ai.CalibrationCurve: no results with a numeric %q score and known outcome", scoreId)
+	}
+	for i, n := range cd.Counts {
+		if n == 0 {
+			continue
+		}
+		cd.ActualPassRates[i] /= float64(n)
+		cd.MeanConfidences[i] /= float64(n)
+		cd.ExpectedCalibrationError += float64(n) / float64(total) * math.Abs(cd.ActualPassRates[i]-cd.MeanConfidences[i])
+	}
+	return cd, nil
+}
+
+// ASCIIChart renders the calibration curve as text, one row per bin, for
+// terminals and CI logs. Each bar shows the actual pass rate and '|' marks
+// the mean confidence, which the bar would reach if the evaluator were
+// perfectly calibrated.
+func (c *CalibrationData) ASCIIChart() string {
+	const width = 40
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-9s  %5s  %-*s  %s\n", "bin", "n", width, "actual pass rate", "actual/confidence")
+	lo := 0.0
+	for i, hi := range c.Bins {
+		bar := []rune(strings.Repeat(" ", width))
+		if c.Counts[i] > 0 {
+			for j := range int(math.Round(c.ActualPassRates[i] * width)) {
+				bar[j] = '#'
+			}
+			bar[min(int(math.Round(c.MeanConfidences[i]*width)), width-1)] = '|'
+		}
+		row := fmt.Sprintf("%.2f-%.2f  %5d  %s", lo, hi, c.Counts[i], string(bar))
+		if c.Counts[i] > 0 {
+			row += fmt.Sprintf("  %.2f/%.2f", c.ActualPassRates[i], c.MeanConfidences[i])
+		}
+		b.WriteString(strings.TrimRight(row, " ") + "\n")
+		lo = hi
+	}
+	fmt.Fprintf(&b, "expected calibration error: %.4f\n", c.ExpectedCalibrationError)
+	return b.String()
+}
+
+// pearson returns the Pearson correlation coefficient of x and y, or 0 if
+// either has no variance.
+func pearson(x, y []float64) float64 {
+	mx, _ := meanStdDev(x)
+	my, _ := meanStdDev(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
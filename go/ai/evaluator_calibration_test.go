@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCalibrateBias(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reference := defineTableEvaluator(t, r, "reference", map[string]float64{"q1": 0.2, "q2": 0.4, "q3": 0.6})
+	// The candidate scores every example 50% higher than the reference.
+	candidate := defineTableEvaluator(t, r, "candidate", map[string]float64{"q1": 0.3, "q2": 0.6, "q3": 0.9})
+
+	ds := Dataset{{Input: "q1"}, {Input: "q2"}, {Input: "q3"}}
+	report, err := CalibrateBias(context.Background(), reference, candidate, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+	if got, want := report.Examples, 3; got != want {
+		t.Errorf("got %d examples, want %d", got, want)
+	}
+	if got, want := report.MeanBias, 0.2; !near(got, want) {
+		t.Errorf("got mean bias %v, want %v", got, want)
+	}
+	if got, want := report.Variance, 0.01; !near(got, want) {
+		t.Errorf("got variance %v, want %v", got, want)
+	}
+	if got, want := report.Correlation, 1.0; !near(got, want) {
+		t.Errorf("got correlation %v, want %v", got, want)
+	}
+	if got, want := report.Factor, 2.0/3; !near(got, want) {
+		t.Errorf("got factor %v, want %v", got, want)
+	}
+
+	calibrated := CalibratedEvaluator(candidate, report.Factor)
+	resp, err := Evaluate(context.Background(), calibrated, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{0.2, 0.4, 0.6} {
+		if got := (*resp)[i].Evaluation[0].Score.(float64); !near(got, want) {
+			t.Errorf("example %d: got calibrated score %v, want %v", i, got, want)
+		}
+	}
+
+	if _, err := CalibrateBias(context.Background(), reference, reference, ds); err == nil {
+		t.Error("got nil, want error for identical evaluators")
+	}
+}
+
+func TestCalibrationCurve(t *testing.T) {
+	zero, low := 0.0, 0.2
+	resp := EvaluatorResponse{
+		// Predicted 0.9: 1 of 2 passed.
+		passFail("a", true, 0.9),
+		passFail("b", false, 0.9),
+		// Predicted 0.2 via confidence: passed.
+		{TestCaseId: "c", Evaluation: []Score{{Id: "s", Score: "yes", Status: "pass", Confidence: &low}}},
+		// Predicted 0 via a reported confidence of 0, although scored 1: failed.
+		{TestCaseId: "h", Evaluation: []Score{{Id: "s", Score: 1.0, Status: "fail", Confidence: &zero}}},
+		// Predicted 0.3, annotated as failing by a reviewer.
+		{TestCaseId: "d", Evaluation: []Score{{Id: "s", Score: 0.3, Status: "pass"}}, HumanAnnotation: []HumanScore{{Score: Score{Id: "s", Status: "fail"}}}},
+		// Skipped: not numeric, unknown status, other score.
+		passFail("e", true, "n/a"),
+		{TestCaseId: "f", Evaluation: []Score{{Id: "s", Score: 0.5}}},
+		{TestCaseId: "g", Evaluation: []Score{{Id: "other", Score: 0.5, Status: "pass"}}},
+	}
+	cd, err := CalibrationCurve(&resp, "s", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CalibrationData{
+		Bins:            []float64{0.5, 1},
+		ActualPassRates: []float64{1.0 / 3, 0.5},
+		MeanConfidences: []float64{0.5 / 3, 0.9},
+		Counts:          []int{3, 2},
+		// 0.6*|1/3-1/6| + 0.4*|0.5-0.9|
+		ExpectedCalibrationError: 0.26,
+	}
+	if diff := cmp.Diff(want, cd, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	chart := cd.ASCIIChart()
+	if !strings.Contains(chart, "0.50-1.00      2  ####################                |     0.50/0.90\n") || !strings.Contains(chart, "expected calibration error: 0.2600") {
+		t.Errorf("unexpected chart:\n%s", chart)
+	}
+	// The header of the bars is aligned with them.
+	lines := strings.Split(chart, "\n")
+	if got, want := strings.Index(lines[0], "actual pass rate"), strings.Index(lines[2], "#"); got != want {
+		t.Errorf("bar header at column %d, bars at column %d:\n%s", got, want, chart)
+	}
+
+	if _, err := CalibrationCurve(&resp, "s", 0); err == nil {
+		t.Error("got nil, want error for zero bins")
+	}
+	if _, err := CalibrationCurve(&resp, "missing", 10); err == nil {
+		t.Error("got nil, want error for no matching scores")
+	}
+	bad := EvaluatorResponse{passFail("a", true, 3)}
+	if _, err := CalibrationCurve(&bad, "s", 10); err == nil {
+		t.Error("got nil, want error for a score above 1")
+	}
+}
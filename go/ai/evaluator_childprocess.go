@@ -0,0 +1,290 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// DefineChildProcessEvaluator registers an [Evaluator] implemented by a
+// separate program, which lets evaluators be written in other languages.
+// command is the program and its arguments. If timeout is positive, an
+// example that the program takes longer than timeout to answer fails and
+// the program is restarted. If opts is nil, default options are used.
+//
+// The program is started on first use and kept running for later examples.
+// It communicates with newline-delimited JSON:
+//
+//   - For each example, one [EvaluatorCallbackRequest] is written to the
+//     program's standard input as a single line.
+//   - The program must answer each request with a single line on its
+//     standard output: either an [EvaluatorCallbackResponse] or, if it could
+//     not evaluate the example, an object with a string "error" field.
+//   - The program should exit when its standard input is closed.
+//
+// Anything the program writes to standard error is included in the error
+// reported if it exits unexpectedly. If it exits while evaluating an
+// example, it is restarted and the example is retried once.
+//
+// The program runs until [ChildProcessEvaluator.Close] is called, which
+// should be done once the evaluator is no longer needed.
+//
+// A reference implementation of the protocol for Python is in the
+// sdk-python directory of the Genkit Go module.
+func DefineChildProcessEvaluator(r *registry.Registry, provider, name string, command []string, timeout time.Duration, opts *EvaluatorOptions) (ChildProcessEvaluator, error) {
+	if len(command) == 0 {
+		return nil, errors.New("ai.DefineChildProcessEvaluator: command is required")
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: name,
+			Definition:  fmt.Sprintf("Evaluates examples with the program %q", command[0]),
+		}
+	}
+	p := &childProcess{command: command, timeout: timeout}
+	e, err := DefineEvaluator(r, provider, name, opts, p.evaluate)
+	if err != nil {
+		return nil, err
+	}
+	return &childProcessEvaluator{Evaluator: e, p: p}, nil
+}
+
+// ChildProcessEvaluator is an [Evaluator] implemented by a separate program,
+// defined with [DefineChildProcessEvaluator].
+type ChildProcessEvaluator interface {
+	Evaluator
+	// Close stops the program. It closes the program's standard input and
+	// kills the program if it has not exited within five seconds. Close waits
+	// for the example being evaluated, if any, and later examples fail.
+	Close() error
+}
+
+type childProcessEvaluator struct {
+	Evaluator
+	p *childProcess
+}
+
+func (e *childProcessEvaluator) Close() error {
+	e.p.close()
+	return nil
+}
+
+// childCloseTimeout is how long Close waits for the program to exit after
+// closing its standard input.
+const childCloseTimeout = 5 * time.Second
+
+var (
+	// errChildExited is returned by childProcess.roundTrip when the program
+	// exits before answering.
+	errChildExited = errors.New("evaluator process exited")
+	// errChildClosed is returned for examples evaluated after Close.
+	errChildClosed = errors.New("evaluator process was closed")
+)
+
+// childProcess is a running evaluator program that is restarted as needed.
+type childProcess struct {
+	command []string
+	timeout time.Duration
+
+	mu     sync.Mutex // serializes requests
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	lines  chan []byte   // lines read from stdout
+	done   chan struct{} // closed when stdout is exhausted
+	quit   chan struct{} // closed by stop
+	stderr *tailBuffer
+	closed bool
+}
+
+// childResponse is a line written by the program.
+type childResponse struct {
+	EvaluationResult
+	Error *string `json:"error"`
+}
+
+func (p *childProcess) evaluate(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+	line, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, errChildClosed
+	}
+	out, err := p.roundTrip(ctx, line)
+	if errors.Is(err, errChildExited) {
+		out, err = p.roundTrip(ctx, line)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resp childResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response from evaluator process: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, errors.New(*resp.Error)
+	}
+	if resp.TestCaseId == "" {
+		resp.TestCaseId = req.Input.TestCaseId
+	}
+	return &resp.EvaluationResult, nil
+}
+
+// roundTrip writes line to the program, starting it if necessary, and
+// returns the line it answers with.
+func (p *childProcess) roundTrip(ctx context.Context, line []byte) ([]byte, error) {
+	if p.cmd == nil {
+		if err := p.start(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.stdin.Write(append(line, '\n')); err != nil {
+		return nil, p.exited()
+	}
+
+	var timeout <-chan time.Time
+	if p.timeout > 0 {
+		timer := time.NewTimer(p.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case out := <-p.lines:
+		return out, nil
+	case <-p.done:
+		return nil, p.exited()
+	case <-timeout:
+		p.stop()
+		return nil, fmt.Errorf("evaluator process did not answer within %v", p.timeout)
+	case <-ctx.Done():
+		p.stop()
+		return nil, ctx.Err()
+	}
+}
+
+func (p *childProcess) start() error {
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	p.stderr = &tailBuffer{max: 4096}
+	cmd.Stderr = p.stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting evaluator process: %w", err)
+	}
+	p.cmd, p.stdin = cmd, stdin
+	p.lines, p.done, p.quit = make(chan []byte), make(chan struct{}), make(chan struct{})
+
+	lines, done, quit := p.lines, p.done, p.quit
+	go func() {
+		defer close(done)
+		sc := bufio.NewScanner(stdout)
+		sc.Buffer(nil, 64<<20)
+		for sc.Scan() {
+			select {
+			case lines <- append([]byte(nil), sc.Bytes()...):
+			case <-quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// exited cleans up after the program exited unexpectedly and returns an
+// error wrapping errChildExited.
+func (p *childProcess) exited() error {
+	p.stop()
+	if stderr := p.stderr.String(); stderr != "" {
+		return fmt.Errorf("%w: %s", errChildExited, stderr)
+	}
+	return errChildExited
+}
+
+// stop kills the program, if it is running, so that the next request starts
+// a new one.
+func (p *childProcess) stop() {
+	if p.cmd == nil {
+		return
+	}
+	close(p.quit)
+	p.stdin.Close()
+	p.cmd.Process.Kill()
+	p.cmd.Wait()
+	p.cmd = nil
+}
+
+// close stops the program, giving it time to exit by itself, and prevents
+// it from being started again.
+func (p *childProcess) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	if p.cmd == nil {
+		return
+	}
+	p.stdin.Close()
+	timer := time.NewTimer(childCloseTimeout)
+	defer timer.Stop()
+	select {
+	case <-p.done:
+	case <-timer.C:
+	}
+	p.stop()
+}
+
+// tailBuffer is an [io.Writer] that keeps the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(data []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, data...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(data), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
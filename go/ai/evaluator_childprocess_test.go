@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// TestChildProcessHelper is not a real test. It is the evaluator program
+// started by TestChildProcessEvaluator, which runs the test binary with
+// GENKIT_TEST_CHILD_EVALUATOR set.
+func TestChildProcessHelper(t *testing.T) {
+	if os.Getenv("GENKIT_TEST_CHILD_EVALUATOR") == "" {
+		t.Skip("not running as an evaluator program")
+	}
+	sc := bufio.NewScanner(os.Stdin)
+	for sc.Scan() {
+		var req EvaluatorCallbackRequest
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		switch req.Input.Input {
+		case "crash":
+			fmt.Fprintln(os.Stderr, "crashing on purpose")
+			os.Exit(1)
+		case "hang":
+			time.Sleep(time.Minute)
+		case "error":
+			fmt.Println(`{"error": "cannot evaluate"}`)
+		default:
+			fmt.Printf(`{"evaluation": [{"id": "child", "score": 1, "status": "pass", "details": {"pid": %d}}]}`+"\n", os.Getpid())
+		}
+	}
+	os.Exit(0)
+}
+
+func TestChildProcessEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GENKIT_TEST_CHILD_EVALUATOR", "1")
+	e, err := DefineChildProcessEvaluator(r, "test", "child", []string{os.Args[0], "-test.run=^TestChildProcessHelper$"}, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	ds := Dataset{
+		{TestCaseId: "a", Input: "ok"},
+		{TestCaseId: "b", Input: "ok"},
+		{TestCaseId: "c", Input: "error"},
+		{TestCaseId: "d", Input: "crash"},
+		{TestCaseId: "e", Input: "hang"},
+		{TestCaseId: "f", Input: "ok"},
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	var evalErr EvaluatorError
+	if !errors.As(err, &evalErr) {
+		t.Fatalf("got error %v, want EvaluatorError", err)
+	}
+	results := indexResults(resp)
+
+	pid := func(id string) any { return results[id].Evaluation[0].Details["pid"] }
+	if results["a"].Evaluation[0].Status != "pass" || results["a"].TestCaseId != "a" {
+		t.Errorf("got %+v, want a passing result for a", results["a"])
+	}
+	if pid("a") != pid("b") {
+		t.Error("process was not reused between examples")
+	}
+	for id, want := range map[string]string{"c": "cannot evaluate", "d": "crashing on purpose", "e": "did not answer"} {
+		if got := results[id].Evaluation[0].Error; !strings.Contains(got, want) {
+			t.Errorf("test case %s: got error %q, want it to contain %q", id, got, want)
+		}
+	}
+	if results["f"].Evaluation[0].Status != "pass" || pid("f") == pid("a") {
+		t.Errorf("got %+v, want a passing result from a restarted process", results["f"])
+	}
+}
+
+func TestChildProcessEvaluatorClose(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GENKIT_TEST_CHILD_EVALUATOR", "1")
+	// Race-enabled programs otherwise sleep for a second before exiting.
+	t.Setenv("GORACE", "atexit_sleep_ms=0")
+	e, err := DefineChildProcessEvaluator(r, "test", "child", []string{os.Args[0], "-test.run=^TestChildProcessHelper$"}, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{TestCaseId: "a", Input: "ok"}}
+	if _, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds)); err != nil {
+		t.Fatal(err)
+	}
+	cmd := e.(*childProcessEvaluator).p.cmd
+	if cmd == nil {
+		t.Fatal("process is not running")
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The program exits by itself once its standard input is closed.
+	if cmd.ProcessState == nil || !cmd.ProcessState.Exited() || !cmd.ProcessState.Success() {
+		t.Errorf("got process state %v, want a clean exit", cmd.ProcessState)
+	}
+
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err == nil || !strings.Contains((*resp)[0].Evaluation[0].Error, "closed") {
+		t.Errorf("got %v, want an error after Close", err)
+	}
+}
+
+func TestChildProcessEvaluatorPythonSDK(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not found")
+	}
+	sdk, err := filepath.Abs(filepath.Join("..", "sdk-python"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(t.TempDir(), "exact_match.py")
+	err = os.WriteFile(script, []byte(`import sys
+sys.path.insert(0, sys.argv[1])
+from genkit_eval import serve
+
+def exact_match(request):
+    example = request['input']
+    if 'reference' not in example:
+        raise ValueError('reference is required')
+    ok = example.get('output') == example['reference']
+    return {'evaluation': [{'id': 'exact_match', 'score': 1.0 if ok else 0.0, 'status': 'pass' if ok else 'fail'}]}
+
+serve(exact_match)
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := DefineChildProcessEvaluator(r, "python", "exact_match", []string{python, script, sdk}, 10*time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	ds := Dataset{
+		{TestCaseId: "same", Input: "q", Output: "4", Reference: "4"},
+		{TestCaseId: "different", Input: "q", Output: "5", Reference: "4"},
+		{TestCaseId: "missing", Input: "q", Output: "4"},
+	}
+	resp, _ := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	results := indexResults(resp)
+	for id, want := range map[string]string{"same": "pass", "different": "fail", "missing": "fail"} {
+		if got := results[id].Evaluation[0].Status; got != want {
+			t.Errorf("test case %s: got status %q, want %q", id, got, want)
+		}
+	}
+	if got := results["missing"].Evaluation[0].Error; !strings.Contains(got, "ValueError: reference is required") {
+		t.Errorf("got error %q, want the Python exception", got)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// CitationCheck is the verification of one citation, reported by
+// [DefineCitationAccuracyEvaluator].
+type CitationCheck struct {
+	// Marker is the citation as written, such as "[1]".
+	Marker string `json:"marker"`
+	// Claim is the sentence the citation is attached to, without citations.
+	Claim string `json:"claim"`
+	// Source is the 1-based index in Context of the cited document, or 0 if
+	// the marker does not refer to a document in Context.
+	Source int `json:"source"`
+	// Supported reports whether the cited document supports the claim.
+	Supported bool   `json:"supported"`
+	Reasoning string `json:"reasoning,omitempty"`
+}
+
+// citationVerdict is the structured output requested from the model.
+type citationVerdict struct {
+	Supported bool   `json:"supported"`
+	Reasoning string `json:"reasoning"`
+}
+
+var (
+	// citationSentenceRe matches a sentence together with any citations
+	// that follow its final punctuation.
+	citationSentenceRe = regexp.MustCompile(`[^.!?]+[.!?]*(?:\s*\[[^\[\]]+\])*`)
+	citationMarkerRe   = regexp.MustCompile(`\[([^\[\]]+)\]`)
+	// citationPunctRe matches the space left before punctuation when a
+	// citation is removed.
+	citationPunctRe = regexp.MustCompile(`\s+([.!?,;:])`)
+	// citationLabelRe matches the citation labels that refer to a context
+	// document: a number or a letter, optionally preceded by a word such as
+	// "Source".
+	citationLabelRe = regexp.MustCompile(`(?i)^(?:source|doc|document|ref|reference)?\s*#?\s*(\d+|[a-z])$`)
+)
+
+// DefineCitationAccuracyEvaluator registers an evaluator that checks the
+// citations in the Output of each [Example] against its Context.
+//
+// Citations are bracketed markers such as "[1]" or "[Source A]". Numbers
+// refer to context documents counting from 1, and letters count from "A".
+// The claim a citation supports is the sentence it appears in or directly
+// follows. For each citation that refers to a context document, model is
+// asked whether the document supports the claim; citations of documents
+// that do not exist are inaccurate.
+//
+// The score is the fraction of accurate citations. The example passes only
+// if it has citations and all of them are accurate. The result of each check
+// is reported as a [CitationCheck] in the "citations" key of
+// [Score.Details]. If opts is nil, default options are used.
+func DefineCitationAccuracyEvaluator(r *registry.Registry, provider, name string, model Model, opts *EvaluatorOptions) (Evaluator, error) {
+	if model == nil {
+		return nil, errors.New("ai.DefineCitationAccuracyEvaluator: model is required")
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName:    "Citation Accuracy",
+			Definition:     "Checks that citations in the output refer to context documents that support the cited claims",
+			RequiredFields: []string{"Output", "Context"},
+		}
+	}
+
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		output, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+
+		checks := extractCitations(output)
+		accurate := 0
+		for i := range checks {
+			c := &checks[i]
+			if c.Source == 0 || c.Source > len(req.Input.Context) {
+				c.Source = 0
+				c.Reasoning = "no such context document"
+				continue
+			}
+			doc, err := exampleText(req.Input.Context[c.Source-1])
+			if err != nil {
+				return nil, fmt.Errorf("context %d: %w", c.Source, err)
+			}
+			v, err := verifyCitation(ctx, r, model, doc, c.Claim)
+			if err != nil {
+				return nil, err
+			}
+			c.Supported, c.Reasoning = v.Supported, v.Reasoning
+			if c.Supported {
+				accurate++
+			}
+		}
+
+		score := Score{
+			Id:      name,
+			Score:   0.0,
+			Status:  passStatus(len(checks) > 0 && accurate == len(checks)).String(),
+			Details: map[string]any{"citations": checks},
+		}
+		if len(checks) > 0 {
+			score.Score = float64(accurate) / float64(len(checks))
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// extractCitations returns the citations in text with their claims. Sources
+// are resolved from the markers but not checked against the context.
+// Bracketed text directly followed by "(" is a Markdown link, not a
+// citation.
+func extractCitations(text string) []CitationCheck {
+	checks := []CitationCheck{}
+	for _, sentence := range citationSentenceRe.FindAllString(text, -1) {
+		var markers []CitationCheck
+		for _, m := range citationMarkerRe.FindAllStringSubmatchIndex(sentence, -1) {
+			if m[1] < len(sentence) && sentence[m[1]] == '(' {
+				continue
+			}
+			markers = append(markers, CitationCheck{
+				Marker: sentence[m[0]:m[1]],
+				Source: citationSource(sentence[m[2]:m[3]]),
+			})
+		}
+		if len(markers) == 0 {
+			continue
+		}
+		claim := strings.Join(strings.Fields(citationMarkerRe.ReplaceAllString(sentence, "")), " ")
+		claim = citationPunctRe.ReplaceAllString(claim, "$1")
+		for _, c := range markers {
+			c.Claim = claim
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// citationSource returns the 1-based context index that label refers to,
+// or 0 if it does not refer to one.
+func citationSource(label string) int {
+	m := citationLabelRe.FindStringSubmatch(strings.TrimSpace(label))
+	if m == nil {
+		return 0
+	}
+	if n, err := strconv.Atoi(m[1]); err == nil {
+		return n
+	}
+	return int(unicode.ToUpper(rune(m[1][0])) - 'A' + 1)
+}
+
+// verifyCitation asks model whether doc supports claim.
+func verifyCitation(ctx context.Context, r *registry.Registry, model Model, doc, claim string) (*citationVerdict, error) {
+	prompt := fmt.Sprintf("Does the source document support the claim? Answer with supported true only if the claim follows from the document, and give a short reasoning.\n\nDocument:\n%s\n\nClaim:\n%s", doc, claim)
+	var v citationVerdict
+	if _, err := GenerateData(ctx, r, &v, WithModel(model), WithPromptText(prompt)); err != nil {
+		return nil, fmt.Errorf("citation model %q: %w", model.Name(), err)
+	}
+	return &v, nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// CitationFormat is an enum used to select the citation style checked by
+// [DefineCitationFormatEvaluator].
+type CitationFormat int
+
+const (
+	// CitationAPA is the APA author-date style, such as
+	// "(Smith & Jones, 2020, p. 4)".
+	CitationAPA CitationFormat = iota
+	// CitationMLA is the MLA author-page style, such as "(Smith and Jones 4)".
+	CitationMLA
+	// CitationChicago is the Chicago author-date style, such as
+	// "(Smith and Jones 2020, 4)".
+	CitationChicago
+)
+
+var citationFormatName = map[CitationFormat]string{
+	CitationAPA:     "apa",
+	CitationMLA:     "mla",
+	CitationChicago: "chicago",
+}
+
+func (f CitationFormat) String() string {
+	return citationFormatName[f]
+}
+
+// CitationFormatCheck is the check of one in-text citation, reported by
+// [DefineCitationFormatEvaluator].
+type CitationFormatCheck struct {
+	// Citation is the citation without its parentheses.
+	Citation string `json:"citation"`
+	Status   string `json:"status"`
+	// Violations describes each format rule the citation breaks.
+	Violations []string `json:"violations,omitempty"`
+}
+
+// citationRule is a rule of a citation format. violated reports whether a
+// citation breaks it.
+type citationRule struct {
+	violated *regexp.Regexp
+	message  string
+}
+
+// citationStyle describes a citation format: the structure of a valid
+// citation and the rules used to explain why a citation does not have it.
+type citationStyle struct {
+	valid *regexp.Regexp
+	rules []citationRule
+}
+
+const (
+	citationAuthor = `\p{Lu}[\p{L}'’-]+`
+	// citationAuthors is a list of authors in MLA and Chicago style.
+	citationAuthors = citationAuthor + `(?: et al\.| and ` + citationAuthor + `)?`
+	// citationAPAAuthors is a list of authors in APA style.
+	citationAPAAuthors = citationAuthor + `(?: et al\.|(?:, ` + citationAuthor + `)*,? & ` + citationAuthor + `)?`
+	citationPages      = `\d+(?:[–-]\d+)?`
+)
+
+var citationStyles = map[CitationFormat]citationStyle{
+	CitationAPA: {
+		valid: regexp.MustCompile(`^` + citationAPAAuthors + `, (?:\d{4}[a-z]?|n\.d\.)(?:, (?:p\. \d+|pp\. \d+[–-]\d+))?$`),
+		rules: []citationRule{
+			{regexp.MustCompile(`^[^,]*\p{L}\.? \d{4}`), "APA separates the author and year with a comma"},
+			{regexp.MustCompile(` and \p{Lu}`), `APA joins authors with "&"`},
+			{regexp.MustCompile(`^[^\d]*$|^[^\d]*\d{1,3}(?:\D|$)`), `APA citations need a four-digit year or "n.d." after the author`},
+			{regexp.MustCompile(`\d{4}[a-z]?,? \d`), `APA prefixes page numbers with "p." or "pp."`},
+		},
+	},
+	CitationMLA: {
+		valid: regexp.MustCompile(`^` + citationAuthors + ` ` + citationPages + `$`),
+		rules: []citationRule{
+			{regexp.MustCompile(`\p{L}\.?, \d`), "MLA does not separate the author and page with a comma"},
+			{regexp.MustCompile(` & `), `MLA joins authors with "and"`},
+			{regexp.MustCompile(`\bpp?\. `), `MLA does not prefix page numbers with "p." or "pp."`},
+		},
+	},
+	CitationChicago: {
+		valid: regexp.MustCompile(`^` + citationAuthors + ` \d{4}[a-z]?(?:, ` + citationPages + `)?$`),
+		rules: []citationRule{
+			{regexp.MustCompile(`^[^,]*\p{L}\.?, \d{4}`), "Chicago does not separate the author and year with a comma"},
+			{regexp.MustCompile(` & `), `Chicago joins authors with "and"`},
+			{regexp.MustCompile(`^[^\d]*$|^[^\d]*\d{1,3}(?:\D|$)`), "Chicago citations need a four-digit year after the author"},
+			{regexp.MustCompile(`\bpp?\. `), `Chicago does not prefix page numbers with "p." or "pp."`},
+		},
+	},
+}
+
+// citationParenRe matches parenthesized text that starts with a capital
+// letter and contains a digit, which is treated as an in-text citation.
+var citationParenRe = regexp.MustCompile(`\((\p{Lu}[^()]*?\d[^()]*?)\)`)
+
+// DefineCitationFormatEvaluator registers an evaluator that checks that the
+// in-text citations in the Output of each [Example] follow format. The
+// evaluator is named after the format, for example "citation_format_apa".
+//
+// Citations are parenthesized author names followed by a year or pages;
+// parentheses holding several citations separated by semicolons are checked
+// one citation at a time. The score is the fraction of citations that are
+// well formed, and the example passes only if it has citations and all of
+// them are well formed. The check of each citation, including the rules it
+// breaks, is reported as a [CitationFormatCheck] in the "citations" key of
+// [Score.Details]. If opts is nil, default options are used.
+func DefineCitationFormatEvaluator(r *registry.Registry, provider string, format CitationFormat, opts *EvaluatorOptions) (Evaluator, error) {
+	style, ok := citationStyles[format]
+	if !ok {
+		return nil, fmt.Errorf("ai.DefineCitationFormatEvaluator: unknown citation format %d", format)
+	}
+	name := "citation_format_" + format.String()
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName:    "Citation Format (" + strings.ToUpper(format.String()) + ")",
+			Definition:     "Checks that in-text citations in the output follow the " + strings.ToUpper(format.String()) + " format",
+			RequiredFields: []string{"Output"},
+		}
+	}
+
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		output, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+
+		var checks []CitationFormatCheck
+		valid := 0
+		for _, m := range citationParenRe.FindAllStringSubmatch(output, -1) {
+			for _, citation := range strings.Split(m[1], ";") {
+				check := style.check(strings.TrimSpace(citation))
+				if check.Status == ScoreStatusPass.String() {
+					valid++
+				}
+				checks = append(checks, check)
+			}
+		}
+
+		score := Score{
+			Id:      name,
+			Score:   0.0,
+			Status:  passStatus(len(checks) > 0 && valid == len(checks)).String(),
+			Details: map[string]any{"citations": checks},
+		}
+		if len(checks) > 0 {
+			score.Score = float64(valid) / float64(len(checks))
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// check checks that citation has the structure of the style.
+func (s citationStyle) check(citation string) CitationFormatCheck {
+	check := CitationFormatCheck{Citation: citation, Status: ScoreStatusPass.String()}
+	if s.valid.MatchString(citation) {
+		return check
+	}
+	check.Status = ScoreStatusFail.String()
+	for _, rule := range s.rules {
+		if rule.violated.MatchString(citation) {
+			check.Violations = append(check.Violations, rule.message)
+		}
+	}
+	if len(check.Violations) == 0 {
+		check.Violations = []string{"citation is not structured as author names followed by a year or pages"}
+	}
+	return check
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestCitationStyles(t *testing.T) {
+	tests := []struct {
+		format    CitationFormat
+		citation  string
+		violation string // substring of the expected violation, or "" if valid
+	}{
+		{CitationAPA, "Smith, 2020", ""},
+		{CitationAPA, "Smith & Jones, 2020, p. 4", ""},
+		{CitationAPA, "Smith, Jones, & Lee, 2019a", ""},
+		{CitationAPA, "Smith et al., n.d.", ""},
+		{CitationAPA, "Smith 2020", "with a comma"},
+		{CitationAPA, "Smith and Jones, 2020", `with "&"`},
+		{CitationAPA, "Smith, 2020, 4", `"p." or "pp."`},
+		{CitationAPA, "Smith, 45", "four-digit year"},
+		{CitationMLA, "Smith 23", ""},
+		{CitationMLA, "Smith and Jones 23-25", ""},
+		{CitationMLA, "Smith et al. 7", ""},
+		{CitationMLA, "Smith, 23", "with a comma"},
+		{CitationMLA, "Smith & Jones 23", `with "and"`},
+		{CitationMLA, "Smith p. 23", `"p." or "pp."`},
+		{CitationChicago, "Smith 2020", ""},
+		{CitationChicago, "Smith and Jones 2020, 23–25", ""},
+		{CitationChicago, "Smith, 2020", "with a comma"},
+		{CitationChicago, "Smith & Jones 2020", `with "and"`},
+		{CitationChicago, "Smith 23", "four-digit year"},
+		{CitationChicago, "Smith 2020, p. 4", `"p." or "pp."`},
+	}
+	for _, test := range tests {
+		check := citationStyles[test.format].check(test.citation)
+		if test.violation == "" {
+			if check.Status != ScoreStatusPass.String() {
+				t.Errorf("%v %q: got violations %q, want valid", test.format, test.citation, check.Violations)
+			}
+			continue
+		}
+		if check.Status != ScoreStatusFail.String() || !slices.ContainsFunc(check.Violations, func(v string) bool { return strings.Contains(v, test.violation) }) {
+			t.Errorf("%v %q: got status %s and violations %q, want a violation containing %q", test.format, test.citation, check.Status, check.Violations, test.violation)
+		}
+	}
+}
+
+func TestCitationFormatEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalAction, err := DefineCitationFormatEvaluator(r, "test", CitationAPA, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := evalAction.Name(), "test/citation_format_apa"; got != want {
+		t.Errorf("got name %q, want %q", got, want)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "valid", Output: "Sleep aids memory (Walker, 2017; Stickgold & Walker, 2013, p. 12)."},
+		{TestCaseId: "mixed", Output: "Sleep aids memory (Walker 2017), as shown before (Stickgold & Walker, 2013) (see Figure 2)."},
+		{TestCaseId: "none", Output: "Sleep aids memory."},
+	}
+	resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := indexResults(resp)
+	tests := []struct {
+		id     string
+		score  float64
+		status ScoreStatus
+		checks int
+	}{
+		{"valid", 1, ScoreStatusPass, 2},
+		{"mixed", 0.5, ScoreStatusFail, 2},
+		{"none", 0, ScoreStatusFail, 0},
+	}
+	for _, test := range tests {
+		score := results[test.id].Evaluation[0]
+		checks := score.Details["citations"].([]CitationFormatCheck)
+		if score.Score != test.score || score.Status != test.status.String() || len(checks) != test.checks {
+			t.Errorf("%s: got score %v, status %q and %d citations, want %v, %q and %d", test.id, score.Score, score.Status, len(checks), test.score, test.status, test.checks)
+		}
+	}
+
+	if _, err := DefineCitationFormatEvaluator(r, "test", CitationFormat(99), nil); err == nil {
+		t.Error("got nil, want error for unknown format")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExtractCitations(t *testing.T) {
+	got := extractCitations("Paris is the capital of France [1]. It has 2 million people.[2][Source C] See [the docs](https://example.com). No citation here! Berlin [citation needed]")
+	want := []CitationCheck{
+		{Marker: "[1]", Claim: "Paris is the capital of France.", Source: 1},
+		{Marker: "[2]", Claim: "It has 2 million people.", Source: 2},
+		{Marker: "[Source C]", Claim: "It has 2 million people.", Source: 3},
+		{Marker: "[citation needed]", Claim: "Berlin", Source: 0},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCitationAccuracyEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The model considers a claim supported if the document mentions the
+	// first word of the claim.
+	model := defineJudgeModel(r, "citations", func(prompt string) any {
+		doc, claim, _ := strings.Cut(strings.TrimPrefix(prompt[strings.Index(prompt, "Document:\n"):], "Document:\n"), "\n\nClaim:\n")
+		subject := strings.Fields(claim)[0]
+		return citationVerdict{Supported: strings.Contains(doc, subject), Reasoning: "checked " + subject}
+	})
+	e, err := DefineCitationAccuracyEvaluator(r, "test", "citation_accuracy", model, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := []any{"Paris is the capital of France.", "Berlin is the capital of Germany."}
+	ds := Dataset{
+		{TestCaseId: "accurate", Input: "q", Context: docs, Output: "Paris is in France [1]. Berlin is in Germany [2]."},
+		{TestCaseId: "wrong source", Input: "q", Context: docs, Output: "Paris is in France [2]. Berlin is in Germany [2]."},
+		{TestCaseId: "missing source", Input: "q", Context: docs, Output: "Paris is in France [3]."},
+		{TestCaseId: "uncited", Input: "q", Context: docs, Output: "Paris is in France."},
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := indexResults(resp)
+	for id, want := range map[string]struct {
+		score  float64
+		status string
+	}{
+		"accurate":       {1, "pass"},
+		"wrong source":   {0.5, "fail"},
+		"missing source": {0, "fail"},
+		"uncited":        {0, "fail"},
+	} {
+		s := results[id].Evaluation[0]
+		if s.Score != want.score || s.Status != want.status {
+			t.Errorf("%s: got score %v (%s), want %v (%s)", id, s.Score, s.Status, want.score, want.status)
+		}
+	}
+	checks := results["wrong source"].Evaluation[0].Details["citations"].([]CitationCheck)
+	if got, want := checks[0], (CitationCheck{Marker: "[2]", Claim: "Paris is in France.", Source: 2, Reasoning: "checked Paris"}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// The default options require a context.
+	noContext := Dataset{{TestCaseId: "none", Input: "q", Output: "Paris [1]."}}
+	if _, err := Evaluate(context.Background(), e, WithEvaluateDataset(&noContext)); err == nil {
+		t.Error("got nil, want error for missing context")
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// codeStyleTimeout bounds each gofmt or golangci-lint invocation.
+const codeStyleTimeout = time.Minute
+
+// CodeStyleOption configures [DefineCodeStyleEvaluator].
+type CodeStyleOption func(opts *codeStyleOptions)
+
+type codeStyleOptions struct {
+	lintConfig string
+}
+
+// WithGolangCILintConfig makes [DefineCodeStyleEvaluator] also run
+// golangci-lint with the configuration file at path. The golangci-lint
+// command must be on the PATH.
+func WithGolangCILintConfig(path string) CodeStyleOption {
+	return func(opts *codeStyleOptions) {
+		opts.lintConfig = path
+	}
+}
+
+// DefineCodeStyleEvaluator registers an evaluator named "code_style" that
+// checks that the Output of each [Example], the source of a Go file, is
+// formatted as gofmt would format it. The source is written to a temporary
+// directory, which is removed afterwards, and checked with "gofmt -l".
+//
+// The example passes if gofmt would not change the file. Otherwise the
+// changes gofmt would make are reported in the "diff" key of [Score.Details],
+// and syntax errors in the "errors" key. If a golangci-lint configuration is
+// given with [WithGolangCILintConfig], golangci-lint is run as well, even
+// if gofmt would change the file, the example fails if it reports issues,
+// and its output is reported in the "lint" key.
+//
+// The gofmt command must be on the PATH. If opts is nil, default options are
+// used.
+func DefineCodeStyleEvaluator(r *registry.Registry, provider string, opts *EvaluatorOptions, styleOpts ...CodeStyleOption) (Evaluator, error) {
+	o := &codeStyleOptions{}
+	for _, opt := range styleOpts {
+		opt(o)
+	}
+	if o.lintConfig != "" {
+		abs, err := filepath.Abs(o.lintConfig)
+		if err != nil {
+			return nil, fmt.Errorf("ai.DefineCodeStyleEvaluator: %w", err)
+		}
+		o.lintConfig = abs
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName:    "Go Code Style",
+			Definition:     "Checks that the generated Go code is formatted with gofmt",
+			RequiredFields: []string{"Output"},
+		}
+	}
+
+	return DefineEvaluator(r, provider, "code_style", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		code, ok := req.Input.Output.(string)
+		if !ok {
+			return nil, errors.New("output must be a string of Go source")
+		}
+		dir, err := os.MkdirTemp("", "genkit-codestyle-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		if err := os.WriteFile(filepath.Join(dir, "code.go"), []byte(code), 0o600); err != nil {
+			return nil, err
+		}
+
+		score := Score{
+			Id:      "code_style",
+			Details: map[string]any{},
+		}
+		ok, err = checkGofmt(ctx, dir, score.Details)
+		if err != nil {
+			return nil, err
+		}
+		if o.lintConfig != "" {
+			lintOk, err := runGolangCILint(ctx, dir, o.lintConfig, score.Details)
+			if err != nil {
+				return nil, err
+			}
+			ok = ok && lintOk
+		}
+		score.Score = 0.0
+		if ok {
+			score.Score = 1.0
+		}
+		score.Status = passStatus(ok).String()
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// checkGofmt reports whether code.go in dir is formatted, recording the
+// diff or syntax errors in details otherwise.
+func checkGofmt(ctx context.Context, dir string, details map[string]any) (bool, error) {
+	gofmt, err := exec.LookPath("gofmt")
+	if err != nil {
+		return false, fmt.Errorf("gofmt command not found: %w", err)
+	}
+	stdout, stderr, err := runCodeStyleCommand(ctx, dir, gofmt, "-l", "code.go")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr != "" {
+			details["errors"] = strings.TrimSpace(stderr)
+			return false, nil
+		}
+		return false, fmt.Errorf("gofmt: %w", err)
+	}
+	if strings.TrimSpace(stdout) == "" {
+		return true, nil
+	}
+	// gofmt -d exits with status 1 when there are differences.
+	diff, _, err := runCodeStyleCommand(ctx, dir, gofmt, "-d", "code.go")
+	if diff == "" && err != nil {
+		return false, fmt.Errorf("gofmt: %w", err)
+	}
+	details["diff"] = diff
+	return false, nil
+}
+
+// runGolangCILint reports whether golangci-lint finds no issues in dir,
+// recording its output in details.
+func runGolangCILint(ctx context.Context, dir, config string, details map[string]any) (bool, error) {
+	lint, err := exec.LookPath("golangci-lint")
+	if err != nil {
+		return false, fmt.Errorf("golangci-lint command not found: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module codestyleeval\n\ngo 1.22\n"), 0o600); err != nil {
+		return false, err
+	}
+	stdout, stderr, err := runCodeStyleCommand(ctx, dir, lint, "run", "--config", config, "./...")
+	details["lint"] = strings.TrimSpace(stdout + stderr)
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr):
+		return false, nil
+	}
+	return false, fmt.Errorf("golangci-lint: %w", err)
+}
+
+// runCodeStyleCommand runs a command in dir and returns its output.
+func runCodeStyleCommand(ctx context.Context, dir, name string, args ...string) (stdout, stderr string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, codeStyleTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOPROXY=off", "GOWORK=off", "GOTOOLCHAIN=local")
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	err = cmd.Run()
+	return out.String(), errOut.String(), err
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestCodeStyleEvaluator(t *testing.T) {
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		t.Skip("gofmt command not available")
+	}
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineCodeStyleEvaluator(r, "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "formatted", Output: "package add\n\nfunc Add(a, b int) int { return a + b }\n"},
+		{TestCaseId: "malformatted", Output: "package add\nfunc  Add(a,b int) int {\nreturn a+b }\n"},
+		{TestCaseId: "broken", Output: "package add\n\nfunc Add(a, b int) int {\n"},
+	}
+	resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := indexResults(resp)
+	for id, want := range map[string]ScoreStatus{"formatted": ScoreStatusPass, "malformatted": ScoreStatusFail, "broken": ScoreStatusFail} {
+		if got := results[id].Evaluation[0].Status; got != want.String() {
+			t.Errorf("%s: got status %v, want %v", id, got, want)
+		}
+	}
+	if diff, _ := results["malformatted"].Evaluation[0].Details["diff"].(string); !strings.Contains(diff, "+func Add(a, b int) int {") {
+		t.Errorf("diff %q does not contain the formatted code", diff)
+	}
+	if _, ok := results["broken"].Evaluation[0].Details["errors"].(string); !ok {
+		t.Errorf("got details %v, want syntax errors", results["broken"].Evaluation[0].Details)
+	}
+}
+
+func TestCodeStyleEvaluatorLint(t *testing.T) {
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		t.Skip("gofmt command not available")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("fake golangci-lint is a shell script")
+	}
+	// A fake golangci-lint that reports an issue for code with a TODO.
+	bin := t.TempDir()
+	script := "#!/bin/sh\nif grep -q TODO code.go; then echo 'code.go:3: TODO found (godox)'; exit 1; fi\n"
+	if err := os.WriteFile(filepath.Join(bin, "golangci-lint"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalAction, err := DefineCodeStyleEvaluator(r, "test", nil, WithGolangCILintConfig(".golangci.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "clean", Output: "package add\n\nfunc Add(a, b int) int { return a + b }\n"},
+		{TestCaseId: "todo", Output: "package add\n\n// TODO: handle overflow.\nfunc Add(a, b int) int { return a + b }\n"},
+		{TestCaseId: "both", Output: "package add\n\n// TODO: handle overflow.\nfunc Add(a,b int) int { return a + b }\n"},
+	}
+	resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := indexResults(resp)
+	if got := results["clean"].Evaluation[0].Status; got != ScoreStatusPass.String() {
+		t.Errorf("clean: got status %v, want pass", got)
+	}
+	todo := results["todo"].Evaluation[0]
+	if todo.Status != ScoreStatusFail.String() || !strings.Contains(todo.Details["lint"].(string), "godox") {
+		t.Errorf("todo: got status %v and details %v, want failure with the lint output", todo.Status, todo.Details)
+	}
+	// golangci-lint runs even if gofmt would change the file.
+	both := results["both"].Evaluation[0]
+	if both.Status != ScoreStatusFail.String() || both.Details["diff"] == nil || both.Details["lint"] == nil {
+		t.Errorf("both: got status %v and details %v, want failure with the diff and the lint output", both.Status, both.Details)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/firebase/genkit/go/core/tracing"
+	"github.com/firebase/genkit/go/internal/registry"
+	"gopkg.in/yaml.v3"
+)
+
+// EvalConfig describes an evaluation run, typically loaded from a YAML file
+// with [ParseEvalConfig]:
+//
+//	evaluator: genkitEval/regex
+//	options:
+//	  caseSensitive: false
+//	datasetPath: testdata/dataset.json
+//	outputPath: out/results.json
+//	labels:
+//	  model: gemini-2.0-flash
+//	concurrency: 4
+type EvalConfig struct {
+	// Evaluator is the registered evaluator to run, as "provider/name".
+	Evaluator string `yaml:"evaluator"`
+	// Options are passed to the evaluator as the request options.
+	Options any `yaml:"options,omitempty"`
+	// DatasetPath is a JSON file holding a list of examples, or a JSONL file
+	// (with a .jsonl extension) holding one example per line.
+	DatasetPath string `yaml:"datasetPath"`
+	// OutputPath, if set, is where the evaluator response is written as JSON,
+	// in the current [SchemaVersion].
+	OutputPath string `yaml:"outputPath,omitempty"`
+	// Labels are recorded on the trace span of the run.
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// Concurrency is the number of parts the dataset is split into and
+	// evaluated in parallel. Zero or one evaluates the whole dataset at once.
+	Concurrency int `yaml:"concurrency,omitempty"`
+}
+
+// ParseEvalConfig reads an [EvalConfig] in YAML format from r. Unknown fields
+// are rejected.
+func ParseEvalConfig(r io.Reader) (*EvalConfig, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var cfg EvalConfig
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("ai.ParseEvalConfig: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("ai.ParseEvalConfig: %w", err)
+	}
+	return &cfg, nil
+}
+
+func (cfg *EvalConfig) validate() error {
+	if provider, name, ok := strings.Cut(cfg.Evaluator, "/"); !ok || provider == "" || name == "" {
+		return fmt.Errorf("evaluator must have the form provider/name, got %q", cfg.Evaluator)
+	}
+	if cfg.DatasetPath == "" {
+		return errors.New("datasetPath is required")
+	}
+	if cfg.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative, got %d", cfg.Concurrency)
+	}
+	return nil
+}
+
+// RunFromConfig runs the evaluation described by cfg with an evaluator
+// registered in r, writes the response to cfg.OutputPath if it is set, and
+// returns it. As with [Evaluator.Evaluate], failures on individual examples
+// are reported in an error returned along with the response.
+func RunFromConfig(ctx context.Context, r *registry.Registry, cfg *EvalConfig) (*EvaluatorResponse, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("ai.RunFromConfig: %w", err)
+	}
+	provider, name, _ := strings.Cut(cfg.Evaluator, "/")
+	e := LookupEvaluator(r, provider, name)
+	if e == nil {
+		return nil, fmt.Errorf("ai.RunFromConfig: evaluator %q not found", cfg.Evaluator)
+	}
+	ds, err := loadDatasetFile(cfg.DatasetPath)
+	if err != nil {
+		return nil, fmt.Errorf("ai.RunFromConfig: %w", err)
+	}
+
+	// Errors for individual examples are returned with the response, which
+	// is still written out.
+	var evalErr error
+	resp, err := tracing.RunInNewSpan(ctx, r.TracingState(), "evalConfig", "evaluationPipeline", true, cfg,
+		func(ctx context.Context, cfg *EvalConfig) (*EvaluatorResponse, error) {
+			for k, v := range cfg.Labels {
+				tracing.SetCustomMetadataAttr(ctx, "label:"+k, v)
+			}
+			var resp *EvaluatorResponse
+			resp, evalErr = evaluateConcurrently(ctx, e, ds, cfg.Concurrency, WithEvaluateOptions(cfg.Options))
+			return resp, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("ai.RunFromConfig: %w", err)
+	}
+
+	if cfg.OutputPath != "" {
+		data, err := encodeEvaluatorResponse(resp)
+		if err != nil {
+			return nil, fmt.Errorf("ai.RunFromConfig: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(cfg.OutputPath), 0o755); err != nil {
+			return nil, fmt.Errorf("ai.RunFromConfig: %w", err)
+		}
+		if err := os.WriteFile(cfg.OutputPath, data, 0o644); err != nil {
+			return nil, fmt.Errorf("ai.RunFromConfig: %w", err)
+		}
+	}
+	return resp, evalErr
+}
+
+// evaluateConcurrently splits ds into n parts, evaluates them in parallel and
+// returns the results in dataset order.
+func evaluateConcurrently(ctx context.Context, e Evaluator, ds Dataset, n int, opts ...EvaluateOption) (*EvaluatorResponse, error) {
+	n = max(1, min(n, len(ds)))
+	size := (len(ds) + n - 1) / n
+	var (
+		wg    sync.WaitGroup
+		resps = make([]*EvaluatorResponse, n)
+		errs  = make([]error, n)
+	)
+	for i := range n {
+		part := ds[min(i*size, len(ds)):min((i+1)*size, len(ds))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resps[i], errs[i] = Evaluate(ctx, e, append([]EvaluateOption{WithEvaluateDataset(&part)}, opts...)...)
+		}()
+	}
+	wg.Wait()
+
+	var merged EvaluatorResponse
+	for _, resp := range resps {
+		if resp != nil {
+			merged = append(merged, *resp...)
+		}
+	}
+	return &merged, errors.Join(errs...)
+}
+
+// loadDatasetFile reads a [Dataset] from a JSON or JSONL file.
+func loadDatasetFile(path string) (Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadDataset(f, path)
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestParseEvalConfig(t *testing.T) {
+	cfg, err := ParseEvalConfig(strings.NewReader(`
+evaluator: test/testEvaluator
+options:
+  threshold: 0.5
+datasetPath: data.jsonl
+outputPath: out/results.json
+labels:
+  model: v2
+concurrency: 3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.Evaluator, "test/testEvaluator"; got != want {
+		t.Errorf("got evaluator %q, want %q", got, want)
+	}
+	if got, want := cfg.Options.(map[string]any)["threshold"], 0.5; got != want {
+		t.Errorf("got threshold %v, want %v", got, want)
+	}
+	if got, want := cfg.Labels["model"], "v2"; got != want {
+		t.Errorf("got label %q, want %q", got, want)
+	}
+	if got, want := cfg.Concurrency, 3; got != want {
+		t.Errorf("got concurrency %d, want %d", got, want)
+	}
+
+	for _, bad := range []string{
+		"evaluator: noProvider\ndatasetPath: d.json\n",
+		"evaluator: test/e\n",
+		"evaluator: test/e\ndatasetPath: d.json\nconcurency: 2\n",
+	} {
+		if _, err := ParseEvalConfig(strings.NewReader(bad)); err == nil {
+			t.Errorf("got nil, want error for config %q", bad)
+		}
+	}
+}
+
+func TestRunFromConfig(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, testEvalFunc); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	var lines []string
+	for i := range 5 {
+		lines = append(lines, fmt.Sprintf(`{"testCaseId": "%d", "input": "q%d"}`, i, i))
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data.jsonl"), []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &EvalConfig{
+		Evaluator:   "test/testEvaluator",
+		Options:     "from-config",
+		DatasetPath: filepath.Join(dir, "data.jsonl"),
+		OutputPath:  filepath.Join(dir, "out", "results.json"),
+		Concurrency: 2,
+	}
+	resp, err := RunFromConfig(context.Background(), r, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(*resp), 5; got != want {
+		t.Fatalf("got %d results, want %d", got, want)
+	}
+	for i, res := range *resp {
+		if got, want := res.TestCaseId, fmt.Sprint(i); got != want {
+			t.Errorf("result %d: got test case %q, want %q", i, got, want)
+		}
+		if got, want := res.Evaluation[0].Details["options"], "from-config"; got != want {
+			t.Errorf("result %d: got options %v, want %v", i, got, want)
+		}
+	}
+
+	data, err := os.ReadFile(cfg.OutputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := MigrateEvaluatorResponse(data, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(*written), 5; got != want {
+		t.Errorf("got %d results in output file, want %d", got, want)
+	}
+
+	cfg.Evaluator = "test/missing"
+	if _, err := RunFromConfig(context.Background(), r, cfg); err == nil {
+		t.Error("got nil, want error for unknown evaluator")
+	}
+}
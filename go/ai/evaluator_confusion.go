@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ConfusionMatrix compares the labels predicted by a classification
+// evaluator with reference labels. See [ComputeConfusionMatrix].
+type ConfusionMatrix struct {
+	Classes []string `json:"classes"`
+	// Matrix[i][j] is the number of results whose reference label is
+	// Classes[i] and whose predicted label is Classes[j].
+	Matrix [][]int `json:"matrix"`
+	// Precision, Recall and F1 hold the metric for each class, in the order
+	// of Classes. A metric whose denominator is zero is 0.
+	Precision []float64 `json:"precision"`
+	Recall    []float64 `json:"recall"`
+	F1        []float64 `json:"f1"`
+	// MacroF1 is the unweighted mean of F1.
+	MacroF1 float64 `json:"macroF1"`
+	// Skipped is the number of results that lack either label.
+	Skipped int `json:"skipped,omitempty"`
+}
+
+// ComputeConfusionMatrix builds a confusion matrix from the results in resp
+// of an evaluator that reports its predicted label as the score with Id
+// scoreId and the reference label as the score with Id referenceScoreId.
+// Labels that are not strings are formatted with [fmt.Sprint]. Results
+// that lack either score, or whose score has an error, are skipped.
+//
+// classes lists the labels in the order of the matrix rows and columns. If
+// it is empty, the labels found in resp are used, sorted. It is an error
+// for a label to be missing from classes.
+func ComputeConfusionMatrix(resp *EvaluatorResponse, scoreId, referenceScoreId string, classes []string) (*ConfusionMatrix, error) {
+	if resp == nil {
+		return nil, errors.New("ai.ComputeConfusionMatrix: response is nil")
+	}
+	type pair struct{ actual, predicted string }
+	var pairs []pair
+	skipped := 0
+	for _, res := range *resp {
+		predicted, ok1 := confusionLabel(res.Evaluation, scoreId)
+		actual, ok2 := confusionLabel(res.Evaluation, referenceScoreId)
+		if !ok1 || !ok2 {
+			skipped++
+			continue
+		}
+		pairs = append(pairs, pair{actual, predicted})
+	}
+
+	if len(classes) == 0 {
+		for _, p := range pairs {
+			classes = append(classes, p.actual, p.predicted)
+		}
+		slices.Sort(classes)
+		classes = slices.Compact(classes)
+	}
+	index := map[string]int{}
+	for i, c := range classes {
+		if _, ok := index[c]; ok {
+			return nil, fmt.Errorf("ai.ComputeConfusionMatrix: duplicate class %q", c)
+		}
+		index[c] = i
+	}
+
+	n := len(classes)
+	cm := &ConfusionMatrix{
+		Classes:   slices.Clone(classes),
+		Matrix:    make([][]int, n),
+		Precision: make([]float64, n),
+		Recall:    make([]float64, n),
+		F1:        make([]float64, n),
+		Skipped:   skipped,
+	}
+	for i := range cm.Matrix {
+		cm.Matrix[i] = make([]int, n)
+	}
+	for _, p := range pairs {
+		i, ok := index[p.actual]
+		if !ok {
+			return nil, fmt.Errorf("ai.ComputeConfusionMatrix: unknown reference label %q", p.actual)
+		}
+		j, ok := index[p.predicted]
+		if !ok {
+			return nil, fmt.Errorf("ai.ComputeConfusionMatrix: unknown predicted label %q", p.predicted)
+		}
+		cm.Matrix[i][j]++
+	}
+
+	for k := range n {
+		tp := cm.Matrix[k][k]
+		var predicted, actual int
+		for i := range n {
+			predicted += cm.Matrix[i][k]
+			actual += cm.Matrix[k][i]
+		}
+		if predicted > 0 {
+			cm.Precision[k] = float64(tp) / float64(predicted)
+		}
+		if actual > 0 {
+			cm.Recall[k] = float64(tp) / float64(actual)
+		}
+		if p, r := cm.Precision[k], cm.Recall[k]; p+r > 0 {
+			cm.F1[k] = 2 * p * r / (p + r)
+		}
+		cm.MacroF1 += cm.F1[k]
+	}
+	if n > 0 {
+		cm.MacroF1 /= float64(n)
+	}
+	return cm, nil
+}
+
+// confusionLabel returns the label held by the score with the given id.
+func confusionLabel(scores []Score, id string) (string, bool) {
+	for _, s := range scores {
+		if s.Id != id {
+			continue
+		}
+		if s.Error != "" || s.Score == nil {
+			return "", false
+		}
+		if label, ok := s.Score.(string); ok {
+			return label, true
+		}
+		return fmt.Sprint(s.Score), true
+	}
+	return "", false
+}
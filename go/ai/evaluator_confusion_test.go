@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func labelResult(id, predicted, actual string) EvaluationResult {
+	return EvaluationResult{TestCaseId: id, Evaluation: []Score{
+		{Id: "label", Score: predicted},
+		{Id: "reference", Score: actual},
+	}}
+}
+
+func TestComputeConfusionMatrix(t *testing.T) {
+	resp := EvaluatorResponse{
+		labelResult("1", "cat", "cat"),
+		labelResult("2", "cat", "cat"),
+		labelResult("3", "dog", "cat"),
+		labelResult("4", "dog", "dog"),
+		labelResult("5", "cat", "dog"),
+		labelResult("6", "bird", "bird"),
+		{TestCaseId: "7", Evaluation: []Score{{Id: "label", Error: "judge failed"}, {Id: "reference", Score: "cat"}}},
+		{TestCaseId: "8", Evaluation: []Score{{Id: "label", Score: "cat"}}},
+	}
+
+	cm, err := ComputeConfusionMatrix(&resp, "label", "reference", []string{"cat", "dog", "bird"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMatrix := [][]int{
+		{2, 1, 0},
+		{1, 1, 0},
+		{0, 0, 1},
+	}
+	if diff := cmp.Diff(wantMatrix, cm.Matrix); diff != "" {
+		t.Errorf("matrix mismatch (-want +got):\n%s", diff)
+	}
+	approx := cmp.Comparer(func(a, b float64) bool { return math.Abs(a-b) < 1e-9 })
+	want := [][]float64{{2.0 / 3, 0.5, 1}, {2.0 / 3, 0.5, 1}, {2.0 / 3, 0.5, 1}}
+	for i, got := range [][]float64{cm.Precision, cm.Recall, cm.F1} {
+		if diff := cmp.Diff(want[i], got, approx); diff != "" {
+			t.Errorf("metric %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+	if got, want := cm.MacroF1, (2.0/3+0.5+1)/3; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got macro F1 %v, want %v", got, want)
+	}
+	if got := cm.Skipped; got != 2 {
+		t.Errorf("got %d skipped, want 2", got)
+	}
+
+	// Without classes, the labels found are used in sorted order.
+	cm, err = ComputeConfusionMatrix(&resp, "label", "reference", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"bird", "cat", "dog"}; !slices.Equal(cm.Classes, want) {
+		t.Errorf("got classes %v, want %v", cm.Classes, want)
+	}
+
+	if _, err := ComputeConfusionMatrix(&resp, "label", "reference", []string{"cat", "dog"}); err == nil {
+		t.Error("got nil error for a label missing from classes, want error")
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// consistencyPassThreshold is the lowest consistency score of
+// [DefineConsistencyEvaluator] that passes.
+const consistencyPassThreshold = 0.8
+
+// ConsistencyReport is the result of [RunConsistencyEval].
+type ConsistencyReport struct {
+	// Results holds the consistency of each example, in dataset order.
+	Results []ConsistencyResult `json:"results"`
+	// Responses holds the evaluator response for each of the k generations.
+	Responses []*EvaluatorResponse `json:"responses"`
+}
+
+// ConsistencyResult is the consistency of the evaluations of a single
+// example across generations.
+type ConsistencyResult struct {
+	TestCaseId string `json:"testCaseId"`
+	// ConsistencyScore is the fraction of evaluations that agree with the
+	// most common pass/fail status.
+	ConsistencyScore float64 `json:"consistencyScore"`
+	// Status is the most common status.
+	Status string `json:"status"`
+}
+
+// RunConsistencyEval measures how stable a model is by generating k outputs
+// for every example of ds with genFunc and evaluating each round with eval.
+// It needs no reference answers: an example whose outputs are sometimes
+// judged to pass and sometimes to fail gets a low consistency score.
+//
+// genFunc receives the dataset example and returns it with a freshly
+// generated Output. Examples without a TestCaseId are assigned one first, and
+// the generated examples keep the TestCaseId of the original. An example
+// whose evaluation fails in a round counts as failing in that round.
+func RunConsistencyEval(ctx context.Context, genFunc func(ctx context.Context, ex Example) (*Example, error), eval Evaluator, ds Dataset, k int, opts ...EvaluateOption) (*ConsistencyReport, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("ai.RunConsistencyEval: k must be at least 1, got %d", k)
+	}
+	ds = withTestCaseIds(ds)
+	report := &ConsistencyReport{}
+	statuses := map[string]map[string]int{}
+	for round := range k {
+		generated := make(Dataset, len(ds))
+		for i, ex := range ds {
+			out, err := genFunc(ctx, ex)
+			if err != nil {
+				return nil, fmt.Errorf("ai.RunConsistencyEval: generating round %d for test case %q: %w", round+1, ex.TestCaseId, err)
+			}
+			generated[i] = *out
+			generated[i].TestCaseId = ex.TestCaseId
+		}
+		resp, err := Evaluate(ctx, eval, append([]EvaluateOption{WithEvaluateDataset(&generated)}, opts...)...)
+		if err != nil && resp == nil {
+			return nil, fmt.Errorf("ai.RunConsistencyEval: evaluating round %d: %w", round+1, err)
+		}
+		report.Responses = append(report.Responses, resp)
+		for _, res := range *resp {
+			if statuses[res.TestCaseId] == nil {
+				statuses[res.TestCaseId] = map[string]int{}
+			}
+			status := resultStatus(res)
+			if res.err != nil {
+				status = ScoreStatusFail
+			}
+			statuses[res.TestCaseId][status.String()]++
+		}
+	}
+
+	for _, ex := range ds {
+		counts := statuses[ex.TestCaseId]
+		if len(counts) == 0 {
+			continue
+		}
+		status := mostCommon(counts, ScoreStatusUnknown.String())
+		total := 0
+		for _, c := range counts {
+			total += c
+		}
+		report.Results = append(report.Results, ConsistencyResult{
+			TestCaseId:       ex.TestCaseId,
+			ConsistencyScore: float64(counts[status]) / float64(total),
+			Status:           status,
+		})
+	}
+	return report, nil
+}
+
+// DefineConsistencyEvaluator defines an evaluator that runs inner n times on
+// each example and scores how consistent its numeric scores are. This
+// measures the reliability of stochastic evaluators such as LLM judges.
+//
+// For each score of inner, matched by Id, the result holds a score with the
+// same Id whose value is 1 - 2*stddev, where stddev is the sample standard
+// deviation of the n trials. Scores are expected to lie in [0, 1] and the
+// consistency is clamped to [0, 1]. Consistencies of at least 0.8 pass.
+// The "trials", "mean", "stdDev" and "variance" keys of [Score.Details] hold
+// the trial scores and their statistics.
+//
+// An example fails if any trial fails or if a score of inner is not numeric.
+func DefineConsistencyEvaluator(r *registry.Registry, provider, name string, inner Evaluator, n int, opts *EvaluatorOptions) (Evaluator, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("ai.DefineConsistencyEvaluator: need at least 2 trials, got %d", n)
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Consistency",
+			Definition:  fmt.Sprintf("Measures how consistently %s scores each example over %d trials", inner.Name(), n),
+		}
+	}
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		ds := Dataset{req.Input}
+		trials := make([]EvaluationResult, n)
+		for i := range n {
+			resp, err := Evaluate(ctx, inner, WithEvaluateDataset(&ds))
+			if resp == nil {
+				return nil, fmt.Errorf("trial %d: %w", i+1, err)
+			}
+			if len(*resp) != 1 {
+				return nil, fmt.Errorf("trial %d: got %d results, want 1", i+1, len(*resp))
+			}
+			if res := (*resp)[0]; res.err != nil {
+				return nil, fmt.Errorf("trial %d failed: %w", i+1, res.err)
+			}
+			trials[i] = (*resp)[0]
+		}
+
+		var scores []Score
+		for _, first := range trials[0].Evaluation {
+			values := make([]float64, 0, n)
+			for i, res := range trials {
+				idx := slices.IndexFunc(res.Evaluation, func(s Score) bool { return s.Id == first.Id })
+				if idx < 0 {
+					return nil, fmt.Errorf("trial %d: no score %q", i+1, first.Id)
+				}
+				v, err := res.Evaluation[idx].Normalize()
+				if err != nil {
+					return nil, fmt.Errorf("trial %d: %w", i+1, err)
+				}
+				values = append(values, v)
+			}
+			mean, sd := meanStdDev(values)
+			consistency := min(max(1-2*sd, 0), 1)
+			scores = append(scores, Score{
+				Id:     first.Id,
+				Score:  consistency,
+				Status: passStatus(consistency >= consistencyPassThreshold).String(),
+				Details: map[string]any{
+					"trials":   values,
+					"mean":     mean,
+					"stdDev":   sd,
+					"variance": sd * sd,
+				},
+			})
+		}
+		if len(scores) == 0 {
+			return nil, errors.New("inner evaluator returned no scores")
+		}
+		return &EvaluatorCallbackResponse{TestCaseId: req.Input.TestCaseId, Evaluation: scores}, nil
+	})
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestRunConsistencyEval(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Outputs containing "ok" pass.
+	evalAction, err := DefineEvaluator(r, "test", "ok", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		pass := req.Input.Output == "ok"
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "ok", Score: pass, Status: passStatus(pass).String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "stable" always produces "ok"; "flaky" produces "ok" every other time.
+	calls := 0
+	gen := func(ctx context.Context, ex Example) (*Example, error) {
+		if ex.Input == "flaky" {
+			calls++
+			if calls%2 == 0 {
+				ex.Output = "nope"
+				return &ex, nil
+			}
+		}
+		ex.Output = "ok"
+		ex.TestCaseId = ""
+		return &ex, nil
+	}
+
+	ds := Dataset{{TestCaseId: "stable", Input: "stable"}, {TestCaseId: "flaky", Input: "flaky"}}
+	report, err := RunConsistencyEval(context.Background(), gen, evalAction, ds, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(report.Responses), 4; got != want {
+		t.Errorf("got %d responses, want %d", got, want)
+	}
+	want := []ConsistencyResult{
+		{TestCaseId: "stable", ConsistencyScore: 1, Status: "pass"},
+		{TestCaseId: "flaky", ConsistencyScore: 0.5, Status: "fail"},
+	}
+	if !slices.Equal(report.Results, want) {
+		t.Errorf("got %v, want %v", report.Results, want)
+	}
+}
+
+func TestRunConsistencyEvalFailedExample(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Outputs containing "boom" make the evaluator fail.
+	evalAction, err := DefineEvaluator(r, "test", "boom", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == "boom" {
+			return nil, errors.New("boom")
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "ok", Score: true, Status: ScoreStatusPass.String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "broken" produces "boom" in every round but the first.
+	calls := 0
+	gen := func(ctx context.Context, ex Example) (*Example, error) {
+		ex.Output = "ok"
+		if ex.Input == "broken" {
+			calls++
+			if calls > 1 {
+				ex.Output = "boom"
+			}
+		}
+		return &ex, nil
+	}
+
+	ds := Dataset{{TestCaseId: "stable", Input: "stable"}, {TestCaseId: "broken", Input: "broken"}}
+	report, err := RunConsistencyEval(context.Background(), gen, evalAction, ds, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(report.Responses), 4; got != want {
+		t.Errorf("got %d responses, want %d", got, want)
+	}
+	want := []ConsistencyResult{
+		{TestCaseId: "stable", ConsistencyScore: 1, Status: "pass"},
+		{TestCaseId: "broken", ConsistencyScore: 0.75, Status: "fail"},
+	}
+	if !slices.Equal(report.Results, want) {
+		t.Errorf("got %v, want %v", report.Results, want)
+	}
+}
+
+func TestDefineConsistencyEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "stable" always scores 0.7; "flaky" alternates between 0 and 1.
+	var mu sync.Mutex
+	calls := map[string]int{}
+	inner, err := DefineEvaluator(r, "test", "judge", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		id := req.Input.TestCaseId
+		calls[id]++
+		score := 0.7
+		if id == "flaky" {
+			score = float64(calls[id] % 2)
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: id,
+			Evaluation: []Score{{Id: "quality", Score: score}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := DefineConsistencyEvaluator(r, "test", "consistency", inner, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{TestCaseId: "stable", Input: "a"}, {TestCaseId: "flaky", Input: "b"}}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := indexResults(resp)
+	stable := results["stable"].Evaluation[0]
+	if stable.Id != "quality" || stable.Score != 1.0 || stable.Status != ScoreStatusPass.String() {
+		t.Errorf("stable: got %+v, want consistency 1 that passes", stable)
+	}
+	flaky := results["flaky"].Evaluation[0]
+	if flaky.Score != 0.0 || flaky.Status != ScoreStatusFail.String() {
+		t.Errorf("flaky: got %+v, want consistency 0 that fails", flaky)
+	}
+	if got, want := flaky.Details["trials"], []float64{1, 0, 1, 0}; !slices.Equal(got.([]float64), want) {
+		t.Errorf("flaky: got trials %v, want %v", got, want)
+	}
+	if got := flaky.Details["variance"].(float64); math.Abs(got-1.0/3) > 1e-9 {
+		t.Errorf("flaky: got variance %v, want 1/3", got)
+	}
+	if got := calls["stable"]; got != 4 {
+		t.Errorf("inner evaluator ran %d times on an example, want 4", got)
+	}
+
+	if _, err := DefineConsistencyEvaluator(r, "test", "once", inner, 1, nil); err == nil {
+		t.Error("got nil, want error for a single trial")
+	}
+}
+
+func TestDefineConsistencyEvaluatorFailedTrial(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The third trial on any example fails.
+	var mu sync.Mutex
+	calls := map[string]int{}
+	inner, err := DefineEvaluator(r, "test", "judge", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		id := req.Input.TestCaseId
+		calls[id]++
+		if calls[id] == 3 {
+			return nil, errors.New("judge unavailable")
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: id,
+			Evaluation: []Score{{Id: "quality", Score: 0.7}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := DefineConsistencyEvaluator(r, "test", "consistency", inner, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{TestCaseId: "a", Input: "a"}}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err == nil {
+		t.Fatal("got nil, want error for a failed trial")
+	}
+	if resp == nil || len(*resp) != 1 {
+		t.Fatalf("got %v, want a partial response with one result", resp)
+	}
+	res := (*resp)[0]
+	if res.err == nil || res.Evaluation[0].Status != ScoreStatusFail.String() {
+		t.Errorf("got %+v, want a failed result", res)
+	}
+	if got := calls["a"]; got != 3 {
+		t.Errorf("inner evaluator ran %d times, want 3", got)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// CorrelationMatrix holds the pairwise correlations between the scores of
+// several evaluators on the same dataset, as computed by
+// [ComputeEvaluatorCorrelation]. Rows and columns follow Evaluators.
+type CorrelationMatrix struct {
+	// Evaluators are the names of the evaluators, sorted.
+	Evaluators []string `json:"evaluators"`
+	// Pearson holds the Pearson correlations of the scores.
+	Pearson [][]float64 `json:"pearson"`
+	// Spearman holds the Spearman rank correlations of the scores.
+	Spearman [][]float64 `json:"spearman"`
+	// Examples holds the number of examples that both evaluators scored.
+	Examples [][]int `json:"examples"`
+}
+
+// Get returns the Pearson and Spearman correlations between the evaluators
+// named eval1 and eval2, or zeros if either is not in the matrix.
+func (m *CorrelationMatrix) Get(eval1, eval2 string) (pearson, spearman float64) {
+	i, ok1 := slices.BinarySearch(m.Evaluators, eval1)
+	j, ok2 := slices.BinarySearch(m.Evaluators, eval2)
+	if !ok1 || !ok2 {
+		return 0, 0
+	}
+	return m.Pearson[i][j], m.Spearman[i][j]
+}
+
+// ComputeEvaluatorCorrelation computes how much the evaluators whose
+// responses are given, keyed by evaluator name, agree with each other. Each
+// evaluator's score for an example is the mean of the numeric scores in its
+// result, and results are matched by TestCaseId. Results without numeric
+// scores are left out. A correlation is 0 if the two evaluators scored
+// fewer than two examples in common, or if either gave them all the same
+// score.
+func ComputeEvaluatorCorrelation(responses map[string]*EvaluatorResponse) (*CorrelationMatrix, error) {
+	if len(responses) < 2 {
+		return nil, errors.New("ai.ComputeEvaluatorCorrelation: at least two evaluators are required")
+	}
+	names := slices.Sorted(maps.Keys(responses))
+	scores := make([]map[string]float64, len(names))
+	for i, name := range names {
+		resp := responses[name]
+		if resp == nil {
+			return nil, fmt.Errorf("ai.ComputeEvaluatorCorrelation: no response for evaluator %q", name)
+		}
+		scores[i] = map[string]float64{}
+		for _, res := range *resp {
+			if v, err := meanScore(res); err == nil {
+				scores[i][res.TestCaseId] = v
+			}
+		}
+	}
+
+	n := len(names)
+	m := &CorrelationMatrix{
+		Evaluators: names,
+		Pearson:    make([][]float64, n),
+		Spearman:   make([][]float64, n),
+		Examples:   make([][]int, n),
+	}
+	for i := range n {
+		m.Pearson[i] = make([]float64, n)
+		m.Spearman[i] = make([]float64, n)
+		m.Examples[i] = make([]int, n)
+	}
+	for i := range n {
+		for j := i; j < n; j++ {
+			var x, y []float64
+			for _, id := range slices.Sorted(maps.Keys(scores[i])) {
+				if v, ok := scores[j][id]; ok {
+					x = append(x, scores[i][id])
+					y = append(y, v)
+				}
+			}
+			var p, s float64
+			if len(x) >= 2 {
+				p, s = pearson(x, y), pearson(ranks(x), ranks(y))
+			}
+			m.Pearson[i][j], m.Pearson[j][i] = p, p
+			m.Spearman[i][j], m.Spearman[j][i] = s, s
+			m.Examples[i][j], m.Examples[j][i] = len(x), len(x)
+		}
+	}
+	return m, nil
+}
+
+// ranks returns the rank of each value in values, starting at 1. Tied
+// values get the mean of their ranks.
+func ranks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortFunc(idx, func(a, b int) int { return cmp.Compare(values[a], values[b]) })
+	r := make([]float64, len(values))
+	for i := 0; i < len(idx); {
+		j := i
+		for j < len(idx) && values[idx[j]] == values[idx[i]] {
+			j++
+		}
+		// Positions i to j-1 hold ranks i+1 to j.
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			r[idx[k]] = rank
+		}
+		i = j
+	}
+	return r
+}
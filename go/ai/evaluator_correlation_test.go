@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestComputeEvaluatorCorrelation(t *testing.T) {
+	base := []float64{0.1, 0.2, 0.3, 0.4}
+	responses := map[string]*EvaluatorResponse{}
+	for name, score := range map[string]func(i int, v float64) float64{
+		"base":        func(i int, v float64) float64 { return v },
+		"scaled":      func(i int, v float64) float64 { return 2 * v },
+		"inverted":    func(i int, v float64) float64 { return 1 - v },
+		"independent": func(i int, v float64) float64 { return []float64{0.9, 0.1, 0.1, 0.9}[i] },
+		"cubed":       func(i int, v float64) float64 { return v * v * v },
+	} {
+		resp := EvaluatorResponse{}
+		for i, v := range base {
+			resp = append(resp, passFail(string(rune('a'+i)), true, score(i, v)))
+		}
+		responses[name] = &resp
+	}
+	// An extra example that only one evaluator scored is left out.
+	*responses["base"] = append(*responses["base"], passFail("z", true, 1.0))
+
+	m, err := ComputeEvaluatorCorrelation(responses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.Evaluators, []string{"base", "cubed", "independent", "inverted", "scaled"}; !cmp.Equal(got, want) {
+		t.Errorf("got evaluators %v, want %v", got, want)
+	}
+	for _, tc := range []struct {
+		a, b              string
+		pearson, spearman float64
+	}{
+		{"base", "scaled", 1, 1},
+		{"scaled", "base", 1, 1},
+		{"base", "inverted", -1, -1},
+		{"base", "independent", 0, 0},
+		{"base", "base", 1, 1},
+		{"base", "missing", 0, 0},
+	} {
+		p, s := m.Get(tc.a, tc.b)
+		if math.Abs(p-tc.pearson) > 1e-9 || math.Abs(s-tc.spearman) > 1e-9 {
+			t.Errorf("%s, %s: got pearson %v and spearman %v, want %v and %v", tc.a, tc.b, p, s, tc.pearson, tc.spearman)
+		}
+	}
+	// A monotonic but nonlinear relation has perfect rank correlation only.
+	if p, s := m.Get("base", "cubed"); p >= 0.99 || math.Abs(s-1) > 1e-9 {
+		t.Errorf("base, cubed: got pearson %v and spearman %v, want pearson below 0.99 and spearman 1", p, s)
+	}
+	if got := m.Examples[0][1]; got != len(base) {
+		t.Errorf("got %d examples in common, want %d", got, len(base))
+	}
+
+	if _, err := ComputeEvaluatorCorrelation(map[string]*EvaluatorResponse{"base": responses["base"]}); err == nil {
+		t.Error("got nil, want error for a single evaluator")
+	}
+}
+
+func TestRanks(t *testing.T) {
+	got := ranks([]float64{0.5, 0.1, 0.5, 0.9})
+	if want := []float64{2.5, 1, 2.5, 4}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
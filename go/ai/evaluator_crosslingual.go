@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// TranslateFunc translates text into the language with the BCP 47 code
+// targetLang.
+type TranslateFunc func(ctx context.Context, text, targetLang string) (string, error)
+
+// CrossLingualOption configures [DefineCrossLingualEvaluator].
+type CrossLingualOption func(opts *crossLingualOptions)
+
+type crossLingualOptions struct {
+	cache bool
+}
+
+// WithTranslationCache makes [DefineCrossLingualEvaluator] remember the
+// translation of each text, keyed by a hash of its content, so that texts
+// repeated across examples and evaluations are translated only once.
+func WithTranslationCache() CrossLingualOption {
+	return func(opts *crossLingualOptions) {
+		opts.cache = true
+	}
+}
+
+// DefineCrossLingualEvaluator registers an evaluator that translates the
+// Output and Reference of each [Example] into targetLang with translator
+// before passing the dataset to inner, so that outputs and references in
+// different languages can be compared. Fields that are not strings are
+// passed through unchanged. The results are those of inner.
+func DefineCrossLingualEvaluator(r *registry.Registry, provider, name string, translator TranslateFunc, inner Evaluator, targetLang string, opts ...CrossLingualOption) (Evaluator, error) {
+	if translator == nil {
+		return nil, errors.New("ai.DefineCrossLingualEvaluator: translator is required")
+	}
+	if inner == nil {
+		return nil, errors.New("ai.DefineCrossLingualEvaluator: inner evaluator is required")
+	}
+	if targetLang == "" {
+		return nil, errors.New("ai.DefineCrossLingualEvaluator: target language is required")
+	}
+	o := &crossLingualOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	t := &cachingTranslator{translate: translator, targetLang: targetLang}
+	if o.cache {
+		t.cache = map[[sha256.Size]byte]string{}
+	}
+
+	evalOpts := &EvaluatorOptions{
+		DisplayName: "Cross-Lingual " + inner.Name(),
+		Definition:  fmt.Sprintf("Translates outputs and references to %s before evaluating them with %s", targetLang, inner.Name()),
+	}
+	return DefineBatchEvaluator(r, provider, name, evalOpts, func(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
+		// The observer of req sees this evaluation, not that of inner.
+		innerReq := *req
+		innerReq.Observer = nil
+		if req.Dataset == nil {
+			return inner.Evaluate(ctx, &innerReq)
+		}
+		ds := make(Dataset, len(*req.Dataset))
+		for i, ex := range *req.Dataset {
+			var err error
+			if ex.Output, err = t.translateValue(ctx, ex.Output); err != nil {
+				return nil, fmt.Errorf("test case %q: output: %w", ex.TestCaseId, err)
+			}
+			if ex.Reference, err = t.translateValue(ctx, ex.Reference); err != nil {
+				return nil, fmt.Errorf("test case %q: reference: %w", ex.TestCaseId, err)
+			}
+			ds[i] = ex
+		}
+		innerReq.Dataset = &ds
+		return inner.Evaluate(ctx, &innerReq)
+	})
+}
+
+// cachingTranslator translates text into one language, remembering the
+// translations if cache is not nil.
+type cachingTranslator struct {
+	translate  TranslateFunc
+	targetLang string
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]string
+}
+
+// translateValue translates v if it is a non-empty string and returns it
+// unchanged otherwise.
+func (t *cachingTranslator) translateValue(ctx context.Context, v any) (any, error) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v, nil
+	}
+	if t.cache == nil {
+		return t.translate(ctx, s, t.targetLang)
+	}
+	key := sha256.Sum256([]byte(s))
+	t.mu.Lock()
+	translated, ok := t.cache[key]
+	t.mu.Unlock()
+	if ok {
+		return translated, nil
+	}
+	translated, err := t.translate(ctx, s, t.targetLang)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	t.cache[key] = translated
+	t.mu.Unlock()
+	return translated, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestCrossLingualEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exactMatch, err := DefineEvaluator(r, "test", "exactMatch", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		match := req.Input.Output == req.Input.Reference
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "match", Score: match, Status: passStatus(match).String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dictionary := map[string]string{"Hola": "Hello", "Bonjour": "Hello", "Adiós": "Goodbye"}
+	var translated []string
+	translate := func(ctx context.Context, text, targetLang string) (string, error) {
+		if targetLang != "en" {
+			return "", errors.New("unsupported language " + targetLang)
+		}
+		if text == "boom" {
+			return "", errors.New("boom")
+		}
+		translated = append(translated, text)
+		if s, ok := dictionary[text]; ok {
+			return s, nil
+		}
+		return text, nil
+	}
+
+	ds := Dataset{
+		{TestCaseId: "es", Output: "Hola", Reference: "Bonjour"},
+		{TestCaseId: "mismatch", Output: "Adiós", Reference: "Hola"},
+		{TestCaseId: "structured", Output: map[string]any{"greeting": "Hola"}, Reference: "Hola"},
+	}
+	want := map[string]bool{"es": true, "mismatch": false, "structured": false}
+
+	for _, cache := range []bool{false, true} {
+		translated = nil
+		var opts []CrossLingualOption
+		name := "crossLingual"
+		if cache {
+			opts = append(opts, WithTranslationCache())
+			name += "Cached"
+		}
+		evalAction, err := DefineCrossLingualEvaluator(r, "test", name, translate, exactMatch, "en", opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 2 {
+			resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for id, res := range indexResults(resp) {
+				if got := res.Evaluation[0].Score; got != want[id] {
+					t.Errorf("cache %v: %s: got match %v, want %v", cache, id, got, want[id])
+				}
+			}
+		}
+		// Two evaluations of five strings, of which only three are distinct.
+		wantCalls := 10
+		if cache {
+			wantCalls = 3
+		}
+		if got := len(translated); got != wantCalls {
+			t.Errorf("cache %v: got %d translations (%s), want %d", cache, got, strings.Join(translated, ", "), wantCalls)
+		}
+	}
+
+	evalAction, err := DefineCrossLingualEvaluator(r, "test", "crossLingualFailing", translate, exactMatch, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := Dataset{{TestCaseId: "bad", Output: "boom"}}
+	if _, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&failing)); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("got error %v, want translation failure", err)
+	}
+
+	// Observers see a single evaluation, not also that of the inner
+	// evaluator.
+	observed, err := DefineCrossLingualEvaluator(r, "test", "crossLingualObserved", translate, exactMatch, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	obs, events := NewChannelObserver()
+	if _, err := Evaluate(context.Background(), observed, WithEvaluateDataset(&ds), WithEvaluateObserver(obs)); err != nil {
+		t.Fatal(err)
+	}
+	var evaluators []string
+	for ev := range events {
+		if ev, ok := ev.(EvaluationStarted); ok {
+			evaluators = append(evaluators, ev.Evaluator)
+		}
+	}
+	if len(evaluators) != 1 || evaluators[0] != "test/crossLingualObserved" {
+		t.Errorf("got evaluations %v, want only test/crossLingualObserved", evaluators)
+	}
+
+	if _, err := DefineCrossLingualEvaluator(r, "test", "noLanguage", translate, exactMatch, ""); err == nil {
+		t.Error("got nil, want error for missing target language")
+	}
+}
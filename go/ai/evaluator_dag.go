@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// EvaluatorNode is an evaluator of an [EvaluatorDAG] together with the
+// evaluators that must run before it.
+type EvaluatorNode struct {
+	Evaluator Evaluator
+	// DependsOn holds the names of the evaluators of other nodes that must
+	// run first.
+	DependsOn []string
+	// SkipIfParentFails makes the evaluator skip the examples that failed
+	// in any of the nodes it depends on. Their result has a single score
+	// with unknown status, "skipped" in the "reason" key of [Score.Details]
+	// and the failed evaluator in the "failedDependency" key.
+	SkipIfParentFails bool
+}
+
+// EvaluatorDAG is a set of evaluators that depend on each other, run with
+// [RunDAG]. The dependencies must not form a cycle; [NewEvaluatorDAG]
+// checks this when the DAG is built.
+type EvaluatorDAG struct {
+	Nodes []EvaluatorNode
+}
+
+// NewEvaluatorDAG returns an [EvaluatorDAG] of nodes. It returns an error if
+// two nodes have evaluators with the same name, if a node depends on an
+// evaluator that is not in the DAG, or if the dependencies form a cycle.
+func NewEvaluatorDAG(nodes ...EvaluatorNode) (*EvaluatorDAG, error) {
+	dag := &EvaluatorDAG{Nodes: nodes}
+	if _, _, err := dag.resolve(); err != nil {
+		return nil, fmt.Errorf("ai.NewEvaluatorDAG: %w", err)
+	}
+	return dag, nil
+}
+
+// resolve returns the indexes of the parents of each node and an order of
+// the nodes in which parents come before their children.
+func (dag *EvaluatorDAG) resolve() (parents [][]int, order []int, err error) {
+	index := map[string]int{}
+	for i, n := range dag.Nodes {
+		if n.Evaluator == nil {
+			return nil, nil, fmt.Errorf("node %d has no evaluator", i)
+		}
+		name := n.Evaluator.Name()
+		if _, ok := index[name]; ok {
+			return nil, nil, fmt.Errorf("duplicate evaluator %q", name)
+		}
+		index[name] = i
+	}
+	parents = make([][]int, len(dag.Nodes))
+	children := make([][]int, len(dag.Nodes))
+	for i, n := range dag.Nodes {
+		for _, dep := range n.DependsOn {
+			p, ok := index[dep]
+			if !ok {
+				return nil, nil, fmt.Errorf("evaluator %q depends on unknown evaluator %q", n.Evaluator.Name(), dep)
+			}
+			parents[i] = append(parents[i], p)
+			children[p] = append(children[p], i)
+		}
+	}
+
+	// Kahn's algorithm, taking ready nodes in their order in dag.Nodes.
+	pending := make([]int, len(dag.Nodes))
+	var ready []int
+	for i := range dag.Nodes {
+		pending[i] = len(parents[i])
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, i)
+		for _, c := range children[i] {
+			if pending[c]--; pending[c] == 0 {
+				ready = append(ready, c)
+				slices.Sort(ready)
+			}
+		}
+	}
+	if len(order) < len(dag.Nodes) {
+		var cycle []string
+		for i, n := range dag.Nodes {
+			if pending[i] > 0 {
+				cycle = append(cycle, n.Evaluator.Name())
+			}
+		}
+		return nil, nil, fmt.Errorf("dependency cycle among evaluators %s", strings.Join(cycle, ", "))
+	}
+	return parents, order, nil
+}
+
+// RunDAG runs the evaluators of dag on ds. Each evaluator starts as soon as
+// the evaluators it depends on have finished, so independent evaluators
+// run concurrently. Examples without a TestCaseId are assigned one first.
+//
+// The response has one result per example, in dataset order, holding the
+// scores of all the evaluators in dependency order. The name of the
+// evaluator that produced each score is added to the "evaluator" key of its
+// [Score.Details]. As with [Evaluator.Evaluate], failures are reported in an
+// error returned along with the response.
+func RunDAG(ctx context.Context, dag *EvaluatorDAG, ds Dataset, opts ...EvaluateOption) (*EvaluatorResponse, error) {
+	parents, order, err := dag.resolve()
+	if err != nil {
+		return nil, fmt.Errorf("ai.RunDAG: %w", err)
+	}
+	ds = withTestCaseIds(ds)
+
+	var (
+		wg      sync.WaitGroup
+		done    = make([]chan struct{}, len(dag.Nodes))
+		results = make([]map[string]EvaluationResult, len(dag.Nodes))
+		errs    = make([]error, len(dag.Nodes))
+	)
+	for i := range dag.Nodes {
+		done[i] = make(chan struct{})
+	}
+	for i, node := range dag.Nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+			for _, p := range parents[i] {
+				<-done[p]
+			}
+			name := node.Evaluator.Name()
+			results[i] = map[string]EvaluationResult{}
+			var todo Dataset
+			for _, ex := range ds {
+				if failed := failedParent(dag, parents[i], results, ex.TestCaseId); node.SkipIfParentFails && failed != "" {
+					results[i][ex.TestCaseId] = EvaluationResult{
+						TestCaseId: ex.TestCaseId,
+						Evaluation: []Score{{
+							Status:  ScoreStatusUnknown.String(),
+							Details: map[string]any{"reason": "skipped", "failedDependency": failed},
+						}},
+					}
+					continue
+				}
+				todo = append(todo, ex)
+			}
+			if len(todo) == 0 {
+				return
+			}
+			resp, err := Evaluate(ctx, node.Evaluator, append([]EvaluateOption{WithEvaluateDataset(&todo)}, opts...)...)
+			if err != nil {
+				errs[i] = fmt.Errorf("evaluator %q: %w", name, err)
+			}
+			maps.Copy(results[i], indexResults(resp))
+		}()
+	}
+	wg.Wait()
+
+	var merged EvaluatorResponse
+	for _, ex := range ds {
+		res := EvaluationResult{TestCaseId: ex.TestCaseId, Evaluation: []Score{}}
+		for _, i := range order {
+			r, ok := results[i][ex.TestCaseId]
+			if !ok {
+				continue
+			}
+			if res.TraceID == "" {
+				res.TraceID, res.SpanID = r.TraceID, r.SpanID
+			}
+			for _, s := range r.Evaluation {
+				details := maps.Clone(s.Details)
+				if details == nil {
+					details = map[string]any{}
+				}
+				details["evaluator"] = dag.Nodes[i].Evaluator.Name()
+				s.Details = details
+				res.Evaluation = append(res.Evaluation, s)
+			}
+		}
+		setPartialCredit(&res, nil)
+		merged = append(merged, res)
+	}
+	return &merged, errors.Join(errs...)
+}
+
+// failedParent returns the name of the first of parents in which the
+// example failed, or whose evaluator produced no result for it, or "" if
+// there is none.
+func failedParent(dag *EvaluatorDAG, parents []int, results []map[string]EvaluationResult, testCaseId string) string {
+	for _, p := range parents {
+		res, ok := results[p][testCaseId]
+		if !ok || resultStatus(res) == ScoreStatusFail {
+			return dag.Nodes[p].Evaluator.Name()
+		}
+	}
+	return ""
+}
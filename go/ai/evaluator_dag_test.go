@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestRunDAG(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// toxicity and length wait for each other to start, so the test only
+	// finishes if independent nodes run concurrently.
+	var started sync.WaitGroup
+	started.Add(2)
+	waitForOther := func() {
+		started.Done()
+		ch := make(chan struct{})
+		go func() { started.Wait(); close(ch) }()
+		select {
+		case <-ch:
+		case <-time.After(5 * time.Second):
+			t.Error("independent evaluators did not run concurrently")
+		}
+	}
+	var once [2]sync.Once
+	define := func(name string, fn func(ex Example) bool, start *sync.Once) Evaluator {
+		e, err := DefineEvaluator(r, "test", name, &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+			if start != nil {
+				start.Do(waitForOther)
+			}
+			ok := fn(req.Input)
+			return &EvaluatorCallbackResponse{
+				TestCaseId: req.Input.TestCaseId,
+				Evaluation: []Score{{Id: name, Score: ok, Status: passStatus(ok).String()}},
+			}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	var qualityInputs []string
+	var mu sync.Mutex
+	toxicity := define("toxicity", func(ex Example) bool { return !strings.Contains(ex.Output.(string), "toxic") }, &once[0])
+	length := define("length", func(ex Example) bool { return len(ex.Output.(string)) < 20 }, &once[1])
+	quality := define("quality", func(ex Example) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		qualityInputs = append(qualityInputs, ex.TestCaseId)
+		return true
+	}, nil)
+
+	dag, err := NewEvaluatorDAG(
+		EvaluatorNode{Evaluator: quality, DependsOn: []string{"test/toxicity"}, SkipIfParentFails: true},
+		EvaluatorNode{Evaluator: toxicity},
+		EvaluatorNode{Evaluator: length},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := Dataset{{TestCaseId: "clean", Output: "nice answer"}, {TestCaseId: "bad", Output: "toxic answer"}}
+	resp, err := RunDAG(context.Background(), dag, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(qualityInputs, ","), "clean"; got != want {
+		t.Errorf("quality evaluated %q, want %q", got, want)
+	}
+	results := indexResults(resp)
+	var ids []string
+	for _, s := range results["clean"].Evaluation {
+		ids = append(ids, s.Details["evaluator"].(string))
+	}
+	if got, want := strings.Join(ids, ","), "test/toxicity,test/quality,test/length"; got != want {
+		t.Errorf("got scores from %s, want %s", got, want)
+	}
+	skipped := results["bad"].Evaluation[1]
+	if skipped.Status != ScoreStatusUnknown.String() || skipped.Details["failedDependency"] != "test/toxicity" || skipped.Details["evaluator"] != "test/quality" {
+		t.Errorf("got score %+v, want quality skipped because of toxicity", skipped)
+	}
+}
+
+func TestNewEvaluatorDAGErrors(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evals := map[string]Evaluator{}
+	for _, name := range []string{"a", "b", "c"} {
+		if evals[name], err = DefineEvaluator(r, "test", name, &evalOptions, testEvalFunc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		nodes []EvaluatorNode
+		want  string
+	}{
+		{"cycle", []EvaluatorNode{
+			{Evaluator: evals["a"], DependsOn: []string{"test/c"}},
+			{Evaluator: evals["b"], DependsOn: []string{"test/a"}},
+			{Evaluator: evals["c"], DependsOn: []string{"test/b"}},
+		}, "cycle"},
+		{"self dependency", []EvaluatorNode{{Evaluator: evals["a"], DependsOn: []string{"test/a"}}}, "cycle"},
+		{"unknown dependency", []EvaluatorNode{{Evaluator: evals["a"], DependsOn: []string{"test/z"}}}, "unknown evaluator"},
+		{"duplicate", []EvaluatorNode{{Evaluator: evals["a"]}, {Evaluator: evals["a"]}}, "duplicate"},
+	}
+	for _, test := range tests {
+		if _, err := NewEvaluatorDAG(test.nodes...); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got error %v, want error containing %q", test.name, err, test.want)
+		}
+	}
+}
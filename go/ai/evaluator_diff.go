@@ -0,0 +1,278 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// ScoreDiff is a score that differs between two evaluator responses. Old or
+// New is nil if the score is only in one of them. OldVersion and NewVersion
+// are the [EvaluationResult.EvaluatorVersion] of the results holding them.
+type ScoreDiff struct {
+	TestCaseId string `json:"testCaseId"`
+	ScoreId    string `json:"scoreId"`
+	Old        *Score `json:"old,omitempty"`
+	New        *Score `json:"new,omitempty"`
+	OldVersion string `json:"oldVersion,omitempty"`
+	NewVersion string `json:"newVersion,omitempty"`
+}
+
+// VersionMismatch reports whether the score was produced by different
+// versions of the evaluator, in which case its values may not be
+// comparable.
+func (d *ScoreDiff) VersionMismatch() bool {
+	return d.Old != nil && d.New != nil && d.OldVersion != d.NewVersion
+}
+
+// Delta returns New minus Old, and whether both scores are numeric.
+func (d *ScoreDiff) Delta() (float64, bool) {
+	if d.Old == nil || d.New == nil {
+		return 0, false
+	}
+	a, errA := d.Old.Normalize()
+	b, errB := d.New.Normalize()
+	if errA != nil || errB != nil {
+		return 0, false
+	}
+	return b - a, true
+}
+
+// IsRegression reports whether the score got worse: it passed before and
+// fails now, or its numeric value dropped by more than threshold.
+func (d *ScoreDiff) IsRegression(threshold float64) bool {
+	if d.Old == nil || d.New == nil {
+		return false
+	}
+	if d.Old.Status == ScoreStatusPass.String() && d.New.Status == ScoreStatusFail.String() {
+		return true
+	}
+	delta, ok := d.Delta()
+	return ok && -delta > threshold
+}
+
+// IsImprovement reports whether the score got better: it failed before and
+// passes now, or its numeric value rose by more than threshold.
+func (d *ScoreDiff) IsImprovement(threshold float64) bool {
+	if d.Old == nil || d.New == nil {
+		return false
+	}
+	if d.Old.Status == ScoreStatusFail.String() && d.New.Status == ScoreStatusPass.String() {
+		return true
+	}
+	delta, ok := d.Delta()
+	return ok && delta > threshold
+}
+
+// DiffEvaluatorResponses returns the scores whose value, status or
+// evaluator version differ between before and after, matched by TestCaseId
+// and [Score.Id], in the order they first appear. Use
+// [ScoreDiff.VersionMismatch] to find the scores of different evaluator
+// versions.
+func DiffEvaluatorResponses(before, after *EvaluatorResponse) []ScoreDiff {
+	diffs, _ := diffEvaluatorResponses(before, after)
+	return diffs
+}
+
+// diffEvaluatorResponses is like [DiffEvaluatorResponses] and also returns
+// the number of scores that are the same in before and after.
+func diffEvaluatorResponses(before, after *EvaluatorResponse) ([]ScoreDiff, int) {
+	type key struct{ testCaseId, scoreId string }
+	var keys []key
+	scores := [2]map[key]*Score{{}, {}}
+	versions := [2]map[key]string{{}, {}}
+	for i, resp := range []*EvaluatorResponse{before, after} {
+		if resp == nil {
+			continue
+		}
+		for _, res := range *resp {
+			for _, s := range res.Evaluation {
+				k := key{res.TestCaseId, s.Id}
+				if _, ok := scores[0][k]; !ok {
+					if _, ok := scores[1][k]; !ok {
+						keys = append(keys, k)
+					}
+				}
+				scores[i][k] = &s
+				versions[i][k] = res.EvaluatorVersion
+			}
+		}
+	}
+
+	diffs := []ScoreDiff{}
+	unchanged := 0
+	for _, k := range keys {
+		a, b := scores[0][k], scores[1][k]
+		d := ScoreDiff{TestCaseId: k.testCaseId, ScoreId: k.scoreId, Old: a, New: b, OldVersion: versions[0][k], NewVersion: versions[1][k]}
+		if a != nil && b != nil && reflect.DeepEqual(a.Score, b.Score) && a.Status == b.Status && !d.VersionMismatch() {
+			unchanged++
+			continue
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs, unchanged
+}
+
+// EvalDiff is the comparison of two evaluator responses, for example of a
+// baseline run and a candidate run in CI. Store it with
+// [MarshalDiffAsJSON] and load it with [UnmarshalEvalDiff].
+type EvalDiff struct {
+	// Threshold is the smallest change of a numeric score that counts as
+	// a regression or an improvement.
+	Threshold float64
+	// Diffs are the scores that differ, as returned by
+	// [DiffEvaluatorResponses].
+	Diffs []ScoreDiff
+	// Unchanged is the number of scores that are the same in both
+	// responses.
+	Unchanged int
+}
+
+// NewEvalDiff compares before and after. See [DiffEvaluatorResponses].
+func NewEvalDiff(before, after *EvaluatorResponse, threshold float64) *EvalDiff {
+	diffs, unchanged := diffEvaluatorResponses(before, after)
+	return &EvalDiff{Threshold: threshold, Diffs: diffs, Unchanged: unchanged}
+}
+
+// Change kinds of a [ScoreDiff] in the JSON form of an [EvalDiff].
+const (
+	changeRegression  = "regression"
+	changeImprovement = "improvement"
+	changeAdded       = "added"
+	changeRemoved     = "removed"
+	changeOther       = "changed"
+)
+
+// evalDiffJSON is the JSON form of an [EvalDiff].
+type evalDiffJSON struct {
+	Summary evalDiffSummary    `json:"summary"`
+	Scores  []evalDiffScoreIds `json:"scores"`
+	Changes []evalDiffChange   `json:"changes"`
+}
+
+type evalDiffSummary struct {
+	Threshold    float64 `json:"threshold"`
+	Regressions  int     `json:"regressions"`
+	Improvements int     `json:"improvements"`
+	Changed      int     `json:"changed"`
+	Unchanged    int     `json:"unchanged"`
+}
+
+// evalDiffScoreIds summarizes the changes to the scores with one ID.
+type evalDiffScoreIds struct {
+	ScoreId      string   `json:"scoreId"`
+	Regressions  int      `json:"regressions"`
+	Improvements int      `json:"improvements"`
+	Changed      int      `json:"changed"`
+	MeanDelta    *float64 `json:"meanDelta,omitempty"`
+}
+
+type evalDiffChange struct {
+	Kind string `json:"kind"`
+	ScoreDiff
+	Delta *float64 `json:"delta,omitempty"`
+}
+
+// kind returns the kind of change of d.
+func (d *ScoreDiff) kind(threshold float64) string {
+	switch {
+	case d.Old == nil:
+		return changeAdded
+	case d.New == nil:
+		return changeRemoved
+	case d.IsRegression(threshold):
+		return changeRegression
+	case d.IsImprovement(threshold):
+		return changeImprovement
+	}
+	return changeOther
+}
+
+// MarshalDiffAsJSON encodes diff as a JSON document holding summary counts
+// of regressions, improvements and unchanged scores, the changes broken
+// down by score ID with their mean numeric delta, and each changed score
+// with its kind and before and after values.
+func MarshalDiffAsJSON(diff *EvalDiff) ([]byte, error) {
+	if diff == nil {
+		return nil, fmt.Errorf("ai.MarshalDiffAsJSON: diff is nil")
+	}
+	doc := evalDiffJSON{
+		Summary: evalDiffSummary{Threshold: diff.Threshold, Unchanged: diff.Unchanged},
+		Scores:  []evalDiffScoreIds{},
+		Changes: []evalDiffChange{},
+	}
+	byId := map[string]*evalDiffScoreIds{}
+	deltas := map[string][]float64{}
+	for _, d := range diff.Diffs {
+		c := evalDiffChange{Kind: d.kind(diff.Threshold), ScoreDiff: d}
+		s, ok := byId[d.ScoreId]
+		if !ok {
+			s = &evalDiffScoreIds{ScoreId: d.ScoreId}
+			byId[d.ScoreId] = s
+		}
+		switch c.Kind {
+		case changeRegression:
+			doc.Summary.Regressions++
+			s.Regressions++
+		case changeImprovement:
+			doc.Summary.Improvements++
+			s.Improvements++
+		default:
+			doc.Summary.Changed++
+			s.Changed++
+		}
+		if delta, ok := d.Delta(); ok {
+			c.Delta = &delta
+			deltas[d.ScoreId] = append(deltas[d.ScoreId], delta)
+		}
+		doc.Changes = append(doc.Changes, c)
+	}
+	for _, id := range slices.Sorted(maps.Keys(byId)) {
+		s := byId[id]
+		if ds := deltas[id]; len(ds) > 0 {
+			mean, _ := meanStdDev(ds)
+			s.MeanDelta = &mean
+		}
+		doc.Scores = append(doc.Scores, *s)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("ai.MarshalDiffAsJSON: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalEvalDiff decodes an [EvalDiff] encoded by [MarshalDiffAsJSON].
+func UnmarshalEvalDiff(data []byte) (*EvalDiff, error) {
+	var doc evalDiffJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("ai.UnmarshalEvalDiff: %w", err)
+	}
+	diff := &EvalDiff{
+		Threshold: doc.Summary.Threshold,
+		Diffs:     make([]ScoreDiff, len(doc.Changes)),
+		Unchanged: doc.Summary.Unchanged,
+	}
+	for i, c := range doc.Changes {
+		diff.Diffs[i] = c.ScoreDiff
+	}
+	return diff, nil
+}
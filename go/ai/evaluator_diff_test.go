@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffEvaluatorResponses(t *testing.T) {
+	before := EvaluatorResponse{passFail("a", true, 0.9), passFail("b", true, 0.8), passFail("c", true, 1.0)}
+	after := EvaluatorResponse{passFail("a", true, 0.9), passFail("b", true, 0.75), passFail("c", false, 1.0), passFail("d", true, 1.0)}
+
+	diffs := DiffEvaluatorResponses(&before, &after)
+	var ids []string
+	for _, d := range diffs {
+		ids = append(ids, d.TestCaseId)
+	}
+	if got, want := len(diffs), 3; got != want {
+		t.Fatalf("got changes to %v, want %d changes", ids, want)
+	}
+	b, c, d := diffs[0], diffs[1], diffs[2]
+	if ids[0] != "b" || ids[1] != "c" || ids[2] != "d" {
+		t.Fatalf("got changes to %v, want b, c, d", ids)
+	}
+	if delta, ok := b.Delta(); !ok || delta > -0.049 || delta < -0.051 {
+		t.Errorf("got delta %v (%v), want -0.05", delta, ok)
+	}
+	if !b.IsRegression(0.01) || b.IsRegression(0.1) {
+		t.Error("a drop of 0.05 should be a regression only for thresholds below it")
+	}
+	if !c.IsRegression(0.1) {
+		t.Error("a change from pass to fail should be a regression")
+	}
+	if d.Old != nil || d.IsRegression(0) {
+		t.Errorf("a new score should not be a regression: %+v", d)
+	}
+}
+
+func TestEvalDiffJSON(t *testing.T) {
+	before := EvaluatorResponse{passFail("a", true, 0.9), passFail("b", true, 0.8), passFail("c", false, 0.2), passFail("e", true, 1.0)}
+	after := EvaluatorResponse{passFail("a", true, 0.9), passFail("b", true, 0.7), passFail("c", true, 0.6), passFail("d", true, 1.0)}
+	diff := NewEvalDiff(&before, &after, 0.05)
+
+	data, err := MarshalDiffAsJSON(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Summary map[string]float64 `json:"summary"`
+		Scores  []struct {
+			ScoreId   string   `json:"scoreId"`
+			MeanDelta *float64 `json:"meanDelta"`
+		} `json:"scores"`
+		Changes []struct {
+			Kind       string `json:"kind"`
+			TestCaseId string `json:"testCaseId"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	wantSummary := map[string]float64{"threshold": 0.05, "regressions": 1, "improvements": 1, "changed": 2, "unchanged": 1}
+	if diff := cmp.Diff(wantSummary, doc.Summary); diff != "" {
+		t.Errorf("summary mismatch (-want +got):\n%s", diff)
+	}
+	var kinds []string
+	for _, c := range doc.Changes {
+		kinds = append(kinds, c.TestCaseId+":"+c.Kind)
+	}
+	if diff := cmp.Diff([]string{"b:regression", "c:improvement", "e:removed", "d:added"}, kinds); diff != "" {
+		t.Errorf("changes mismatch (-want +got):\n%s", diff)
+	}
+	if len(doc.Scores) != 1 || doc.Scores[0].ScoreId != "s" || doc.Scores[0].MeanDelta == nil || math.Abs(*doc.Scores[0].MeanDelta-0.15) > 1e-9 {
+		t.Errorf("got score breakdown %+v, want mean delta 0.15 for s", doc.Scores)
+	}
+
+	got, err := UnmarshalEvalDiff(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(diff, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
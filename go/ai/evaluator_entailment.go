@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// NLILabel is the relation between a premise and a hypothesis as judged by a
+// natural language inference model.
+type NLILabel string
+
+const (
+	NLILabelEntailment    NLILabel = "entailment"
+	NLILabelNeutral       NLILabel = "neutral"
+	NLILabelContradiction NLILabel = "contradiction"
+)
+
+// nliLabelScores maps each [NLILabel] to its contribution to the entailment
+// score.
+var nliLabelScores = map[NLILabel]float64{
+	NLILabelEntailment:    1,
+	NLILabelNeutral:       0.5,
+	NLILabelContradiction: 0,
+}
+
+// nliVerdict is the structured output requested from the NLI model.
+type nliVerdict struct {
+	Label NLILabel `json:"label" jsonschema:"enum=entailment,enum=neutral,enum=contradiction"`
+}
+
+// DefineEntailmentEvaluator registers an evaluator that checks whether the
+// Output and Reference of each [Example] are semantically equivalent by
+// asking nliModel, a natural language inference model, whether the output
+// entails the reference and whether the reference entails the output.
+//
+// Each direction scores 1 for entailment, 0.5 for neutral and 0 for
+// contradiction, and the score is the mean of both directions. The example
+// passes only if the entailment holds both ways. The labels are reported in
+// the "forward" (output to reference) and "backward" keys of [Score.Details].
+// If opts is nil, default options are used.
+func DefineEntailmentEvaluator(r *registry.Registry, provider, name string, nliModel Model, opts *EvaluatorOptions) (Evaluator, error) {
+	if nliModel == nil {
+		return nil, errors.New("ai.DefineEntailmentEvaluator: NLI model is required")
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Semantic Equivalence",
+			Definition:  "Checks that the output and reference entail each other",
+		}
+	}
+
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		if req.Input.Reference == nil {
+			return nil, errors.New("reference was not provided")
+		}
+		output, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+		reference, err := exampleText(req.Input.Reference)
+		if err != nil {
+			return nil, err
+		}
+
+		forward, err := classifyEntailment(ctx, r, nliModel, output, reference)
+		if err != nil {
+			return nil, err
+		}
+		backward, err := classifyEntailment(ctx, r, nliModel, reference, output)
+		if err != nil {
+			return nil, err
+		}
+
+		both := forward == NLILabelEntailment && backward == NLILabelEntailment
+		score := Score{
+			Id:     name,
+			Score:  (nliLabelScores[forward] + nliLabelScores[backward]) / 2,
+			Status: passStatus(both).String(),
+			Details: map[string]any{
+				"forward":  string(forward),
+				"backward": string(backward),
+			},
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// classifyEntailment asks model whether premise entails hypothesis.
+func classifyEntailment(ctx context.Context, r *registry.Registry, model Model, premise, hypothesis string) (NLILabel, error) {
+	prompt := fmt.Sprintf("Classify the relation between the premise and the hypothesis as entailment, neutral or contradiction.\n\nPremise:\n%s\n\nHypothesis:\n%s", premise, hypothesis)
+	var v nliVerdict
+	if _, err := GenerateData(ctx, r, &v, WithModel(model), WithPromptText(prompt)); err != nil {
+		return "", fmt.Errorf("NLI model %q: %w", model.Name(), err)
+	}
+	if _, ok := nliLabelScores[v.Label]; !ok {
+		return "", fmt.Errorf("NLI model %q returned unknown label %q", model.Name(), v.Label)
+	}
+	return v.Label, nil
+}
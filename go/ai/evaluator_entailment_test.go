@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestEntailmentEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The NLI model contradicts anything mentioning Berlin, entails any
+	// hypothesis from a specific description of Paris and is neutral otherwise.
+	nli := defineJudgeModel(r, "nli", func(prompt string) any {
+		_, rest, _ := strings.Cut(prompt, "Premise:\n")
+		premise, _, _ := strings.Cut(rest, "\n\nHypothesis:\n")
+		specific := func(s string) bool { return s == "Paris" || s == "France's capital" }
+		switch {
+		case strings.Contains(prompt, "Berlin"):
+			return nliVerdict{Label: NLILabelContradiction}
+		case specific(premise):
+			return nliVerdict{Label: NLILabelEntailment}
+		}
+		return nliVerdict{Label: NLILabelNeutral}
+	})
+	evalAction, err := DefineEntailmentEvaluator(r, "test", "equivalence", nli, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{Input: "q", Output: "Paris", Reference: "France's capital"},
+		{Input: "q", Output: "Paris", Reference: "a city in France"},
+		{Input: "q", Output: "Berlin", Reference: "Paris"},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		score             float64
+		status            string
+		forward, backward string
+	}{
+		{1, "pass", "entailment", "entailment"},
+		{0.75, "fail", "entailment", "neutral"},
+		{0, "fail", "contradiction", "contradiction"},
+	}
+	for i, test := range tests {
+		s := (*resp)[i].Evaluation[0]
+		if got := s.Score; got != test.score {
+			t.Errorf("example %d: got score %v, want %v", i, got, test.score)
+		}
+		if got := s.Status; got != test.status {
+			t.Errorf("example %d: got status %v, want %v", i, got, test.status)
+		}
+		if got := s.Details["forward"]; got != test.forward {
+			t.Errorf("example %d: got forward %v, want %v", i, got, test.forward)
+		}
+		if got := s.Details["backward"]; got != test.backward {
+			t.Errorf("example %d: got backward %v, want %v", i, got, test.backward)
+		}
+	}
+}
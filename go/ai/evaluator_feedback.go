@@ -0,0 +1,189 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HumanFeedback is a review of an example's automated evaluation by a
+// person, with corrected scores where the reviewer disagreed.
+type HumanFeedback struct {
+	// EvaluationId is the evaluation that produced the reviewed scores.
+	EvaluationId string `json:"evaluationId"`
+	Reviewer     string `json:"reviewer"`
+	Comment      string `json:"comment,omitempty"`
+	// CorrectedScores replace the automated scores with the same [Score.Id].
+	CorrectedScores []Score   `json:"correctedScores,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// FeedbackStore persists [HumanFeedback].
+type FeedbackStore interface {
+	// Store adds feedback for the example with the given TestCaseId.
+	Store(ctx context.Context, exampleId string, feedback HumanFeedback) error
+	// Load returns the feedback for the evaluation evalId keyed by the
+	// example's TestCaseId, in the order it was stored.
+	Load(ctx context.Context, evalId string) (map[string][]HumanFeedback, error)
+}
+
+// CollectHumanFeedback stores feedback for the example exampleId in store.
+// The feedback must name the reviewer and the evaluation. If
+// feedback.Timestamp is zero, the current time is used.
+func CollectHumanFeedback(ctx context.Context, exampleId string, feedback HumanFeedback, store FeedbackStore) error {
+	switch {
+	case exampleId == "":
+		return errors.New("ai.CollectHumanFeedback: example ID is required")
+	case feedback.EvaluationId == "":
+		return errors.New("ai.CollectHumanFeedback: evaluation ID is required")
+	case feedback.Reviewer == "":
+		return errors.New("ai.CollectHumanFeedback: reviewer is required")
+	}
+	if feedback.Timestamp.IsZero() {
+		feedback.Timestamp = time.Now()
+	}
+	if err := store.Store(ctx, exampleId, feedback); err != nil {
+		return fmt.Errorf("ai.CollectHumanFeedback: %w", err)
+	}
+	return nil
+}
+
+// LoadFeedbackForEvaluation returns the feedback stored for the evaluation
+// evalId, keyed by TestCaseId.
+func LoadFeedbackForEvaluation(ctx context.Context, evalId string, store FeedbackStore) (map[string][]HumanFeedback, error) {
+	fb, err := store.Load(ctx, evalId)
+	if err != nil {
+		return nil, fmt.Errorf("ai.LoadFeedbackForEvaluation: %w", err)
+	}
+	if fb == nil {
+		fb = map[string][]HumanFeedback{}
+	}
+	return fb, nil
+}
+
+// MergeHumanFeedback returns a copy of resp in which every score that was
+// corrected in feedback, keyed by TestCaseId as returned by
+// [LoadFeedbackForEvaluation], is replaced by
+//
+//	humanWeight*human + (1-humanWeight)*automated
+//
+// where human is the mean of the corrected scores with the same [Score.Id].
+// Both must be numeric. If humanWeight is above 0.5, the status of the most
+// recent correction that has one replaces the automated status. The merged
+// score's details record the "automatedScore", "humanScore" and
+// "reviewers".
+func MergeHumanFeedback(resp *EvaluatorResponse, feedback map[string][]HumanFeedback, humanWeight float64) (*EvaluatorResponse, error) {
+	if humanWeight < 0 || humanWeight > 1 {
+		return nil, fmt.Errorf("ai.MergeHumanFeedback: human weight %v is not in [0, 1]", humanWeight)
+	}
+	merged := EvaluatorResponse{}
+	if resp == nil {
+		return &merged, nil
+	}
+	for _, res := range *resp {
+		fbs := slices.Clone(feedback[res.TestCaseId])
+		slices.SortStableFunc(fbs, func(a, b HumanFeedback) int { return a.Timestamp.Compare(b.Timestamp) })
+		res.Evaluation = slices.Clone(res.Evaluation)
+		for i, s := range res.Evaluation {
+			var values []float64
+			var reviewers []string
+			status := ""
+			for _, fb := range fbs {
+				for _, c := range fb.CorrectedScores {
+					if c.Id != s.Id {
+						continue
+					}
+					v, err := c.Normalize()
+					if err != nil {
+						return nil, fmt.Errorf("ai.MergeHumanFeedback: test case %q: correction by %s: %w", res.TestCaseId, fb.Reviewer, err)
+					}
+					values = append(values, v)
+					reviewers = append(reviewers, fb.Reviewer)
+					if c.Status != "" {
+						status = c.Status
+					}
+				}
+			}
+			if len(values) == 0 {
+				continue
+			}
+			auto, err := s.Normalize()
+			if err != nil {
+				return nil, fmt.Errorf("ai.MergeHumanFeedback: test case %q: %w", res.TestCaseId, err)
+			}
+			human, _ := meanStdDev(values)
+			s.Score = humanWeight*human + (1-humanWeight)*auto
+			if status != "" && humanWeight > 0.5 {
+				s.Status = status
+			}
+			s.Details = maps.Clone(s.Details)
+			if s.Details == nil {
+				s.Details = map[string]any{}
+			}
+			s.Details["automatedScore"] = auto
+			s.Details["humanScore"] = human
+			s.Details["reviewers"] = reviewers
+			res.Evaluation[i] = s
+		}
+		merged = append(merged, res)
+	}
+	return &merged, nil
+}
+
+// InMemoryFeedbackStore is a [FeedbackStore] that keeps feedback in memory.
+// It is safe for concurrent use.
+type InMemoryFeedbackStore struct {
+	mu sync.RWMutex
+	// feedback maps evaluation IDs to feedback keyed by TestCaseId.
+	feedback map[string]map[string][]HumanFeedback
+}
+
+// NewInMemoryFeedbackStore returns an empty [InMemoryFeedbackStore].
+func NewInMemoryFeedbackStore() *InMemoryFeedbackStore {
+	return &InMemoryFeedbackStore{feedback: map[string]map[string][]HumanFeedback{}}
+}
+
+// Store implements [FeedbackStore.Store].
+func (s *InMemoryFeedbackStore) Store(ctx context.Context, exampleId string, feedback HumanFeedback) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byExample, ok := s.feedback[feedback.EvaluationId]
+	if !ok {
+		byExample = map[string][]HumanFeedback{}
+		s.feedback[feedback.EvaluationId] = byExample
+	}
+	feedback.CorrectedScores = slices.Clone(feedback.CorrectedScores)
+	byExample[exampleId] = append(byExample[exampleId], feedback)
+	return nil
+}
+
+// Load implements [FeedbackStore.Load].
+func (s *InMemoryFeedbackStore) Load(ctx context.Context, evalId string) (map[string][]HumanFeedback, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fb := map[string][]HumanFeedback{}
+	for id, fbs := range s.feedback[evalId] {
+		fb[id] = slices.Clone(fbs)
+	}
+	return fb, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHumanFeedback(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryFeedbackStore()
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, fb := range []struct {
+		id string
+		HumanFeedback
+	}{
+		{"b", HumanFeedback{EvaluationId: "eval1", Reviewer: "ann", CorrectedScores: []Score{{Id: "s", Score: 0.2, Status: "fail"}}, Timestamp: t0}},
+		{"b", HumanFeedback{EvaluationId: "eval1", Reviewer: "bob", Comment: "too harsh", CorrectedScores: []Score{{Id: "s", Score: 0.4}}, Timestamp: t0.Add(time.Hour)}},
+		{"a", HumanFeedback{EvaluationId: "eval2", Reviewer: "ann", CorrectedScores: []Score{{Id: "s", Score: 0}}}},
+	} {
+		if err := CollectHumanFeedback(ctx, fb.id, fb.HumanFeedback, store); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, bad := range []HumanFeedback{{EvaluationId: "eval1"}, {Reviewer: "ann"}} {
+		if err := CollectHumanFeedback(ctx, "a", bad, store); err == nil {
+			t.Errorf("%+v: got nil, want error", bad)
+		}
+	}
+
+	fb, err := LoadFeedbackForEvaluation(ctx, "eval1", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(fb["b"]), 2; len(fb) != 1 || got != want {
+		t.Fatalf("got feedback %v, want %d entries for b only", fb, want)
+	}
+	if fb2, _ := LoadFeedbackForEvaluation(ctx, "eval2", store); fb2["a"][0].Timestamp.IsZero() {
+		t.Error("timestamp was not defaulted to now")
+	}
+
+	resp := EvaluatorResponse{passFail("a", true, 1.0), passFail("b", true, 0.9)}
+	merged, err := MergeHumanFeedback(&resp, fb, 0.75)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (*merged)[0].Evaluation[0].Score; got != 1.0 {
+		t.Errorf("a: got score %v, want it unchanged", got)
+	}
+	b := (*merged)[1].Evaluation[0]
+	if got, want := b.Score.(float64), 0.75*0.3+0.25*0.9; math.Abs(got-want) > 1e-9 {
+		t.Errorf("b: got score %v, want %v", got, want)
+	}
+	if b.Status != "fail" {
+		t.Errorf("b: got status %q, want fail from the human correction", b.Status)
+	}
+	if resp[1].Evaluation[0].Score != 0.9 {
+		t.Error("MergeHumanFeedback modified its input")
+	}
+
+	merged, err = MergeHumanFeedback(&resp, fb, 0.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (*merged)[1].Evaluation[0].Status; got != "pass" {
+		t.Errorf("got status %q with a low human weight, want the automated status", got)
+	}
+	if _, err := MergeHumanFeedback(&resp, fb, 2); err == nil {
+		t.Error("got nil, want error for a weight above 1")
+	}
+}
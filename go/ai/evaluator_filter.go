@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ScoreFilter transforms a [Score] after evaluation, for example to
+// calibrate or clip it.
+type ScoreFilter interface {
+	Apply(s Score) (Score, error)
+}
+
+// ApplyScoreFilters returns a copy of resp in which each score with the
+// given id has been passed through filters, in order. Other scores are
+// copied unchanged. It returns an error if a filter fails on any score.
+func ApplyScoreFilters(resp *EvaluatorResponse, scoreId string, filters ...ScoreFilter) (*EvaluatorResponse, error) {
+	if resp == nil {
+		return nil, nil
+	}
+	out := make(EvaluatorResponse, len(*resp))
+	for i, res := range *resp {
+		res.Evaluation = slices.Clone(res.Evaluation)
+		for j, s := range res.Evaluation {
+			if s.Id != scoreId {
+				continue
+			}
+			for _, f := range filters {
+				var err error
+				if s, err = f.Apply(s); err != nil {
+					return nil, fmt.Errorf("ai.ApplyScoreFilters: test case %q: %w", res.TestCaseId, err)
+				}
+			}
+			res.Evaluation[j] = s
+		}
+		out[i] = res
+	}
+	return &out, nil
+}
+
+// numericFilter is a [ScoreFilter] that maps the numeric value of a score.
+// A [ProbabilisticScore] is replaced by its transformed mean.
+type numericFilter struct {
+	name string
+	fn   func(float64) float64
+	err  error // Configuration error, reported by Apply.
+}
+
+func (f *numericFilter) Apply(s Score) (Score, error) {
+	if f.err != nil {
+		return s, fmt.Errorf("%s filter: %w", f.name, f.err)
+	}
+	v, err := s.Normalize()
+	if err != nil {
+		return s, fmt.Errorf("%s filter: score %q: %w", f.name, s.Id, err)
+	}
+	s.Score = f.fn(v)
+	return s, nil
+}
+
+// NewClipFilter returns a [ScoreFilter] that limits numeric scores to the
+// range [lo, hi]. Applying it fails if lo is greater than hi.
+func NewClipFilter(lo, hi float64) ScoreFilter {
+	f := &numericFilter{name: "clip", fn: func(v float64) float64 { return min(max(v, lo), hi) }}
+	if lo > hi {
+		f.err = fmt.Errorf("min %v is greater than max %v", lo, hi)
+	}
+	return f
+}
+
+// NewSigmoidFilter returns a [ScoreFilter] that maps a numeric score x to
+// 1 / (1 + exp(-x / temperature)), squashing it into (0, 1). Applying it
+// fails if temperature is not positive.
+func NewSigmoidFilter(temperature float64) ScoreFilter {
+	f := &numericFilter{name: "sigmoid", fn: func(v float64) float64 { return 1 / (1 + math.Exp(-v/temperature)) }}
+	if !(temperature > 0) {
+		f.err = fmt.Errorf("temperature %v is not positive", temperature)
+	}
+	return f
+}
+
+// NewLinearScaleFilter returns a [ScoreFilter] that maps a numeric score x
+// to x*scale + offset.
+func NewLinearScaleFilter(scale, offset float64) ScoreFilter {
+	return &numericFilter{name: "linear scale", fn: func(v float64) float64 { return v*scale + offset }}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"math"
+	"testing"
+)
+
+func TestApplyScoreFilters(t *testing.T) {
+	resp := EvaluatorResponse{
+		{TestCaseId: "a", Evaluation: []Score{{Id: "s", Score: 4.0}, {Id: "other", Score: 4.0}}},
+		{TestCaseId: "b", Evaluation: []Score{{Id: "s", Score: -1.0}}},
+	}
+	got, err := ApplyScoreFilters(&resp, "s", NewLinearScaleFilter(0.5, 0), NewClipFilter(0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{1, 0} {
+		if s := (*got)[i].Evaluation[0].Score; s != want {
+			t.Errorf("result %d: got score %v, want %v", i, s, want)
+		}
+	}
+	if s := (*got)[0].Evaluation[1].Score; s != 4.0 {
+		t.Errorf("got other score %v, want it unchanged", s)
+	}
+	if s := resp[0].Evaluation[0].Score; s != 4.0 {
+		t.Errorf("input response was modified: got score %v", s)
+	}
+
+	got, err = ApplyScoreFilters(&resp, "s", NewSigmoidFilter(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, want := (*got)[0].Evaluation[0].Score.(float64), 1/(1+math.Exp(-2)); math.Abs(s-want) > 1e-12 {
+		t.Errorf("got sigmoid score %v, want %v", s, want)
+	}
+
+	for name, f := range map[string]ScoreFilter{
+		"sigmoid": NewSigmoidFilter(0),
+		"clip":    NewClipFilter(1, 0),
+	} {
+		if _, err := ApplyScoreFilters(&resp, "s", f); err == nil {
+			t.Errorf("%s: got nil, want configuration error", name)
+		}
+	}
+	text := EvaluatorResponse{{TestCaseId: "a", Evaluation: []Score{{Id: "s", Score: "good"}}}}
+	if _, err := ApplyScoreFilters(&text, "s", NewClipFilter(0, 1)); err == nil {
+		t.Error("got nil, want error for non-numeric score")
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+const (
+	// goCodeTestTimeout is passed to go test with -timeout.
+	goCodeTestTimeout = 5 * time.Second
+	// goCodeBuildTimeout bounds the whole go test invocation, including
+	// compilation.
+	goCodeBuildTimeout = time.Minute
+)
+
+// DefineGoCodeEvaluator registers an evaluator named "go_code" that checks
+// generated Go code by running tests against it. The Output of each [Example]
+// must be the source of a Go file and its Reference the source of a Go test
+// file for the same package.
+//
+// The two files are written to a fresh temporary module, which is removed
+// afterwards, and checked with "go test". Tests are limited to 5 seconds and
+// the module cannot download dependencies, so only the standard library is
+// available. The example passes if go test succeeds; its output is reported in
+// the "stdout" and "stderr" keys of [Score.Details].
+//
+// go test runs with a minimal environment whose home directory and GOPATH are
+// inside the temporary module directory. Instead of the build cache of the
+// user, the examples of the evaluator share a build cache in another
+// temporary directory, so that the standard library is compiled only once.
+// That directory is not removed. This is not isolation: the generated code
+// runs with the privileges of the calling process and can read its files and
+// reach the network. Only evaluate untrusted code inside a sandbox such as a
+// container.
+//
+// The go command must be on the PATH. If opts is nil, default options are
+// used.
+func DefineGoCodeEvaluator(r *registry.Registry, provider string, opts *EvaluatorOptions) (Evaluator, error) {
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Go Code Correctness",
+			Definition:  "Runs the reference Go tests against the generated Go code",
+		}
+	}
+
+	cache := &goBuildCache{}
+	return DefineEvaluator(r, provider, "go_code", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		code, ok := req.Input.Output.(string)
+		if !ok {
+			return nil, errors.New("output must be a string of Go source")
+		}
+		tests, ok := req.Input.Reference.(string)
+		if !ok {
+			return nil, errors.New("reference must be a string of Go test source")
+		}
+
+		cacheDir, err := cache.get()
+		if err != nil {
+			return nil, err
+		}
+		res, err := runGoTest(ctx, cacheDir, code, tests)
+		if err != nil {
+			return nil, err
+		}
+		score := Score{
+			Id:     "go_code",
+			Score:  1.0,
+			Status: ScoreStatusPass.String(),
+			Details: map[string]any{
+				"stdout":   res.stdout,
+				"stderr":   res.stderr,
+				"exitCode": res.exitCode,
+			},
+		}
+		if res.timedOut {
+			score.Details["timedOut"] = true
+		}
+		if res.exitCode != 0 {
+			score.Score = 0.0
+			score.Status = ScoreStatusFail.String()
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// goTestResult is the outcome of running go test.
+type goTestResult struct {
+	stdout, stderr string
+	exitCode       int
+	timedOut       bool
+}
+
+// goBuildCache is the build cache shared by the go test runs of one
+// evaluator, created on first use.
+type goBuildCache struct {
+	once sync.Once
+	dir  string
+	err  error
+}
+
+func (c *goBuildCache) get() (string, error) {
+	c.once.Do(func() {
+		c.dir, c.err = os.MkdirTemp("", "genkit-gocode-cache-")
+	})
+	return c.dir, c.err
+}
+
+// runGoTest runs go test on a temporary module holding code and tests, using
+// the build cache in cacheDir. It returns an error only if the tests could
+// not be run at all.
+func runGoTest(ctx context.Context, cacheDir, code, tests string) (*goTestResult, error) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return nil, fmt.Errorf("go command not found: %w", err)
+	}
+	tmp, err := os.MkdirTemp("", "genkit-gocode-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	dir := filepath.Join(tmp, "module")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{
+		"go.mod":       "module gocodeeval\n\ngo 1.22\n",
+		"code.go":      code,
+		"code_test.go": tests,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, goCodeBuildTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(runCtx, goBin, "test", "-timeout", goCodeTestTimeout.String(), ".")
+	cmd.Dir = dir
+	cmd.Env = goTestEnv(tmp, cacheDir)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	res := &goTestResult{}
+	err = cmd.Run()
+	res.stdout, res.stderr = stdout.String(), stderr.String()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case runCtx.Err() != nil:
+		res.exitCode = -1
+		res.timedOut = true
+	case errors.As(err, &exitErr):
+		res.exitCode = exitErr.ExitCode()
+	default:
+		return nil, err
+	}
+	return res, nil
+}
+
+// goTestEnv returns the environment of go test runs under tmp. It passes on
+// only PATH, so that credentials and other settings of the calling process do
+// not leak to the code under test, and keeps the home directory and GOPATH
+// under tmp and the build cache in cacheDir.
+func goTestEnv(tmp, cacheDir string) []string {
+	return []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + tmp,
+		"TMPDIR=" + tmp,
+		"GOCACHE=" + cacheDir,
+		"GOPATH=" + filepath.Join(tmp, "gopath"),
+		"GOFLAGS=-mod=mod",
+		"GOPROXY=off",
+		"GOWORK=off",
+		"GOTOOLCHAIN=local",
+	}
+}
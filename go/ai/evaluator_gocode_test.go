@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestGoCodeEvaluator(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	if testing.Short() {
+		t.Skip("skipping go test invocation in short mode")
+	}
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineGoCodeEvaluator(r, "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const tests = `package add
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if got := Add(2, 3); got != 5 {
+		t.Errorf("Add(2, 3) = %d, want 5", got)
+	}
+}
+`
+	ds := Dataset{
+		{TestCaseId: "correct", Input: "q", Output: "package add\n\nfunc Add(a, b int) int { return a + b }\n", Reference: tests},
+		{TestCaseId: "wrong", Input: "q", Output: "package add\n\nfunc Add(a, b int) int { return a - b }\n", Reference: tests},
+		{TestCaseId: "broken", Input: "q", Output: "package add\n\nfunc Add(a, b int) int {\n", Reference: tests},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []string{"pass", "fail", "fail"} {
+		if got := (*resp)[i].Evaluation[0].Status; got != want {
+			t.Errorf("%s: got status %v, want %v", (*resp)[i].TestCaseId, got, want)
+		}
+	}
+	if out := (*resp)[1].Evaluation[0].Details["stdout"].(string); !strings.Contains(out, "Add(2, 3) = -1") {
+		t.Errorf("stdout %q does not contain the test failure", out)
+	}
+}
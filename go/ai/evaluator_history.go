@@ -0,0 +1,242 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// EvalRun records the outcome of an evaluation so that metrics can be
+// tracked over time. See [RecordEvalRun] and [LoadEvalHistory].
+type EvalRun struct {
+	EvalId       string       `json:"evalId"`
+	RunId        string       `json:"runId,omitempty"`
+	Evaluator    string       `json:"evaluator,omitempty"`
+	ModelVersion string       `json:"modelVersion,omitempty"`
+	Timestamp    time.Time    `json:"timestamp"`
+	Summary      ScoreSummary `json:"summary"`
+}
+
+// EvalFilter selects runs in [LoadEvalHistory]. Zero fields match all runs.
+type EvalFilter struct {
+	// Since and Until bound the run timestamps, inclusively.
+	Since        time.Time
+	Until        time.Time
+	ModelVersion string
+	Evaluator    string
+}
+
+func (f *EvalFilter) matches(run *EvalRun) bool {
+	switch {
+	case !f.Since.IsZero() && run.Timestamp.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && run.Timestamp.After(f.Until):
+		return false
+	case f.ModelVersion != "" && run.ModelVersion != f.ModelVersion:
+		return false
+	case f.Evaluator != "" && run.Evaluator != f.Evaluator:
+		return false
+	}
+	return true
+}
+
+// RecordEvalRun stores run in store. If run.Timestamp is zero, the current
+// time is used.
+func RecordEvalRun(ctx context.Context, store StoreEvaluatorResponse, run EvalRun) error {
+	if run.EvalId == "" {
+		return errors.New("ai.RecordEvalRun: evaluation ID is required")
+	}
+	if run.Timestamp.IsZero() {
+		run.Timestamp = time.Now()
+	}
+	if err := store.SaveRun(ctx, &run); err != nil {
+		return fmt.Errorf("ai.RecordEvalRun: %w", err)
+	}
+	return nil
+}
+
+// LoadEvalHistory returns the runs in store that match filter, ordered by
+// timestamp.
+func LoadEvalHistory(ctx context.Context, store StoreEvaluatorResponse, filter EvalFilter) ([]EvalRun, error) {
+	runs, err := store.LoadRuns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ai.LoadEvalHistory: %w", err)
+	}
+	var history []EvalRun
+	for _, run := range runs {
+		if filter.matches(run) {
+			history = append(history, *run)
+		}
+	}
+	slices.SortStableFunc(history, func(a, b EvalRun) int { return a.Timestamp.Compare(b.Timestamp) })
+	return history, nil
+}
+
+// TrendDirection is the direction in which a metric is moving.
+type TrendDirection int
+
+const (
+	TrendFlat TrendDirection = iota
+	TrendImproving
+	TrendDegrading
+)
+
+var trendDirectionName = map[TrendDirection]string{
+	TrendFlat:      "flat",
+	TrendImproving: "improving",
+	TrendDegrading: "degrading",
+}
+
+func (d TrendDirection) String() string {
+	if n, ok := trendDirectionName[d]; ok {
+		return n
+	}
+	return "unknown"
+}
+
+// Trend describes how the mean of a score evolved over a series of runs.
+type Trend struct {
+	ScoreId string `json:"scoreId"`
+	// Points is the number of runs that had numeric values for the score.
+	Points int `json:"points"`
+	// Slope is the least-squares slope of the mean score, per day.
+	Slope     float64        `json:"slope"`
+	Min       float64        `json:"min"`
+	Max       float64        `json:"max"`
+	Direction TrendDirection `json:"direction"`
+}
+
+// ComputeTrend fits a line through the mean of scoreId in each run of
+// history, which need not be sorted. Higher scores are assumed to be better.
+// It returns nil if no run has numeric values for the score.
+func ComputeTrend(history []EvalRun, scoreId string) *Trend {
+	var times []time.Time
+	var ys []float64
+	for _, run := range history {
+		if stats := run.Summary.Scores[scoreId]; stats != nil && stats.Numeric > 0 {
+			times = append(times, run.Timestamp)
+			ys = append(ys, stats.Mean)
+		}
+	}
+	if len(ys) == 0 {
+		return nil
+	}
+
+	t := &Trend{ScoreId: scoreId, Points: len(ys), Min: slices.Min(ys), Max: slices.Max(ys)}
+	start := slices.MinFunc(times, time.Time.Compare)
+	xs := make([]float64, len(times))
+	for i, ts := range times {
+		xs[i] = ts.Sub(start).Hours() / 24
+	}
+	mx, _ := meanStdDev(xs)
+	my, _ := meanStdDev(ys)
+	var num, den float64
+	for i := range xs {
+		num += (xs[i] - mx) * (ys[i] - my)
+		den += (xs[i] - mx) * (xs[i] - mx)
+	}
+	if den > 0 {
+		t.Slope = num / den
+	}
+	switch {
+	case t.Slope > 0:
+		t.Direction = TrendImproving
+	case t.Slope < 0:
+		t.Direction = TrendDegrading
+	}
+	return t
+}
+
+// ScoreHistoryEntry is the score of one test case in one recorded run. See
+// [GetScoreHistory].
+type ScoreHistoryEntry struct {
+	EvalId       string    `json:"evalId"`
+	ModelVersion string    `json:"modelVersion,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+	Score        Score     `json:"score"`
+}
+
+// GetScoreHistory returns the score with id scoreId that testCaseId received
+// in each run recorded in store, ordered by timestamp. Runs in which the test
+// case has no such score are skipped, as are evaluations without a run
+// record, since they have no timestamp.
+func GetScoreHistory(ctx context.Context, store StoreEvaluatorResponse, testCaseId, scoreId string) ([]ScoreHistoryEntry, error) {
+	runs, err := LoadEvalHistory(ctx, store, EvalFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("ai.GetScoreHistory: %w", err)
+	}
+	var history []ScoreHistoryEntry
+	for _, run := range runs {
+		resp, err := store.Load(ctx, run.EvalId)
+		if errors.Is(err, ErrEvaluationNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ai.GetScoreHistory: %w", err)
+		}
+		for _, res := range *resp {
+			if res.TestCaseId != testCaseId {
+				continue
+			}
+			for _, s := range res.Evaluation {
+				if s.Id == scoreId {
+					history = append(history, ScoreHistoryEntry{
+						EvalId:       run.EvalId,
+						ModelVersion: run.ModelVersion,
+						Timestamp:    run.Timestamp,
+						Score:        s,
+					})
+				}
+			}
+		}
+	}
+	return history, nil
+}
+
+// RegressionEvent is a drop in score between two consecutive entries of a
+// score history. See [DetectRegressions].
+type RegressionEvent struct {
+	From ScoreHistoryEntry `json:"from"`
+	To   ScoreHistoryEntry `json:"to"`
+	// Drop is the decrease in the numeric score, always positive.
+	Drop float64 `json:"drop"`
+}
+
+// DetectRegressions returns an event for each pair of consecutive entries in
+// history whose numeric score drops by more than threshold. Entries whose
+// score is not numeric are skipped, so the comparison is with the previous
+// numeric entry.
+func DetectRegressions(history []ScoreHistoryEntry, threshold float64) []RegressionEvent {
+	var events []RegressionEvent
+	var prev *ScoreHistoryEntry
+	var prevScore float64
+	for i := range history {
+		v, err := history[i].Score.Normalize()
+		if err != nil {
+			continue
+		}
+		if prev != nil && prevScore-v > threshold {
+			events = append(events, RegressionEvent{From: *prev, To: history[i], Drop: prevScore - v})
+		}
+		prev, prevScore = &history[i], v
+	}
+	return events
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvalHistory(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileEvaluationStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := EvaluatorResponse{passFail("a", true, 1)}
+	if err := store.Save(ctx, "run1", "eval1", &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	summary := func(mean float64) ScoreSummary {
+		return ScoreSummary{Scores: map[string]*ScoreStats{"s": {Count: 1, Numeric: 1, Mean: mean, Min: mean, Max: mean}}}
+	}
+	runs := []EvalRun{
+		{EvalId: "eval3", Evaluator: "test/e", ModelVersion: "v2", Timestamp: day(3), Summary: summary(0.8)},
+		{EvalId: "eval1", RunId: "run1", Evaluator: "test/e", ModelVersion: "v1", Timestamp: day(1), Summary: summary(0.6)},
+		{EvalId: "eval2", Evaluator: "test/e", ModelVersion: "v1", Timestamp: day(2), Summary: summary(0.7)},
+		{EvalId: "other", Evaluator: "test/other", ModelVersion: "v1", Timestamp: day(2), Summary: summary(0.1)},
+	}
+	for _, run := range runs {
+		if err := RecordEvalRun(ctx, store, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := LoadEvalHistory(ctx, store, EvalFilter{Evaluator: "test/e"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, run := range history {
+		ids = append(ids, run.EvalId)
+	}
+	if got, want := len(ids), 3; got != want || ids[0] != "eval1" || ids[2] != "eval3" {
+		t.Errorf("got history %v, want eval1, eval2, eval3", ids)
+	}
+
+	if h, _ := LoadEvalHistory(ctx, store, EvalFilter{ModelVersion: "v1", Since: day(2), Until: day(2)}); len(h) != 2 {
+		t.Errorf("got %d runs for v1 on day 2, want 2", len(h))
+	}
+
+	// Recording a run keeps the stored response and its run membership.
+	if got, err := store.LoadAllForRun(ctx, "run1"); err != nil || len(got) != 1 || len(*got[0]) != 1 {
+		t.Errorf("stored response was lost after recording a run: %v, %v", got, err)
+	}
+
+	trend := ComputeTrend(history, "s")
+	if trend == nil {
+		t.Fatal("got nil trend")
+	}
+	if math.Abs(trend.Slope-0.1) > 1e-9 {
+		t.Errorf("got slope %v, want 0.1 per day", trend.Slope)
+	}
+	if trend.Min != 0.6 || trend.Max != 0.8 {
+		t.Errorf("got range [%v, %v], want [0.6, 0.8]", trend.Min, trend.Max)
+	}
+	if got, want := trend.Direction, TrendImproving; got != want {
+		t.Errorf("got direction %v, want %v", got, want)
+	}
+	if ComputeTrend(history, "missing") != nil {
+		t.Error("got trend for a score that was never recorded")
+	}
+}
+
+func TestScoreHistory(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileEvaluationStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	// Runs are recorded out of order; "a" scores 0.9, 0.5, "n/a", 0.45, 0.2.
+	for _, run := range []struct {
+		evalId string
+		day    int
+		score  any
+	}{
+		{"eval2", 2, 0.5},
+		{"eval1", 1, 0.9},
+		{"eval4", 4, 0.45},
+		{"eval3", 3, "n/a"},
+		{"eval5", 5, 0.2},
+	} {
+		resp := EvaluatorResponse{passFail("a", true, run.score), passFail("b", true, 1.0)}
+		if err := store.Save(ctx, "", run.evalId, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if err := RecordEvalRun(ctx, store, EvalRun{EvalId: run.evalId, ModelVersion: "v" + run.evalId[4:], Timestamp: day(run.day)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// An evaluation without a run record is not part of the history.
+	unrecorded := EvaluatorResponse{passFail("a", true, 0.0)}
+	if err := store.Save(ctx, "", "eval0", &unrecorded); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := GetScoreHistory(ctx, store, "a", "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, h := range history {
+		ids = append(ids, h.EvalId)
+	}
+	if got, want := strings.Join(ids, ","), "eval1,eval2,eval3,eval4,eval5"; got != want {
+		t.Fatalf("got history %s, want %s", got, want)
+	}
+	if got, want := history[1].ModelVersion, "v2"; got != want {
+		t.Errorf("got model version %q, want %q", got, want)
+	}
+
+	events := DetectRegressions(history, 0.2)
+	if got, want := len(events), 2; got != want {
+		t.Fatalf("got %d regressions, want %d: %+v", got, want, events)
+	}
+	// The non-numeric score of eval3 is skipped, so the drop from 0.5 to 0.45
+	// is compared across it and is below the threshold.
+	for i, want := range [][2]string{{"eval1", "eval2"}, {"eval4", "eval5"}} {
+		if got := [2]string{events[i].From.EvalId, events[i].To.EvalId}; got != want {
+			t.Errorf("regression %d: got %v, want %v", i, got, want)
+		}
+	}
+	if got, want := events[0].Drop, 0.4; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got drop %v, want %v", got, want)
+	}
+}
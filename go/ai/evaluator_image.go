@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// ImageExample is an image evaluated by [DefineImageEvaluator]. Either URL
+// or Data must be set.
+type ImageExample struct {
+	// URL is an http, https or data URL of the image.
+	URL string `json:"url,omitempty"`
+	// Data is the base64-encoded image.
+	Data string `json:"data,omitempty"`
+	// ContentType is the MIME type of the image, such as "image/png". It is
+	// required with Data.
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// MediaPart returns the image as a media [Part]. Data is sent as a data URL.
+func (img *ImageExample) MediaPart() (*Part, error) {
+	switch {
+	case img.URL != "":
+		return NewMediaPart(img.ContentType, img.URL), nil
+	case img.Data == "":
+		return nil, errors.New("image has neither URL nor data")
+	case img.ContentType == "":
+		return nil, errors.New("image data has no content type")
+	}
+	return NewMediaPart(img.ContentType, fmt.Sprintf("data:%s;base64,%s", img.ContentType, img.Data)), nil
+}
+
+// imageFromValue returns the image in v, the Output of an [Example]. v may
+// be an [ImageExample], a pointer to one, its JSON form, or a URL.
+func imageFromValue(v any) (*ImageExample, error) {
+	switch t := v.(type) {
+	case nil:
+		return nil, errors.New("output was not provided")
+	case ImageExample:
+		return &t, nil
+	case *ImageExample:
+		return t, nil
+	case string:
+		return &ImageExample{URL: t}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("output is not an image: %w", err)
+	}
+	var img ImageExample
+	if err := json.Unmarshal(b, &img); err != nil {
+		return nil, fmt.Errorf("output is not an image: %w", err)
+	}
+	if img.URL == "" && img.Data == "" {
+		return nil, errors.New("output is not an image: neither url nor data is set")
+	}
+	return &img, nil
+}
+
+// ImageEvaluatorCallbackRequest is the request passed to a [VisionModel] by
+// evaluators defined with [DefineImageEvaluator].
+type ImageEvaluatorCallbackRequest struct {
+	// Input is the example being evaluated.
+	Input Example `json:"input"`
+	// Image is the Output of Input.
+	Image *ImageExample `json:"image"`
+	// Reference is the text Reference of Input, such as the prompt the
+	// image was generated from or its expected caption.
+	Reference string `json:"reference,omitempty"`
+	// Criteria is the [EvaluatorOptions.Definition] of the evaluator.
+	Criteria string `json:"criteria,omitempty"`
+	Options  any    `json:"options,omitempty"`
+}
+
+// VisionModel judges images for evaluators defined with
+// [DefineImageEvaluator].
+type VisionModel interface {
+	// EvaluateImage returns the scores of req.Image. Scores without an Id
+	// are given the name of the evaluator.
+	EvaluateImage(ctx context.Context, req *ImageEvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error)
+}
+
+// VisionModelFunc is a function that implements [VisionModel].
+type VisionModelFunc func(ctx context.Context, req *ImageEvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error)
+
+// EvaluateImage calls f.
+func (f VisionModelFunc) EvaluateImage(ctx context.Context, req *ImageEvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+	return f(ctx, req)
+}
+
+// DefineImageEvaluator registers an evaluator for examples whose Output is
+// an image, such as the results of image generation. The Output may be an
+// [ImageExample], its JSON form, or a URL. model is given the image and the
+// text Reference of each example, and its response is the result. If opts
+// is nil, default options are used.
+func DefineImageEvaluator(r *registry.Registry, provider, name string, model VisionModel, opts *EvaluatorOptions) (Evaluator, error) {
+	if model == nil {
+		return nil, errors.New("ai.DefineImageEvaluator: model is required")
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName:    "Image",
+			Definition:     "Checks that the image matches the reference",
+			RequiredFields: []string{"Output"},
+		}
+	}
+
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		img, err := imageFromValue(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+		var reference string
+		if req.Input.Reference != nil {
+			if reference, err = exampleText(req.Input.Reference); err != nil {
+				return nil, fmt.Errorf("reference: %w", err)
+			}
+		}
+		resp, err := model.EvaluateImage(ctx, &ImageEvaluatorCallbackRequest{
+			Input:     req.Input,
+			Image:     img,
+			Reference: reference,
+			Criteria:  opts.Definition,
+			Options:   req.Options,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, errors.New("vision model returned no response")
+		}
+		resp.TestCaseId = req.Input.TestCaseId
+		for i := range resp.Evaluation {
+			if resp.Evaluation[i].Id == "" {
+				resp.Evaluation[i].Id = name
+			}
+		}
+		return resp, nil
+	})
+}
+
+// NewModelVisionModel returns a [VisionModel] that asks model, which must
+// accept images, to grade each image against the evaluation criteria and
+// reference. The score is the judge's grade between 0 and 1, and passes
+// from 0.5. The judge's reasoning is reported in the "reasoning" key of
+// [Score.Details].
+func NewModelVisionModel(r *registry.Registry, model Model) VisionModel {
+	return VisionModelFunc(func(ctx context.Context, req *ImageEvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		part, err := req.Image.MediaPart()
+		if err != nil {
+			return nil, err
+		}
+		var v judgeVerdict
+		msg := NewUserMessage(NewTextPart(visionJudgePrompt(req)), part)
+		if _, err := GenerateData(ctx, r, &v, WithModel(model), WithMessages(msg)); err != nil {
+			return nil, fmt.Errorf("vision model %q: %w", model.Name(), err)
+		}
+		v.Score = min(max(v.Score, 0), 1)
+		return &EvaluatorCallbackResponse{
+			Evaluation: []Score{{
+				Score:   v.Score,
+				Status:  passStatus(v.Score >= judgePassThreshold).String(),
+				Details: map[string]any{"reasoning": v.Reasoning},
+			}},
+		}, nil
+	})
+}
+
+// visionJudgePrompt returns the text that accompanies the image of req in
+// the prompt of a vision judge.
+func visionJudgePrompt(req *ImageEvaluatorCallbackRequest) string {
+	var sb strings.Builder
+	sb.WriteString("You are grading an image produced by an AI system.\n\n")
+	if req.Criteria != "" {
+		fmt.Fprintf(&sb, "Criteria:\n%s\n\n", req.Criteria)
+	}
+	if req.Reference != "" {
+		fmt.Fprintf(&sb, "Reference:\n%s\n\n", req.Reference)
+	}
+	sb.WriteString("Respond with a score between 0 and 1, where 1 means the attached image fully satisfies the criteria and matches the reference, and a short reasoning.")
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestImageEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*ImageEvaluatorCallbackRequest
+	model := VisionModelFunc(func(ctx context.Context, req *ImageEvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		got = append(got, req)
+		switch req.Image.URL {
+		case "https://example.com/broken.png":
+			return nil, errors.New("cannot load image")
+		case "https://example.com/empty.png":
+			return nil, nil
+		}
+		return &EvaluatorCallbackResponse{Evaluation: []Score{{Score: 1.0, Status: "pass"}}}, nil
+	})
+	e, err := DefineImageEvaluator(r, "test", "image", model, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "struct", Output: ImageExample{Data: "aW1n", ContentType: "image/png"}, Reference: "a cat"},
+		{TestCaseId: "json", Output: map[string]any{"url": "https://example.com/dog.png"}, Reference: "a dog"},
+		{TestCaseId: "url", Output: "https://example.com/broken.png"},
+		{TestCaseId: "text", Output: map[string]any{"text": "not an image"}},
+		{TestCaseId: "empty", Output: "https://example.com/empty.png"},
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err == nil {
+		t.Error("got nil, want error for unusable outputs")
+	}
+	if len(got) != 4 {
+		t.Fatalf("vision model called %d times, want 4", len(got))
+	}
+	if got[0].Image.Data != "aW1n" || got[0].Reference != "a cat" || got[0].Criteria == "" {
+		t.Errorf("got request %+v, want image data, reference and criteria", got[0])
+	}
+	if got[1].Image.URL != "https://example.com/dog.png" || got[1].Reference != "a dog" {
+		t.Errorf("got request %+v, want image URL and reference", got[1])
+	}
+
+	results := indexResults(resp)
+	for _, id := range []string{"struct", "json"} {
+		s := results[id].Evaluation[0]
+		if s.Id != "image" || s.Status != "pass" {
+			t.Errorf("%s: got score %+v, want passing score with id image", id, s)
+		}
+	}
+	for _, id := range []string{"url", "text", "empty"} {
+		if results[id].Evaluation[0].Error == "" {
+			t.Errorf("%s: got no error", id)
+		}
+	}
+}
+
+func TestNewModelVisionModel(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var media *Part
+	var prompt string
+	model := DefineModel(r, "test", "vision", &ModelInfo{Supports: &ModelSupports{Media: true, Constrained: ConstrainedSupportAll}}, func(ctx context.Context, req *ModelRequest, cb ModelStreamCallback) (*ModelResponse, error) {
+		for _, m := range req.Messages {
+			if m.Role != RoleUser {
+				continue
+			}
+			prompt = m.Text()
+			for _, p := range m.Content {
+				if p.IsMedia() {
+					media = p
+				}
+			}
+		}
+		b, err := json.Marshal(judgeVerdict{Score: 0.3, Reasoning: "wrong animal"})
+		if err != nil {
+			return nil, err
+		}
+		return &ModelResponse{Request: req, Message: NewModelTextMessage(string(b))}, nil
+	})
+
+	resp, err := NewModelVisionModel(r, model).EvaluateImage(context.Background(), &ImageEvaluatorCallbackRequest{
+		Image:     &ImageExample{Data: "aW1n", ContentType: "image/png"},
+		Reference: "a cat",
+		Criteria:  "Shows the reference",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if media == nil || media.Text != "data:image/png;base64,aW1n" {
+		t.Errorf("got media part %+v, want data URL", media)
+	}
+	for _, want := range []string{"Criteria:\nShows the reference", "Reference:\na cat"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt %q does not contain %q", prompt, want)
+		}
+	}
+	s := resp.Evaluation[0]
+	if s.Score != 0.3 || s.Status != "fail" || s.Details["reasoning"] != "wrong animal" {
+		t.Errorf("got score %+v, want failing 0.3 with reasoning", s)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// InstructionCheck is the verification of one instruction, reported by
+// [DefineInstructionFollowingEvaluator].
+type InstructionCheck struct {
+	Instruction string `json:"instruction"`
+	Satisfied   bool   `json:"satisfied"`
+	Reasoning   string `json:"reasoning,omitempty"`
+}
+
+// instructionList is the structured output requested when extracting
+// instructions.
+type instructionList struct {
+	Instructions []string `json:"instructions"`
+}
+
+// instructionVerdict is the structured output requested when checking an
+// instruction.
+type instructionVerdict struct {
+	Satisfied bool   `json:"satisfied"`
+	Reasoning string `json:"reasoning"`
+}
+
+// DefineInstructionFollowingEvaluator registers an evaluator that checks
+// whether the Output of each [Example] follows the instructions in its
+// Input, in the manner of IFEval.
+//
+// model is first asked to list the imperatives in the Input, such as
+// "answer in French" or "use at most three bullet points", and then to
+// judge for each one whether the Output satisfies it. The score is the
+// fraction of satisfied instructions, and the example passes only if all
+// of them are satisfied. Each check is reported as an [InstructionCheck] in
+// the "instructions" key of [Score.Details]. Examples whose Input has no
+// instructions fail with an error. No Reference is needed. If opts is nil,
+// default options are used.
+func DefineInstructionFollowingEvaluator(r *registry.Registry, provider, name string, model Model, opts *EvaluatorOptions) (Evaluator, error) {
+	if model == nil {
+		return nil, errors.New("ai.DefineInstructionFollowingEvaluator: model is required")
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName:    "Instruction Following",
+			Definition:     "Checks that the output satisfies each instruction given in the input",
+			RequiredFields: []string{"Input", "Output"},
+		}
+	}
+
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		input, err := exampleText(req.Input.Input)
+		if err != nil {
+			return nil, fmt.Errorf("input: %w", err)
+		}
+		output, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, fmt.Errorf("output: %w", err)
+		}
+
+		instructions, err := extractInstructions(ctx, r, model, input)
+		if err != nil {
+			return nil, err
+		}
+		if len(instructions) == 0 {
+			return nil, errors.New("input contains no instructions")
+		}
+		checks := make([]InstructionCheck, len(instructions))
+		satisfied := 0
+		for i, instruction := range instructions {
+			v, err := checkInstruction(ctx, r, model, instruction, output)
+			if err != nil {
+				return nil, err
+			}
+			checks[i] = InstructionCheck{Instruction: instruction, Satisfied: v.Satisfied, Reasoning: v.Reasoning}
+			if v.Satisfied {
+				satisfied++
+			}
+		}
+
+		score := Score{
+			Id:      name,
+			Score:   float64(satisfied) / float64(len(checks)),
+			Status:  passStatus(satisfied == len(checks)).String(),
+			Details: map[string]any{"instructions": checks},
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// extractInstructions asks model for the instructions in input. Blank
+// instructions are dropped.
+func extractInstructions(ctx context.Context, r *registry.Registry, model Model, input string) ([]string, error) {
+	prompt := fmt.Sprintf("List every instruction that the following request gives about how to respond, such as requirements on content, format, length, language or style. Rewrite each as a single imperative sentence. Do not include the topic of the request itself unless it constrains the response.\n\nRequest:\n%s", input)
+	var list instructionList
+	if _, err := GenerateData(ctx, r, &list, WithModel(model), WithPromptText(prompt)); err != nil {
+		return nil, fmt.Errorf("instruction model %q: %w", model.Name(), err)
+	}
+	var instructions []string
+	for _, instruction := range list.Instructions {
+		if instruction = strings.TrimSpace(instruction); instruction != "" {
+			instructions = append(instructions, instruction)
+		}
+	}
+	return instructions, nil
+}
+
+// checkInstruction asks model whether output satisfies instruction.
+func checkInstruction(ctx context.Context, r *registry.Registry, model Model, instruction, output string) (*instructionVerdict, error) {
+	prompt := fmt.Sprintf("Does the response follow the instruction? Answer with satisfied true only if the response fully complies, and give a short reasoning.\n\nInstruction:\n%s\n\nResponse:\n%s", instruction, output)
+	var v instructionVerdict
+	if _, err := GenerateData(ctx, r, &v, WithModel(model), WithPromptText(prompt)); err != nil {
+		return nil, fmt.Errorf("instruction model %q: %w", model.Name(), err)
+	}
+	return &v, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestInstructionFollowingEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The model extracts the sentences starting with "Mention", and an
+	// instruction to mention a word is satisfied if the response contains it.
+	model := defineJudgeModel(r, "instructions", func(prompt string) any {
+		if _, request, ok := strings.Cut(prompt, "Request:\n"); ok {
+			var instructions []string
+			for _, sentence := range strings.Split(request, ".") {
+				if sentence = strings.TrimSpace(sentence); strings.HasPrefix(sentence, "Mention") {
+					instructions = append(instructions, sentence)
+				}
+			}
+			return instructionList{Instructions: instructions}
+		}
+		_, rest, _ := strings.Cut(prompt, "Instruction:\n")
+		instruction, response, _ := strings.Cut(rest, "\n\nResponse:\n")
+		word := strings.TrimPrefix(instruction, "Mention ")
+		return instructionVerdict{Satisfied: strings.Contains(response, word), Reasoning: "looked for " + word}
+	})
+	e, err := DefineInstructionFollowingEvaluator(r, "test", "instruction_following", model, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "Write about fruit. Mention apples. Mention pears."
+	ds := Dataset{
+		{TestCaseId: "all", Input: input, Output: "I like apples and pears."},
+		{TestCaseId: "half", Input: input, Output: "I like apples."},
+		{TestCaseId: "none", Input: "Write about fruit.", Output: "Fruit is good."},
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err == nil {
+		t.Error("got nil, want error for input without instructions")
+	}
+	results := indexResults(resp)
+	for id, want := range map[string]struct {
+		score  float64
+		status string
+	}{
+		"all":  {1, "pass"},
+		"half": {0.5, "fail"},
+	} {
+		s := results[id].Evaluation[0]
+		if s.Score != want.score || s.Status != want.status {
+			t.Errorf("%s: got score %v (%s), want %v (%s)", id, s.Score, s.Status, want.score, want.status)
+		}
+	}
+	checks := results["half"].Evaluation[0].Details["instructions"].([]InstructionCheck)
+	if len(checks) != 2 || !checks[0].Satisfied || checks[1].Satisfied || checks[1].Instruction != "Mention pears" {
+		t.Errorf("got checks %+v, want apples satisfied and pears not", checks)
+	}
+	if results["none"].Evaluation[0].Error == "" {
+		t.Error("got no error for input without instructions")
+	}
+}
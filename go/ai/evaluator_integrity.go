@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ValidateResponseIntegrity checks that resp is a consistent response to
+// req. It reports:
+//
+//   - examples in req.Dataset without a result, except those listed in
+//     req.ExcludeTestCaseIds, and results for test cases that are not in the
+//     dataset. The latter are only reported if every example has a
+//     TestCaseId;
+//   - TestCaseIds that appear in more than one result;
+//   - scores whose Status is not empty or the name of a [ScoreStatus];
+//   - numeric scores that are NaN or infinite, and scores whose value is of
+//     a different kind (number, boolean, string, [ProbabilisticScore] or
+//     other) than the first score with the same Id.
+//
+// The returned error joins one error per violation, or is nil if there are
+// none.
+func ValidateResponseIntegrity(req *EvaluatorRequest, resp *EvaluatorResponse) error {
+	var errs []error
+	var results EvaluatorResponse
+	if resp != nil {
+		results = *resp
+	}
+
+	seen := map[string]bool{}
+	for _, res := range results {
+		if seen[res.TestCaseId] {
+			errs = append(errs, fmt.Errorf("duplicate result for test case %q", res.TestCaseId))
+		}
+		seen[res.TestCaseId] = true
+	}
+
+	if req != nil && req.Dataset != nil {
+		excluded := map[string]bool{}
+		for _, id := range req.ExcludeTestCaseIds {
+			excluded[id] = true
+		}
+		expected := map[string]bool{}
+		anonymous := false
+		for _, ex := range *req.Dataset {
+			if ex.TestCaseId == "" {
+				anonymous = true
+				continue
+			}
+			if excluded[ex.TestCaseId] || expected[ex.TestCaseId] {
+				continue
+			}
+			expected[ex.TestCaseId] = true
+			if !seen[ex.TestCaseId] {
+				errs = append(errs, fmt.Errorf("no result for test case %q", ex.TestCaseId))
+			}
+		}
+		// Examples without a TestCaseId are assigned one during evaluation,
+		// so their results cannot be told apart from unexpected ones.
+		if !anonymous {
+			for _, res := range results {
+				if !expected[res.TestCaseId] {
+					errs = append(errs, fmt.Errorf("result for test case %q that is not in the dataset", res.TestCaseId))
+				}
+			}
+		}
+	}
+
+	kinds := map[string]string{}
+	for _, res := range results {
+		for _, s := range res.Evaluation {
+			if !validScoreStatus(s.Status) {
+				errs = append(errs, fmt.Errorf("test case %q: score %q has invalid status %q", res.TestCaseId, s.Id, s.Status))
+			}
+			if s.Score == nil {
+				continue
+			}
+			kind := scoreKind(s.Score)
+			if kind == "number" {
+				if v, err := s.Normalize(); err == nil && (math.IsNaN(v) || math.IsInf(v, 0)) {
+					errs = append(errs, fmt.Errorf("test case %q: score %q has non-finite value %v", res.TestCaseId, s.Id, v))
+				}
+			}
+			if first, ok := kinds[s.Id]; !ok {
+				kinds[s.Id] = kind
+			} else if kind != first {
+				errs = append(errs, fmt.Errorf("test case %q: score %q is a %s, but earlier scores with that id are a %s", res.TestCaseId, s.Id, kind, first))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validScoreStatus(status string) bool {
+	if status == "" {
+		return true
+	}
+	for _, name := range statusName {
+		if status == name {
+			return true
+		}
+	}
+	return false
+}
+
+// scoreKind classifies a [Score.Score] value as "number", "boolean",
+// "string", "probabilistic" or "other".
+func scoreKind(v any) string {
+	if _, ok := asProbabilisticScore(v); ok {
+		return "probabilistic"
+	}
+	switch v.(type) {
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return "other"
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidateResponseIntegrity(t *testing.T) {
+	ds := Dataset{{TestCaseId: "a"}, {TestCaseId: "b"}, {TestCaseId: "c"}, {TestCaseId: "skipped"}}
+	req := &EvaluatorRequest{Dataset: &ds, ExcludeTestCaseIds: []string{"skipped"}}
+
+	good := EvaluatorResponse{passFail("a", true, 1), passFail("b", false, 0.5), passFail("c", true, 1)}
+	if err := ValidateResponseIntegrity(req, &good); err != nil {
+		t.Errorf("valid response: %v", err)
+	}
+
+	bad := EvaluatorResponse{
+		passFail("a", true, 1),
+		passFail("a", true, 1),
+		{TestCaseId: "b", Evaluation: []Score{{Id: "s", Score: "high", Status: "PASS"}}},
+		{TestCaseId: "extra", Evaluation: []Score{{Id: "s", Score: math.NaN()}}},
+	}
+	err := ValidateResponseIntegrity(req, &bad)
+	if err == nil {
+		t.Fatal("got nil, want errors")
+	}
+	for _, want := range []string{
+		`duplicate result for test case "a"`,
+		`no result for test case "c"`,
+		`result for test case "extra" that is not in the dataset`,
+		`score "s" has invalid status "PASS"`,
+		`score "s" is a string, but earlier scores with that id are a number`,
+		`score "s" has non-finite value NaN`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "skipped") {
+		t.Errorf("error %q mentions an excluded example", err)
+	}
+}
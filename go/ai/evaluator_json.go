@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// JSONType is the type of a JSON value.
+type JSONType string
+
+const (
+	JSONTypeObject  JSONType = "object"
+	JSONTypeArray   JSONType = "array"
+	JSONTypeString  JSONType = "string"
+	JSONTypeNumber  JSONType = "number"
+	JSONTypeBoolean JSONType = "boolean"
+	JSONTypeNull    JSONType = "null"
+)
+
+// JSONValidityOptions are the request options understood by the evaluator
+// defined with [DefineJSONValidityEvaluator]. Pass them with
+// [WithEvaluateOptions].
+type JSONValidityOptions struct {
+	// TopLevelType, if set, is the type the top-level value must have.
+	TopLevelType JSONType `json:"topLevelType,omitempty"`
+}
+
+// DefineJSONValidityEvaluator registers an evaluator named "json_validity"
+// that checks that the Output of each [Example] is valid JSON. String outputs
+// are parsed as JSON text; other outputs are checked by encoding them.
+//
+// Valid outputs score 1.0 and the parsed value is reported in the "parsed" key
+// of [Score.Details]. Invalid outputs score 0.0 and the parse error is
+// reported in the "error" key. A [JSONValidityOptions] passed as the request
+// options can additionally require a top-level type. If opts is nil, default
+// options are used.
+func DefineJSONValidityEvaluator(r *registry.Registry, provider string, opts *EvaluatorOptions) (Evaluator, error) {
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "JSON Validity",
+			Definition:  "Checks that the output is valid JSON",
+		}
+	}
+
+	return DefineEvaluator(r, provider, "json_validity", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		var jsonOpts JSONValidityOptions
+		if err := decodeEvaluatorOptions(req.Options, &jsonOpts); err != nil {
+			return nil, err
+		}
+
+		score := Score{
+			Id:      "json_validity",
+			Score:   1.0,
+			Status:  ScoreStatusPass.String(),
+			Details: map[string]any{},
+		}
+		parsed, err := parseJSONOutput(req.Input.Output)
+		if err == nil && jsonOpts.TopLevelType != "" {
+			if got := jsonTypeOf(parsed); got != jsonOpts.TopLevelType {
+				err = fmt.Errorf("top-level value is %s, want %s", got, jsonOpts.TopLevelType)
+			}
+		}
+		if err != nil {
+			score.Score = 0.0
+			score.Status = ScoreStatusFail.String()
+			score.Details["error"] = err.Error()
+		} else {
+			score.Details["parsed"] = parsed
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// parseJSONOutput parses output as JSON text if it is a string, and
+// round-trips it through JSON otherwise.
+func parseJSONOutput(output any) (any, error) {
+	var data []byte
+	if s, ok := output.(string); ok {
+		data = []byte(s)
+	} else {
+		var err error
+		if data, err = json.Marshal(output); err != nil {
+			return nil, err
+		}
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// jsonTypeOf returns the type of the decoded JSON value v.
+func jsonTypeOf(v any) JSONType {
+	switch v.(type) {
+	case map[string]any:
+		return JSONTypeObject
+	case []any:
+		return JSONTypeArray
+	case string:
+		return JSONTypeString
+	case float64:
+		return JSONTypeNumber
+	case bool:
+		return JSONTypeBoolean
+	}
+	return JSONTypeNull
+}
+
+// decodeEvaluatorOptions stores the request options in dst. Options may be a
+// T, a *T, or any value whose JSON encoding decodes into a T, such as a map
+// sent by the Dev UI.
+func decodeEvaluatorOptions[T any](options any, dst *T) error {
+	switch o := options.(type) {
+	case nil:
+		return nil
+	case T:
+		*dst = o
+		return nil
+	case *T:
+		if o != nil {
+			*dst = *o
+		}
+		return nil
+	}
+	b, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestJSONValidityEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineJSONValidityEvaluator(r, "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{Input: "q", Output: `{"name": "Bob"}`},
+		{Input: "q", Output: `[1, 2, 3]`},
+		{Input: "q", Output: `{"name": "Bob"`},
+		{Input: "q", Output: map[string]any{"structured": true}},
+	}
+
+	tests := []struct {
+		options any
+		want    []string
+	}{
+		{nil, []string{"pass", "pass", "fail", "pass"}},
+		{JSONValidityOptions{TopLevelType: JSONTypeObject}, []string{"pass", "fail", "fail", "pass"}},
+		{map[string]any{"topLevelType": "array"}, []string{"fail", "pass", "fail", "fail"}},
+	}
+	for _, test := range tests {
+		resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds, Options: test.options})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range test.want {
+			if got := (*resp)[i].Evaluation[0].Status; got != want {
+				t.Errorf("options %v, example %d: got status %v, want %v", test.options, i, got, want)
+			}
+		}
+	}
+
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (*resp)[2].Evaluation[0].Details["error"]; got == nil {
+		t.Error("got no parse error for invalid JSON")
+	}
+	if got := (*resp)[0].Evaluation[0].Details["parsed"].(map[string]any)["name"]; got != "Bob" {
+		t.Errorf("got parsed name %v, want Bob", got)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// judgeVerdict is the structured output requested from LLM judges.
+type judgeVerdict struct {
+	Score     float64 `json:"score"`
+	Reasoning string  `json:"reasoning"`
+	// Confidence is the judge's certainty in Score, if it reported one.
+	Confidence *float64 `json:"confidence,omitempty"`
+	// Rationale is the step-by-step reasoning of a chain-of-thought judge.
+	Rationale string `json:"-"`
+}
+
+// cotJudgeVerdict is the structured output requested from LLM judges that
+// use chain of thought. The rationale comes first so that the judge writes
+// it before deciding on a score.
+type cotJudgeVerdict struct {
+	Rationale  string   `json:"rationale"`
+	Score      float64  `json:"score"`
+	Reasoning  string   `json:"reasoning"`
+	Confidence *float64 `json:"confidence,omitempty"`
+}
+
+// chainOfThoughtInstruction is added to the prompt of judges that use chain
+// of thought.
+const chainOfThoughtInstruction = "Think step by step, then provide your final score. Write your step-by-step reasoning in the rationale field."
+
+// judgePassThreshold is the lowest judge score that passes.
+const judgePassThreshold = 0.5
+
+// runJudge asks model to grade ex according to rubric and returns its
+// verdict. Scores and confidences are clamped to [0, 1]. If cot is set, the
+// judge is asked to reason step by step first.
+func runJudge(ctx context.Context, r *registry.Registry, model Model, rubric string, ex *Example, cot bool) (*judgeVerdict, error) {
+	prompt, err := judgePrompt(rubric, ex)
+	if err != nil {
+		return nil, err
+	}
+	var v judgeVerdict
+	if cot {
+		var cv cotJudgeVerdict
+		_, err = GenerateData(ctx, r, &cv, WithModel(model), WithPromptText(prompt+"\n"+chainOfThoughtInstruction))
+		v = judgeVerdict{Score: cv.Score, Reasoning: cv.Reasoning, Confidence: cv.Confidence, Rationale: cv.Rationale}
+	} else {
+		_, err = GenerateData(ctx, r, &v, WithModel(model), WithPromptText(prompt))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("judge model %q: %w", model.Name(), err)
+	}
+	v.Score = min(max(v.Score, 0), 1)
+	if v.Confidence != nil {
+		c := min(max(*v.Confidence, 0), 1)
+		v.Confidence = &c
+	}
+	return &v, nil
+}
+
+// judgePrompt returns the prompt asking an LLM judge to grade ex according
+// to rubric.
+func judgePrompt(rubric string, ex *Example) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("You are grading the output of an AI system.\n\n")
+	sb.WriteString("Rubric:\n")
+	sb.WriteString(rubric)
+	sb.WriteString("\n\n")
+	if err := writeJudgeExample(&sb, ex); err != nil {
+		return "", err
+	}
+	sb.WriteString("Respond with a score between 0 and 1, where 1 fully satisfies the rubric, a short reasoning, and your confidence in the score between 0 and 1.")
+	return sb.String(), nil
+}
+
+// writeJudgeExample writes the Input, Output and Reference of ex to sb as
+// labeled sections of a judge prompt.
+func writeJudgeExample(sb *strings.Builder, ex *Example) error {
+	for _, f := range []struct {
+		label string
+		value any
+	}{
+		{"Input", ex.Input},
+		{"Output", ex.Output},
+		{"Reference", ex.Reference},
+	} {
+		if f.value == nil {
+			continue
+		}
+		text, err := exampleText(f.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.ToLower(f.label), err)
+		}
+		fmt.Fprintf(sb, "%s:\n%s\n\n", f.label, text)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// defineJudgeModel defines a model that answers each request with the JSON
+// encoding of judge applied to the text of the last user message.
+func defineJudgeModel(r *registry.Registry, name string, judge func(prompt string) any) Model {
+	return DefineModel(r, "test", name, &ModelInfo{Supports: &ModelSupports{Constrained: ConstrainedSupportAll}}, func(ctx context.Context, req *ModelRequest, cb ModelStreamCallback) (*ModelResponse, error) {
+		var prompt string
+		for _, m := range req.Messages {
+			if m.Role == RoleUser {
+				prompt = m.Text()
+			}
+		}
+		b, err := json.Marshal(judge(prompt))
+		if err != nil {
+			return nil, err
+		}
+		return &ModelResponse{Request: req, Message: NewModelTextMessage(string(b))}, nil
+	})
+}
+
+func TestJudgePrompt(t *testing.T) {
+	prompt, err := judgePrompt("Be concise.", &Example{Input: "q", Output: map[string]any{"a": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Rubric:\nBe concise.", "Input:\nq", `Output:
+{"a":1}`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt %q does not contain %q", prompt, want)
+		}
+	}
+	if strings.Contains(prompt, "Reference:") {
+		t.Errorf("prompt %q contains an empty reference", prompt)
+	}
+}
+
+func TestJudgeChainOfThought(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The judge only gives a rationale when asked for one in the schema.
+	var schemas []map[string]any
+	judge := DefineModel(r, "test", "cotJudge", &ModelInfo{Supports: &ModelSupports{Constrained: ConstrainedSupportAll}}, func(ctx context.Context, req *ModelRequest, cb ModelStreamCallback) (*ModelResponse, error) {
+		schema := req.Output.Schema
+		schemas = append(schemas, schema)
+		verdict := map[string]any{"score": 0.8, "reasoning": "good"}
+		if props, _ := schema["properties"].(map[string]any); props["rationale"] != nil && strings.Contains(req.Messages[len(req.Messages)-1].Text(), "Think step by step") {
+			verdict["rationale"] = "The output answers the question, so it scores well."
+		}
+		b, err := json.Marshal(verdict)
+		if err != nil {
+			return nil, err
+		}
+		return &ModelResponse{Request: req, Message: NewModelTextMessage(string(b))}, nil
+	})
+
+	for _, cot := range []bool{false, true} {
+		e, err := DefineMultilingualEvaluator(r, "test", fmt.Sprint("cot", cot), map[string]string{DefaultRubricKey: "Grade it."}, judge, NewEvaluatorOptions(WithChainOfThought(cot)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds := Dataset{{Input: "q", Output: "a"}}
+		resp, err := e.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rationale, ok := (*resp)[0].Evaluation[0].Details["rationale"]
+		if cot && rationale == "" {
+			t.Error("got empty rationale with chain of thought")
+		}
+		if !cot && ok {
+			t.Errorf("got rationale %q without chain of thought", rationale)
+		}
+	}
+	if props, _ := schemas[0]["properties"].(map[string]any); props["rationale"] != nil {
+		t.Errorf("schema without chain of thought has a rationale: %v", schemas[0])
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/firebase/genkit/go/core/tracing"
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// KFoldReport is the result of [RunKFoldEvaluation].
+type KFoldReport struct {
+	K    int   `json:"k"`
+	Seed int64 `json:"seed"`
+	// Folds holds the results of each fold, in order.
+	Folds []KFoldResult `json:"folds"`
+	// Scores summarizes each score of each evaluator across the folds,
+	// sorted by evaluator name and score Id.
+	Scores []KFoldScoreStats `json:"scores"`
+}
+
+// KFoldResult holds the results of evaluating the test examples of one fold.
+type KFoldResult struct {
+	TestCaseIds []string `json:"testCaseIds"`
+	// Results holds the response of each evaluator, keyed by its name.
+	Results map[string]EvaluatorResponse `json:"results"`
+	// Errors holds the error of each evaluator that failed on some of the
+	// test examples, keyed by its name.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// KFoldScoreStats summarizes one score of one evaluator across folds.
+type KFoldScoreStats struct {
+	Evaluator string `json:"evaluator"`
+	ScoreId   string `json:"scoreId"`
+	// FoldScores holds the mean numeric score on each fold's test examples.
+	// Folds without numeric values of the score are left out.
+	FoldScores []float64 `json:"foldScores"`
+	Mean       float64   `json:"mean"`
+	// StdDev is the sample standard deviation of FoldScores.
+	StdDev float64 `json:"stdDev"`
+	// ConfidenceInterval is the 95% confidence interval of Mean under a
+	// normal approximation.
+	ConfidenceInterval [2]float64 `json:"confidenceInterval"`
+}
+
+// RunKFoldEvaluation estimates the performance of evals by k-fold
+// cross-validation: ds is split into k folds with [KFoldDataset] and every
+// evaluator is run on the test examples of each fold. The evaluation of each
+// fold runs in its own trace span. Examples without a TestCaseId are
+// assigned one first.
+//
+// Evaluators are not trained, so the training examples of each fold are not
+// used; the folds only provide independent samples of the dataset, whose
+// spread is reported in the [KFoldReport].
+//
+// Examples on which an evaluator fails are recorded in the Errors of their
+// fold and left out of the score summaries, which cover only the examples
+// that were scored.
+func RunKFoldEvaluation(ctx context.Context, r *registry.Registry, evals []Evaluator, ds Dataset, k int, seed int64, opts ...EvaluateOption) (*KFoldReport, error) {
+	if len(evals) == 0 {
+		return nil, errors.New("ai.RunKFoldEvaluation: at least one evaluator is required")
+	}
+	folds, err := KFoldDataset(withTestCaseIds(ds), k, seed)
+	if err != nil {
+		return nil, fmt.Errorf("ai.RunKFoldEvaluation: %w", err)
+	}
+
+	report := &KFoldReport{K: k, Seed: seed}
+	for i, fold := range folds {
+		res, err := tracing.RunInNewSpan(ctx, r.TracingState(), fmt.Sprintf("fold %d", i+1), "evaluationFold", false, fold.Test,
+			func(ctx context.Context, test Dataset) (KFoldResult, error) {
+				res := KFoldResult{Results: map[string]EvaluatorResponse{}}
+				for _, ex := range test {
+					res.TestCaseIds = append(res.TestCaseIds, ex.TestCaseId)
+				}
+				for _, eval := range evals {
+					resp, err := Evaluate(ctx, eval, append([]EvaluateOption{WithEvaluateDataset(&test)}, opts...)...)
+					if resp == nil {
+						return res, fmt.Errorf("evaluator %q: %w", eval.Name(), err)
+					}
+					if err != nil {
+						if res.Errors == nil {
+							res.Errors = map[string]string{}
+						}
+						res.Errors[eval.Name()] = err.Error()
+					}
+					res.Results[eval.Name()] = *resp
+				}
+				return res, nil
+			})
+		if err != nil {
+			return nil, fmt.Errorf("ai.RunKFoldEvaluation: fold %d: %w", i+1, err)
+		}
+		report.Folds = append(report.Folds, res)
+	}
+
+	for _, eval := range evals {
+		foldScores := map[string][]float64{}
+		for _, fold := range report.Folds {
+			var resp EvaluatorResponse
+			for _, res := range fold.Results[eval.Name()] {
+				if res.err == nil {
+					resp = append(resp, res)
+				}
+			}
+			summary, err := AggregateScores(&resp)
+			if err != nil {
+				return nil, fmt.Errorf("ai.RunKFoldEvaluation: %w", err)
+			}
+			for id, stats := range summary.Scores {
+				if stats.Numeric > 0 {
+					foldScores[id] = append(foldScores[id], stats.Mean)
+				}
+			}
+		}
+		for id, values := range foldScores {
+			mean, sd := meanStdDev(values)
+			half := z95 * sd / math.Sqrt(float64(len(values)))
+			report.Scores = append(report.Scores, KFoldScoreStats{
+				Evaluator:          eval.Name(),
+				ScoreId:            id,
+				FoldScores:         values,
+				Mean:               mean,
+				StdDev:             sd,
+				ConfidenceInterval: [2]float64{mean - half, mean + half},
+			})
+		}
+	}
+	slices.SortFunc(report.Scores, func(a, b KFoldScoreStats) int {
+		return cmp.Or(cmp.Compare(a.Evaluator, b.Evaluator), cmp.Compare(a.ScoreId, b.ScoreId))
+	})
+	return report, nil
+}
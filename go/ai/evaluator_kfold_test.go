@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRunKFoldEvaluation(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	r.RegisterSpanProcessor(recorder)
+
+	// The classifier is right on even inputs only.
+	classifier, err := DefineEvaluator(r, "test", "classifier", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		n, err := strconv.Atoi(req.Input.Input.(string))
+		if err != nil {
+			return nil, err
+		}
+		correct := n%2 == 0
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "accuracy", Score: correct, Status: passStatus(correct).String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	constant, err := DefineEvaluator(r, "test", "constant", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "quality", Score: 0.5}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ds Dataset
+	for i := range 20 {
+		ds = append(ds, Example{Input: strconv.Itoa(i)})
+	}
+	report, err := RunKFoldEvaluation(context.Background(), r, []Evaluator{classifier, constant}, ds, 4, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(report.Folds); got != 4 {
+		t.Fatalf("got %d folds, want 4", got)
+	}
+	total := 0
+	for i, fold := range report.Folds {
+		total += len(fold.TestCaseIds)
+		if got := len(fold.Results["test/classifier"]); got != len(fold.TestCaseIds) {
+			t.Errorf("fold %d: got %d classifier results, want %d", i, got, len(fold.TestCaseIds))
+		}
+	}
+	if total != len(ds) {
+		t.Errorf("got %d test examples across folds, want %d", total, len(ds))
+	}
+
+	if got := len(report.Scores); got != 2 {
+		t.Fatalf("got %d score summaries, want 2", got)
+	}
+	accuracy := report.Scores[0]
+	if accuracy.Evaluator != "test/classifier" || accuracy.ScoreId != "accuracy" || len(accuracy.FoldScores) != 4 {
+		t.Errorf("got %+v, want the classifier accuracy over 4 folds", accuracy)
+	}
+	if math.Abs(accuracy.Mean-0.5) > 1e-9 {
+		t.Errorf("got mean accuracy %v, want 0.5", accuracy.Mean)
+	}
+	if lo, hi := accuracy.ConfidenceInterval[0], accuracy.ConfidenceInterval[1]; lo > accuracy.Mean || hi < accuracy.Mean {
+		t.Errorf("confidence interval %v does not contain the mean %v", accuracy.ConfidenceInterval, accuracy.Mean)
+	}
+	quality := report.Scores[1]
+	if quality.Mean != 0.5 || quality.StdDev != 0 {
+		t.Errorf("got quality mean %v and standard deviation %v, want 0.5 and 0", quality.Mean, quality.StdDev)
+	}
+
+	spans := map[string]bool{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = true
+	}
+	for i := 1; i <= 4; i++ {
+		if name := fmt.Sprintf("fold %d", i); !spans[name] {
+			t.Errorf("span %q was not recorded", name)
+		}
+	}
+
+	if _, err := RunKFoldEvaluation(context.Background(), r, nil, ds, 4, 7); err == nil {
+		t.Error("got nil, want error for no evaluators")
+	}
+}
+
+func TestRunKFoldEvaluationPartialFailure(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The evaluator fails on inputs divisible by 5 and scores the rest 1.
+	e, err := DefineEvaluator(r, "test", "flaky", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		n, err := strconv.Atoi(req.Input.Input.(string))
+		if err != nil {
+			return nil, err
+		}
+		if n%5 == 0 {
+			return nil, fmt.Errorf("cannot score %d", n)
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "quality", Score: 1.0}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ds Dataset
+	for i := range 20 {
+		ds = append(ds, Example{Input: strconv.Itoa(i)})
+	}
+	report, err := RunKFoldEvaluation(context.Background(), r, []Evaluator{e}, ds, 4, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := 0
+	for i, fold := range report.Folds {
+		if got := len(fold.Results["test/flaky"]); got != len(fold.TestCaseIds) {
+			t.Errorf("fold %d: got %d results, want %d", i, got, len(fold.TestCaseIds))
+		}
+		if fold.Errors["test/flaky"] != "" {
+			failed++
+		}
+	}
+	if failed == 0 {
+		t.Error("no fold recorded an error")
+	}
+	if got := len(report.Scores); got != 1 {
+		t.Fatalf("got %d score summaries, want 1", got)
+	}
+	if quality := report.Scores[0]; len(quality.FoldScores) != 4 || quality.Mean != 1 {
+		t.Errorf("got %+v, want mean 1 over 4 folds", quality)
+	}
+}
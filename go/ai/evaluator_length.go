@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"golang.org/x/text/unicode/norm"
+)
+
+// LengthMode is an enum used to select how [DefineLengthEvaluator] measures
+// the length of an output.
+type LengthMode int
+
+const (
+	LengthModeCharacterCount LengthMode = iota
+	LengthModeWordCount
+	LengthModeSentenceCount
+)
+
+var lengthModeName = map[LengthMode]string{
+	LengthModeCharacterCount: "characters",
+	LengthModeWordCount:      "words",
+	LengthModeSentenceCount:  "sentences",
+}
+
+func (m LengthMode) String() string {
+	return lengthModeName[m]
+}
+
+// DefineLengthEvaluator registers an evaluator that checks that the length of
+// each Output, measured according to mode, is within [min, max]. A max of
+// zero means there is no upper bound. The evaluator is named after the mode,
+// for example "length_words".
+//
+// The score is 1.0 if the length is within range and 0.0 otherwise; the
+// measured length is reported in the "count" key of [Score.Details].
+// Characters are counted as Unicode code points after NFC normalization, so
+// multi-byte and combining characters are counted once. Words are separated
+// by white space, except in Chinese and Japanese text, which does not use
+// spaces: each Han, Hiragana or Katakana character is counted as a word.
+func DefineLengthEvaluator(r *registry.Registry, provider string, mode LengthMode, min, max int) (Evaluator, error) {
+	if _, ok := lengthModeName[mode]; !ok {
+		return nil, fmt.Errorf("ai.DefineLengthEvaluator: unknown length mode %d", mode)
+	}
+	if min < 0 || max < 0 || (max > 0 && max < min) {
+		return nil, fmt.Errorf("ai.DefineLengthEvaluator: invalid range [%d, %d]", min, max)
+	}
+	rangeDesc := fmt.Sprintf("at least %d", min)
+	if max > 0 {
+		rangeDesc = fmt.Sprintf("between %d and %d", min, max)
+	}
+	opts := &EvaluatorOptions{
+		DisplayName: "Length",
+		Definition:  fmt.Sprintf("Tests that the output is %s %s long", rangeDesc, mode),
+	}
+
+	return DefineEvaluator(r, provider, "length_"+mode.String(), opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		text, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+
+		count := measureLength(text, mode)
+		score := Score{
+			Id:     "length",
+			Score:  1.0,
+			Status: ScoreStatusPass.String(),
+			Details: map[string]any{
+				"count": count,
+				"mode":  mode.String(),
+			},
+		}
+		if count < min || (max > 0 && count > max) {
+			score.Score = 0.0
+			score.Status = ScoreStatusFail.String()
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// measureLength returns the length of text according to mode.
+func measureLength(text string, mode LengthMode) int {
+	switch mode {
+	case LengthModeWordCount:
+		return countWords(text)
+	case LengthModeSentenceCount:
+		return countSentences(text)
+	default:
+		return utf8.RuneCountInString(norm.NFC.String(text))
+	}
+}
+
+// countWords counts the words of text separated by white space, counting
+// each Han, Hiragana or Katakana character as a word.
+func countWords(text string) int {
+	count := 0
+	for _, field := range strings.Fields(text) {
+		if !strings.ContainsFunc(field, isCJK) {
+			count++
+			continue
+		}
+		// Count the other words of mixed fields as runs of letters and
+		// digits, so that CJK punctuation is not counted.
+		inWord := false
+		for _, r := range field {
+			switch {
+			case isCJK(r):
+				count++
+				inWord = false
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				if !inWord {
+					count++
+				}
+				inWord = true
+			}
+		}
+	}
+	return count
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// countSentences counts runs of text terminated by sentence-ending
+// punctuation, or by the end of the text.
+func countSentences(text string) int {
+	count := 0
+	inSentence := false
+	for _, r := range text {
+		switch {
+		case isSentenceEnd(r):
+			if inSentence {
+				count++
+			}
+			inSentence = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			inSentence = true
+		}
+	}
+	if inSentence {
+		count++
+	}
+	return count
+}
+
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestMeasureLength(t *testing.T) {
+	tests := []struct {
+		text string
+		mode LengthMode
+		want int
+	}{
+		{"hello", LengthModeCharacterCount, 5},
+		{"héllo", LengthModeCharacterCount, 5},
+		{"he\u0301llo", LengthModeCharacterCount, 5}, // combining accent
+		{"日本語", LengthModeCharacterCount, 3},
+		{"  one two\tthree\n", LengthModeWordCount, 3},
+		{"", LengthModeWordCount, 0},
+		{"日本語です。", LengthModeWordCount, 5},
+		{"Go言語 is fun", LengthModeWordCount, 5},
+		{"One. Two! Three? Four", LengthModeSentenceCount, 4},
+		{"Wait... what?!", LengthModeSentenceCount, 2},
+		{"これは文です。もう一つ。", LengthModeSentenceCount, 2},
+	}
+	for _, test := range tests {
+		if got := measureLength(test.text, test.mode); got != test.want {
+			t.Errorf("measureLength(%q, %v) = %d, want %d", test.text, test.mode, got, test.want)
+		}
+	}
+}
+
+func TestLengthEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineLengthEvaluator(r, "test", LengthModeWordCount, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := evalAction.Name(), "test/length_words"; got != want {
+		t.Errorf("got name %q, want %q", got, want)
+	}
+
+	ds := Dataset{
+		{Input: "q", Output: "short"},
+		{Input: "q", Output: "just right here"},
+		{Input: "q", Output: "this one is far too long"},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []float64{0, 1, 0} {
+		if got := (*resp)[i].Evaluation[0].Score; got != want {
+			t.Errorf("example %d: got score %v, want %v", i, got, want)
+		}
+	}
+	if got, want := (*resp)[1].Evaluation[0].Details["count"], 3; got != want {
+		t.Errorf("got count %v, want %v", got, want)
+	}
+
+	if _, err := DefineLengthEvaluator(r, "test", LengthModeCharacterCount, 10, 5); err == nil {
+		t.Error("got nil, want error for invalid range")
+	}
+}
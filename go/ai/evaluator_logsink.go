@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// streamingLogSink is an [EvalObserver] that writes a log line for each
+// event.
+type streamingLogSink struct {
+	w    io.Writer
+	json bool
+	now  func() time.Time
+
+	mu sync.Mutex
+}
+
+// logRecord is a log line in the "json" format of [NewStreamingLogSink].
+type logRecord struct {
+	Time         string  `json:"time"`
+	Event        string  `json:"event"`
+	Evaluator    string  `json:"evaluator"`
+	EvaluationId string  `json:"evaluationId,omitempty"`
+	TestCaseId   string  `json:"testCaseId,omitempty"`
+	DatasetSize  *int    `json:"datasetSize,omitempty"`
+	Scores       []Score `json:"scores,omitempty"`
+	Error        string  `json:"error,omitempty"`
+	Results      *int    `json:"results,omitempty"`
+	Failed       *int    `json:"failed,omitempty"`
+}
+
+// NewStreamingLogSink returns an [EvalObserver] that writes a line to w for
+// each event of an evaluation, so that its progress can be followed as it
+// runs. In the "json" format each line is a JSON object with "time",
+// "event" and "evaluator" fields and the fields of the event, which can be
+// processed with tools such as jq; any other format writes human-readable
+// text. Errors writing to w are ignored.
+func NewStreamingLogSink(w io.Writer, format string) EvalObserver {
+	return &streamingLogSink{w: w, json: format == "json", now: time.Now}
+}
+
+func (s *streamingLogSink) Observe(ctx context.Context, ev EvalEvent) {
+	rec := logRecord{Time: s.now().UTC().Format(time.RFC3339Nano)}
+	var text string
+	switch e := ev.(type) {
+	case EvaluationStarted:
+		rec.Event, rec.Evaluator, rec.EvaluationId, rec.DatasetSize = "evaluationStarted", e.Evaluator, e.EvaluationId, &e.DatasetSize
+		text = fmt.Sprintf("evaluation started with %d examples", e.DatasetSize)
+	case ExampleStarted:
+		rec.Event, rec.Evaluator, rec.TestCaseId = "exampleStarted", e.Evaluator, e.TestCaseId
+		text = fmt.Sprintf("example %s started", e.TestCaseId)
+	case ExampleCompleted:
+		rec.Event, rec.Evaluator, rec.TestCaseId, rec.Scores = "exampleCompleted", e.Evaluator, e.Result.TestCaseId, e.Result.Evaluation
+		text = fmt.Sprintf("example %s completed: %s", e.Result.TestCaseId, formatLogScores(e.Result.Evaluation))
+	case ExampleFailed:
+		rec.Event, rec.Evaluator, rec.TestCaseId, rec.Error = "exampleFailed", e.Evaluator, e.TestCaseId, e.Err.Error()
+		text = fmt.Sprintf("example %s failed: %v", e.TestCaseId, e.Err)
+	case EvaluationCompleted:
+		rec.Event, rec.Evaluator, rec.EvaluationId, rec.Results, rec.Failed = "evaluationCompleted", e.Evaluator, e.EvaluationId, &e.Results, &e.Failed
+		text = fmt.Sprintf("evaluation completed with %d results, %d failed", e.Results, e.Failed)
+	default:
+		return
+	}
+
+	var line []byte
+	if s.json {
+		b, err := json.Marshal(rec)
+		if err != nil {
+			return
+		}
+		line = append(b, '\n')
+	} else {
+		if rec.EvaluationId != "" {
+			text += " (evaluation " + rec.EvaluationId + ")"
+		}
+		line = []byte(fmt.Sprintf("%s %s: %s\n", rec.Time, rec.Evaluator, text))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w.Write(line)
+}
+
+// formatLogScores returns scores as "id=score (status)" pairs.
+func formatLogScores(scores []Score) string {
+	if len(scores) == 0 {
+		return "no scores"
+	}
+	parts := make([]string, len(scores))
+	for i, s := range scores {
+		id := s.Id
+		if id == "" {
+			id = "score"
+		}
+		parts[i] = fmt.Sprintf("%s=%v", id, s.Score)
+		if s.Status != "" {
+			parts[i] += " (" + s.Status + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestStreamingLogSink(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalAction, err := DefineEvaluator(r, "test", "logged", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Input == "boom" {
+			return nil, errors.New("boom")
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "ok", Score: 0.9, Status: ScoreStatusPass.String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "boom"}}
+	now := func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := NewStreamingLogSink(&buf, "text")
+		sink.(*streamingLogSink).now = now
+		Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds), WithEvaluateObserver(sink))
+
+		want := []string{
+			"2025-01-02T03:04:05Z test/logged: evaluation started with 2 examples",
+			"2025-01-02T03:04:05Z test/logged: example a started",
+			"2025-01-02T03:04:05Z test/logged: example a completed: ok=0.9 (pass)",
+			"2025-01-02T03:04:05Z test/logged: example b started",
+			"2025-01-02T03:04:05Z test/logged: example b failed: boom",
+			"2025-01-02T03:04:05Z test/logged: evaluation completed with 2 results, 1 failed",
+		}
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != len(want) {
+			t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), buf.String())
+		}
+		for i := range want {
+			// Evaluate assigns an evaluation id to the run.
+			if got := strings.Split(lines[i], " (evaluation ")[0]; got != want[i] {
+				t.Errorf("line %d: got %q, want %q", i, got, want[i])
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := NewStreamingLogSink(&buf, "json")
+		sink.(*streamingLogSink).now = now
+		Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds), WithEvaluateObserver(sink))
+
+		var events []string
+		var failure, completion map[string]any
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var rec map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+			}
+			if rec["time"] != "2025-01-02T03:04:05Z" || rec["evaluator"] != "test/logged" {
+				t.Errorf("got record %v, want time and evaluator", rec)
+			}
+			events = append(events, rec["event"].(string))
+			switch rec["event"] {
+			case "exampleFailed":
+				failure = rec
+			case "evaluationCompleted":
+				completion = rec
+			}
+		}
+		want := "evaluationStarted exampleStarted exampleCompleted exampleStarted exampleFailed evaluationCompleted"
+		if got := strings.Join(events, " "); got != want {
+			t.Errorf("got events %q, want %q", got, want)
+		}
+		if failure["testCaseId"] != "b" || failure["error"] != "boom" {
+			t.Errorf("got failure record %v, want test case b with error boom", failure)
+		}
+		if completion["results"] != 2.0 || completion["failed"] != 1.0 {
+			t.Errorf("got completion record %v, want 2 results and 1 failure", completion)
+		}
+	})
+}
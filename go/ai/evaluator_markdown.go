@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// MarkdownRule is a structural requirement checked by the evaluator defined
+// with [DefineMarkdownEvaluator].
+type MarkdownRule struct {
+	// Name identifies the rule in the evaluator's results.
+	Name        string
+	Description string
+	// Predicate reports whether the parsed document satisfies the rule.
+	// source is the Markdown text, which the positions in doc refer to.
+	Predicate func(doc ast.Node, source []byte) bool
+}
+
+// MarkdownHeadingRule returns a [MarkdownRule] named "heading" that requires
+// a heading of the given level, or of any level if level is 0.
+func MarkdownHeadingRule(level int) MarkdownRule {
+	desc := "Contains a heading"
+	if level > 0 {
+		desc = fmt.Sprintf("Contains a level %d heading", level)
+	}
+	return MarkdownRule{
+		Name:        "heading",
+		Description: desc,
+		Predicate: func(doc ast.Node, source []byte) bool {
+			return hasMarkdownNode(doc, func(n ast.Node) bool {
+				h, ok := n.(*ast.Heading)
+				return ok && (level == 0 || h.Level == level)
+			})
+		},
+	}
+}
+
+// MarkdownCodeBlockRule returns a [MarkdownRule] named "code_block" that
+// requires a code block. If language is set, the block must be fenced and
+// tagged with that language.
+func MarkdownCodeBlockRule(language string) MarkdownRule {
+	desc := "Contains a code block"
+	if language != "" {
+		desc = fmt.Sprintf("Contains a %s code block", language)
+	}
+	return MarkdownRule{
+		Name:        "code_block",
+		Description: desc,
+		Predicate: func(doc ast.Node, source []byte) bool {
+			return hasMarkdownNode(doc, func(n ast.Node) bool {
+				switch b := n.(type) {
+				case *ast.FencedCodeBlock:
+					return language == "" || string(b.Language(source)) == language
+				case *ast.CodeBlock:
+					return language == ""
+				}
+				return false
+			})
+		},
+	}
+}
+
+// MarkdownNumberedListRule returns a [MarkdownRule] named "numbered_list"
+// that requires an ordered list.
+func MarkdownNumberedListRule() MarkdownRule {
+	return MarkdownRule{
+		Name:        "numbered_list",
+		Description: "Contains a numbered list",
+		Predicate: func(doc ast.Node, source []byte) bool {
+			return hasMarkdownNode(doc, func(n ast.Node) bool {
+				l, ok := n.(*ast.List)
+				return ok && l.IsOrdered()
+			})
+		},
+	}
+}
+
+// hasMarkdownNode reports whether any node in doc satisfies match.
+func hasMarkdownNode(doc ast.Node, match func(ast.Node) bool) bool {
+	found := false
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && match(n) {
+			found = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return found
+}
+
+// DefineMarkdownEvaluator registers an evaluator named "markdown" that
+// parses the Output of each [Example] as Markdown and checks it against
+// rules.
+//
+// The score is the fraction of rules satisfied, and the example passes only
+// if all of them are. Whether each rule passed is reported in the "rules"
+// key of [Score.Details], keyed by rule name, and the names of the violated
+// rules in the "violations" key. If opts is nil, default options are used.
+func DefineMarkdownEvaluator(r *registry.Registry, provider string, rules []MarkdownRule, opts *EvaluatorOptions) (Evaluator, error) {
+	if len(rules) == 0 {
+		return nil, errors.New("ai.DefineMarkdownEvaluator: at least one rule is required")
+	}
+	seen := map[string]bool{}
+	for _, rule := range rules {
+		if rule.Name == "" || rule.Predicate == nil {
+			return nil, errors.New("ai.DefineMarkdownEvaluator: rules need a name and a predicate")
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("ai.DefineMarkdownEvaluator: duplicate rule %q", rule.Name)
+		}
+		seen[rule.Name] = true
+	}
+	if opts == nil {
+		descs := make([]string, len(rules))
+		for i, rule := range rules {
+			descs[i] = rule.Name
+			if rule.Description != "" {
+				descs[i] += ": " + rule.Description
+			}
+		}
+		opts = &EvaluatorOptions{
+			DisplayName: "Markdown Structure",
+			Definition:  "Checks that the output is Markdown with the required structure (" + strings.Join(descs, "; ") + ")",
+		}
+	}
+	md := goldmark.New()
+
+	return DefineEvaluator(r, provider, "markdown", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		output, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+		source := []byte(output)
+		doc := md.Parser().Parse(text.NewReader(source))
+
+		results := map[string]bool{}
+		violations := []string{}
+		for _, rule := range rules {
+			ok := rule.Predicate(doc, source)
+			results[rule.Name] = ok
+			if !ok {
+				violations = append(violations, rule.Name)
+			}
+		}
+		score := Score{
+			Id:     "markdown",
+			Score:  float64(len(rules)-len(violations)) / float64(len(rules)),
+			Status: passStatus(len(violations) == 0).String(),
+			Details: map[string]any{
+				"rules":      results,
+				"violations": violations,
+			},
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMarkdownEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []MarkdownRule{MarkdownHeadingRule(1), MarkdownCodeBlockRule("sh"), MarkdownNumberedListRule()}
+	e, err := DefineMarkdownEvaluator(r, "test", rules, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ds Dataset
+	for _, name := range []string{"complete", "bullets", "plain"} {
+		data, err := os.ReadFile(filepath.Join("testdata", "markdown", name+".md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds = append(ds, Example{TestCaseId: name, Input: "q", Output: string(data)})
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := indexResults(resp)
+	for id, want := range map[string]struct {
+		status     string
+		violations []string
+	}{
+		"complete": {"pass", []string{}},
+		// The heading is level 2 and the code block is indented, not fenced.
+		"bullets": {"fail", []string{"heading", "code_block", "numbered_list"}},
+		"plain":   {"fail", []string{"heading", "code_block", "numbered_list"}},
+	} {
+		s := results[id].Evaluation[0]
+		if s.Status != want.status {
+			t.Errorf("%s: got status %s, want %s", id, s.Status, want.status)
+		}
+		if diff := cmp.Diff(want.violations, s.Details["violations"]); diff != "" {
+			t.Errorf("%s: violations mismatch (-want +got):\n%s", id, diff)
+		}
+	}
+
+	// Without a language, any code block will do.
+	anyCode := MarkdownCodeBlockRule("")
+	if _, err := DefineMarkdownEvaluator(r, "other", []MarkdownRule{anyCode, anyCode}, nil); err == nil {
+		t.Error("got nil, want error for duplicate rules")
+	}
+	e, err = DefineMarkdownEvaluator(r, "other", []MarkdownRule{anyCode, MarkdownHeadingRule(0)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = Evaluate(context.Background(), e, WithEvaluateDataset(&Dataset{ds[1]}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := (*resp)[0].Evaluation[0]; s.Status != "pass" {
+		t.Errorf("got %+v, want pass for any heading and code block", s)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+
+	"github.com/firebase/genkit/go/core/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// MetricsEmitter records custom OpenTelemetry metrics from within an
+// evaluator callback, for secondary measurements such as an embedding
+// similarity or a token count that belong in a metrics backend rather than
+// in [Score.Details].
+type MetricsEmitter interface {
+	// RecordGauge records value as the current value of the gauge called
+	// name. Every value is also labelled with the evaluator's name.
+	RecordGauge(name string, value float64, attrs ...attribute.KeyValue)
+}
+
+// meterEmitter is a [MetricsEmitter] that records to an OpenTelemetry meter.
+type meterEmitter struct {
+	ctx       context.Context
+	meter     metric.Meter
+	evaluator string
+}
+
+func newMetricsEmitter(ctx context.Context, meter metric.Meter, evaluator string) *meterEmitter {
+	return &meterEmitter{ctx: ctx, meter: meter, evaluator: evaluator}
+}
+
+// RecordGauge implements [MetricsEmitter.RecordGauge].
+func (e *meterEmitter) RecordGauge(name string, value float64, attrs ...attribute.KeyValue) {
+	gauge, err := e.meter.Float64Gauge(name)
+	if err != nil {
+		// Do not fail the evaluation because a metric can't be recorded.
+		logger.FromContext(e.ctx).Error("creating gauge failed", "evaluator", e.evaluator, "gauge", name, "err", err)
+		return
+	}
+	attrs = append([]attribute.KeyValue{attribute.String("evaluator", e.evaluator)}, attrs...)
+	gauge.Record(e.ctx, value, metric.WithAttributes(attrs...))
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestEvaluatorMetrics(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := sdkmetric.NewManualReader()
+	r.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	evalAction, err := DefineEvaluator(r, "test", "similarity", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		req.Metrics.RecordGauge("eval/cosine_similarity", 0.75, attribute.String("model", "embedder"))
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "similarity", Score: 0.75}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := Dataset{{TestCaseId: "a", Input: "x"}}
+	if _, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds}); err != nil {
+		t.Fatal(err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	var gauge *metricdata.Gauge[float64]
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "eval/cosine_similarity" {
+				g, ok := m.Data.(metricdata.Gauge[float64])
+				if !ok {
+					t.Fatalf("got metric data %T, want gauge", m.Data)
+				}
+				gauge = &g
+			}
+		}
+	}
+	if gauge == nil {
+		t.Fatal("gauge was not recorded")
+	}
+	if got := len(gauge.DataPoints); got != 1 {
+		t.Fatalf("got %d data points, want 1", got)
+	}
+	dp := gauge.DataPoints[0]
+	if dp.Value != 0.75 {
+		t.Errorf("got value %v, want 0.75", dp.Value)
+	}
+	for k, want := range map[attribute.Key]string{"evaluator": "test/similarity", "model": "embedder"} {
+		if got, ok := dp.Attributes.Value(k); !ok || got.AsString() != want {
+			t.Errorf("got attribute %s = %v, want %q", k, got.AsString(), want)
+		}
+	}
+}
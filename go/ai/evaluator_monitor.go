@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/firebase/genkit/go/core/logger"
+)
+
+// MonitorRule is a condition checked by an [EvalMonitor]: the value of a
+// score, averaged over the latest runs of an evaluator, must not fall below
+// a threshold.
+type MonitorRule struct {
+	Evaluator string
+	ScoreId   string
+	// Threshold is the lowest acceptable value of the score. The value of
+	// the score in a run is its mean if it has numeric values, and its
+	// pass rate otherwise.
+	Threshold float64
+	// Window is the number of latest runs averaged. It defaults to 1.
+	Window int
+	// AlertFn is called with the alert and the context of the check that
+	// raised it. To stop the monitor from AlertFn, pass that context to
+	// [EvalMonitor.Stop].
+	AlertFn func(ctx context.Context, alert MonitorAlert)
+}
+
+// MonitorAlert reports that the score checked by a [MonitorRule] fell below
+// its threshold.
+type MonitorAlert struct {
+	Evaluator string    `json:"evaluator"`
+	ScoreId   string    `json:"scoreId"`
+	Threshold float64   `json:"threshold"`
+	Value     float64   `json:"value"`
+	EvalIds   []string  `json:"evalIds"` // Runs in the window, oldest first.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// EvalMonitor watches the runs recorded in a store, for example by an
+// [EvaluationScheduler] or with [RecordEvalRun], and alerts when a score
+// degrades. Each rule is checked whenever its evaluator has a new run, so a
+// degraded score is reported once per run rather than on every check.
+type EvalMonitor struct {
+	store StoreEvaluatorResponse
+	rules []MonitorRule
+
+	// newTicker returns a channel delivering ticks every interval, and a
+	// function that stops it. Tests replace it with a fake clock.
+	newTicker func(interval time.Duration) (<-chan time.Time, func())
+
+	mu      sync.Mutex
+	checked []string // Latest run seen by each rule.
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// monitorRunKey is the context key identifying the goroutine started by
+// [EvalMonitor.Start]. Its value is the done channel of that goroutine.
+type monitorRunKey struct{}
+
+// NewEvalMonitor returns a monitor checking rules against the runs in store.
+func NewEvalMonitor(store StoreEvaluatorResponse, rules []MonitorRule) *EvalMonitor {
+	return &EvalMonitor{
+		store:   store,
+		rules:   rules,
+		checked: make([]string, len(rules)),
+	}
+}
+
+// Check checks every rule against the latest runs in the store and calls
+// the AlertFn of those whose score is below threshold. AlertFns are called
+// after the rules are checked, so they may call Stop.
+func (m *EvalMonitor) Check(ctx context.Context) error {
+	runs, err := LoadEvalHistory(ctx, m.store, EvalFilter{})
+	if err != nil {
+		return fmt.Errorf("ai.EvalMonitor.Check: %w", err)
+	}
+	byEvaluator := map[string][]EvalRun{}
+	for _, run := range runs {
+		byEvaluator[run.Evaluator] = append(byEvaluator[run.Evaluator], run)
+	}
+
+	type alert struct {
+		fn    func(context.Context, MonitorAlert)
+		alert MonitorAlert
+	}
+	var alerts []alert
+	m.mu.Lock()
+	for i, rule := range m.rules {
+		history := byEvaluator[rule.Evaluator]
+		if len(history) == 0 || history[len(history)-1].EvalId == m.checked[i] {
+			continue
+		}
+		m.checked[i] = history[len(history)-1].EvalId
+		window := max(rule.Window, 1)
+		history = history[max(len(history)-window, 0):]
+
+		var sum float64
+		var evalIds []string
+		for _, run := range history {
+			if v, ok := monitoredValue(run.Summary.Scores[rule.ScoreId]); ok {
+				sum += v
+				evalIds = append(evalIds, run.EvalId)
+			}
+		}
+		if len(evalIds) == 0 {
+			continue
+		}
+		value := sum / float64(len(evalIds))
+		if value < rule.Threshold && rule.AlertFn != nil {
+			alerts = append(alerts, alert{rule.AlertFn, MonitorAlert{
+				Evaluator: rule.Evaluator,
+				ScoreId:   rule.ScoreId,
+				Threshold: rule.Threshold,
+				Value:     value,
+				EvalIds:   evalIds,
+				Timestamp: history[len(history)-1].Timestamp,
+			}})
+		}
+	}
+	m.mu.Unlock()
+
+	for _, a := range alerts {
+		a.fn(ctx, a.alert)
+	}
+	return nil
+}
+
+// monitoredValue returns the value of a score in a run: its mean if it has
+// numeric values, and its pass rate otherwise.
+func monitoredValue(stats *ScoreStats) (float64, bool) {
+	switch {
+	case stats == nil:
+		return 0, false
+	case stats.Numeric > 0:
+		return stats.Mean, true
+	case stats.Passed+stats.Failed > 0:
+		return float64(stats.Passed) / float64(stats.Passed+stats.Failed), true
+	}
+	return 0, false
+}
+
+// Start starts checking the rules every interval, until Stop is called or
+// ctx is canceled. Errors from individual checks are logged. Start returns
+// an error if interval is not positive or the monitor is already running.
+func (m *EvalMonitor) Start(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("ai.EvalMonitor.Start: interval must be positive")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != nil {
+		return errors.New("ai.EvalMonitor.Start: already running")
+	}
+
+	newTicker := m.newTicker
+	if newTicker == nil {
+		newTicker = func(d time.Duration) (<-chan time.Time, func()) {
+			t := time.NewTicker(d)
+			return t.C, t.Stop
+		}
+	}
+	ticks, stopTicker := newTicker(interval)
+	stop, done := make(chan struct{}), make(chan struct{})
+	m.stop, m.done = stop, done
+	ctx = context.WithValue(ctx, monitorRunKey{}, done)
+	go func() {
+		defer close(done)
+		defer stopTicker()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticks:
+				if err := m.Check(ctx); err != nil {
+					logger.FromContext(ctx).Error("evaluation monitor check failed", "err", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the monitor and waits for a check in progress to finish. If
+// ctx is the context passed to an AlertFn by that check, Stop does not wait,
+// since the check cannot finish before AlertFn returns. It does nothing if
+// the monitor is not running.
+func (m *EvalMonitor) Stop(ctx context.Context) {
+	m.mu.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	if running, _ := ctx.Value(monitorRunKey{}).(chan struct{}); running != done {
+		<-done
+	}
+}
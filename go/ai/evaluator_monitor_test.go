@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEvalMonitor(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryEvaluationStore()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := func(n int, evaluator string, stats ScoreStats) {
+		t.Helper()
+		run := EvalRun{
+			EvalId:    fmt.Sprintf("%s-%d", evaluator, n),
+			Evaluator: evaluator,
+			Timestamp: start.Add(time.Duration(n) * time.Hour),
+			Summary:   ScoreSummary{Scores: map[string]*ScoreStats{"quality": &stats}},
+		}
+		if err := RecordEvalRun(ctx, store, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var alerts []MonitorAlert
+	alertFn := func(_ context.Context, a MonitorAlert) { alerts = append(alerts, a) }
+	m := NewEvalMonitor(store, []MonitorRule{
+		{Evaluator: "judge", ScoreId: "quality", Threshold: 0.7, Window: 2, AlertFn: alertFn},
+		{Evaluator: "regex", ScoreId: "quality", Threshold: 0.5, AlertFn: alertFn},
+	})
+
+	record(1, "judge", ScoreStats{Numeric: 1, Mean: 0.9})
+	record(2, "judge", ScoreStats{Numeric: 1, Mean: 0.8})
+	record(1, "regex", ScoreStats{Passed: 3, Failed: 1})
+	if err := m.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("got alerts %+v for healthy runs, want none", alerts)
+	}
+
+	// The judge's window mean drops to 0.65 and the regex pass rate to 0.25.
+	record(3, "judge", ScoreStats{Numeric: 1, Mean: 0.5})
+	record(2, "regex", ScoreStats{Passed: 1, Failed: 3})
+	if err := m.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(alerts), 2; got != want {
+		t.Fatalf("got %d alerts, want %d", got, want)
+	}
+	if a := alerts[0]; a.Evaluator != "judge" || math.Abs(a.Value-0.65) > 1e-9 || !slices.Equal(a.EvalIds, []string{"judge-2", "judge-3"}) {
+		t.Errorf("got judge alert %+v, want value 0.65 over judge-2 and judge-3", a)
+	}
+	if a := alerts[1]; a.Evaluator != "regex" || a.Value != 0.25 || !a.Timestamp.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("got regex alert %+v, want value 0.25 at run 2", a)
+	}
+
+	// Without new runs, nothing is reported again.
+	if err := m.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(alerts); got != 2 {
+		t.Errorf("got %d alerts after checking unchanged runs, want 2", got)
+	}
+
+	// The polling loop checks on each tick.
+	ticks := make(chan time.Time)
+	m.newTicker = fakeTicker(ticks)
+	if err := m.Start(ctx, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Start(ctx, time.Minute); err == nil {
+		t.Error("got nil, want error when starting twice")
+	}
+	record(4, "judge", ScoreStats{Numeric: 1, Mean: 0.4})
+	ticks <- start
+	m.Stop(ctx)
+	if got := len(alerts); got != 3 {
+		t.Errorf("got %d alerts after polling, want 3", got)
+	}
+}
+
+func TestEvalMonitorStopFromAlert(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryEvaluationStore()
+	n := 0
+	record := func() {
+		t.Helper()
+		n++
+		run := EvalRun{
+			EvalId:    fmt.Sprint(n),
+			Evaluator: "judge",
+			Summary:   ScoreSummary{Scores: map[string]*ScoreStats{"quality": {Numeric: 1, Mean: 0.1}}},
+		}
+		if err := RecordEvalRun(ctx, store, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var m *EvalMonitor
+	alerted := make(chan struct{}, 2)
+	m = NewEvalMonitor(store, []MonitorRule{{Evaluator: "judge", ScoreId: "quality", Threshold: 0.5, AlertFn: func(ctx context.Context, _ MonitorAlert) {
+		m.Stop(ctx)
+		alerted <- struct{}{}
+	}}})
+	finished := func(name string, f func()) {
+		t.Helper()
+		done := make(chan struct{})
+		go func() {
+			f()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: AlertFn calling Stop deadlocked", name)
+		}
+	}
+
+	record()
+	finished("Check", func() {
+		if err := m.Check(ctx); err != nil {
+			t.Error(err)
+		}
+	})
+
+	ticks := make(chan time.Time)
+	m.newTicker = fakeTicker(ticks)
+	if err := m.Start(ctx, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	record()
+	finished("polling", func() {
+		ticks <- time.Now()
+		<-alerted
+		<-alerted
+	})
+	// The monitor was stopped by the alert, so it can be started again.
+	if err := m.Start(ctx, time.Minute); err != nil {
+		t.Errorf("restarting: %v", err)
+	}
+	m.Stop(ctx)
+}
+
+func TestEvalMonitorStopWhileAlerting(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryEvaluationStore()
+	run := EvalRun{
+		EvalId:    "1",
+		Evaluator: "judge",
+		Summary:   ScoreSummary{Scores: map[string]*ScoreStats{"quality": {Numeric: 1, Mean: 0.1}}},
+	}
+	if err := RecordEvalRun(ctx, store, run); err != nil {
+		t.Fatal(err)
+	}
+
+	alerting, release := make(chan struct{}), make(chan struct{})
+	m := NewEvalMonitor(store, []MonitorRule{{Evaluator: "judge", ScoreId: "quality", Threshold: 0.5, AlertFn: func(context.Context, MonitorAlert) {
+		close(alerting)
+		<-release
+	}}})
+	ticks := make(chan time.Time)
+	m.newTicker = fakeTicker(ticks)
+	if err := m.Start(ctx, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	ticks <- time.Now()
+	<-alerting
+
+	// A concurrent Check must not stop Stop from waiting for the alert.
+	if err := m.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		m.Stop(ctx)
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while the monitor was alerting")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the alert finished")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// DefaultRubricKey is the key of the rubric used for examples whose language
+// has no rubric of its own.
+const DefaultRubricKey = "default"
+
+// DefineMultilingualEvaluator registers an evaluator that grades each
+// [Example] with model, an LLM judge, using the rubric for the example's
+// Language.
+//
+// Rubrics are looked up by the exact language code, then by its base
+// language (so "pt-BR" falls back to "pt"), and finally under
+// [DefaultRubricKey]. Examples without a Language use the default rubric.
+// The judge's score is in [0, 1] and passes at 0.5 or above; the rubric used
+// and the judge's reasoning are reported in the "rubric" and "reasoning" keys
+// of [Score.Details], together with "rationale" if
+// [EvaluatorOptions.UseChainOfThought] is set, and the judge's confidence in
+// [Score.Confidence]. If opts is nil, default options are used.
+func DefineMultilingualEvaluator(r *registry.Registry, provider, name string, rubrics map[string]string, model Model, opts *EvaluatorOptions) (Evaluator, error) {
+	if model == nil {
+		return nil, errors.New("ai.DefineMultilingualEvaluator: model is required")
+	}
+	if len(rubrics) == 0 {
+		return nil, errors.New("ai.DefineMultilingualEvaluator: at least one rubric is required")
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Multilingual Judge",
+			Definition:  "Grades outputs with a language-specific rubric",
+		}
+	}
+
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		key, ok := selectRubric(rubrics, req.Input.Language)
+		if !ok {
+			return nil, fmt.Errorf("no rubric for language %q and no %q rubric", req.Input.Language, DefaultRubricKey)
+		}
+		verdict, err := runJudge(ctx, r, model, rubrics[key], &req.Input, opts.UseChainOfThought)
+		if err != nil {
+			return nil, err
+		}
+
+		score := Score{
+			Id:         name,
+			Score:      verdict.Score,
+			Status:     passStatus(verdict.Score >= judgePassThreshold).String(),
+			Confidence: verdict.Confidence,
+			Details: map[string]any{
+				"rubric":    key,
+				"reasoning": verdict.Reasoning,
+			},
+		}
+		if opts.UseChainOfThought {
+			score.Details["rationale"] = verdict.Rationale
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// selectRubric returns the key of the rubric to use for lang.
+func selectRubric(rubrics map[string]string, lang string) (string, bool) {
+	if lang != "" {
+		if _, ok := rubrics[lang]; ok {
+			return lang, true
+		}
+		if base, _, found := strings.Cut(lang, "-"); found {
+			if _, ok := rubrics[base]; ok {
+				return base, true
+			}
+		}
+	}
+	_, ok := rubrics[DefaultRubricKey]
+	return DefaultRubricKey, ok
+}
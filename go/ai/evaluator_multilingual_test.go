@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestMultilingualEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The judge passes outputs graded with the Portuguese rubric and fails
+	// everything else.
+	judge := defineJudgeModel(r, "judge", func(prompt string) any {
+		if strings.Contains(prompt, "Avalie") {
+			confidence := 0.8
+			return judgeVerdict{Score: 0.9, Reasoning: "bom", Confidence: &confidence}
+		}
+		return judgeVerdict{Score: 0.1, Reasoning: "bad"}
+	})
+	rubrics := map[string]string{
+		"pt":             "Avalie a fluência.",
+		DefaultRubricKey: "Grade fluency.",
+	}
+	evalAction, err := DefineMultilingualEvaluator(r, "test", "fluency", rubrics, judge, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "exact", Input: "q", Output: "a", Language: "pt"},
+		{TestCaseId: "region", Input: "q", Output: "a", Language: "pt-BR"},
+		{TestCaseId: "fallback", Input: "q", Output: "a", Language: "fr"},
+		{TestCaseId: "missing", Input: "q", Output: "a"},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rubric, status string
+		confidence     string
+	}{
+		{"pt", "pass", "0.8"},
+		{"pt", "pass", "0.8"},
+		{DefaultRubricKey, "fail", "none"},
+		{DefaultRubricKey, "fail", "none"},
+	}
+	for i, test := range tests {
+		score := (*resp)[i].Evaluation[0]
+		if got := score.Details["rubric"]; got != test.rubric {
+			t.Errorf("%s: got rubric %v, want %v", (*resp)[i].TestCaseId, got, test.rubric)
+		}
+		if got := score.Status; got != test.status {
+			t.Errorf("%s: got status %v, want %v", (*resp)[i].TestCaseId, got, test.status)
+		}
+		got := "none"
+		if score.Confidence != nil {
+			got = fmt.Sprint(*score.Confidence)
+		}
+		if got != test.confidence {
+			t.Errorf("%s: got confidence %v, want %v", (*resp)[i].TestCaseId, got, test.confidence)
+		}
+	}
+}
+
+func TestMultilingualEvaluatorNoDefault(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	judge := defineJudgeModel(r, "judge", func(string) any { return judgeVerdict{Score: 1} })
+	evalAction, err := DefineMultilingualEvaluator(r, "test", "fluency", map[string]string{"en": "Grade fluency."}, judge, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{TestCaseId: "missing", Input: "q", Output: "a"}}
+	_, err = evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	var evalErr EvaluatorError
+	if !errors.As(err, &evalErr) || !strings.Contains(evalErr.Cause.Error(), "no rubric") {
+		t.Errorf("got error %v, want missing rubric error", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aymerick/raymond"
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// multiScoreVerdict is the structured output requested from multi-score
+// judges.
+type multiScoreVerdict struct {
+	Scores []namedJudgeScore `json:"scores"`
+}
+
+// cotMultiScoreVerdict is the structured output requested from multi-score
+// judges that use chain of thought.
+type cotMultiScoreVerdict struct {
+	Rationale string            `json:"rationale"`
+	Scores    []namedJudgeScore `json:"scores"`
+}
+
+type namedJudgeScore struct {
+	Name      string  `json:"name"`
+	Score     float64 `json:"score"`
+	Reasoning string  `json:"reasoning"`
+}
+
+// DefineMultiScoreEvaluator registers an evaluator that asks model for
+// several named scores of each [Example] in a single call, rather than
+// calling a judge once per metric.
+//
+// promptTemplate is a Handlebars template describing the task to the judge.
+// It is rendered with the variables "input", "output", "reference" and
+// "context", holding the example's fields as text; use triple braces, as in
+// {{{output}}}, to insert them without HTML escaping. If promptTemplate is
+// empty, the example's fields are listed in a default prompt. The judge is
+// then asked to score the example on each of scoreNames between 0 and 1.
+//
+// The evaluator returns one [Score] per name, in the order of scoreNames,
+// with the judge's reasoning in the "reasoning" key of [Score.Details]. A
+// score passes when it is at least 0.5. With
+// [EvaluatorOptions.UseChainOfThought], the judge's rationale is reported
+// in the "rationale" key of every score. The example fails with an error if
+// the judge omits a score or returns an unknown one. If opts is nil,
+// default options are used.
+func DefineMultiScoreEvaluator(r *registry.Registry, provider, name string, model Model, scoreNames []string, promptTemplate string, opts *EvaluatorOptions) (Evaluator, error) {
+	if model == nil {
+		return nil, errors.New("ai.DefineMultiScoreEvaluator: model is required")
+	}
+	if len(scoreNames) == 0 {
+		return nil, errors.New("ai.DefineMultiScoreEvaluator: at least one score name is required")
+	}
+	seen := map[string]bool{}
+	for _, n := range scoreNames {
+		if n == "" {
+			return nil, errors.New("ai.DefineMultiScoreEvaluator: score name is empty")
+		}
+		if seen[n] {
+			return nil, fmt.Errorf("ai.DefineMultiScoreEvaluator: duplicate score name %q", n)
+		}
+		seen[n] = true
+	}
+	if promptTemplate != "" {
+		if _, err := raymond.Parse(promptTemplate); err != nil {
+			return nil, fmt.Errorf("ai.DefineMultiScoreEvaluator: invalid prompt template: %w", err)
+		}
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Multi-Score Judge",
+			Definition:  "Scores the output on several metrics with a single LLM judge call",
+		}
+	}
+
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		prompt, err := multiScorePrompt(promptTemplate, scoreNames, &req.Input)
+		if err != nil {
+			return nil, err
+		}
+		var v multiScoreVerdict
+		var rationale string
+		if opts.UseChainOfThought {
+			var cv cotMultiScoreVerdict
+			_, err = GenerateData(ctx, r, &cv, WithModel(model), WithPromptText(prompt+"\n"+chainOfThoughtInstruction))
+			v, rationale = multiScoreVerdict{Scores: cv.Scores}, cv.Rationale
+		} else {
+			_, err = GenerateData(ctx, r, &v, WithModel(model), WithPromptText(prompt))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("judge model %q: %w", model.Name(), err)
+		}
+
+		byName := map[string]namedJudgeScore{}
+		for _, s := range v.Scores {
+			if !seen[s.Name] {
+				return nil, fmt.Errorf("judge returned unknown score %q", s.Name)
+			}
+			if _, ok := byName[s.Name]; ok {
+				return nil, fmt.Errorf("judge returned score %q more than once", s.Name)
+			}
+			byName[s.Name] = s
+		}
+		scores := make([]Score, len(scoreNames))
+		for i, n := range scoreNames {
+			s, ok := byName[n]
+			if !ok {
+				return nil, fmt.Errorf("judge did not return score %q", n)
+			}
+			value := min(max(s.Score, 0), 1)
+			scores[i] = Score{
+				Id:      n,
+				Score:   value,
+				Status:  passStatus(value >= judgePassThreshold).String(),
+				Details: map[string]any{"reasoning": s.Reasoning},
+			}
+			if opts.UseChainOfThought {
+				scores[i].Details["rationale"] = rationale
+			}
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: scores,
+		}, nil
+	})
+}
+
+// multiScorePrompt returns the prompt asking an LLM judge to score ex on
+// each of scoreNames, with the task described by promptTemplate.
+func multiScorePrompt(promptTemplate string, scoreNames []string, ex *Example) (string, error) {
+	var sb strings.Builder
+	if promptTemplate == "" {
+		sb.WriteString("You are grading the output of an AI system.\n\n")
+		if err := writeJudgeExample(&sb, ex); err != nil {
+			return "", err
+		}
+	} else {
+		vars := map[string]any{}
+		for key, value := range map[string]any{"input": ex.Input, "output": ex.Output, "reference": ex.Reference} {
+			if value == nil {
+				continue
+			}
+			text, err := exampleText(value)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", key, err)
+			}
+			vars[key] = text
+		}
+		if len(ex.Context) > 0 {
+			text, err := exampleText(ex.Context)
+			if err != nil {
+				return "", fmt.Errorf("context: %w", err)
+			}
+			vars["context"] = text
+		}
+		rendered, err := renderDotprompt(promptTemplate, vars, nil)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+		sb.WriteString("\n\n")
+	}
+	fmt.Fprintf(&sb, "Score the output on each of these metrics: %s. ", strings.Join(scoreNames, ", "))
+	sb.WriteString("For each metric, respond with its name, a score between 0 and 1, where 1 is best, and a short reasoning.")
+	return sb.String(), nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestMultiScoreEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prompts []string
+	judge := defineJudgeModel(r, "multiJudge", func(prompt string) any {
+		prompts = append(prompts, prompt)
+		if strings.Contains(prompt, "omit") {
+			return multiScoreVerdict{Scores: []namedJudgeScore{{Name: "relevance", Score: 1}}}
+		}
+		return multiScoreVerdict{Scores: []namedJudgeScore{
+			{Name: "relevance", Score: 0.9, Reasoning: "on topic"},
+			{Name: "faithfulness", Score: 0.2, Reasoning: "made up"},
+			{Name: "coherence", Score: 1.5, Reasoning: "clear"},
+		}}
+	})
+
+	e, err := DefineMultiScoreEvaluator(r, "test", "multi", judge, []string{"faithfulness", "relevance", "coherence"},
+		"Question: {{{input}}}\nAnswer: {{{output}}}\nSources: {{{context}}}", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "a", Input: "Who wrote Hamlet?", Output: "Marlowe & Kyd", Context: []any{"Shakespeare wrote Hamlet."}},
+		{TestCaseId: "b", Input: "omit", Output: "x"},
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err == nil {
+		t.Error("got nil error, want error for the omitted scores")
+	}
+	if got := len(prompts); got != 2 {
+		t.Errorf("got %d judge calls, want one per example", got)
+	}
+	for _, want := range []string{"Question: Who wrote Hamlet?", "Answer: Marlowe & Kyd", `Sources: ["Shakespeare wrote Hamlet."]`, "faithfulness, relevance, coherence"} {
+		if !strings.Contains(prompts[0], want) {
+			t.Errorf("prompt %q does not contain %q", prompts[0], want)
+		}
+	}
+
+	res := indexResults(resp)
+	want := []struct {
+		id     string
+		score  float64
+		status string
+	}{
+		{"faithfulness", 0.2, "fail"},
+		{"relevance", 0.9, "pass"},
+		{"coherence", 1, "pass"},
+	}
+	got := res["a"].Evaluation
+	if len(got) != len(want) {
+		t.Fatalf("got %d scores, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Id != w.id || got[i].Score != w.score || got[i].Status != w.status {
+			t.Errorf("score %d: got %s=%v (%s), want %s=%v (%s)", i, got[i].Id, got[i].Score, got[i].Status, w.id, w.score, w.status)
+		}
+	}
+	if got := res["a"].Evaluation[0].Details["reasoning"]; got != "made up" {
+		t.Errorf("got reasoning %v, want made up", got)
+	}
+	if got := res["b"].Evaluation[0].Error; !strings.Contains(got, `did not return score "faithfulness"`) {
+		t.Errorf("got error %q, want missing score error", got)
+	}
+
+	for _, names := range [][]string{nil, {"a", "a"}, {""}} {
+		if _, err := DefineMultiScoreEvaluator(r, "test", "bad", judge, names, "", nil); err == nil {
+			t.Errorf("score names %q: got nil error, want error", names)
+		}
+	}
+	if _, err := DefineMultiScoreEvaluator(r, "test", "badTemplate", judge, []string{"a"}, "{{#if}}", nil); err == nil {
+		t.Error("got nil error for invalid template, want error")
+	}
+}
+
+func TestMultiScorePromptDefault(t *testing.T) {
+	prompt, err := multiScorePrompt("", []string{"relevance"}, &Example{Input: "q", Output: "a", Reference: "r"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Input:\nq", "Output:\na", "Reference:\nr", "metrics: relevance."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt %q does not contain %q", prompt, want)
+		}
+	}
+}
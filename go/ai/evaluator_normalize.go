@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/firebase/genkit/go/core/tracing"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization is an enum of string transformations that
+// [DefineNormalizedEvaluator] applies to examples before evaluating them.
+type Normalization int
+
+const (
+	// NormalizeTrimWhitespace removes leading and trailing whitespace.
+	NormalizeTrimWhitespace Normalization = iota
+	// NormalizeLowerCase maps all letters to lower case.
+	NormalizeLowerCase
+	// NormalizeNFC converts to Unicode Normalization Form C.
+	NormalizeNFC
+	// NormalizeNFD converts to Unicode Normalization Form D.
+	NormalizeNFD
+	// NormalizeRemovePunctuation removes all Unicode punctuation characters.
+	NormalizeRemovePunctuation
+	// NormalizeCollapseWhitespace replaces each run of whitespace with a
+	// single space.
+	NormalizeCollapseWhitespace
+)
+
+var normalizationName = map[Normalization]string{
+	NormalizeTrimWhitespace:     "trimWhitespace",
+	NormalizeLowerCase:          "lowerCase",
+	NormalizeNFC:                "nfc",
+	NormalizeNFD:                "nfd",
+	NormalizeRemovePunctuation:  "removePunctuation",
+	NormalizeCollapseWhitespace: "collapseWhitespace",
+}
+
+func (n Normalization) String() string {
+	if s, ok := normalizationName[n]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+var whitespaceRunRe = regexp.MustCompile(`\s+`)
+
+// apply returns s transformed by n.
+func (n Normalization) apply(s string) string {
+	switch n {
+	case NormalizeTrimWhitespace:
+		return strings.TrimSpace(s)
+	case NormalizeLowerCase:
+		return strings.ToLower(s)
+	case NormalizeNFC:
+		return norm.NFC.String(s)
+	case NormalizeNFD:
+		return norm.NFD.String(s)
+	case NormalizeRemovePunctuation:
+		return strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) {
+				return -1
+			}
+			return r
+		}, s)
+	case NormalizeCollapseWhitespace:
+		return whitespaceRunRe.ReplaceAllString(s, " ")
+	}
+	return s
+}
+
+// NormalizeOption configures [DefineNormalizedEvaluator].
+type NormalizeOption func(*normalizedEvaluator)
+
+// WithNormalizeReference makes [DefineNormalizedEvaluator] normalize
+// Example.Reference in addition to Example.Output.
+func WithNormalizeReference() NormalizeOption {
+	return func(e *normalizedEvaluator) {
+		e.reference = true
+	}
+}
+
+// DefineNormalizedEvaluator returns an [Evaluator] that applies norms, in
+// order, to the Output of each example before passing the dataset to inner.
+// Outputs that are not strings are passed through unchanged. The applied
+// normalizations are recorded as the "evaluator:normalizations" attribute
+// of the current span.
+func DefineNormalizedEvaluator(inner Evaluator, norms []Normalization, opts ...NormalizeOption) Evaluator {
+	e := &normalizedEvaluator{inner: inner, norms: norms}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+type normalizedEvaluator struct {
+	inner     Evaluator
+	norms     []Normalization
+	reference bool
+}
+
+func (e *normalizedEvaluator) Name() string {
+	return e.inner.Name()
+}
+
+func (e *normalizedEvaluator) Evaluate(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
+	names := make([]string, len(e.norms))
+	for i, n := range e.norms {
+		names[i] = n.String()
+	}
+	tracing.SetCustomMetadataAttr(ctx, "evaluator:normalizations", strings.Join(names, ","))
+
+	if req == nil || req.Dataset == nil {
+		return e.inner.Evaluate(ctx, req)
+	}
+	ds := make(Dataset, len(*req.Dataset))
+	for i, ex := range *req.Dataset {
+		ex.Output = e.normalize(ex.Output)
+		if e.reference {
+			ex.Reference = e.normalize(ex.Reference)
+		}
+		ds[i] = ex
+	}
+	r := *req
+	r.Dataset = &ds
+	return e.inner.Evaluate(ctx, &r)
+}
+
+// normalize applies e.norms to v if it is a string.
+func (e *normalizedEvaluator) normalize(v any) any {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	for _, n := range e.norms {
+		s = n.apply(s)
+	}
+	return s
+}
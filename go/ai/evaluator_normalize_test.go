@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestDefineNormalizedEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seen []Example
+	inner, err := DefineEvaluator(r, "test", "recorder", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		seen = append(seen, req.Input)
+		return &EvaluatorCallbackResponse{TestCaseId: req.Input.TestCaseId, Evaluation: []Score{{Id: "s", Score: 1}}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{Input: "q", Output: "  Hello,\n  World!  ", Reference: "Hello, World!"}}
+	norms := []Normalization{NormalizeTrimWhitespace, NormalizeCollapseWhitespace, NormalizeRemovePunctuation, NormalizeLowerCase}
+
+	e := DefineNormalizedEvaluator(inner, norms)
+	if _, err := e.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := seen[0].Output, "hello world"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+	if got, want := seen[0].Reference, "Hello, World!"; got != want {
+		t.Errorf("got reference %q, want it unchanged (%q)", got, want)
+	}
+	if got, want := ds[0].Output, "  Hello,\n  World!  "; got != want {
+		t.Errorf("caller's dataset was modified: got %q", got)
+	}
+
+	seen = nil
+	e = DefineNormalizedEvaluator(inner, norms, WithNormalizeReference())
+	if _, err := e.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := seen[0].Reference, "hello world"; got != want {
+		t.Errorf("got reference %q, want %q", got, want)
+	}
+}
+
+func TestNormalizationApply(t *testing.T) {
+	decomposed := "é"
+	composed := "é"
+	for _, tc := range []struct {
+		n        Normalization
+		in, want string
+	}{
+		{NormalizeNFC, decomposed, composed},
+		{NormalizeNFD, composed, decomposed},
+		{NormalizeCollapseWhitespace, " a \t\n b ", " a b "},
+		{NormalizeRemovePunctuation, "¿qué?", "qué"},
+	} {
+		if got := tc.n.apply(tc.in); got != tc.want {
+			t.Errorf("%v.apply(%q) = %q, want %q", tc.n, tc.in, got, tc.want)
+		}
+	}
+	if got, want := Normalization(99).String(), "unknown"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+// EvalEvent is an event emitted to an [EvalObserver] during an evaluation.
+// It is one of [EvaluationStarted], [ExampleStarted], [ExampleCompleted],
+// [ExampleFailed] or [EvaluationCompleted].
+type EvalEvent interface {
+	isEvalEvent()
+}
+
+// EvaluationStarted is emitted once before any example is evaluated.
+type EvaluationStarted struct {
+	Evaluator    string
+	EvaluationId string
+	DatasetSize  int
+}
+
+// ExampleStarted is emitted before an example is evaluated.
+type ExampleStarted struct {
+	Evaluator  string
+	TestCaseId string
+}
+
+// ExampleCompleted is emitted after an example was evaluated successfully.
+type ExampleCompleted struct {
+	Evaluator string
+	Result    EvaluationResult
+}
+
+// ExampleFailed is emitted after the evaluator returned an error for an
+// example.
+type ExampleFailed struct {
+	Evaluator  string
+	TestCaseId string
+	Err        error
+}
+
+// EvaluationCompleted is emitted once after the evaluation finished,
+// including when it was stopped early because the context was cancelled.
+type EvaluationCompleted struct {
+	Evaluator    string
+	EvaluationId string
+	// Results is the number of examples with a result, and Failed the number
+	// of those for which the evaluator returned an error.
+	Results int
+	Failed  int
+	Summary EvaluatorRunSummary
+}
+
+func (EvaluationStarted) isEvalEvent()   {}
+func (ExampleStarted) isEvalEvent()      {}
+func (ExampleCompleted) isEvalEvent()    {}
+func (ExampleFailed) isEvalEvent()       {}
+func (EvaluationCompleted) isEvalEvent() {}
+
+// EvalObserver receives the events of an evaluation. Evaluators defined with
+// [DefineEvaluator] emit all events; batch evaluators only emit
+// [EvaluationStarted] and [EvaluationCompleted]. Observe is called
+// synchronously from the evaluation, so it should return quickly.
+type EvalObserver interface {
+	Observe(ctx context.Context, ev EvalEvent)
+}
+
+// WithEvaluateObserver sets an [EvalObserver] on [EvaluatorRequest]
+func WithEvaluateObserver(obs EvalObserver) EvaluateOption {
+	return func(req *EvaluatorRequest) error {
+		req.Observer = obs
+		return nil
+	}
+}
+
+// notifyObserver sends ev to the observer of req, if any.
+func notifyObserver(ctx context.Context, req *EvaluatorRequest, ev EvalEvent) {
+	if req.Observer != nil {
+		req.Observer.Observe(ctx, ev)
+	}
+}
+
+// summaryObserver is an [EvalObserver] that records the run summary of the
+// evaluation and forwards all events to next.
+type summaryObserver struct {
+	next    EvalObserver
+	summary EvaluatorRunSummary
+}
+
+func (o *summaryObserver) Observe(ctx context.Context, ev EvalEvent) {
+	if c, ok := ev.(EvaluationCompleted); ok {
+		o.summary = c.Summary
+	}
+	if o.next != nil {
+		o.next.Observe(ctx, ev)
+	}
+}
+
+// channelObserver is an [EvalObserver] that sends events on a channel.
+type channelObserver struct {
+	mu     sync.Mutex
+	ch     chan EvalEvent
+	depth  int  // Number of evaluations started but not completed.
+	closed bool // Whether ch is closed.
+}
+
+// NewChannelObserver returns an [EvalObserver] that delivers events on the
+// returned channel. Evaluations that run other evaluations, such as
+// [DefineCrossLingualEvaluator] or [RunDAG], may report nested
+// [EvaluationStarted] and [EvaluationCompleted] pairs; the channel is
+// closed after the [EvaluationCompleted] event of the outermost evaluation.
+// The observer must therefore be used for a single top-level evaluation;
+// events observed after the channel is closed are dropped. The channel is
+// buffered, but the evaluation blocks if the buffer is full, so the channel
+// must be drained until it is closed.
+func NewChannelObserver() (EvalObserver, <-chan EvalEvent) {
+	o := &channelObserver{ch: make(chan EvalEvent, 64)}
+	return o, o.ch
+}
+
+func (o *channelObserver) Observe(ctx context.Context, ev EvalEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return
+	}
+	switch ev.(type) {
+	case EvaluationStarted:
+		o.depth++
+	case EvaluationCompleted:
+		o.depth--
+	}
+	o.ch <- ev
+	if o.depth <= 0 {
+		if _, ok := ev.(EvaluationCompleted); ok {
+			o.closed = true
+			close(o.ch)
+		}
+	}
+}
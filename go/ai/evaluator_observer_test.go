@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestChannelObserver(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineEvaluator(r, "test", "observed", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Input == "bad" {
+			return nil, errors.New("bad input")
+		}
+		return testEvalFunc(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, events := NewChannelObserver()
+	done := make(chan []string)
+	go func() {
+		var kinds []string
+		for ev := range events {
+			switch ev := ev.(type) {
+			case EvaluationStarted:
+				kinds = append(kinds, fmt.Sprintf("started %s %d", ev.Evaluator, ev.DatasetSize))
+			case ExampleStarted:
+				kinds = append(kinds, "example "+ev.TestCaseId)
+			case ExampleCompleted:
+				kinds = append(kinds, "completed "+ev.Result.TestCaseId)
+			case ExampleFailed:
+				kinds = append(kinds, "failed "+ev.TestCaseId)
+			case EvaluationCompleted:
+				kinds = append(kinds, fmt.Sprintf("done %d/%d", ev.Failed, ev.Results))
+			}
+		}
+		done <- kinds
+	}()
+
+	ds := Dataset{{TestCaseId: "a", Input: "good"}, {TestCaseId: "b", Input: "bad"}}
+	Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds), WithEvaluateObserver(obs))
+
+	want := []string{
+		"started test/observed 2",
+		"example a",
+		"completed a",
+		"example b",
+		"failed b",
+		"done 1/2",
+	}
+	if got := <-done; !slices.Equal(got, want) {
+		t.Errorf("got events %q, want %q", got, want)
+	}
+}
+
+func TestChannelObserverNested(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner, err := DefineEvaluator(r, "test", "inner", &evalOptions, testEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The outer evaluator forwards its request, and so its observer, to the
+	// inner one.
+	outer, err := DefineBatchEvaluator(r, "test", "outer", &evalOptions, func(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
+		return inner.Evaluate(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, events := NewChannelObserver()
+	done := make(chan []string)
+	go func() {
+		var kinds []string
+		for ev := range events {
+			switch ev := ev.(type) {
+			case EvaluationStarted:
+				kinds = append(kinds, "started "+ev.Evaluator)
+			case EvaluationCompleted:
+				kinds = append(kinds, "done "+ev.Evaluator)
+			}
+		}
+		done <- kinds
+	}()
+	ds := Dataset{{TestCaseId: "a", Input: "hello"}}
+	if _, err := Evaluate(context.Background(), outer, WithEvaluateDataset(&ds), WithEvaluateObserver(obs)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"started test/outer", "started test/inner", "done test/inner", "done test/outer"}
+	if got := <-done; !slices.Equal(got, want) {
+		t.Errorf("got events %q, want %q", got, want)
+	}
+
+	// Events after the outermost evaluation completed are dropped.
+	obs.Observe(context.Background(), EvaluationCompleted{})
+}
+
+func TestBatchEvaluatorObserver(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineBatchEvaluator(r, "test", "batch", &evalOptions, testBatchEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, events := NewChannelObserver()
+	if _, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&dataset), WithEvaluateObserver(obs)); err != nil {
+		t.Fatal(err)
+	}
+	var got []EvalEvent
+	for ev := range events {
+		got = append(got, ev)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2", len(got))
+	}
+	if ev, ok := got[1].(EvaluationCompleted); !ok || ev.Results != 2 {
+		t.Errorf("got %#v, want EvaluationCompleted with 2 results", got[1])
+	}
+}
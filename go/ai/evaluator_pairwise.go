@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// PairwiseReport compares two evaluators run on the same dataset.
+type PairwiseReport struct {
+	// Evaluators holds the names of the compared evaluators, in the order
+	// they were given.
+	Evaluators []string `json:"evaluators"`
+	// Results holds one comparison per example that both evaluators scored.
+	Results []PairwiseResult `json:"results"`
+	// WinRates is the fraction of compared examples won by each evaluator,
+	// keyed by evaluator name.
+	WinRates map[string]float64 `json:"winRates"`
+	// TieRate is the fraction of compared examples that were ties.
+	TieRate float64 `json:"tieRate"`
+	// Kappa is Cohen's kappa for the agreement between the pass/fail
+	// statuses assigned by the two evaluators.
+	Kappa float64 `json:"kappa"`
+}
+
+// PairwiseResult is the comparison of two evaluators on a single example.
+// Each evaluator's score is the mean of the numeric scores in its result.
+type PairwiseResult struct {
+	TestCaseId string             `json:"testCaseId"`
+	Scores     map[string]float64 `json:"scores"`
+	Winner     string             `json:"winner,omitempty"`
+	Loser      string             `json:"loser,omitempty"`
+	Tie        bool               `json:"tie,omitempty"`
+}
+
+// RunPairwiseEvaluation runs two evaluators concurrently on ds, aligns their
+// results by TestCaseId and compares them. Examples without a TestCaseId are
+// assigned one before evaluation. Examples that either evaluator failed to
+// score numerically are left out of the comparison.
+func RunPairwiseEvaluation(ctx context.Context, ds Dataset, evals []Evaluator, opts ...EvaluateOption) (*PairwiseReport, error) {
+	if len(evals) != 2 {
+		return nil, fmt.Errorf("ai.RunPairwiseEvaluation: need exactly 2 evaluators, got %d", len(evals))
+	}
+	names := []string{evals[0].Name(), evals[1].Name()}
+	if names[0] == names[1] {
+		return nil, errors.New("ai.RunPairwiseEvaluation: evaluators must be distinct")
+	}
+
+	ds = withTestCaseIds(ds)
+	resps, err := evaluateAll(ctx, ds, evals, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("ai.RunPairwiseEvaluation: %w", err)
+	}
+
+	byId := [2]map[string]EvaluationResult{indexResults(resps[0]), indexResults(resps[1])}
+	report := &PairwiseReport{
+		Evaluators: names,
+		WinRates:   map[string]float64{names[0]: 0, names[1]: 0},
+	}
+	var statusA, statusB []ScoreStatus
+	for _, ex := range ds {
+		resA, okA := byId[0][ex.TestCaseId]
+		resB, okB := byId[1][ex.TestCaseId]
+		if !okA || !okB || resA.err != nil || resB.err != nil {
+			continue
+		}
+		statusA = append(statusA, resultStatus(resA))
+		statusB = append(statusB, resultStatus(resB))
+
+		a, errA := meanScore(resA)
+		b, errB := meanScore(resB)
+		if errA != nil || errB != nil {
+			continue
+		}
+		res := PairwiseResult{
+			TestCaseId: ex.TestCaseId,
+			Scores:     map[string]float64{names[0]: a, names[1]: b},
+		}
+		switch {
+		case a > b:
+			res.Winner, res.Loser = names[0], names[1]
+		case b > a:
+			res.Winner, res.Loser = names[1], names[0]
+		default:
+			res.Tie = true
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	if n := float64(len(report.Results)); n > 0 {
+		ties := 0
+		wins := map[string]int{}
+		for _, res := range report.Results {
+			if res.Tie {
+				ties++
+			} else {
+				wins[res.Winner]++
+			}
+		}
+		report.TieRate = float64(ties) / n
+		for _, name := range names {
+			report.WinRates[name] = float64(wins[name]) / n
+		}
+	}
+	report.Kappa = cohensKappa(statusA, statusB)
+	return report, nil
+}
+
+// evaluateAll runs each of evals concurrently on ds and returns their
+// responses in the same order. Responses in which some examples failed are
+// returned as they are; it is an error only if an evaluator returned no
+// response at all.
+func evaluateAll(ctx context.Context, ds Dataset, evals []Evaluator, opts ...EvaluateOption) ([]*EvaluatorResponse, error) {
+	var wg sync.WaitGroup
+	resps := make([]*EvaluatorResponse, len(evals))
+	errs := make([]error, len(evals))
+	for i, e := range evals {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resps[i], errs[i] = Evaluate(ctx, e, append([]EvaluateOption{WithEvaluateDataset(&ds)}, opts...)...)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if resps[i] == nil {
+			return nil, fmt.Errorf("evaluator %q: %w", evals[i].Name(), err)
+		}
+	}
+	return resps, nil
+}
+
+// withTestCaseIds returns a copy of ds in which every example has a
+// TestCaseId.
+func withTestCaseIds(ds Dataset) Dataset {
+	out := make(Dataset, len(ds))
+	copy(out, ds)
+	for i := range out {
+		if out[i].TestCaseId == "" {
+			out[i].TestCaseId = uuid.New().String()
+		}
+	}
+	return out
+}
+
+// indexResults returns the results in resp keyed by TestCaseId.
+func indexResults(resp *EvaluatorResponse) map[string]EvaluationResult {
+	m := map[string]EvaluationResult{}
+	if resp == nil {
+		return m
+	}
+	for _, res := range *resp {
+		m[res.TestCaseId] = res
+	}
+	return m
+}
+
+// meanScore returns the mean of the numeric scores in res.
+func meanScore(res EvaluationResult) (float64, error) {
+	var sum float64
+	n := 0
+	for _, s := range res.Evaluation {
+		if v, err := s.Normalize(); err == nil {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("result for test case %q has no numeric scores", res.TestCaseId)
+	}
+	return sum / float64(n), nil
+}
+
+// cohensKappa computes Cohen's kappa for two raters that labeled the same
+// items. It returns 1 when both raters always agree.
+func cohensKappa[T comparable](a, b []T) float64 {
+	n := float64(len(a))
+	if n == 0 {
+		return 0
+	}
+	agree := 0.0
+	countA, countB := map[T]float64{}, map[T]float64{}
+	for i := range a {
+		if a[i] == b[i] {
+			agree++
+		}
+		countA[a[i]]++
+		countB[b[i]]++
+	}
+	po := agree / n
+	pe := 0.0
+	for label, c := range countA {
+		pe += (c / n) * (countB[label] / n)
+	}
+	if pe == 1 {
+		return 1
+	}
+	return (po - pe) / (1 - pe)
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// defineTableEvaluator defines an evaluator that returns the score in scores
+// for each example, keyed by its Input. Scores of at least 0.5 pass, and
+// inputs without a score fail with an error.
+func defineTableEvaluator(t *testing.T, r *registry.Registry, name string, scores map[string]float64) Evaluator {
+	t.Helper()
+	e, err := DefineEvaluator(r, "test", name, &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		v, ok := scores[req.Input.Input.(string)]
+		if !ok {
+			return nil, errors.New("no score")
+		}
+		status := ScoreStatusFail
+		if v >= 0.5 {
+			status = ScoreStatusPass
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "quality", Score: v, Status: status.String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestRunPairwiseEvaluation(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelA := defineTableEvaluator(t, r, "modelA", map[string]float64{"q1": 0.9, "q2": 0.7, "q3": 0.2, "q4": 0.1})
+	modelB := defineTableEvaluator(t, r, "modelB", map[string]float64{"q1": 0.6, "q2": 0.3, "q3": 0.2, "q4": 0.4})
+
+	ds := Dataset{{Input: "q1"}, {Input: "q2"}, {Input: "q3"}, {Input: "q4"}}
+	report, err := RunPairwiseEvaluation(context.Background(), ds, []Evaluator{modelA, modelB})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(report.Results), 4; got != want {
+		t.Fatalf("got %d results, want %d", got, want)
+	}
+	if got, want := report.Results[0].Winner, "test/modelA"; got != want {
+		t.Errorf("got winner %q, want %q", got, want)
+	}
+	if !report.Results[2].Tie {
+		t.Error("expected a tie on q3")
+	}
+	if got, want := report.WinRates["test/modelA"], 0.5; got != want {
+		t.Errorf("got win rate %v, want %v", got, want)
+	}
+	if got, want := report.WinRates["test/modelB"], 0.25; got != want {
+		t.Errorf("got win rate %v, want %v", got, want)
+	}
+	if got, want := report.TieRate, 0.25; got != want {
+		t.Errorf("got tie rate %v, want %v", got, want)
+	}
+	// Statuses are A: pass, pass, fail, fail and B: pass, fail, fail, fail.
+	if got, want := report.Kappa, 0.5; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got kappa %v, want %v", got, want)
+	}
+
+	// Examples that either evaluator failed on are left out.
+	modelC := defineTableEvaluator(t, r, "modelC", map[string]float64{"q1": 0.1, "q2": 0.1, "q3": 0.1})
+	report, err = RunPairwiseEvaluation(context.Background(), ds, []Evaluator{modelA, modelC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(report.Results), 3; got != want {
+		t.Errorf("with a failed example: got %d results, want %d", got, want)
+	}
+	if got, want := report.WinRates["test/modelA"], 1.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("with a failed example: got win rate %v, want %v", got, want)
+	}
+
+	if _, err := RunPairwiseEvaluation(context.Background(), ds, []Evaluator{modelA}); err == nil {
+		t.Error("got nil, want error for a single evaluator")
+	}
+}
+
+func TestCohensKappa(t *testing.T) {
+	if got := cohensKappa([]string{"a", "b"}, []string{"a", "b"}); got != 1 {
+		t.Errorf("perfect agreement: got %v, want 1", got)
+	}
+	if got := cohensKappa([]string{"a", "b"}, []string{"b", "a"}); got != -1 {
+		t.Errorf("perfect disagreement: got %v, want -1", got)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import "fmt"
+
+// PartialCreditThresholds make evaluators defined with [DefineEvaluator] set
+// the [EvaluationResult.PartialCreditStatus] of a result from its
+// [EvaluationResult.PartialCredit]: pass if the credit is at least Pass,
+// fail if it is below Fail, and unknown in between. The statuses of the
+// individual scores are left as the evaluator set them. Set the thresholds
+// with [EvaluatorOptions.SetPartialCreditThresholds].
+type PartialCreditThresholds struct {
+	Pass float64 `json:"pass"`
+	Fail float64 `json:"fail"`
+}
+
+// SetPartialCreditThresholds makes the evaluator pass results whose partial
+// credit is at least pass and fail those whose partial credit is below fail.
+// Both thresholds must be in [0, 1], with fail no greater than pass.
+func (o *EvaluatorOptions) SetPartialCreditThresholds(pass, fail float64) {
+	o.PartialCreditThresholds = &PartialCreditThresholds{Pass: pass, Fail: fail}
+}
+
+func (t *PartialCreditThresholds) validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.Pass < 0 || t.Pass > 1 || t.Fail < 0 || t.Fail > 1 {
+		return fmt.Errorf("partial credit thresholds %v and %v must be in [0, 1]", t.Pass, t.Fail)
+	}
+	if t.Fail > t.Pass {
+		return fmt.Errorf("partial credit fail threshold %v is greater than pass threshold %v", t.Fail, t.Pass)
+	}
+	return nil
+}
+
+// status returns the status of a result with the given partial credit.
+func (t *PartialCreditThresholds) status(credit float64) ScoreStatus {
+	switch {
+	case credit >= t.Pass:
+		return ScoreStatusPass
+	case credit < t.Fail:
+		return ScoreStatusFail
+	}
+	return ScoreStatusUnknown
+}
+
+// partialCredit returns the mean of the numeric scores of res, clamped to
+// [0, 1], and whether res has any.
+func partialCredit(res EvaluationResult) (float64, bool) {
+	mean, err := meanScore(res)
+	if err != nil {
+		return 0, false
+	}
+	return min(max(mean, 0), 1), true
+}
+
+// setPartialCredit sets the PartialCredit of res and, if t is not nil, its
+// PartialCreditStatus.
+func setPartialCredit(res *EvaluationResult, t *PartialCreditThresholds) {
+	credit, ok := partialCredit(*res)
+	if !ok {
+		return
+	}
+	res.PartialCredit = credit
+	if t != nil {
+		res.PartialCreditStatus = t.status(credit).String()
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindPII(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []map[string]string
+	}{
+		{
+			name: "no pii",
+			text: "The meeting is at 10:30 in room 12.",
+			want: []map[string]string{},
+		},
+		{
+			name: "email",
+			text: "Contact jane.doe@example.com for details.",
+			want: []map[string]string{{"pattern": "email", "match": "****.***@******e.com"}},
+		},
+		{
+			name: "ssn",
+			text: "SSN: 123-45-6789",
+			want: []map[string]string{{"pattern": "ssn", "match": "***-**-6789"}},
+		},
+		{
+			name: "partial ssn is ignored",
+			text: "Order 123-45-67890 shipped",
+			want: []map[string]string{},
+		},
+		{
+			name: "phone",
+			text: "Call (555) 123-4567 now",
+			want: []map[string]string{{"pattern": "phone", "match": "(***) ***-4567"}},
+		},
+		{
+			name: "overlapping patterns report once",
+			text: "Card 4111 1111 1111 1111",
+			want: []map[string]string{{"pattern": "credit_card", "match": "**** **** **** 1111"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := findPII(test.text, DefaultPIIPatterns)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPIIDetectionEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	employeeID := PIIPattern{Name: "employee_id", Pattern: regexp.MustCompile(`\bEMP-\d{5}\b`)}
+	evalAction, err := DefinePIIDetectionEvaluator(r, "test", []PIIPattern{employeeID, EmailPattern}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{Input: "q", Output: "Nothing to see here"},
+		{Input: "q", Output: "Ask EMP-12345 or bob@corp.io"},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := (*resp)[0].Evaluation[0].Status, "pass"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := (*resp)[1].Evaluation[0].Status, "fail"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := len((*resp)[1].Evaluation[0].Details["matches"].([]map[string]string)), 2; got != want {
+		t.Errorf("got %d matches, want %d", got, want)
+	}
+}
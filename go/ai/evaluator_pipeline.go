@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/firebase/genkit/go/core/tracing"
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// PipelineStage is a preprocessing step of an [EvaluationPipeline]. It
+// receives the dataset produced by the previous stage and returns the dataset
+// for the next one, for example after augmenting examples with model outputs.
+type PipelineStage func(ctx context.Context, ds Dataset) (Dataset, error)
+
+// EvaluationPipeline chains loading a dataset, transforming it through a
+// sequence of stages, evaluating it and exporting the results. Each step runs
+// in its own trace span, and the first error stops the pipeline.
+type EvaluationPipeline struct {
+	// Load loads the initial dataset. It is required.
+	Load func(ctx context.Context) (Dataset, error)
+	// Evaluator is run on the dataset produced by the last stage. It is
+	// required.
+	Evaluator Evaluator
+	// Options are passed to the evaluator in addition to the dataset.
+	Options []EvaluateOption
+	// Export, if set, receives the evaluation results.
+	Export func(ctx context.Context, resp *EvaluatorResponse) error
+
+	r      *registry.Registry
+	name   string
+	stages []namedStage
+}
+
+type namedStage struct {
+	name string
+	fn   PipelineStage
+}
+
+// NewEvaluationPipeline returns an empty [EvaluationPipeline] with the given
+// name. The name is used for the pipeline's trace span.
+func NewEvaluationPipeline(r *registry.Registry, name string) *EvaluationPipeline {
+	return &EvaluationPipeline{r: r, name: name}
+}
+
+// AddStage appends a preprocessing stage to the pipeline. Stages run in the
+// order they are added.
+func (p *EvaluationPipeline) AddStage(name string, fn PipelineStage) {
+	p.stages = append(p.stages, namedStage{name: name, fn: fn})
+}
+
+// Run executes the pipeline and returns the evaluation results. If the
+// evaluator fails on some examples, the results are still exported, and Run
+// returns them together with the error of [Evaluate].
+func (p *EvaluationPipeline) Run(ctx context.Context) (*EvaluatorResponse, error) {
+	if p.Load == nil {
+		return nil, errors.New("ai.EvaluationPipeline.Run: Load is required")
+	}
+	if p.Evaluator == nil {
+		return nil, errors.New("ai.EvaluationPipeline.Run: Evaluator is required")
+	}
+	tstate := p.r.TracingState()
+
+	// evalErr is the error of an evaluation that failed on some examples.
+	var evalErr error
+	resp, err := tracing.RunInNewSpan(ctx, tstate, p.name, "evaluationPipeline", false, p.name,
+		func(ctx context.Context, _ string) (*EvaluatorResponse, error) {
+			ds, err := tracing.RunInNewSpan(ctx, tstate, "load", "pipelineStage", false, struct{}{},
+				func(ctx context.Context, _ struct{}) (Dataset, error) {
+					return p.Load(ctx)
+				})
+			if err != nil {
+				return nil, fmt.Errorf("ai.EvaluationPipeline.Run: load: %w", err)
+			}
+
+			for _, stage := range p.stages {
+				ds, err = tracing.RunInNewSpan(ctx, tstate, stage.name, "pipelineStage", false, ds, stage.fn)
+				if err != nil {
+					return nil, fmt.Errorf("ai.EvaluationPipeline.Run: stage %q: %w", stage.name, err)
+				}
+			}
+
+			opts := append([]EvaluateOption{WithEvaluateDataset(&ds)}, p.Options...)
+			resp, err := Evaluate(ctx, p.Evaluator, opts...)
+			if resp == nil {
+				return nil, fmt.Errorf("ai.EvaluationPipeline.Run: evaluate: %w", err)
+			}
+			evalErr = err
+
+			if p.Export != nil {
+				_, err = tracing.RunInNewSpan(ctx, tstate, "export", "pipelineStage", false, resp,
+					func(ctx context.Context, resp *EvaluatorResponse) (struct{}, error) {
+						return struct{}{}, p.Export(ctx, resp)
+					})
+				if err != nil {
+					return nil, fmt.Errorf("ai.EvaluationPipeline.Run: export: %w", err)
+				}
+			}
+			return resp, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if evalErr != nil {
+		return resp, fmt.Errorf("ai.EvaluationPipeline.Run: evaluate: %w", evalErr)
+	}
+	return resp, nil
+}
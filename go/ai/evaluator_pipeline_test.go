@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEvaluationPipeline(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	r.RegisterSpanProcessor(recorder)
+
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, testEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exported *EvaluatorResponse
+	p := NewEvaluationPipeline(r, "myPipeline")
+	p.Load = func(ctx context.Context) (Dataset, error) {
+		return Dataset{{TestCaseId: "a", Input: "hello"}, {TestCaseId: "b", Input: "bye"}}, nil
+	}
+	p.AddStage("augment", func(ctx context.Context, ds Dataset) (Dataset, error) {
+		for i := range ds {
+			ds[i].Output = strings.ToUpper(ds[i].Input.(string))
+		}
+		return ds, nil
+	})
+	p.AddStage("filter", func(ctx context.Context, ds Dataset) (Dataset, error) {
+		return ds[:1], nil
+	})
+	p.Evaluator = evalAction
+	p.Options = []EvaluateOption{WithEvaluateOptions("test-options")}
+	p.Export = func(ctx context.Context, resp *EvaluatorResponse) error {
+		exported = resp
+		return nil
+	}
+
+	resp, err := p.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(*resp), 1; got != want {
+		t.Fatalf("got %d results, want %d", got, want)
+	}
+	if got, want := (*resp)[0].Evaluation[0].Details["options"], "test-options"; got != want {
+		t.Errorf("got options %v, want %v", got, want)
+	}
+	if exported != resp {
+		t.Error("export stage did not receive the results")
+	}
+
+	names := map[string]bool{}
+	for _, span := range recorder.Ended() {
+		names[span.Name()] = true
+	}
+	for _, want := range []string{"myPipeline", "load", "augment", "filter", "export"} {
+		if !names[want] {
+			t.Errorf("missing span %q", want)
+		}
+	}
+}
+
+func TestEvaluationPipelineStageError(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, testEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errBoom := errors.New("boom")
+	evaluated := false
+	p := NewEvaluationPipeline(r, "failing")
+	p.Load = func(ctx context.Context) (Dataset, error) { return dataset, nil }
+	p.AddStage("explode", func(ctx context.Context, ds Dataset) (Dataset, error) { return nil, errBoom })
+	p.AddStage("never", func(ctx context.Context, ds Dataset) (Dataset, error) {
+		evaluated = true
+		return ds, nil
+	})
+	p.Evaluator = evalAction
+
+	if _, err := p.Run(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("got %v, want %v", err, errBoom)
+	}
+	if evaluated {
+		t.Error("stage after the failing stage was run")
+	}
+}
+
+func TestEvaluationPipelinePartialFailure(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Input == "bad" {
+			return nil, errors.New("bad input")
+		}
+		return testEvalFunc(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exported *EvaluatorResponse
+	p := NewEvaluationPipeline(r, "partial")
+	p.Load = func(ctx context.Context) (Dataset, error) {
+		return Dataset{{TestCaseId: "a", Input: "good"}, {TestCaseId: "b", Input: "bad"}}, nil
+	}
+	p.Evaluator = evalAction
+	p.Export = func(ctx context.Context, resp *EvaluatorResponse) error {
+		exported = resp
+		return nil
+	}
+
+	resp, err := p.Run(context.Background())
+	if !errors.As(err, new(*MultiEvaluatorError)) {
+		t.Errorf("got error %v, want MultiEvaluatorError", err)
+	}
+	if resp == nil || len(*resp) != 2 {
+		t.Fatalf("got %v, want both results", resp)
+	}
+	if exported != resp {
+		t.Error("export stage did not receive the partial results")
+	}
+}
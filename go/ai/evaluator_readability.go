@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// ReadabilityFormula is an enum used to select the readability formula
+// computed by [DefineReadabilityEvaluator]. All formulas produce an
+// approximate US school grade level.
+type ReadabilityFormula int
+
+const (
+	ReadabilityFleschKincaid ReadabilityFormula = iota
+	ReadabilityGunningFog
+	ReadabilitySMOG
+)
+
+var readabilityFormulaName = map[ReadabilityFormula]string{
+	ReadabilityFleschKincaid: "flesch_kincaid",
+	ReadabilityGunningFog:    "gunning_fog",
+	ReadabilitySMOG:          "smog",
+}
+
+func (f ReadabilityFormula) String() string {
+	return readabilityFormulaName[f]
+}
+
+// DefineReadabilityEvaluator registers an evaluator that computes the
+// readability of each Output with the given formula. An example passes if the
+// computed grade level is within targetRange (inclusive). The evaluator is
+// named after the formula, for example "readability_smog".
+//
+// Syllables are counted with a vowel-group heuristic that approximates the
+// CMU pronouncing dictionary, so scores may differ slightly from tools that
+// use a full dictionary.
+func DefineReadabilityEvaluator(r *registry.Registry, provider string, formula ReadabilityFormula, targetRange [2]float64) (Evaluator, error) {
+	if _, ok := readabilityFormulaName[formula]; !ok {
+		return nil, fmt.Errorf("ai.DefineReadabilityEvaluator: unknown formula %d", formula)
+	}
+	if targetRange[0] > targetRange[1] {
+		return nil, fmt.Errorf("ai.DefineReadabilityEvaluator: invalid target range %v", targetRange)
+	}
+	opts := &EvaluatorOptions{
+		DisplayName: "Readability",
+		Definition:  fmt.Sprintf("Tests that the %s grade level of the output is between %g and %g", formula, targetRange[0], targetRange[1]),
+	}
+
+	return DefineEvaluator(r, provider, "readability_"+formula.String(), opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		text, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+
+		stats := computeTextStats(text)
+		if stats.words == 0 {
+			return nil, errors.New("output contains no words")
+		}
+		grade := stats.grade(formula)
+		status := ScoreStatusFail
+		if grade >= targetRange[0] && grade <= targetRange[1] {
+			status = ScoreStatusPass
+		}
+		score := Score{
+			Id:     "readability",
+			Score:  grade,
+			Status: status.String(),
+			Details: map[string]any{
+				"formula":   formula.String(),
+				"words":     stats.words,
+				"sentences": stats.sentences,
+				"syllables": stats.syllables,
+			},
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// textStats holds the counts needed by the readability formulas.
+type textStats struct {
+	words         int
+	sentences     int
+	syllables     int
+	polysyllables int // words with three or more syllables
+}
+
+func computeTextStats(text string) textStats {
+	stats := textStats{sentences: max(countSentences(text), 1)}
+	for _, w := range strings.Fields(text) {
+		w = strings.TrimFunc(w, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if w == "" {
+			continue
+		}
+		n := countSyllables(w)
+		stats.words++
+		stats.syllables += n
+		if n >= 3 {
+			stats.polysyllables++
+		}
+	}
+	return stats
+}
+
+// grade returns the grade level for the given formula. The caller must ensure
+// that s.words is non-zero.
+func (s textStats) grade(formula ReadabilityFormula) float64 {
+	words, sentences := float64(s.words), float64(s.sentences)
+	switch formula {
+	case ReadabilityGunningFog:
+		return 0.4 * (words/sentences + 100*float64(s.polysyllables)/words)
+	case ReadabilitySMOG:
+		return 1.0430*math.Sqrt(float64(s.polysyllables)*30/sentences) + 3.1291
+	default:
+		return 0.39*words/sentences + 11.8*float64(s.syllables)/words - 15.59
+	}
+}
+
+// syllableExceptions holds common words the vowel-group heuristic gets wrong.
+var syllableExceptions = map[string]int{
+	"business": 2, "every": 2, "different": 3, "evening": 2, "interesting": 3,
+	"people": 2, "being": 2, "area": 3, "idea": 3, "create": 2, "poem": 2,
+	"science": 2, "quiet": 2, "real": 1, "really": 2, "the": 1, "said": 1,
+}
+
+// countSyllables estimates the number of syllables in an English word by
+// counting groups of vowels and adjusting for silent endings.
+func countSyllables(word string) int {
+	word = strings.ToLower(word)
+	if n, ok := syllableExceptions[word]; ok {
+		return n
+	}
+	isVowel := func(r rune) bool { return strings.ContainsRune("aeiouy", r) }
+
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		v := isVowel(r)
+		if v && !prevVowel {
+			count++
+		}
+		prevVowel = v
+	}
+	// A trailing "e" is usually silent ("make"), except in "-le" endings
+	// after a consonant ("table").
+	if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && count > 1 {
+		count--
+	}
+	// "-ed" is silent unless it follows "t" or "d" ("jumped" vs "wanted").
+	if strings.HasSuffix(word, "ed") && len(word) > 3 && !strings.ContainsRune("td", rune(word[len(word)-3])) && count > 1 {
+		count--
+	}
+	return max(count, 1)
+}
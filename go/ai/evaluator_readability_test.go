@@ -0,0 +1,154 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestCountSyllables(t *testing.T) {
+	tests := map[string]int{
+		"cat":         1,
+		"make":        1,
+		"table":       2,
+		"jumped":      1,
+		"wanted":      2,
+		"lazy":        2,
+		"over":        2,
+		"beautiful":   3,
+		"people":      2,
+		"information": 4,
+	}
+	for word, want := range tests {
+		if got := countSyllables(word); got != want {
+			t.Errorf("countSyllables(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestReadabilityGrade(t *testing.T) {
+	// The Wikipedia article on the Flesch-Kincaid tests gives this sentence
+	// a Flesch-Kincaid grade of 11.3, counting 13 words and 24 syllables.
+	const platypus = "The Australian platypus is seemingly a hybrid of a mammal and reptilian creature."
+	stats := computeTextStats(platypus)
+	if want := (textStats{words: 13, sentences: 1, syllables: 24, polysyllables: 4}); stats != want {
+		t.Fatalf("got stats %+v, want %+v", stats, want)
+	}
+	if got := stats.grade(ReadabilityFleschKincaid); math.Abs(got-11.3) > 0.05 {
+		t.Errorf("Flesch-Kincaid grade = %.4f, want 11.3", got)
+	}
+}
+
+// readabilityPassage returns a passage of the given number of sentences of
+// one-syllable words, with polysyllables occurrences of "beautiful" spread
+// over them.
+func readabilityPassage(sentences, polysyllables int) string {
+	var b strings.Builder
+	for i := range sentences {
+		b.WriteString("The cat sat on the mat")
+		for range polysyllables / sentences {
+			b.WriteString(" beautiful")
+		}
+		if i < polysyllables%sentences {
+			b.WriteString(" beautiful")
+		}
+		b.WriteString(". ")
+	}
+	return b.String()
+}
+
+func TestReadabilitySMOG(t *testing.T) {
+	// McLaughlin's SMOG conversion table gives the grade of a 30-sentence
+	// sample from its number of polysyllables. The table rounds the
+	// simplified formula 3 + sqrt(polysyllables), which the exact formula
+	// exceeds by less than 0.6 for these counts.
+	tests := []struct {
+		polysyllables int
+		want          float64
+	}{
+		{4, 5},   // 3-6 polysyllables
+		{25, 8},  // 21-30
+		{64, 11}, // 57-72
+		{100, 13},
+	}
+	for _, test := range tests {
+		stats := computeTextStats(readabilityPassage(30, test.polysyllables))
+		if stats.sentences != 30 || stats.polysyllables != test.polysyllables {
+			t.Fatalf("%d polysyllables: got stats %+v", test.polysyllables, stats)
+		}
+		if got := stats.grade(ReadabilitySMOG); got < test.want || got >= test.want+0.6 {
+			t.Errorf("%d polysyllables: SMOG grade = %.4f, want %v from the conversion table", test.polysyllables, got, test.want)
+		}
+	}
+}
+
+func TestReadabilityGunningFog(t *testing.T) {
+	// Gunning's procedure on a 100-word sample: 10 sentences of 6 one-
+	// syllable words, and 4 complex words in each of them. The average
+	// sentence length is 10 and 40% of the words are complex, so the fog
+	// index is 0.4 * (10 + 40) = 20.
+	stats := computeTextStats(readabilityPassage(10, 40))
+	if want := (textStats{words: 100, sentences: 10, syllables: 180, polysyllables: 40}); stats != want {
+		t.Fatalf("got stats %+v, want %+v", stats, want)
+	}
+	if got := stats.grade(ReadabilityGunningFog); math.Abs(got-20) > 1e-9 {
+		t.Errorf("Gunning fog index = %.4f, want 20", got)
+	}
+	// Without complex words, the index is 0.4 times the sentence length.
+	if got := computeTextStats(readabilityPassage(10, 0)).grade(ReadabilityGunningFog); math.Abs(got-2.4) > 1e-9 {
+		t.Errorf("Gunning fog index without complex words = %.4f, want 2.4", got)
+	}
+}
+
+func TestReadabilityEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineReadabilityEvaluator(r, "test", ReadabilityFleschKincaid, [2]float64{0, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{Input: "q", Output: "The quick brown fox jumps over the lazy dog."},
+		{Input: "q", Output: "Notwithstanding considerable organizational complexity, interdisciplinary collaboration substantially accelerated institutional transformation."},
+		{Input: "q", Output: "..."},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if !errors.As(err, new(EvaluatorError)) {
+		t.Fatalf("got error %v, want EvaluatorError for the output without words", err)
+	}
+
+	for i, want := range []string{"pass", "fail", "fail"} {
+		if got := (*resp)[i].Evaluation[0].Status; got != want {
+			t.Errorf("example %d: got status %v, want %v", i, got, want)
+		}
+	}
+	if got := (*resp)[2].Evaluation[0].Error; got == "" {
+		t.Errorf("got %q, want error for output without words", got)
+	}
+
+	if _, err := DefineReadabilityEvaluator(r, "test", ReadabilitySMOG, [2]float64{10, 5}); err == nil {
+		t.Error("got nil, want error for invalid range")
+	}
+}
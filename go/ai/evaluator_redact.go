@@ -0,0 +1,163 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"slices"
+	"strings"
+)
+
+// RedactEvaluatorResponse returns a deep copy of resp in which the values
+// at the given field paths are replaced by [RedactedValue], so that results
+// can be shared without sensitive data. Keys are kept, so the redacted
+// response has the same shape as the original. resp is not modified.
+//
+// A path is a dot-separated list of JSON field names. It is resolved
+// against each [EvaluationResult], such as "traceId" or
+// "humanAnnotation.annotator", and otherwise against each of its scores,
+// such as "error" or "details.pii". Paths into [Score.Details] may descend
+// into nested objects, as in "details.user.email", and apply to every
+// element of the lists they pass through. Paths that match nothing are
+// ignored.
+//
+// Details are copied in their JSON form, so a value that is not a JSON
+// object, list or scalar is replaced by its JSON decoding, and a value that
+// cannot be encoded as JSON is redacted.
+func RedactEvaluatorResponse(resp *EvaluatorResponse, fields []string) *EvaluatorResponse {
+	out := EvaluatorResponse{}
+	if resp == nil {
+		return &out
+	}
+	paths := make([][]string, len(fields))
+	for i, f := range fields {
+		paths[i] = strings.Split(f, ".")
+	}
+	for _, res := range *resp {
+		res.Evaluation = slices.Clone(res.Evaluation)
+		for i := range res.Evaluation {
+			copyDetails(&res.Evaluation[i])
+		}
+		res.HumanAnnotation = slices.Clone(res.HumanAnnotation)
+		for i := range res.HumanAnnotation {
+			copyDetails(&res.HumanAnnotation[i].Score)
+		}
+		for _, p := range paths {
+			redactResult(&res, p)
+		}
+		out = append(out, res)
+	}
+	return &out
+}
+
+// copyDetails replaces the details of s by a deep copy in JSON form.
+// Values that cannot be encoded are redacted.
+func copyDetails(s *Score) {
+	if s.Details == nil {
+		return
+	}
+	details := make(map[string]any, len(s.Details))
+	for k, v := range s.Details {
+		jv, err := jsonValue(v)
+		if err != nil {
+			jv = RedactedValue
+		}
+		details[k] = jv
+	}
+	s.Details = details
+}
+
+// redactResult redacts the value at path in res, or in each of its scores
+// if path does not name a field of res.
+func redactResult(res *EvaluationResult, path []string) {
+	rest := path[1:]
+	switch path[0] {
+	case "testCaseId":
+		res.TestCaseId = RedactedValue
+	case "traceId":
+		res.TraceID = RedactedValue
+	case "spanId":
+		res.SpanID = RedactedValue
+	case "evaluation":
+		if len(rest) > 0 {
+			for i := range res.Evaluation {
+				redactScore(&res.Evaluation[i], rest)
+			}
+		}
+	case "humanAnnotation":
+		if len(rest) == 0 {
+			return
+		}
+		for i := range res.HumanAnnotation {
+			switch rest[0] {
+			case "annotator":
+				res.HumanAnnotation[i].Annotator = RedactedValue
+			case "score":
+				if len(rest) > 1 {
+					redactScore(&res.HumanAnnotation[i].Score, rest[1:])
+				}
+			}
+		}
+	default:
+		for i := range res.Evaluation {
+			redactScore(&res.Evaluation[i], path)
+		}
+	}
+}
+
+// redactScore redacts the value at path in s.
+func redactScore(s *Score, path []string) {
+	switch path[0] {
+	case "id":
+		s.Id = RedactedValue
+	case "score":
+		s.Score = RedactedValue
+	case "status":
+		s.Status = RedactedValue
+	case "error":
+		if s.Error != "" {
+			s.Error = RedactedValue
+		}
+	case "details":
+		for k, v := range s.Details {
+			if len(path) == 1 {
+				s.Details[k] = RedactedValue
+			} else if k == path[1] {
+				s.Details[k] = redactJSON(v, path[2:])
+			}
+		}
+	}
+}
+
+// redactJSON returns v, a decoded JSON value, with the value at path
+// replaced by [RedactedValue]. Lists are traversed element by element. v is
+// modified in place.
+func redactJSON(v any, path []string) any {
+	if len(path) == 0 {
+		return RedactedValue
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		if e, ok := v[path[0]]; ok {
+			v[path[0]] = redactJSON(e, path[1:])
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = redactJSON(e, path)
+		}
+	}
+	return v
+}
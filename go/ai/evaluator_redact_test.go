@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRedactEvaluatorResponse(t *testing.T) {
+	resp := func() *EvaluatorResponse {
+		return &EvaluatorResponse{{
+			TestCaseId: "a",
+			TraceID:    "trace",
+			Evaluation: []Score{{
+				Id:     "s",
+				Score:  0.5,
+				Status: "pass",
+				Details: map[string]any{
+					"reasoning": "ok",
+					"pii":       []string{"555-0100"},
+					"user":      map[string]any{"email": "a@example.com", "name": "Ann"},
+					"records":   []any{map[string]any{"email": "b@example.com"}, map[string]any{"id": 1}},
+					"noise":     math.NaN(),
+				},
+			}},
+			HumanAnnotation: []HumanScore{{Annotator: "rev@example.com", Score: Score{Id: "s", Details: map[string]any{"note": "x"}}}},
+		}}
+	}
+	orig := resp()
+	got := RedactEvaluatorResponse(orig, []string{
+		"traceId",
+		"details.pii",
+		"details.user.email",
+		"evaluation.details.records.email",
+		"humanAnnotation.annotator",
+		"humanAnnotation.score.details",
+		"details.missing",
+		"unknown.path",
+	})
+
+	want := &EvaluatorResponse{{
+		TestCaseId: "a",
+		TraceID:    RedactedValue,
+		Evaluation: []Score{{
+			Id:     "s",
+			Score:  0.5,
+			Status: "pass",
+			Details: map[string]any{
+				"reasoning": "ok",
+				"pii":       RedactedValue,
+				"user":      map[string]any{"email": RedactedValue, "name": "Ann"},
+				"records":   []any{map[string]any{"email": RedactedValue}, map[string]any{"id": 1.0}},
+				"noise":     RedactedValue,
+			},
+		}},
+		HumanAnnotation: []HumanScore{{Annotator: RedactedValue, Score: Score{Id: "s", Details: map[string]any{"note": RedactedValue}}}},
+	}}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(EvaluationResult{})); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// The original is unchanged, including nested values.
+	(*got)[0].Evaluation[0].Details["reasoning"] = "changed"
+	if diff := cmp.Diff(resp(), orig, cmp.AllowUnexported(EvaluationResult{}), cmp.Comparer(func(a, b float64) bool {
+		return a == b || math.IsNaN(a) && math.IsNaN(b)
+	})); diff != "" {
+		t.Errorf("original was modified (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// z95 is the two-sided 97.5th percentile of the standard normal
+// distribution, used for 95% confidence intervals.
+const z95 = 1.959964
+
+// RunWithRepetitions evaluates every example of ds n times with eval and
+// merges the results, which is useful with stochastic evaluators such as LLM
+// judges. Examples without a TestCaseId are assigned one first.
+//
+// Each numeric score in the merged response is the mean over the
+// repetitions, its standard deviation is reported in the "stdDev" key of
+// [Score.Details], and ConfidenceInterval holds the 95% confidence interval of
+// the mean under a normal approximation. The status of a merged score is the
+// most common status across repetitions. Scores that are not numeric are
+// taken from the first repetition.
+//
+// Repetitions in which eval failed on an example are left out of that
+// example's merged result; if it failed in every repetition, the failed
+// result of the first one is returned. The merged response is then returned
+// together with an error joining those of the repetitions.
+func RunWithRepetitions(ctx context.Context, eval Evaluator, ds Dataset, n int, opts ...EvaluateOption) (*EvaluatorResponse, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("ai.RunWithRepetitions: repetitions must be at least 1, got %d", n)
+	}
+	ds = withTestCaseIds(ds)
+	runs := make([]map[string]EvaluationResult, n)
+	var errs []error
+	for i := range n {
+		resp, err := Evaluate(ctx, eval, append([]EvaluateOption{WithEvaluateDataset(&ds)}, opts...)...)
+		if resp == nil {
+			return nil, fmt.Errorf("ai.RunWithRepetitions: repetition %d: %w", i+1, err)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ai.RunWithRepetitions: repetition %d: %w", i+1, err))
+		}
+		runs[i] = indexResults(resp)
+	}
+
+	var merged EvaluatorResponse
+	for _, ex := range ds {
+		var results, failed []EvaluationResult
+		for _, run := range runs {
+			if res, ok := run[ex.TestCaseId]; ok {
+				if res.err != nil {
+					failed = append(failed, res)
+				} else {
+					results = append(results, res)
+				}
+			}
+		}
+		switch {
+		case len(results) > 0:
+			merged = append(merged, mergeRepetitions(results))
+		case len(failed) > 0:
+			merged = append(merged, failed[0])
+		}
+	}
+	return &merged, errors.Join(errs...)
+}
+
+// mergeRepetitions merges the results of repeated evaluations of the same
+// example. Scores are matched by Id.
+func mergeRepetitions(results []EvaluationResult) EvaluationResult {
+	out := results[0]
+	out.Evaluation = make([]Score, len(results[0].Evaluation))
+	for i, first := range results[0].Evaluation {
+		var values []float64
+		statuses := map[string]int{}
+		for _, res := range results {
+			for _, s := range res.Evaluation {
+				if s.Id != first.Id {
+					continue
+				}
+				statuses[s.Status]++
+				if v, err := s.Normalize(); err == nil {
+					values = append(values, v)
+				}
+				break
+			}
+		}
+
+		merged := first
+		merged.Status = mostCommon(statuses, first.Status)
+		if len(values) == len(results) {
+			mean, sd := meanStdDev(values)
+			half := z95 * sd / math.Sqrt(float64(len(values)))
+			merged.Score = mean
+			merged.ConfidenceInterval = &[2]float64{mean - half, mean + half}
+			merged.Details = map[string]any{}
+			for k, v := range first.Details {
+				merged.Details[k] = v
+			}
+			merged.Details["stdDev"] = sd
+			merged.Details["repetitions"] = len(values)
+		}
+		out.Evaluation[i] = merged
+	}
+	return out
+}
+
+// meanStdDev returns the mean and sample standard deviation of values.
+func meanStdDev(values []float64) (mean, sd float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var ss float64
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(values)-1))
+}
+
+// mostCommon returns the key with the highest count, preferring def on ties.
+func mostCommon(counts map[string]int, def string) string {
+	best := def
+	for _, k := range slices.Sorted(maps.Keys(counts)) {
+		if counts[k] > counts[best] {
+			best = k
+		}
+	}
+	return best
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestRunWithRepetitions(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The evaluator adds a fixed sequence of noise to a base score of 0.5.
+	noise := []float64{-0.1, 0.1, 0, 0}
+	var mu sync.Mutex
+	calls := map[string]int{}
+	evalAction, err := DefineEvaluator(r, "test", "noisy", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		mu.Lock()
+		i := calls[req.Input.TestCaseId]
+		calls[req.Input.TestCaseId]++
+		mu.Unlock()
+		v := 0.5 + noise[i]
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "quality", Score: v, Status: passStatus(v >= 0.5).String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := RunWithRepetitions(context.Background(), evalAction, Dataset{{Input: "a"}, {Input: "b"}}, len(noise))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(*resp), 2; got != want {
+		t.Fatalf("got %d results, want %d", got, want)
+	}
+
+	score := (*resp)[0].Evaluation[0]
+	wantSD := math.Sqrt(0.02 / 3)
+	wantHalf := z95 * wantSD / 2
+	if got := score.Score.(float64); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("got mean %v, want 0.5", got)
+	}
+	if got := score.Details["stdDev"].(float64); math.Abs(got-wantSD) > 1e-9 {
+		t.Errorf("got std dev %v, want %v", got, wantSD)
+	}
+	if ci := score.ConfidenceInterval; ci == nil || math.Abs(ci[0]-(0.5-wantHalf)) > 1e-9 || math.Abs(ci[1]-(0.5+wantHalf)) > 1e-9 {
+		t.Errorf("got confidence interval %v, want [%v, %v]", ci, 0.5-wantHalf, 0.5+wantHalf)
+	}
+	if got, want := score.Status, "pass"; got != want {
+		t.Errorf("got status %v, want %v", got, want)
+	}
+
+	if _, err := RunWithRepetitions(context.Background(), evalAction, Dataset{{Input: "a"}}, 0); err == nil {
+		t.Error("got nil, want error for zero repetitions")
+	}
+}
+
+func TestRunWithRepetitionsPartialFailure(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Example "flaky" fails in the second repetition and "broken" in all.
+	var mu sync.Mutex
+	calls := map[string]int{}
+	evalAction, err := DefineEvaluator(r, "test", "flaky", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		id := req.Input.TestCaseId
+		mu.Lock()
+		calls[id]++
+		call := calls[id]
+		mu.Unlock()
+		if id == "broken" || (id == "flaky" && call == 2) {
+			return nil, errors.New("judge unavailable")
+		}
+		return &EvaluatorCallbackResponse{TestCaseId: id, Evaluation: []Score{{Id: "s", Score: 0.5, Status: "pass"}}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{TestCaseId: "steady", Input: "x"}, {TestCaseId: "flaky", Input: "x"}, {TestCaseId: "broken", Input: "x"}}
+	resp, err := RunWithRepetitions(context.Background(), evalAction, ds, 3)
+	if !errors.As(err, new(*MultiEvaluatorError)) {
+		t.Errorf("got error %v, want MultiEvaluatorError", err)
+	}
+	if resp == nil || len(*resp) != 3 {
+		t.Fatalf("got %v, want 3 results", resp)
+	}
+	results := indexResults(resp)
+	for id, want := range map[string]int{"steady": 3, "flaky": 2} {
+		if got := results[id].Evaluation[0].Details["repetitions"]; got != want {
+			t.Errorf("%s: got %v repetitions, want %d", id, got, want)
+		}
+	}
+	if s := results["broken"].Evaluation[0]; s.Status != "fail" || s.Error == "" {
+		t.Errorf("broken: got %+v, want failed score", s)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// EvaluatorRubric is a structured grading rubric for an LLM judge.
+type EvaluatorRubric struct {
+	Criteria []RubricCriterion `json:"criteria"`
+}
+
+// RubricCriterion is one dimension of an [EvaluatorRubric]. The judge scores
+// it on the scale from MinScore to MaxScore.
+type RubricCriterion struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	MinScore    float64 `json:"minScore"`
+	MaxScore    float64 `json:"maxScore"`
+	// Anchors illustrate what outputs deserve particular scores.
+	Anchors []RubricAnchor `json:"anchors,omitempty"`
+}
+
+// RubricAnchor is an example of an output that deserves Score.
+type RubricAnchor struct {
+	Score   float64 `json:"score"`
+	Example string  `json:"example"`
+}
+
+// Validate reports whether the rubric is well formed: it has at least one
+// criterion, criterion names are unique and non-empty, every scale is
+// non-empty, and every anchor lies on its criterion's scale.
+func (rb *EvaluatorRubric) Validate() error {
+	if len(rb.Criteria) == 0 {
+		return errors.New("rubric has no criteria")
+	}
+	seen := map[string]bool{}
+	for _, c := range rb.Criteria {
+		if c.Name == "" {
+			return errors.New("rubric criterion has no name")
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("duplicate rubric criterion %q", c.Name)
+		}
+		seen[c.Name] = true
+		if c.MinScore >= c.MaxScore {
+			return fmt.Errorf("criterion %q: min score %v is not less than max score %v", c.Name, c.MinScore, c.MaxScore)
+		}
+		for _, a := range c.Anchors {
+			if a.Score < c.MinScore || a.Score > c.MaxScore {
+				return fmt.Errorf("criterion %q: anchor score %v is outside [%v, %v]", c.Name, a.Score, c.MinScore, c.MaxScore)
+			}
+		}
+	}
+	return nil
+}
+
+// rubricVerdict is the structured output requested from rubric judges.
+type rubricVerdict struct {
+	Scores []rubricCriterionScore `json:"scores"`
+
+	rationale string // Set for chain-of-thought judges.
+}
+
+// cotRubricVerdict is the structured output requested from rubric judges
+// that use chain of thought.
+type cotRubricVerdict struct {
+	Rationale string                 `json:"rationale"`
+	Scores    []rubricCriterionScore `json:"scores"`
+}
+
+type rubricCriterionScore struct {
+	Criterion string  `json:"criterion"`
+	Score     float64 `json:"score"`
+	Reasoning string  `json:"reasoning"`
+}
+
+// DefineRubricEvaluator registers an evaluator that asks model to grade each
+// [Example] against rubric.
+//
+// The rubric is formatted into the judge prompt together with the example's
+// Input, Output and Reference. The evaluator returns one [Score] per
+// criterion, with the criterion name as its Id and the judge's score on the
+// criterion's scale. A criterion passes when its score is at least halfway
+// up the scale. With [EvaluatorOptions.UseChainOfThought], the judge's
+// overall rationale is reported in the "rationale" key of [Score.Details]
+// of every criterion. The example fails with an error if the judge omits a
+// criterion, scores an unknown one, or gives a score outside the
+// criterion's range. If opts is nil, default options are used.
+func DefineRubricEvaluator(r *registry.Registry, provider, name string, model Model, rubric EvaluatorRubric, opts *EvaluatorOptions) (Evaluator, error) {
+	if model == nil {
+		return nil, errors.New("ai.DefineRubricEvaluator: model is required")
+	}
+	if err := rubric.Validate(); err != nil {
+		return nil, fmt.Errorf("ai.DefineRubricEvaluator: %w", err)
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Rubric",
+			Definition:  "Grades the output against each criterion of a rubric using an LLM judge",
+		}
+	}
+
+	return DefineEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		prompt, err := rubricPrompt(&rubric, &req.Input)
+		if err != nil {
+			return nil, err
+		}
+		var v rubricVerdict
+		if opts.UseChainOfThought {
+			var cv cotRubricVerdict
+			_, err = GenerateData(ctx, r, &cv, WithModel(model), WithPromptText(prompt+"\n"+chainOfThoughtInstruction))
+			v = rubricVerdict{Scores: cv.Scores}
+			v.rationale = cv.Rationale
+		} else {
+			_, err = GenerateData(ctx, r, &v, WithModel(model), WithPromptText(prompt))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("judge model %q: %w", model.Name(), err)
+		}
+		scores, err := rubricScores(&rubric, &v)
+		if err != nil {
+			return nil, err
+		}
+		if opts.UseChainOfThought {
+			for i := range scores {
+				scores[i].Details["rationale"] = v.rationale
+			}
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: scores,
+		}, nil
+	})
+}
+
+// rubricScores validates v against rubric and returns one score per
+// criterion, in rubric order.
+func rubricScores(rubric *EvaluatorRubric, v *rubricVerdict) ([]Score, error) {
+	byName := map[string]rubricCriterionScore{}
+	for _, cs := range v.Scores {
+		if _, ok := byName[cs.Criterion]; ok {
+			return nil, fmt.Errorf("judge scored criterion %q more than once", cs.Criterion)
+		}
+		byName[cs.Criterion] = cs
+	}
+	scores := make([]Score, 0, len(rubric.Criteria))
+	for _, c := range rubric.Criteria {
+		cs, ok := byName[c.Name]
+		if !ok {
+			return nil, fmt.Errorf("judge did not score criterion %q", c.Name)
+		}
+		delete(byName, c.Name)
+		if cs.Score < c.MinScore || cs.Score > c.MaxScore {
+			return nil, fmt.Errorf("criterion %q: judge score %v is outside [%v, %v]", c.Name, cs.Score, c.MinScore, c.MaxScore)
+		}
+		normalized := (cs.Score - c.MinScore) / (c.MaxScore - c.MinScore)
+		scores = append(scores, Score{
+			Id:      c.Name,
+			Score:   cs.Score,
+			Status:  passStatus(normalized >= judgePassThreshold).String(),
+			Details: map[string]any{"reasoning": cs.Reasoning},
+		})
+	}
+	for name := range byName {
+		return nil, fmt.Errorf("judge scored unknown criterion %q", name)
+	}
+	return scores, nil
+}
+
+// rubricPrompt returns the prompt asking an LLM judge to grade ex against
+// each criterion of rubric.
+func rubricPrompt(rubric *EvaluatorRubric, ex *Example) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("You are grading the output of an AI system against a rubric.\n\n")
+	sb.WriteString("Rubric:\n")
+	for _, c := range rubric.Criteria {
+		fmt.Fprintf(&sb, "- %s (score from %s to %s)", c.Name, formatRubricScore(c.MinScore), formatRubricScore(c.MaxScore))
+		if c.Description != "" {
+			fmt.Fprintf(&sb, ": %s", c.Description)
+		}
+		sb.WriteString("\n")
+		for _, a := range c.Anchors {
+			fmt.Fprintf(&sb, "  - Example of a %s: %s\n", formatRubricScore(a.Score), a.Example)
+		}
+	}
+	sb.WriteString("\n")
+	if err := writeJudgeExample(&sb, ex); err != nil {
+		return "", err
+	}
+	sb.WriteString("Respond with a score for every criterion, using the criterion name exactly as given and a score within its range, and a short reasoning for each.")
+	return sb.String(), nil
+}
+
+func formatRubricScore(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
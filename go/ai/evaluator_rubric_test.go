@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+var testRubric = EvaluatorRubric{Criteria: []RubricCriterion{
+	{
+		Name:        "clarity",
+		Description: "The answer is easy to follow.",
+		MinScore:    1,
+		MaxScore:    5,
+		Anchors:     []RubricAnchor{{Score: 1, Example: "word salad"}, {Score: 5, Example: "a crisp answer"}},
+	},
+	{Name: "accuracy", MinScore: 0, MaxScore: 10},
+}}
+
+func TestDefineRubricEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prompts []string
+	model := defineJudgeModel(r, "rubricJudge", func(prompt string) any {
+		prompts = append(prompts, prompt)
+		scores := []map[string]any{
+			{"criterion": "accuracy", "score": 3, "reasoning": "mostly wrong"},
+			{"criterion": "clarity", "score": 4, "reasoning": "clear"},
+		}
+		switch {
+		case strings.Contains(prompt, "out of range"):
+			scores[1]["score"] = 6
+		case strings.Contains(prompt, "missing"):
+			scores = scores[:1]
+		}
+		return map[string]any{"scores": scores}
+	})
+	e, err := DefineRubricEvaluator(r, "test", "rubric", model, testRubric, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "ok", Input: "q", Output: "fine"},
+		{TestCaseId: "range", Input: "q", Output: "out of range"},
+		{TestCaseId: "missing", Input: "q", Output: "missing"},
+	}
+	// The examples the judge scored invalidly fail, and the rest are scored.
+	resp, err := e.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err == nil {
+		t.Fatal("got nil, want error for invalid judge scores")
+	}
+	results := indexResults(resp)
+
+	scores := results["ok"].Evaluation
+	if got, want := len(scores), 2; got != want {
+		t.Fatalf("got %d scores, want %d", got, want)
+	}
+	for i, want := range []struct {
+		id     string
+		score  float64
+		status string
+	}{{"clarity", 4, "pass"}, {"accuracy", 3, "fail"}} {
+		s := scores[i]
+		if s.Id != want.id || s.Score != want.score || s.Status != want.status {
+			t.Errorf("score %d: got %s=%v (%s), want %s=%v (%s)", i, s.Id, s.Score, s.Status, want.id, want.score, want.status)
+		}
+	}
+	for _, id := range []string{"range", "missing"} {
+		if results[id].Evaluation[0].Error == "" {
+			t.Errorf("%s: got no error", id)
+		}
+	}
+
+	for _, want := range []string{"- clarity (score from 1 to 5): The answer is easy to follow.", "Example of a 5: a crisp answer", "- accuracy (score from 0 to 10)", "Output:\nfine"} {
+		if !strings.Contains(prompts[0], want) {
+			t.Errorf("prompt %q does not contain %q", prompts[0], want)
+		}
+	}
+}
+
+func TestEvaluatorRubricValidate(t *testing.T) {
+	if err := testRubric.Validate(); err != nil {
+		t.Errorf("valid rubric: %v", err)
+	}
+	for name, rb := range map[string]EvaluatorRubric{
+		"empty":     {},
+		"unnamed":   {Criteria: []RubricCriterion{{MaxScore: 1}}},
+		"duplicate": {Criteria: []RubricCriterion{{Name: "a", MaxScore: 1}, {Name: "a", MaxScore: 1}}},
+		"scale":     {Criteria: []RubricCriterion{{Name: "a", MinScore: 1, MaxScore: 1}}},
+		"anchor":    {Criteria: []RubricCriterion{{Name: "a", MaxScore: 1, Anchors: []RubricAnchor{{Score: 2}}}}},
+	} {
+		if err := rb.Validate(); err == nil {
+			t.Errorf("%s: got nil, want error", name)
+		}
+	}
+}
+
+func TestRubricEvaluatorChainOfThought(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := defineJudgeModel(r, "cotRubricJudge", func(prompt string) any {
+		if !strings.Contains(prompt, "Think step by step") {
+			return map[string]any{"scores": []any{}}
+		}
+		return map[string]any{
+			"rationale": "Clear but partly wrong.",
+			"scores": []map[string]any{
+				{"criterion": "clarity", "score": 4, "reasoning": "clear"},
+				{"criterion": "accuracy", "score": 6, "reasoning": "partly wrong"},
+			},
+		}
+	})
+	e, err := DefineRubricEvaluator(r, "test", "cotRubric", model, testRubric, NewEvaluatorOptions(WithChainOfThought(true)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := Dataset{{Input: "q", Output: "a"}}
+	resp, err := e.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range (*resp)[0].Evaluation {
+		if got, want := s.Details["rationale"], "Clear but partly wrong."; got != want {
+			t.Errorf("%s: got rationale %q, want %q", s.Id, got, want)
+		}
+	}
+}
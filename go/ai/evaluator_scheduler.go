@@ -0,0 +1,205 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/firebase/genkit/go/core/logger"
+	"github.com/google/uuid"
+)
+
+// EvalAlert reports the regressions found by an [EvaluationScheduler] when
+// comparing an evaluation with the previous one.
+type EvalAlert struct {
+	Evaluator      string      `json:"evaluator"`
+	EvalId         string      `json:"evalId"`
+	PreviousEvalId string      `json:"previousEvalId"`
+	Timestamp      time.Time   `json:"timestamp"`
+	Regressions    []ScoreDiff `json:"regressions"`
+}
+
+// EvaluationScheduler runs an evaluator on a dataset periodically and
+// alerts when results regress compared with the previous run.
+//
+// Each run is compared with the previous one using
+// [DiffEvaluatorResponses]; scores for which [ScoreDiff.IsRegression]
+// reports true with Threshold are passed to AlertFn. If Store is set, each
+// run's response and [EvalRun] record are saved to it, and the latest
+// stored run of the evaluator is the baseline for the first comparison.
+//
+// Results are matched across runs by TestCaseId. Examples without one are
+// assigned an ID when the scheduler starts, so they are only comparable
+// between runs of the same Start.
+type EvaluationScheduler struct {
+	Interval  time.Duration
+	Evaluator Evaluator
+	Dataset   Dataset
+	Store     StoreEvaluatorResponse // Optional.
+	// Threshold is the largest drop in a numeric score that is not a
+	// regression.
+	Threshold float64
+	AlertFn   func(*EvalAlert)
+
+	// newTicker returns a channel delivering ticks every interval, and a
+	// function that stops it. Tests replace it with a fake clock.
+	newTicker func(interval time.Duration) (<-chan time.Time, func())
+
+	mu     sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+	lastId string
+	last   *EvaluatorResponse
+}
+
+// Start starts running evaluations every Interval, until Stop is called or
+// ctx is canceled. The first evaluation runs after one Interval. Errors
+// from individual runs are logged. Start returns an error if the scheduler
+// is misconfigured or already running.
+func (s *EvaluationScheduler) Start(ctx context.Context) error {
+	if s.Interval <= 0 {
+		return errors.New("ai.EvaluationScheduler.Start: interval must be positive")
+	}
+	if s.Evaluator == nil {
+		return errors.New("ai.EvaluationScheduler.Start: evaluator is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return errors.New("ai.EvaluationScheduler.Start: already running")
+	}
+	if s.Store != nil && s.last == nil {
+		if err := s.loadBaseline(ctx); err != nil {
+			return fmt.Errorf("ai.EvaluationScheduler.Start: %w", err)
+		}
+	}
+
+	newTicker := s.newTicker
+	if newTicker == nil {
+		newTicker = func(d time.Duration) (<-chan time.Time, func()) {
+			t := time.NewTicker(d)
+			return t.C, t.Stop
+		}
+	}
+	ds := withTestCaseIds(s.Dataset)
+	ticks, stopTicker := newTicker(s.Interval)
+	stop, done := make(chan struct{}), make(chan struct{})
+	s.stop, s.done = stop, done
+	go func() {
+		defer close(done)
+		defer stopTicker()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case now := <-ticks:
+				if err := s.run(ctx, ds, now); err != nil {
+					logger.FromContext(ctx).Error("scheduled evaluation failed", "evaluator", s.Evaluator.Name(), "err", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the scheduler and waits for a run in progress to finish. It
+// does nothing if the scheduler is not running.
+func (s *EvaluationScheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// loadBaseline makes the latest run of the evaluator in s.Store the
+// baseline for the next comparison.
+func (s *EvaluationScheduler) loadBaseline(ctx context.Context) error {
+	history, err := LoadEvalHistory(ctx, s.Store, EvalFilter{Evaluator: s.Evaluator.Name()})
+	if err != nil || len(history) == 0 {
+		return err
+	}
+	id := history[len(history)-1].EvalId
+	resp, err := s.Store.Load(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.lastId, s.last = id, resp
+	return nil
+}
+
+// run evaluates the dataset once, records the result and alerts on
+// regressions.
+func (s *EvaluationScheduler) run(ctx context.Context, ds Dataset, now time.Time) error {
+	evalId := uuid.New().String()
+	resp, err := Evaluate(ctx, s.Evaluator, WithEvaluateDataset(&ds), WithEvaluateId(evalId))
+	if resp == nil {
+		return err
+	}
+	if err != nil {
+		// Examples that failed are reported as errors in resp.
+		logger.FromContext(ctx).Warn("scheduled evaluation had failures", "evaluator", s.Evaluator.Name(), "err", err)
+	}
+
+	if s.Store != nil {
+		if err := s.Store.Save(ctx, "", evalId, resp); err != nil {
+			return err
+		}
+		summary, err := AggregateScores(resp)
+		if err != nil {
+			return err
+		}
+		run := EvalRun{EvalId: evalId, Evaluator: s.Evaluator.Name(), Timestamp: now, Summary: *summary}
+		if err := RecordEvalRun(ctx, s.Store, run); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	prevId, prev := s.lastId, s.last
+	s.lastId, s.last = evalId, resp
+	s.mu.Unlock()
+	if prev == nil || s.AlertFn == nil {
+		return nil
+	}
+	var regressions []ScoreDiff
+	for _, d := range DiffEvaluatorResponses(prev, resp) {
+		if d.IsRegression(s.Threshold) {
+			regressions = append(regressions, d)
+		}
+	}
+	if len(regressions) > 0 {
+		s.AlertFn(&EvalAlert{
+			Evaluator:      s.Evaluator.Name(),
+			EvalId:         evalId,
+			PreviousEvalId: prevId,
+			Timestamp:      now,
+			Regressions:    regressions,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// fakeTicker returns a newTicker function for an [EvaluationScheduler] or
+// [EvalMonitor] that delivers the ticks sent on ticks. Because ticks is
+// unbuffered, a send completes only once the previous run has finished.
+func fakeTicker(ticks chan time.Time) func(time.Duration) (<-chan time.Time, func()) {
+	return func(time.Duration) (<-chan time.Time, func()) {
+		return ticks, func() {}
+	}
+}
+
+func TestEvaluationScheduler(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The score of each successive run.
+	scores := []float64{0.9, 0.85, 0.4, 0.2}
+	var runs atomic.Int32
+	e, err := DefineEvaluator(r, "test", "scheduled", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		v := scores[runs.Add(1)-1]
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "quality", Score: v, Status: passStatus(v >= 0.5).String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	store := NewInMemoryEvaluationStore()
+	var alerts []*EvalAlert
+	ticks := make(chan time.Time)
+	s := &EvaluationScheduler{
+		Interval:  time.Hour,
+		Evaluator: e,
+		Dataset:   Dataset{{TestCaseId: "q1", Input: "q1"}},
+		Store:     store,
+		Threshold: 0.1,
+		AlertFn:   func(a *EvalAlert) { alerts = append(alerts, a) },
+		newTicker: fakeTicker(ticks),
+	}
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(ctx); err == nil {
+		t.Error("got nil, want error when starting twice")
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		ticks <- start.Add(time.Duration(i) * time.Hour)
+	}
+	s.Stop()
+
+	// The first run is the baseline and the second is within the threshold.
+	if got, want := len(alerts), 1; got != want {
+		t.Fatalf("got %d alerts, want %d", got, want)
+	}
+	alert := alerts[0]
+	if got, want := alert.Timestamp, start.Add(2*time.Hour); !got.Equal(want) {
+		t.Errorf("got alert time %v, want %v", got, want)
+	}
+	if len(alert.Regressions) != 1 || alert.Regressions[0].TestCaseId != "q1" {
+		t.Errorf("got regressions %+v, want one for q1", alert.Regressions)
+	}
+	history, err := LoadEvalHistory(ctx, store, EvalFilter{Evaluator: e.Name()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(history), 3; got != want {
+		t.Fatalf("got %d stored runs, want %d", got, want)
+	}
+	if got, want := alert.EvalId, history[2].EvalId; got != want {
+		t.Errorf("got alert eval ID %q, want %q", got, want)
+	}
+
+	// A restarted scheduler compares its first run with the latest stored one.
+	alerts = nil
+	s2 := &EvaluationScheduler{
+		Interval:  time.Hour,
+		Evaluator: e,
+		Dataset:   s.Dataset,
+		Store:     store,
+		Threshold: 0.1,
+		AlertFn:   func(a *EvalAlert) { alerts = append(alerts, a) },
+		newTicker: fakeTicker(ticks),
+	}
+	if err := s2.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ticks <- start.Add(3 * time.Hour)
+	s2.Stop()
+	if len(alerts) != 1 || alerts[0].PreviousEvalId != history[2].EvalId {
+		t.Errorf("got alerts %+v, want one against the stored run %q", alerts, history[2].EvalId)
+	}
+
+	if err := (&EvaluationScheduler{Evaluator: e}).Start(ctx); err == nil {
+		t.Error("got nil, want error for zero interval")
+	}
+}
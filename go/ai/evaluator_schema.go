@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SchemaVersion is the version of the format in which [EvaluatorResponse]
+// values are persisted, for example by [FileEvaluationStore]. Data written
+// with an earlier version can be upgraded with [MigrateEvaluatorResponse].
+//
+// The versions are:
+//   - "1": a JSON array of results.
+//   - "2": an object with "schemaVersion" and "results" fields, with score
+//     statuses in upper case ("PASS") as used by the Genkit Dev UI.
+//   - "3": as version 2, with score statuses in lower case as produced by
+//     [ScoreStatus.String].
+const SchemaVersion = "3"
+
+// versionedResponse is the persisted format of an [EvaluatorResponse] since
+// schema version 2.
+type versionedResponse struct {
+	SchemaVersion string          `json:"schemaVersion"`
+	Results       json.RawMessage `json:"results"`
+}
+
+// schemaMigration upgrades persisted data from one schema version to the
+// next.
+type schemaMigration struct {
+	to      string
+	migrate func([]byte) ([]byte, error)
+}
+
+// schemaMigrations holds the migration from each schema version to the next,
+// keyed by the version it upgrades from.
+var schemaMigrations = map[string]schemaMigration{
+	"1": {to: "2", migrate: migrateSchemaV1},
+	"2": {to: "3", migrate: migrateSchemaV2},
+}
+
+// MigrateEvaluatorResponse upgrades old, persisted with the given schema
+// version, to the current [SchemaVersion] and decodes it. old may be the
+// serialized JSON as a []byte or [json.RawMessage], or a value that encodes
+// to it, such as the result of unmarshaling it into an any. If version is
+// empty it is detected from the data.
+func MigrateEvaluatorResponse(old any, version string) (*EvaluatorResponse, error) {
+	var data []byte
+	switch v := old.(type) {
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		var err error
+		if data, err = json.Marshal(old); err != nil {
+			return nil, fmt.Errorf("ai.MigrateEvaluatorResponse: %w", err)
+		}
+	}
+	if version == "" {
+		var err error
+		if version, err = detectSchemaVersion(data); err != nil {
+			return nil, fmt.Errorf("ai.MigrateEvaluatorResponse: %w", err)
+		}
+	}
+	for version != SchemaVersion {
+		m, ok := schemaMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("ai.MigrateEvaluatorResponse: unsupported schema version %q", version)
+		}
+		var err error
+		if data, err = m.migrate(data); err != nil {
+			return nil, fmt.Errorf("ai.MigrateEvaluatorResponse: migrating from version %q: %w", version, err)
+		}
+		version = m.to
+	}
+
+	var vr versionedResponse
+	if err := json.Unmarshal(data, &vr); err != nil {
+		return nil, fmt.Errorf("ai.MigrateEvaluatorResponse: %w", err)
+	}
+	var resp EvaluatorResponse
+	if err := json.Unmarshal(vr.Results, &resp); err != nil {
+		return nil, fmt.Errorf("ai.MigrateEvaluatorResponse: %w", err)
+	}
+	return &resp, nil
+}
+
+// encodeEvaluatorResponse serializes resp in the current schema version.
+func encodeEvaluatorResponse(resp *EvaluatorResponse) ([]byte, error) {
+	results := EvaluatorResponse{}
+	if resp != nil {
+		results = *resp
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(versionedResponse{SchemaVersion: SchemaVersion, Results: data})
+}
+
+// detectSchemaVersion returns the schema version of persisted data: version
+// 1 for a JSON array, otherwise the value of its schemaVersion field.
+func detectSchemaVersion(data []byte) (string, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return "1", nil
+	}
+	var vr versionedResponse
+	if err := json.Unmarshal(data, &vr); err != nil {
+		return "", err
+	}
+	if vr.SchemaVersion == "" {
+		return "", fmt.Errorf("missing schema version")
+	}
+	return vr.SchemaVersion, nil
+}
+
+// migrateSchemaV1 wraps a version 1 array of results in a version 2 object.
+func migrateSchemaV1(data []byte) ([]byte, error) {
+	var results []json.RawMessage
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []json.RawMessage{}
+	}
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(versionedResponse{SchemaVersion: "2", Results: raw})
+}
+
+// migrateSchemaV2 lower-cases the score statuses of version 2 data. Only the
+// status fields are changed; other fields, including unknown ones, are kept
+// as they are.
+func migrateSchemaV2(data []byte) ([]byte, error) {
+	var vr versionedResponse
+	if err := json.Unmarshal(data, &vr); err != nil {
+		return nil, err
+	}
+	var results []map[string]json.RawMessage
+	if err := json.Unmarshal(vr.Results, &results); err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		raw, ok := res["evaluation"]
+		if !ok {
+			continue
+		}
+		var scores []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &scores); err != nil {
+			return nil, err
+		}
+		for _, s := range scores {
+			var status string
+			if json.Unmarshal(s["status"], &status) != nil {
+				continue
+			}
+			var err error
+			if s["status"], err = json.Marshal(strings.ToLower(status)); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if res["evaluation"], err = json.Marshal(scores); err != nil {
+			return nil, err
+		}
+	}
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(versionedResponse{SchemaVersion: "3", Results: raw})
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestMigrateEvaluatorResponse(t *testing.T) {
+	want := &EvaluatorResponse{
+		{
+			TestCaseId: "a",
+			Evaluation: []Score{{Id: "s", Score: 1.0, Status: "pass", Details: map[string]any{"reasoning": "ok"}}},
+		},
+		{
+			TestCaseId: "b",
+			Evaluation: []Score{{Id: "s", Score: 0.0, Status: "fail"}},
+		},
+	}
+	current, err := encodeEvaluatorResponse(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		old     string
+		version string
+	}{
+		{"v1", `[{"testCaseId":"a","evaluation":[{"id":"s","score":1,"status":"pass","details":{"reasoning":"ok"}}]},{"testCaseId":"b","evaluation":[{"id":"s","score":0,"status":"fail"}]}]`, "1"},
+		{"v2", `{"schemaVersion":"2","results":[{"testCaseId":"a","evaluation":[{"id":"s","score":1,"status":"PASS","details":{"reasoning":"ok"}}]},{"testCaseId":"b","evaluation":[{"id":"s","score":0,"status":"FAIL"}]}]}`, "2"},
+		{"current", string(current), SchemaVersion},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			data := []byte(test.old)
+			for _, version := range []string{test.version, ""} {
+				got, err := MigrateEvaluatorResponse(data, version)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(EvaluationResult{})); diff != "" {
+					t.Errorf("version %q: mismatch (-want +got):\n%s", version, diff)
+				}
+			}
+			// A decoded value migrates the same as its encoding.
+			var decoded any
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := MigrateEvaluatorResponse(decoded, test.version); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	if _, err := MigrateEvaluatorResponse([]byte(`{"schemaVersion":"99","results":[]}`), ""); err == nil {
+		t.Error("got nil, want error for unknown schema version")
+	}
+}
+
+func TestFileEvaluationStoreMigratesLegacyFiles(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `{"evalId":"old","runIds":["run1"],"response":[{"testCaseId":"a","evaluation":[{"id":"s","score":1,"status":"pass"}]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "old.json"), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewFileEvaluationStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := store.Load(context.Background(), "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := (*resp)[0].TestCaseId, "a"; got != want {
+		t.Errorf("got test case %q, want %q", got, want)
+	}
+
+	if err := store.Save(context.Background(), "", "old", resp); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "old.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry struct {
+		RunIds   []string          `json:"runIds"`
+		Response versionedResponse `json:"response"`
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if got := entry.Response.SchemaVersion; got != SchemaVersion {
+		t.Errorf("got schema version %q, want %q", got, SchemaVersion)
+	}
+	if got, want := entry.RunIds, []string{"run1"}; !cmp.Equal(got, want) {
+		t.Errorf("got run IDs %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// ResponseSerializer encodes and decodes an [EvaluatorResponse] in one
+// format, such as JSON or Parquet. Serializers are registered with
+// [RegisterResponseSerializer] and used by [SerializeResponse] and
+// [DeserializeResponse].
+type ResponseSerializer interface {
+	// Serialize writes resp to w.
+	Serialize(resp *EvaluatorResponse, w io.Writer) error
+	// Deserialize reads a response written by Serialize from r.
+	Deserialize(r io.Reader) (*EvaluatorResponse, error)
+}
+
+// builtinResponseSerializers holds the serializers available from every
+// registry.
+var builtinResponseSerializers = map[string]ResponseSerializer{
+	"json":  jsonResponseSerializer{},
+	"jsonl": jsonlResponseSerializer{},
+}
+
+// RegisterResponseSerializer registers s under format for use with
+// [SerializeResponse] and [DeserializeResponse]. A registered serializer
+// takes precedence over a built-in one with the same format. It panics if
+// a serializer for the same format is already registered.
+func RegisterResponseSerializer(r *registry.Registry, format string, s ResponseSerializer) {
+	r.RegisterValue(responseSerializerKey(format), s)
+}
+
+// LookupResponseSerializer returns the serializer registered under format
+// or, if there is none, the built-in serializer for that format. The
+// built-in formats are:
+//
+//   - "json": a JSON list of results.
+//   - "jsonl": one JSON result per line.
+//
+// It returns nil if there is no serializer for format.
+func LookupResponseSerializer(r *registry.Registry, format string) ResponseSerializer {
+	if s, ok := r.LookupValue(responseSerializerKey(format)).(ResponseSerializer); ok {
+		return s
+	}
+	return builtinResponseSerializers[format]
+}
+
+func responseSerializerKey(format string) string {
+	return "responseSerializer/" + format
+}
+
+// SerializeResponse writes resp to w in format, using the serializer
+// returned by [LookupResponseSerializer].
+func SerializeResponse(r *registry.Registry, resp *EvaluatorResponse, format string, w io.Writer) error {
+	s := LookupResponseSerializer(r, format)
+	if s == nil {
+		return fmt.Errorf("ai.SerializeResponse: unknown format %q", format)
+	}
+	if err := s.Serialize(resp, w); err != nil {
+		return fmt.Errorf("ai.SerializeResponse: %s: %w", format, err)
+	}
+	return nil
+}
+
+// DeserializeResponse reads a response in format from rd, using the
+// serializer returned by [LookupResponseSerializer].
+func DeserializeResponse(r *registry.Registry, rd io.Reader, format string) (*EvaluatorResponse, error) {
+	s := LookupResponseSerializer(r, format)
+	if s == nil {
+		return nil, fmt.Errorf("ai.DeserializeResponse: unknown format %q", format)
+	}
+	resp, err := s.Deserialize(rd)
+	if err != nil {
+		return nil, fmt.Errorf("ai.DeserializeResponse: %s: %w", format, err)
+	}
+	return resp, nil
+}
+
+// jsonResponseSerializer is the built-in "json" [ResponseSerializer].
+type jsonResponseSerializer struct{}
+
+func (jsonResponseSerializer) Serialize(resp *EvaluatorResponse, w io.Writer) error {
+	if resp == nil {
+		resp = &EvaluatorResponse{}
+	}
+	return json.NewEncoder(w).Encode(resp)
+}
+
+func (jsonResponseSerializer) Deserialize(r io.Reader) (*EvaluatorResponse, error) {
+	var resp EvaluatorResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// jsonlResponseSerializer is the built-in "jsonl" [ResponseSerializer].
+// Results are encoded and decoded one at a time, so that large responses
+// can be streamed.
+type jsonlResponseSerializer struct{}
+
+func (jsonlResponseSerializer) Serialize(resp *EvaluatorResponse, w io.Writer) error {
+	if resp == nil {
+		return nil
+	}
+	enc := json.NewEncoder(w)
+	for i, res := range *resp {
+		if err := enc.Encode(res); err != nil {
+			return fmt.Errorf("result %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (jsonlResponseSerializer) Deserialize(r io.Reader) (*EvaluatorResponse, error) {
+	dec := json.NewDecoder(r)
+	resp := EvaluatorResponse{}
+	for {
+		var res EvaluationResult
+		err := dec.Decode(&res)
+		if errors.Is(err, io.EOF) {
+			return &resp, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("result %d: %w", len(resp), err)
+		}
+		resp = append(resp, res)
+	}
+}
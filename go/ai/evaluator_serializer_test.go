@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// idSerializer writes one TestCaseId per line.
+type idSerializer struct{}
+
+func (idSerializer) Serialize(resp *EvaluatorResponse, w io.Writer) error {
+	for _, res := range *resp {
+		if _, err := fmt.Fprintln(w, res.TestCaseId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idSerializer) Deserialize(r io.Reader) (*EvaluatorResponse, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	resp := EvaluatorResponse{}
+	for _, id := range strings.Fields(string(b)) {
+		resp = append(resp, EvaluationResult{TestCaseId: id})
+	}
+	return &resp, nil
+}
+
+func TestResponseSerializers(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterResponseSerializer(r, "ids", idSerializer{})
+	resp := EvaluatorResponse{passFail("a", true, 0.9), passFail("b", false, 0.2)}
+
+	for _, format := range []string{"json", "jsonl"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := SerializeResponse(r, &resp, format, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if format == "jsonl" {
+				if got := strings.Count(buf.String(), "\n"); got != len(resp) {
+					t.Errorf("got %d lines, want %d", got, len(resp))
+				}
+			}
+			got, err := DeserializeResponse(r, &buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(&resp, got, cmpopts.IgnoreUnexported(EvaluationResult{})); diff != "" {
+				t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := SerializeResponse(r, &resp, "ids", &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "a\nb\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got, err := DeserializeResponse(r, &buf, "ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*got) != 2 || (*got)[1].TestCaseId != "b" {
+		t.Errorf("got %+v, want results a and b", *got)
+	}
+
+	if err := SerializeResponse(r, &resp, "parquet", io.Discard); err == nil {
+		t.Error("got nil, want error for unknown format")
+	}
+	if _, err := DeserializeResponse(r, strings.NewReader("{"), "json"); err == nil {
+		t.Error("got nil, want error for invalid JSON")
+	}
+}
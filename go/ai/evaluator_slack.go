@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlackOption configures [ExportToSlack].
+type SlackOption func(opts *slackOptions)
+
+type slackOptions struct {
+	reportURL string
+	title     string
+}
+
+// WithSlackReportURL adds a link to the full report to the message.
+func WithSlackReportURL(url string) SlackOption {
+	return func(opts *slackOptions) {
+		opts.reportURL = url
+	}
+}
+
+// WithSlackTitle sets the title of the message. It defaults to
+// "Evaluation results".
+func WithSlackTitle(title string) SlackOption {
+	return func(opts *slackOptions) {
+		opts.title = title
+	}
+}
+
+// slackMaxAttempts is the number of times ExportToSlack posts a message
+// before giving up on a rate-limited or failing webhook.
+const slackMaxAttempts = 5
+
+// slackBackoff is the delay before the first retry. It doubles with each
+// further retry. A Retry-After header sent by the webhook replaces the
+// delay of that retry only.
+var slackBackoff = time.Second
+
+// ExportToSlack posts a summary of resp to the Slack incoming webhook at
+// webhookURL, as a Block Kit message with the pass rate, the number of
+// results and the three failures with the lowest scores. If summary is nil,
+// it is computed with [AggregateScores].
+//
+// Requests that are rate limited or fail with a server error are retried
+// with exponential backoff, honoring the Retry-After header.
+func ExportToSlack(ctx context.Context, resp *EvaluatorResponse, summary *ScoreSummary, webhookURL string, opts ...SlackOption) error {
+	o := &slackOptions{title: "Evaluation results"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if summary == nil {
+		var err error
+		if summary, err = AggregateScores(resp); err != nil {
+			return fmt.Errorf("ai.ExportToSlack: %w", err)
+		}
+	}
+	body, err := json.Marshal(slackMessage(resp, summary, o))
+	if err != nil {
+		return fmt.Errorf("ai.ExportToSlack: %w", err)
+	}
+
+	delay := slackBackoff
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := postSlack(ctx, webhookURL, body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter < 0 || attempt == slackMaxAttempts {
+			return fmt.Errorf("ai.ExportToSlack: %w", err)
+		}
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("ai.ExportToSlack: %w", ctx.Err())
+		}
+		delay *= 2
+	}
+}
+
+// postSlack posts body to the webhook. If it fails, it returns the delay
+// requested by the webhook's Retry-After header, zero if the request may be
+// retried after the default backoff, or a negative value if it must not be
+// retried.
+func postSlack(ctx context.Context, webhookURL string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, err
+		}
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		return 0, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	err = fmt.Errorf("webhook returned %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	if res.StatusCode != http.StatusTooManyRequests && res.StatusCode < 500 {
+		return -1, err
+	}
+	if secs, perr := strconv.Atoi(res.Header.Get("Retry-After")); perr == nil && secs > 0 {
+		return time.Duration(secs) * time.Second, err
+	}
+	return 0, err
+}
+
+// slackEscaper escapes the characters that Slack's mrkdwn format uses for
+// links and mentions.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackMessage returns the Block Kit message describing resp.
+func slackMessage(resp *EvaluatorResponse, summary *ScoreSummary, o *slackOptions) map[string]any {
+	text := fmt.Sprintf("%s: %.1f%% passed (%d of %d)", slackEscaper.Replace(o.title), 100*summary.PassRate, summary.Passed, summary.Total)
+	blocks := []map[string]any{
+		{
+			"type": "header",
+			"text": map[string]any{"type": "plain_text", "text": o.title},
+		},
+		{
+			"type": "section",
+			"fields": []map[string]any{
+				{"type": "mrkdwn", "text": fmt.Sprintf("*Pass rate:*\n%.1f%%", 100*summary.PassRate)},
+				{"type": "mrkdwn", "text": fmt.Sprintf("*Total:*\n%d (%d passed, %d failed)", summary.Total, summary.Passed, summary.Failed)},
+			},
+		},
+	}
+	if failures := topFailures(resp, 3); len(failures) > 0 {
+		var sb strings.Builder
+		sb.WriteString("*Top failures:*")
+		for _, res := range failures {
+			fmt.Fprintf(&sb, "\n• `%s`", slackEscaper.Replace(res.TestCaseId))
+			if detail := failureDetail(res); detail != "" {
+				fmt.Fprintf(&sb, ": %s", slackEscaper.Replace(detail))
+			}
+		}
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": sb.String()},
+		})
+	}
+	if o.reportURL != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("<%s|View full report>", slackEscaper.Replace(o.reportURL))},
+		})
+	}
+	return map[string]any{"text": text, "blocks": blocks}
+}
+
+// topFailures returns up to n failed results in resp, lowest mean score
+// first. Results without numeric scores come last.
+func topFailures(resp *EvaluatorResponse, n int) []EvaluationResult {
+	if resp == nil {
+		return nil
+	}
+	var failed []EvaluationResult
+	for _, res := range *resp {
+		if resultStatus(res) == ScoreStatusFail {
+			failed = append(failed, res)
+		}
+	}
+	key := func(res EvaluationResult) float64 {
+		if v, err := meanScore(res); err == nil {
+			return v
+		}
+		return math.Inf(1)
+	}
+	slices.SortStableFunc(failed, func(a, b EvaluationResult) int {
+		return cmp.Compare(key(a), key(b))
+	})
+	return failed[:min(n, len(failed))]
+}
+
+// failureDetail describes why res failed: the error of its first failed
+// score, or the scores that failed.
+func failureDetail(res EvaluationResult) string {
+	var failed []string
+	for _, s := range res.Evaluation {
+		if s.Status != ScoreStatusFail.String() {
+			continue
+		}
+		if s.Error != "" {
+			return strings.TrimSpace(s.Error)
+		}
+		if v, err := s.Normalize(); err == nil {
+			failed = append(failed, fmt.Sprintf("%s=%g", cmp.Or(s.Id, "score"), v))
+		}
+	}
+	return strings.Join(failed, ", ")
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportToSlack(t *testing.T) {
+	defer func(d time.Duration) { slackBackoff = d }(slackBackoff)
+	slackBackoff = time.Millisecond
+
+	var (
+		calls   int
+		message map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, "rate_limited", http.StatusTooManyRequests)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	resp := EvaluatorResponse{
+		passFail("good", true, 1.0),
+		passFail("bad", false, 0.4),
+		passFail("worse", false, 0.1),
+		passFail("worst", false, 0.0),
+		passFail("mild", false, 0.45),
+		passFail("<!channel> & co", false, 0.2),
+	}
+	err := ExportToSlack(context.Background(), &resp, nil, srv.URL, WithSlackReportURL("https://example.com/report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := calls, 2; got != want {
+		t.Errorf("got %d calls, want %d", got, want)
+	}
+
+	// Collect the text of all blocks.
+	var texts []string
+	var collect func(v any)
+	collect = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if text, ok := v["text"].(string); ok {
+				texts = append(texts, text)
+			}
+			for _, child := range v {
+				collect(child)
+			}
+		case []any:
+			for _, child := range v {
+				collect(child)
+			}
+		}
+	}
+	collect(message["blocks"])
+	blocks := strings.Join(texts, "\n")
+	for _, want := range []string{
+		"*Pass rate:*\n16.7%",
+		"*Total:*\n6 (1 passed, 5 failed)",
+		"• `worst`: s=0\n• `worse`: s=0.1\n• `&lt;!channel&gt; &amp; co`: s=0.2",
+		"<https://example.com/report|View full report>",
+	} {
+		if !strings.Contains(blocks, want) {
+			t.Errorf("message %q does not contain %q", blocks, want)
+		}
+	}
+	if strings.Contains(blocks, "mild") {
+		t.Errorf("message %q contains more than three failures", blocks)
+	}
+}
+
+func TestExportToSlackErrors(t *testing.T) {
+	defer func(d time.Duration) { slackBackoff = d }(slackBackoff)
+	slackBackoff = time.Millisecond
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if strings.HasSuffix(r.URL.Path, "/invalid") {
+			http.Error(w, "invalid_payload", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "rate_limited", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	resp := EvaluatorResponse{passFail("a", true, 1)}
+	if err := ExportToSlack(context.Background(), &resp, nil, srv.URL+"/invalid"); err == nil || calls != 1 {
+		t.Errorf("got error %v after %d calls, want an error after 1 call", err, calls)
+	}
+	calls = 0
+	if err := ExportToSlack(context.Background(), &resp, nil, srv.URL+"/limited"); err == nil || calls != slackMaxAttempts {
+		t.Errorf("got error %v after %d calls, want an error after %d calls", err, calls, slackMaxAttempts)
+	}
+}
+
+func TestExportToSlackRetryAfter(t *testing.T) {
+	defer func(d time.Duration) { slackBackoff = d }(slackBackoff)
+	slackBackoff = time.Millisecond
+
+	var calls []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, time.Now())
+		switch len(calls) {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate_limited", http.StatusTooManyRequests)
+		case 2:
+			http.Error(w, "rate_limited", http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	resp := EvaluatorResponse{passFail("a", true, 1)}
+	if err := ExportToSlack(context.Background(), &resp, nil, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("got %d calls, want 3", len(calls))
+	}
+	if d := calls[1].Sub(calls[0]); d < time.Second {
+		t.Errorf("first retry after %v, want the Retry-After delay of 1s", d)
+	}
+	// Retry-After applies to one retry only; the backoff resumes after it.
+	if d := calls[2].Sub(calls[1]); d > 500*time.Millisecond {
+		t.Errorf("second retry after %v, want the backoff of 2ms", d)
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/firebase/genkit/go/internal/base"
+)
+
+// ErrEvaluationNotFound is returned by a [StoreEvaluatorResponse] when no
+// response is stored under the requested evaluation ID.
+var ErrEvaluationNotFound = errors.New("evaluation not found")
+
+// StoreEvaluatorResponse persists [EvaluatorResponse] values by evaluation ID.
+// Evaluations can be grouped into runs, identified by [EvaluatorRequest.RunId].
+type StoreEvaluatorResponse interface {
+	// Save stores resp under evalId, replacing any previous response. If runId
+	// is non-empty the evaluation is recorded as part of that run. Save never
+	// removes an evaluation from a run it was previously saved with.
+	Save(ctx context.Context, runId, evalId string, resp *EvaluatorResponse) error
+	// Load returns the response stored under evalId, or an error wrapping
+	// [ErrEvaluationNotFound].
+	Load(ctx context.Context, evalId string) (*EvaluatorResponse, error)
+	// LoadAllForRun returns the responses of all evaluations in the run,
+	// ordered by evaluation ID.
+	LoadAllForRun(ctx context.Context, runId string) ([]*EvaluatorResponse, error)
+	// SaveRun stores run under run.EvalId, replacing any previous record of
+	// the run. It does not change the response stored under that ID.
+	SaveRun(ctx context.Context, run *EvalRun) error
+	// LoadRuns returns all stored run records, ordered by evaluation ID.
+	LoadRuns(ctx context.Context) ([]*EvalRun, error)
+	// List returns the IDs of all stored evaluations, in order.
+	List(ctx context.Context) ([]string, error)
+}
+
+// SaveEvaluatorResponse stores resp in store under the evaluation and run IDs
+// of req. It refuses to store the results of a dry run.
+func SaveEvaluatorResponse(ctx context.Context, store StoreEvaluatorResponse, req *EvaluatorRequest, resp *EvaluatorResponse) error {
+	if req.DryRun {
+		return errors.New("ai.SaveEvaluatorResponse: cannot save the results of a dry run")
+	}
+	if req.EvaluationId == "" {
+		return errors.New("ai.SaveEvaluatorResponse: evaluation ID is required")
+	}
+	return store.Save(ctx, req.RunId, req.EvaluationId, resp)
+}
+
+// FileEvaluationStore is a [StoreEvaluatorResponse] that keeps each
+// evaluation in a JSON file in a directory.
+type FileEvaluationStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// storedEvaluation is the on-disk format of a [FileEvaluationStore] entry.
+// The response is written in the current [SchemaVersion] and migrated from
+// earlier versions when read.
+type storedEvaluation struct {
+	EvalId   string            `json:"evalId"`
+	RunIds   []string          `json:"runIds,omitempty"`
+	Response EvaluatorResponse `json:"-"`
+	Run      *EvalRun          `json:"run,omitempty"`
+	Job      *EvalJobRecord    `json:"job,omitempty"`
+}
+
+// storedEvaluationJSON is the JSON encoding of a storedEvaluation.
+type storedEvaluationJSON struct {
+	*storedEvaluationFields
+	Response json.RawMessage `json:"response"`
+}
+
+type storedEvaluationFields storedEvaluation
+
+func (e *storedEvaluation) MarshalJSON() ([]byte, error) {
+	resp, err := encodeEvaluatorResponse(&e.Response)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(storedEvaluationJSON{(*storedEvaluationFields)(e), resp})
+}
+
+func (e *storedEvaluation) UnmarshalJSON(data []byte) error {
+	enc := storedEvaluationJSON{storedEvaluationFields: (*storedEvaluationFields)(e)}
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+	e.Response = nil
+	if len(enc.Response) == 0 || string(enc.Response) == "null" {
+		return nil
+	}
+	resp, err := MigrateEvaluatorResponse(enc.Response, "")
+	if err != nil {
+		return err
+	}
+	e.Response = *resp
+	return nil
+}
+
+// NewFileEvaluationStore returns a [FileEvaluationStore] that writes to dir,
+// creating it if necessary.
+func NewFileEvaluationStore(dir string) (*FileEvaluationStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ai.NewFileEvaluationStore: %w", err)
+	}
+	return &FileEvaluationStore{dir: dir}, nil
+}
+
+func (s *FileEvaluationStore) path(evalId string) string {
+	return filepath.Join(s.dir, base.Clean(evalId)+".json")
+}
+
+// Save implements [StoreEvaluatorResponse.Save].
+func (s *FileEvaluationStore) Save(ctx context.Context, runId, evalId string, resp *EvaluatorResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := storedEvaluation{EvalId: evalId}
+	prev, err := s.read(evalId)
+	if err == nil {
+		entry.RunIds = prev.RunIds
+		entry.Run = prev.Run
+		entry.Job = prev.Job
+	} else if !errors.Is(err, ErrEvaluationNotFound) {
+		return err
+	}
+	if runId != "" && !slices.Contains(entry.RunIds, runId) {
+		entry.RunIds = append(entry.RunIds, runId)
+	}
+	if resp != nil {
+		entry.Response = *resp
+	}
+	return s.write(&entry)
+}
+
+// Load implements [StoreEvaluatorResponse.Load].
+func (s *FileEvaluationStore) Load(ctx context.Context, evalId string) (*EvaluatorResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, err := s.read(evalId)
+	if err != nil {
+		return nil, err
+	}
+	return &entry.Response, nil
+}
+
+// LoadAllForRun implements [StoreEvaluatorResponse.LoadAllForRun].
+func (s *FileEvaluationStore) LoadAllForRun(ctx context.Context, runId string) ([]*EvaluatorResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	var resps []*EvaluatorResponse
+	for _, entry := range entries {
+		if slices.Contains(entry.RunIds, runId) {
+			resps = append(resps, &entry.Response)
+		}
+	}
+	return resps, nil
+}
+
+// SaveRun implements [StoreEvaluatorResponse.SaveRun].
+func (s *FileEvaluationStore) SaveRun(ctx context.Context, run *EvalRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.read(run.EvalId)
+	if errors.Is(err, ErrEvaluationNotFound) {
+		entry, err = &storedEvaluation{EvalId: run.EvalId}, nil
+	}
+	if err != nil {
+		return err
+	}
+	entry.Run = run
+	return s.write(entry)
+}
+
+// SaveJob implements [EvalJobStore.SaveJob].
+func (s *FileEvaluationStore) SaveJob(ctx context.Context, job *EvalJobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.read(job.EvaluationId)
+	if errors.Is(err, ErrEvaluationNotFound) {
+		entry, err = &storedEvaluation{EvalId: job.EvaluationId}, nil
+	}
+	if err != nil {
+		return err
+	}
+	entry.Job = job
+	return s.write(entry)
+}
+
+// LoadJob implements [EvalJobStore.LoadJob].
+func (s *FileEvaluationStore) LoadJob(ctx context.Context, evalId string) (*EvalJobRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, err := s.read(evalId)
+	if errors.Is(err, ErrEvaluationNotFound) {
+		return nil, fmt.Errorf("%w: no job for evaluation %q", ErrEvalJobNotFound, evalId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry.Job == nil {
+		return nil, fmt.Errorf("%w: no job for evaluation %q", ErrEvalJobNotFound, evalId)
+	}
+	return entry.Job, nil
+}
+
+// LoadRuns implements [StoreEvaluatorResponse.LoadRuns].
+func (s *FileEvaluationStore) LoadRuns(ctx context.Context) ([]*EvalRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	var runs []*EvalRun
+	for _, entry := range entries {
+		if entry.Run != nil {
+			runs = append(runs, entry.Run)
+		}
+	}
+	return runs, nil
+}
+
+// List implements [StoreEvaluatorResponse.List].
+func (s *FileEvaluationStore) List(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.EvalId
+	}
+	return ids, nil
+}
+
+func (s *FileEvaluationStore) read(evalId string) (*storedEvaluation, error) {
+	data, err := os.ReadFile(s.path(evalId))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %q", ErrEvaluationNotFound, evalId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry storedEvaluation
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("reading evaluation %q: %w", evalId, err)
+	}
+	return &entry, nil
+}
+
+// readAll returns all stored evaluations ordered by evaluation ID.
+func (s *FileEvaluationStore) readAll() ([]*storedEvaluation, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*storedEvaluation
+	for _, f := range files {
+		name, ok := strings.CutSuffix(f.Name(), ".json")
+		if f.IsDir() || !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var entry storedEvaluation
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("reading evaluation file %q: %w", name, err)
+		}
+		entries = append(entries, &entry)
+	}
+	slices.SortFunc(entries, func(a, b *storedEvaluation) int { return strings.Compare(a.EvalId, b.EvalId) })
+	return entries, nil
+}
+
+func (s *FileEvaluationStore) write(entry *storedEvaluation) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so readers never see a partial entry.
+	tmp := s.path(entry.EvalId) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(entry.EvalId))
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// InMemoryEvaluationStore is a [StoreEvaluatorResponse] that keeps
+// evaluations in memory. It is safe for concurrent use.
+type InMemoryEvaluationStore struct {
+	mu      sync.RWMutex
+	entries map[string]*storedEvaluation
+}
+
+// NewInMemoryEvaluationStore returns an empty [InMemoryEvaluationStore].
+func NewInMemoryEvaluationStore() *InMemoryEvaluationStore {
+	return &InMemoryEvaluationStore{entries: map[string]*storedEvaluation{}}
+}
+
+// Save implements [StoreEvaluatorResponse.Save].
+func (s *InMemoryEvaluationStore) Save(ctx context.Context, runId, evalId string, resp *EvaluatorResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[evalId]
+	if !ok {
+		entry = &storedEvaluation{EvalId: evalId}
+		s.entries[evalId] = entry
+	}
+	if runId != "" && !slices.Contains(entry.RunIds, runId) {
+		entry.RunIds = append(entry.RunIds, runId)
+	}
+	entry.Response = nil
+	if resp != nil {
+		entry.Response = cloneResponse(*resp)
+	}
+	return nil
+}
+
+// Load implements [StoreEvaluatorResponse.Load].
+func (s *InMemoryEvaluationStore) Load(ctx context.Context, evalId string) (*EvaluatorResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[evalId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrEvaluationNotFound, evalId)
+	}
+	resp := cloneResponse(entry.Response)
+	return &resp, nil
+}
+
+// LoadAllForRun implements [StoreEvaluatorResponse.LoadAllForRun].
+func (s *InMemoryEvaluationStore) LoadAllForRun(ctx context.Context, runId string) ([]*EvaluatorResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var resps []*EvaluatorResponse
+	for _, entry := range s.sorted() {
+		if slices.Contains(entry.RunIds, runId) {
+			resp := cloneResponse(entry.Response)
+			resps = append(resps, &resp)
+		}
+	}
+	return resps, nil
+}
+
+// SaveRun implements [StoreEvaluatorResponse.SaveRun].
+func (s *InMemoryEvaluationStore) SaveRun(ctx context.Context, run *EvalRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[run.EvalId]
+	if !ok {
+		entry = &storedEvaluation{EvalId: run.EvalId}
+		s.entries[run.EvalId] = entry
+	}
+	r := *run
+	entry.Run = &r
+	return nil
+}
+
+// SaveJob implements [EvalJobStore.SaveJob].
+func (s *InMemoryEvaluationStore) SaveJob(ctx context.Context, job *EvalJobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[job.EvaluationId]
+	if !ok {
+		entry = &storedEvaluation{EvalId: job.EvaluationId}
+		s.entries[job.EvaluationId] = entry
+	}
+	j := *job
+	entry.Job = &j
+	return nil
+}
+
+// LoadJob implements [EvalJobStore.LoadJob].
+func (s *InMemoryEvaluationStore) LoadJob(ctx context.Context, evalId string) (*EvalJobRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[evalId]
+	if !ok || entry.Job == nil {
+		return nil, fmt.Errorf("%w: no job for evaluation %q", ErrEvalJobNotFound, evalId)
+	}
+	j := *entry.Job
+	return &j, nil
+}
+
+// LoadRuns implements [StoreEvaluatorResponse.LoadRuns].
+func (s *InMemoryEvaluationStore) LoadRuns(ctx context.Context) ([]*EvalRun, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var runs []*EvalRun
+	for _, entry := range s.sorted() {
+		if entry.Run != nil {
+			r := *entry.Run
+			runs = append(runs, &r)
+		}
+	}
+	return runs, nil
+}
+
+// List implements [StoreEvaluatorResponse.List].
+func (s *InMemoryEvaluationStore) List(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Sorted(maps.Keys(s.entries)), nil
+}
+
+// Clear removes all stored evaluations and runs.
+func (s *InMemoryEvaluationStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.entries)
+}
+
+// sorted returns the stored evaluations ordered by evaluation ID.
+// The caller must hold s.mu.
+func (s *InMemoryEvaluationStore) sorted() []*storedEvaluation {
+	entries := make([]*storedEvaluation, 0, len(s.entries))
+	for _, id := range slices.Sorted(maps.Keys(s.entries)) {
+		entries = append(entries, s.entries[id])
+	}
+	return entries
+}
+
+// cloneResponse returns a copy of resp that shares no scores, annotations or
+// details maps with it, so that stored evaluations are not changed through
+// the responses saved or loaded. The values in the details maps are not
+// copied.
+func cloneResponse(resp EvaluatorResponse) EvaluatorResponse {
+	if resp == nil {
+		return nil
+	}
+	out := make(EvaluatorResponse, len(resp))
+	for i, res := range resp {
+		res.Evaluation = slices.Clone(res.Evaluation)
+		for j := range res.Evaluation {
+			cloneScore(&res.Evaluation[j])
+		}
+		res.HumanAnnotation = slices.Clone(res.HumanAnnotation)
+		for j := range res.HumanAnnotation {
+			cloneScore(&res.HumanAnnotation[j].Score)
+		}
+		out[i] = res
+	}
+	return out
+}
+
+// cloneScore replaces the details and confidence interval of s by copies.
+func cloneScore(s *Score) {
+	s.Details = maps.Clone(s.Details)
+	if s.ConfidenceInterval != nil {
+		ci := *s.ConfidenceInterval
+		s.ConfidenceInterval = &ci
+	}
+}
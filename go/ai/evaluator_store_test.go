@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestFileEvaluationStore(t *testing.T) {
+	store, err := NewFileEvaluationStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testEvaluationStore(t, store)
+}
+
+func TestInMemoryEvaluationStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryEvaluationStore()
+	testEvaluationStore(t, store)
+
+	// Callers cannot modify stored responses through loaded ones.
+	got, err := store.Load(ctx, "eval-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	(*got)[0].TestCaseId = "changed"
+	(*got)[0].Evaluation[0].Status = "changed"
+	if got, _ := store.Load(ctx, "eval-b"); (*got)[0].TestCaseId != "1" || (*got)[0].Evaluation[0].Status == "changed" {
+		t.Errorf("stored response was modified through a loaded copy")
+	}
+
+	// Nor through saved ones.
+	saved := EvaluatorResponse{{
+		TestCaseId: "d",
+		Evaluation: []Score{{Id: "s", Score: 1.0, Details: map[string]any{"reasoning": "ok"}}},
+	}}
+	if err := store.Save(ctx, "", "eval-details", &saved); err != nil {
+		t.Fatal(err)
+	}
+	saved[0].Evaluation[0].Details["reasoning"] = "changed"
+	saved[0].Evaluation[0].Score = 0.0
+	got, err = store.Load(ctx, "eval-details")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := (*got)[0].Evaluation[0]; s.Score != 1.0 || s.Details["reasoning"] != "ok" {
+		t.Errorf("got score %+v, want the score as saved", s)
+	}
+	(*got)[0].Evaluation[0].Details["reasoning"] = "changed"
+	if got, _ := store.Load(ctx, "eval-details"); (*got)[0].Evaluation[0].Details["reasoning"] != "ok" {
+		t.Errorf("stored details were modified through a loaded copy")
+	}
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := EvaluatorResponse{passFail("x", true, 1)}
+			if err := store.Save(ctx, "concurrent", fmt.Sprint("eval-", i), &resp); err != nil {
+				t.Error(err)
+			}
+			if _, err := store.LoadAllForRun(ctx, "concurrent"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if run, _ := store.LoadAllForRun(ctx, "concurrent"); len(run) != 10 {
+		t.Errorf("got %d evaluations in run, want 10", len(run))
+	}
+
+	store.Clear()
+	if ids, _ := store.List(ctx); len(ids) != 0 {
+		t.Errorf("got %v after Clear, want no evaluations", ids)
+	}
+}
+
+// testEvaluationStore checks the behavior common to all implementations of
+// [StoreEvaluatorResponse]. It leaves evaluations "eval-a", "eval-b" and
+// "eval-c" in store.
+func testEvaluationStore(t *testing.T, store StoreEvaluatorResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	modelA := EvaluatorResponse{passFail("1", true, 1)}
+	modelB := EvaluatorResponse{passFail("1", false, 0)}
+	other := EvaluatorResponse{passFail("2", true, 1)}
+	for _, s := range []struct {
+		runId, evalId string
+		resp          EvaluatorResponse
+	}{
+		{"run1", "eval-a", modelA},
+		{"run1", "eval-b", modelB},
+		{"run2", "eval-c", other},
+	} {
+		if err := store.Save(ctx, s.runId, s.evalId, &s.resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.Load(ctx, "eval-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := (*got)[0].Evaluation[0].Status, "fail"; got != want {
+		t.Errorf("got status %v, want %v", got, want)
+	}
+
+	run, err := store.LoadAllForRun(ctx, "run1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(run), 2; got != want {
+		t.Fatalf("got %d evaluations in run, want %d", got, want)
+	}
+	if got, want := (*run[0])[0].Evaluation[0].Status, "pass"; got != want {
+		t.Errorf("got status %v, want %v", got, want)
+	}
+
+	// Saving without a run ID keeps the existing run membership.
+	if err := store.Save(ctx, "", "eval-a", &other); err != nil {
+		t.Fatal(err)
+	}
+	if run, err := store.LoadAllForRun(ctx, "run1"); err != nil || len(run) != 2 {
+		t.Errorf("got %d evaluations in run (err %v), want 2", len(run), err)
+	}
+
+	if _, err := store.Load(ctx, "missing"); !errors.Is(err, ErrEvaluationNotFound) {
+		t.Errorf("got %v, want ErrEvaluationNotFound", err)
+	}
+
+	if err := store.SaveRun(ctx, &EvalRun{EvalId: "eval-c", ModelVersion: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if runs, err := store.LoadRuns(ctx); err != nil || len(runs) != 1 || runs[0].ModelVersion != "v2" {
+		t.Errorf("got runs %v (err %v), want the run of eval-c", runs, err)
+	}
+
+	ids, err := store.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"eval-a", "eval-b", "eval-c"}, ids); diff != "" {
+		t.Errorf("List mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEvaluateRunIdSpanAttribute(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	r.RegisterSpanProcessor(recorder)
+
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, testEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Evaluate(context.Background(), evalAction,
+		WithEvaluateDataset(&dataset),
+		WithEvaluateId("eval1"),
+		WithEvaluateRunId("run1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := attribute.String("genkit:metadata:evaluator:runId", "run1")
+	spans := recorder.Ended()
+	if len(spans) == 0 {
+		t.Fatal("no spans recorded")
+	}
+	for _, span := range spans {
+		found := false
+		for _, attr := range span.Attributes() {
+			if attr == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("span %q is missing attribute %v", span.Name(), want)
+		}
+	}
+}
+
+func TestEvaluateAnnotations(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	r.RegisterSpanProcessor(recorder)
+
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, testEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batchAction, err := DefineBatchEvaluator(r, "test", "testBatchEvaluator", &evalOptions, testBatchEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ann := map[string]any{"gpu": "a100", "replicas": 2}
+	for _, e := range []Evaluator{evalAction, batchAction} {
+		resp, err := EvaluateRun(context.Background(), e,
+			WithEvaluateDataset(&dataset),
+			WithEvaluateAnnotations(ann))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ann, resp.Summary.Annotations); diff != "" {
+			t.Errorf("%s: Summary.Annotations mismatch (-want +got):\n%s", e.Name(), diff)
+		}
+	}
+
+	wants := []attribute.KeyValue{
+		attribute.String("genkit:metadata:evaluator:annotation:gpu", "a100"),
+		attribute.String("genkit:metadata:evaluator:annotation:replicas", "2"),
+	}
+	roots := 0
+	for _, span := range recorder.Ended() {
+		if span.Parent().IsValid() {
+			continue
+		}
+		roots++
+		attrs := span.Attributes()
+		for _, want := range wants {
+			if !slices.Contains(attrs, want) {
+				t.Errorf("span %q is missing attribute %v", span.Name(), want)
+			}
+		}
+	}
+	if roots != 2 {
+		t.Errorf("got %d root spans, want 2", roots)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// DefineBatchEvaluatorStream registers an evaluator whose callback runs as
+// a pipeline stage, for example to compare examples tournament-style or to
+// chain evaluators. fn receives the examples of the dataset on examples,
+// each with a TestCaseId, and sends their results on results in any order
+// and at any pace. examples is closed after the last example. fn must not
+// send on results after it returns; results is closed then. Nil results are
+// ignored.
+//
+// The response holds the results in the order they were sent. fn may
+// return without reading every example. If fn returns an error, the
+// evaluation fails with it.
+func DefineBatchEvaluatorStream(r *registry.Registry, provider, name string, opts *EvaluatorOptions, fn func(ctx context.Context, examples <-chan Example, results chan<- *EvaluationResult) error) (Evaluator, error) {
+	if fn == nil {
+		return nil, errors.New("ai.DefineBatchEvaluatorStream: fn is required")
+	}
+	return DefineBatchEvaluator(r, provider, name, opts, func(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
+		var ds Dataset
+		if req.Dataset != nil {
+			ds = withTestCaseIds(*req.Dataset)
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		examples := make(chan Example)
+		go func() {
+			defer close(examples)
+			for _, ex := range ds {
+				select {
+				case examples <- ex:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		results := make(chan *EvaluationResult)
+		errc := make(chan error, 1)
+		go func() {
+			defer close(results)
+			errc <- fn(ctx, examples, results)
+		}()
+
+		resp := EvaluatorResponse{}
+		for res := range results {
+			if res != nil {
+				resp = append(resp, *res)
+			}
+		}
+		if err := <-errc; err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestBatchEvaluatorStream(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Three workers score examples concurrently.
+	e, err := DefineBatchEvaluatorStream(r, "test", "workers", &evalOptions, func(ctx context.Context, examples <-chan Example, results chan<- *EvaluationResult) error {
+		var wg sync.WaitGroup
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for ex := range examples {
+					res := passFail(ex.TestCaseId, ex.Input == "good", 1)
+					results <- &res
+				}
+			}()
+		}
+		wg.Wait()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := Dataset{{TestCaseId: "a", Input: "good"}, {TestCaseId: "b", Input: "bad"}, {Input: "good"}, {TestCaseId: "d", Input: "good"}}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*resp) != len(ds) {
+		t.Fatalf("got %d results, want %d", len(*resp), len(ds))
+	}
+	results := indexResults(resp)
+	if results["a"].Evaluation[0].Status != "pass" || results["b"].Evaluation[0].Status != "fail" {
+		t.Errorf("got results %+v, want a to pass and b to fail", results)
+	}
+	if _, ok := results[""]; ok {
+		t.Error("got a result without TestCaseId")
+	}
+
+	// A stage that stops after the first example.
+	first, err := DefineBatchEvaluatorStream(r, "test", "first", &evalOptions, func(ctx context.Context, examples <-chan Example, results chan<- *EvaluationResult) error {
+		ex := <-examples
+		results <- nil
+		results <- &EvaluationResult{TestCaseId: ex.TestCaseId, Evaluation: []Score{}}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = Evaluate(context.Background(), first, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*resp) != 1 || (*resp)[0].TestCaseId != "a" {
+		t.Errorf("got %+v, want only the result of a", *resp)
+	}
+
+	failing, err := DefineBatchEvaluatorStream(r, "test", "failing", &evalOptions, func(ctx context.Context, examples <-chan Example, results chan<- *EvaluationResult) error {
+		return errors.New("pipeline broke")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Evaluate(context.Background(), failing, WithEvaluateDataset(&ds)); err == nil {
+		t.Error("got nil, want error from the pipeline")
+	}
+}
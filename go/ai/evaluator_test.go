@@ -16,11 +16,18 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/firebase/genkit/go/internal/registry"
+	"github.com/google/go-cmp/cmp"
 )
 
 var testEvalFunc = func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
@@ -138,6 +145,224 @@ func TestOptionsRequired(t *testing.T) {
 	}
 }
 
+func TestNewEvaluatorOptions(t *testing.T) {
+	got := NewEvaluatorOptions(
+		WithDisplayName("Test Evaluator"),
+		WithDefinition("Returns pass score for all"),
+		WithIsBilled(true),
+		WithMaxDatasetSize(10),
+		WithRequiredFields("Output"),
+	)
+	want := &EvaluatorOptions{
+		DisplayName:    "Test Evaluator",
+		Definition:     "Returns pass score for all",
+		IsBilled:       true,
+		MaxDatasetSize: 10,
+		RequiredFields: []string{"Output"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMaxDatasetSize(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	opts := evalOptions
+	opts.MaxDatasetSize = 1
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		called = true
+		return testEvalFunc(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = evalAction.Evaluate(context.Background(), &testRequest)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "2 examples") || !strings.Contains(err.Error(), "maximum of 1") {
+		t.Errorf("error %q does not report the dataset size and maximum", err)
+	}
+	if called {
+		t.Error("evaluator was called for an oversized dataset")
+	}
+}
+
+func TestRequiredFields(t *testing.T) {
+	full := Example{
+		TestCaseId: "full",
+		Input:      "q",
+		Output:     "a",
+		Context:    []any{"c"},
+		Reference:  "r",
+		TraceIds:   []string{"t"},
+	}
+	tests := []struct {
+		name    string
+		fields  []string
+		example func(ex *Example)
+		missing string
+	}{
+		{"Input", []string{"Input"}, func(ex *Example) { ex.Input = nil }, "Input"},
+		{"Output", []string{"Output"}, func(ex *Example) { ex.Output = "" }, "Output"},
+		{"Context", []string{"Context"}, func(ex *Example) { ex.Context = nil }, "Context"},
+		{"Reference", []string{"Reference"}, func(ex *Example) { ex.Reference = nil }, "Reference"},
+		{"TraceIds", []string{"TraceIds"}, func(ex *Example) { ex.TraceIds = []string{} }, "TraceIds"},
+		{"all set", []string{"Input", "Output", "Context", "Reference", "TraceIds"}, func(*Example) {}, ""},
+		{"first missing of several", []string{"Input", "Reference", "Context"}, func(ex *Example) { ex.Reference, ex.Context = nil, nil }, "Reference"},
+		{"unrequired field missing", []string{"Output"}, func(ex *Example) { ex.Reference = nil }, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := registry.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := NewEvaluatorOptions(WithRequiredFields(test.fields...))
+			e, err := DefineEvaluator(r, "test", "testEvaluator", opts, testEvalFunc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ex := full
+			test.example(&ex)
+			resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&Dataset{ex}))
+			score := (*resp)[0].Evaluation[0]
+			if test.missing == "" {
+				if err != nil || score.Status != ScoreStatusPass.String() {
+					t.Errorf("got status %q, error %v; want pass", score.Status, err)
+				}
+				return
+			}
+			if score.Status != ScoreStatusFail.String() || !strings.Contains(score.Error, "missing required field: "+test.missing) {
+				t.Errorf("got status %q, error %q; want failure for missing %s", score.Status, score.Error, test.missing)
+			}
+			if !errors.As(err, new(EvaluatorError)) {
+				t.Errorf("got error %v, want EvaluatorError", err)
+			}
+		})
+	}
+
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DefineEvaluator(r, "test", "testEvaluator", NewEvaluatorOptions(WithRequiredFields("Answer")), testEvalFunc); err == nil {
+		t.Error("got nil, want error for unknown field")
+	}
+}
+
+func TestDatasetSchema(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := evalOptions
+	opts.DatasetSchema = []byte(`{
+		"type": "object",
+		"required": ["input", "reference"],
+		"properties": {
+			"input": {"type": "string"},
+			"reference": {"type": "object", "required": ["label"]}
+		}
+	}`)
+	var evaluated []string
+	e, err := DefineEvaluator(r, "test", "schemaEvaluator", &opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		evaluated = append(evaluated, req.Input.TestCaseId)
+		return testEvalFunc(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "valid", Input: "q", Reference: map[string]any{"label": "yes"}},
+		{TestCaseId: "invalid", Input: 42, Reference: map[string]any{}},
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if !errors.As(err, new(EvaluatorError)) {
+		t.Errorf("got error %v, want EvaluatorError", err)
+	}
+	if want := []string{"valid"}; !slices.Equal(evaluated, want) {
+		t.Errorf("evaluated %v, want %v", evaluated, want)
+	}
+	results := indexResults(resp)
+	if got := results["valid"].Evaluation[0].Status; got != ScoreStatusPass.String() {
+		t.Errorf("valid: got status %q, want pass", got)
+	}
+	score := results["invalid"].Evaluation[0]
+	violations, _ := score.Details["schemaViolations"].([]string)
+	if score.Status != ScoreStatusFail.String() || len(violations) != 2 {
+		t.Fatalf("invalid: got status %q and violations %v, want failure with 2 violations", score.Status, violations)
+	}
+	for i, field := range []string{"input", "label"} {
+		if !strings.Contains(violations[i], field) {
+			t.Errorf("violation %q does not mention %s", violations[i], field)
+		}
+	}
+
+	opts.DatasetSchema = []byte(`{"type": 1}`)
+	if _, err := DefineEvaluator(r, "test", "badSchema", &opts, testEvalFunc); err == nil {
+		t.Error("got nil, want error for invalid schema")
+	}
+}
+
+func TestEvaluatorTransformers(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := evalOptions
+	opts.InputTransformer = func(ctx context.Context, input any) (any, error) {
+		s, ok := input.(string)
+		if !ok {
+			return nil, fmt.Errorf("input %v is not a string", input)
+		}
+		return strings.ToUpper(s), nil
+	}
+	opts.OutputTransformer = func(ctx context.Context, output any) (any, error) {
+		var v any
+		err := json.Unmarshal([]byte(output.(string)), &v)
+		return v, err
+	}
+	var got []Example
+	e, err := DefineEvaluator(r, "test", "transformed", &opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		got = append(got, req.Input)
+		return &EvaluatorCallbackResponse{TestCaseId: req.Input.TestCaseId, Evaluation: []Score{{Score: 1, Status: "pass"}}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "ok", Input: "hello", Output: `{"a": 1}`},
+		{TestCaseId: "badInput", Input: 42, Output: `{}`},
+		{TestCaseId: "badOutput", Input: "hi", Output: `{`},
+	}
+	resp, err := Evaluate(context.Background(), e, WithEvaluateDataset(&ds))
+	if err == nil {
+		t.Error("got nil, want error for failed transformations")
+	}
+	if len(got) != 1 || got[0].Input != "HELLO" || got[0].Output.(map[string]any)["a"] != 1.0 {
+		t.Fatalf("callback got %+v, want one transformed example", got)
+	}
+	if ds[0].Input != "hello" {
+		t.Errorf("dataset was modified: %+v", ds[0])
+	}
+	results := indexResults(resp)
+	for id, want := range map[string]string{"badInput": "transforming input", "badOutput": "transforming output"} {
+		s := results[id].Evaluation[0]
+		if s.Status != ScoreStatusFail.String() || !strings.Contains(s.Error, want) {
+			t.Errorf("%s: got %+v, want failure mentioning %q", id, s, want)
+		}
+	}
+}
+
 func TestFailingEvaluator(t *testing.T) {
 	r, err := registry.New()
 	if err != nil {
@@ -150,8 +375,34 @@ func TestFailingEvaluator(t *testing.T) {
 	}
 
 	resp, err := evalAction.Evaluate(context.Background(), &testRequest)
-	if err != nil {
-		t.Fatal(err)
+	var evalErr EvaluatorError
+	if !errors.As(err, &evalErr) {
+		t.Fatalf("got error %v, want EvaluatorError", err)
+	}
+	if got, want := evalErr.EvaluatorName, "test/testEvaluator"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := evalErr.TestCaseId, (*resp)[0].TestCaseId; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := evalErr.Cause.Error(), "i give up"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var multiErr *MultiEvaluatorError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("got error %v, want MultiEvaluatorError", err)
+	}
+	if got, want := len(multiErr.Errors), len(*resp); got != want {
+		t.Fatalf("got %d errors, want one per example (%d)", got, want)
+	}
+	for i, e := range multiErr.Errors {
+		if e.TestCaseId != (*resp)[i].TestCaseId {
+			t.Errorf("error %d: got test case %q, want %q", i, e.TestCaseId, (*resp)[i].TestCaseId)
+		}
+	}
+	if want := fmt.Sprintf("%d examples failed", len(*resp)); !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("got error %q, want prefix %q", err, want)
 	}
 
 	if got, dontWant := (*resp)[0].Evaluation[0].Error, ""; got == dontWant {
@@ -210,6 +461,9 @@ func TestLookupEvaluator(t *testing.T) {
 	if got, want := LookupEvaluator(r, "test", "testBatchEvaluator"), batchEvalAction; got != want {
 		t.Errorf("got %v, want %v", got, want)
 	}
+	if got := LookupEvaluator(r, "test", "missing"); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
 }
 
 func TestEvaluate(t *testing.T) {
@@ -277,3 +531,429 @@ func TestBatchEvaluator(t *testing.T) {
 		t.Errorf("got %v, want %v", got, want)
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, testFailingEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := EvaluateRun(context.Background(), evalAction, WithEvaluateDataset(&dataset), WithEvaluateDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsDryRun {
+		t.Error("got IsDryRun false, want true")
+	}
+	if got, want := len(resp.Results), 2; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := len(resp.Results[0].Evaluation), 0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var gotDryRun bool
+	batchAction, err := DefineBatchEvaluator(r, "test", "testBatchEvaluator", &evalOptions, func(ctx context.Context, req *EvaluatorRequest) (*EvaluatorResponse, error) {
+		gotDryRun = req.DryRun
+		return testBatchEvalFunc(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = EvaluateRun(context.Background(), batchAction, WithEvaluateDataset(&dataset), WithEvaluateDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotDryRun || !resp.IsDryRun {
+		t.Errorf("dry run flag was not propagated: callback saw %v, response has %v", gotDryRun, resp.IsDryRun)
+	}
+
+	req := &EvaluatorRequest{EvaluationId: "e", DryRun: true}
+	if err := SaveEvaluatorResponse(context.Background(), nil, req, &resp.Results); err == nil {
+		t.Error("got nil, want error saving a dry run")
+	}
+}
+
+func TestEvaluateCancellation(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu      sync.Mutex
+		running int
+	)
+	slowEvalFunc := func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		mu.Lock()
+		running++
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		if req.Input.Input == "fast" {
+			return testEvalFunc(ctx, req)
+		}
+		select {
+		case <-time.After(time.Second):
+			return testEvalFunc(ctx, req)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	evalAction, err := DefineEvaluator(r, "test", "slowEvaluator", &evalOptions, slowEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{Input: "fast"}, {Input: "slow"}, {Input: "slow"}, {Input: "slow"}}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	resp, err := Evaluate(ctx, evalAction, WithEvaluateDataset(&ds))
+	mu.Lock()
+	stillRunning := running
+	mu.Unlock()
+	if stillRunning != 0 {
+		t.Errorf("%d evaluations still running after Evaluate returned", stillRunning)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Evaluate took %v after cancellation", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+
+	if resp == nil {
+		t.Fatal("got nil response, want partial results")
+	}
+	if got, want := len(*resp), 2; got != want {
+		t.Fatalf("got %d results, want %d", got, want)
+	}
+	if got, want := (*resp)[0].Evaluation[0].Status, "pass"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := (*resp)[1].Evaluation[0].Status, "fail"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDedupStrategy(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "input", Score: req.Input.Input}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "a", Input: "a1"},
+		{TestCaseId: "b", Input: "b1"},
+		{TestCaseId: "a", Input: "a2"},
+		{Input: "none"},
+		{TestCaseId: "a", Input: "a3"},
+		{Input: "none"},
+	}
+	tests := []struct {
+		strategy DedupStrategy
+		want     []any
+		removed  int
+	}{
+		{DedupStrategyNone, []any{"a1", "b1", "a2", "none", "a3", "none"}, 0},
+		{DedupStrategyKeepFirst, []any{"a1", "b1", "none", "none"}, 2},
+		{DedupStrategyKeepLast, []any{"b1", "none", "a3", "none"}, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.strategy.String(), func(t *testing.T) {
+			resp, err := EvaluateRun(context.Background(), evalAction, WithEvaluateDataset(&ds), WithEvaluateDedupStrategy(test.strategy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []any
+			for _, res := range resp.Results {
+				got = append(got, res.Evaluation[0].Score)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if got := resp.Summary.DuplicatesRemoved; got != test.removed {
+				t.Errorf("got %d duplicates removed, want %d", got, test.removed)
+			}
+		})
+	}
+}
+
+func TestExcludeTestCaseIds(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, testEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "y"}, {TestCaseId: "c", Input: "z"}}
+	resp, err := EvaluateRun(context.Background(), evalAction, WithEvaluateDataset(&ds), WithEvaluateExcludeIds("b", "missing"), WithEvaluateExcludeIds("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(resp.Results), 1; got != want {
+		t.Fatalf("got %d results, want %d", got, want)
+	}
+	if got, want := resp.Results[0].TestCaseId, "a"; got != want {
+		t.Errorf("got test case %q, want %q", got, want)
+	}
+	if got, want := resp.Summary.ExcludedExamples, 2; got != want {
+		t.Errorf("got %d excluded examples, want %d", got, want)
+	}
+}
+
+func TestStopAfterFailures(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Odd inputs fail with a score and "boom" fails with an error.
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Input == "boom" {
+			return nil, errors.New("boom")
+		}
+		pass := req.Input.Input.(int)%2 == 0
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "even", Score: pass, Status: passStatus(pass).String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{Input: 0}, {Input: 1}, {Input: 2}, {Input: "boom"}, {Input: 4}, {Input: 5}, {Input: 7}}
+	tests := []struct {
+		limit, results int
+		stopped        bool
+	}{
+		{1, 2, true},
+		{2, 4, true},
+		{3, 6, true},
+		{4, 7, false},
+		{0, 7, false},
+	}
+	for _, test := range tests {
+		resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds), WithEvaluateStopAfterFailures(test.limit))
+		if got := errors.Is(err, ErrStoppedEarly); got != test.stopped {
+			t.Errorf("limit %d: got error %v, want stopped early %v", test.limit, err, test.stopped)
+		}
+		if got := len(*resp); got != test.results {
+			t.Errorf("limit %d: got %d results, want %d", test.limit, got, test.results)
+		}
+	}
+}
+
+func TestRequireAllExamples(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int
+	evalAction, err := DefineEvaluator(r, "test", "testEvaluator", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		calls++
+		if req.Input.Input == "boom" {
+			return nil, errors.New("boom")
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "ok", Score: true, Status: ScoreStatusPass.String()}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "boom"}, {TestCaseId: "c", Input: "y"}}
+	resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds), WithEvaluateRequireAllExamples(true))
+	if resp != nil {
+		t.Errorf("got response %v, want nil", resp)
+	}
+	var reqErr *RequiredExampleError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("got error %v, want RequiredExampleError", err)
+	}
+	if reqErr.TestCaseId != "b" || reqErr.Cause.Error() != "boom" {
+		t.Errorf("got failure of %q with %v, want b with boom", reqErr.TestCaseId, reqErr.Cause)
+	}
+	if got := reqErr.Partial; len(got) != 1 || got[0].TestCaseId != "a" {
+		t.Errorf("got partial results %v, want the result for a", got)
+	}
+	if calls != 2 {
+		t.Errorf("got %d evaluator calls, want 2", calls)
+	}
+
+	// Without the option, the failure is recorded and evaluation continues.
+	resp, err = Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds))
+	if !errors.As(err, new(EvaluatorError)) {
+		t.Errorf("got error %v, want EvaluatorError", err)
+	}
+	if got := len(*resp); got != 3 {
+		t.Errorf("got %d results, want 3", got)
+	}
+}
+
+func TestPartialCredit(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := evalOptions
+	opts.SetPartialCreditThresholds(0.7, 0.4)
+	evalAction, err := DefineEvaluator(r, "test", "partialEvaluator", &opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		scores := req.Input.Reference.([]any)
+		res := &EvaluatorCallbackResponse{TestCaseId: req.Input.TestCaseId}
+		for i, s := range scores {
+			res.Evaluation = append(res.Evaluation, Score{Id: fmt.Sprint(i), Score: s, Status: ScoreStatusPass.String()})
+		}
+		return res, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "pass", Reference: []any{1, 0.5}},
+		{TestCaseId: "unknown", Reference: []any{true, 0, 0.5}},
+		{TestCaseId: "fail", Reference: []any{0.2, "n/a"}},
+	}
+	resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct {
+		credit float64
+		status ScoreStatus
+	}{
+		"pass":    {0.75, ScoreStatusPass},
+		"unknown": {0.5, ScoreStatusUnknown},
+		"fail":    {0.2, ScoreStatusFail},
+	}
+	for id, res := range indexResults(resp) {
+		if got := res.PartialCredit; math.Abs(got-want[id].credit) > 1e-9 {
+			t.Errorf("%s: got partial credit %v, want %v", id, got, want[id].credit)
+		}
+		if got := res.PartialCreditStatus; got != want[id].status.String() {
+			t.Errorf("%s: got partial credit status %q, want %q", id, got, want[id].status)
+		}
+		if got := resultStatus(res); got != want[id].status {
+			t.Errorf("%s: got result status %v, want %v", id, got, want[id].status)
+		}
+		// The statuses set by the evaluator are kept.
+		for _, score := range res.Evaluation {
+			if score.Status != ScoreStatusPass.String() {
+				t.Errorf("%s: got score %s status %q, want %q", id, score.Id, score.Status, ScoreStatusPass)
+			}
+		}
+	}
+
+	opts.SetPartialCreditThresholds(0.3, 0.6)
+	if _, err := DefineEvaluator(r, "test", "invalidThresholds", &opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		return nil, nil
+	}); err == nil {
+		t.Error("got nil, want error for fail threshold above pass threshold")
+	}
+}
+
+type thresholdOptions struct {
+	Threshold float64 `json:"threshold" jsonschema:"description=Minimum passing score,minimum=0,maximum=1"`
+	Mode      string  `json:"mode,omitempty" jsonschema:"enum=strict,enum=lenient"`
+}
+
+func TestDefineEvaluatorTyped(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineEvaluatorTyped(r, "test", "typedEvaluator", &evalOptions, func(ctx context.Context, req *EvaluatorCallbackRequest, opts thresholdOptions) (*EvaluatorCallbackResponse, error) {
+		if _, ok := req.Options.(thresholdOptions); !ok {
+			return nil, fmt.Errorf("request options have type %T", req.Options)
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{{Id: "threshold", Score: opts.Threshold, Details: map[string]any{"mode": opts.Mode}}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		schema := (*evaluatorAction)(evalAction.(*evaluatorActionDef)).Desc().InputSchema
+		opts, ok := schema.Properties.Get("options")
+		if !ok {
+			t.Fatal("input schema has no options property")
+		}
+		b, err := json.Marshal(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"threshold": map[string]any{"type": "number", "description": "Minimum passing score", "minimum": 0.0, "maximum": 1.0},
+				"mode":      map[string]any{"type": "string", "enum": []any{"strict", "lenient"}},
+			},
+			"required":             []any{"threshold"},
+			"additionalProperties": false,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("options schema mismatch (-want +got):\n%s", diff)
+		}
+		if _, ok := schema.Properties.Get("dataset"); !ok {
+			t.Error("input schema has no dataset property")
+		}
+	})
+
+	t.Run("options decoded", func(t *testing.T) {
+		ds := Dataset{{TestCaseId: "a", Input: "x"}}
+		resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{
+			Dataset: &ds,
+			Options: map[string]any{"threshold": 0.5, "mode": "strict"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		score := (*resp)[0].Evaluation[0]
+		if score.Score != 0.5 || score.Details["mode"] != "strict" {
+			t.Errorf("got score %v with details %v, want 0.5 with mode strict", score.Score, score.Details)
+		}
+	})
+
+	t.Run("options validated", func(t *testing.T) {
+		action := (*evaluatorAction)(evalAction.(*evaluatorActionDef))
+		input := `{"dataset": [{"testCaseId": "a", "input": "x"}], "evalRunId": "r", "options": {"threshold": 2}}`
+		if _, err := action.RunJSON(context.Background(), json.RawMessage(input), nil); err == nil {
+			t.Error("got nil error for out of range threshold, want error")
+		}
+	})
+}
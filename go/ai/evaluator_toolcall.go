@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// toolCallTolerance is the relative tolerance used when comparing numeric
+// tool call arguments.
+const toolCallTolerance = 1e-6
+
+// DefineToolCallAccuracyEvaluator registers an evaluator named
+// "tool_call_accuracy" that compares the tool calls in the Output of each
+// [Example] against its ExpectedToolCalls.
+//
+// Output may be a []*ToolRequest, a []ToolRequest, a *ModelResponse, or any
+// value (including a JSON string) that decodes to a list of tool requests.
+// Each expected call is matched with the first unused actual call of the same
+// name and scored on the name match, the fraction of expected argument keys
+// present (coverage) and the fraction of expected argument values that are
+// equal (accuracy). Numbers are compared with a small relative tolerance.
+//
+// The evaluator returns a "tool_call_accuracy" score averaging the per-call
+// scores over the larger of the expected and actual call counts, so missing
+// and extra calls both lower the score, followed by one "tool_call_<i>" score
+// per expected call. An example passes if every call matches exactly.
+// If opts is nil, default options are used.
+func DefineToolCallAccuracyEvaluator(r *registry.Registry, provider string, opts *EvaluatorOptions) (Evaluator, error) {
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Tool Call Accuracy",
+			Definition:  "Compares the tool calls made by a model against the expected tool calls",
+		}
+	}
+
+	return DefineEvaluator(r, provider, "tool_call_accuracy", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		expected := req.Input.ExpectedToolCalls
+		if len(expected) == 0 {
+			return nil, errors.New("expected tool calls were not provided")
+		}
+		actual, err := parseToolCalls(req.Input.Output)
+		if err != nil {
+			return nil, fmt.Errorf("output is not a list of tool calls: %w", err)
+		}
+
+		var total float64
+		used := make([]bool, len(actual))
+		scores := []Score{{}}
+		for i, want := range expected {
+			sub := compareToolCall(want, nil)
+			for j, got := range actual {
+				if !used[j] && got != nil && got.Name == want.Name {
+					used[j] = true
+					sub = compareToolCall(want, got)
+					break
+				}
+			}
+			total += sub.score()
+			scores = append(scores, Score{
+				Id:     fmt.Sprintf("tool_call_%d", i),
+				Score:  sub.score(),
+				Status: passStatus(sub.score() == 1).String(),
+				Details: map[string]any{
+					"name":             want.Name,
+					"nameMatch":        sub.nameMatch,
+					"argumentCoverage": sub.coverage,
+					"argumentAccuracy": sub.accuracy,
+				},
+			})
+		}
+
+		overall := total / float64(max(len(expected), len(actual)))
+		scores[0] = Score{
+			Id:     "tool_call_accuracy",
+			Score:  overall,
+			Status: passStatus(overall == 1).String(),
+			Details: map[string]any{
+				"expectedCalls": len(expected),
+				"actualCalls":   len(actual),
+			},
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: scores,
+		}, nil
+	})
+}
+
+// toolCallScore is the comparison of an expected tool call with an actual one.
+type toolCallScore struct {
+	nameMatch bool
+	coverage  float64
+	accuracy  float64
+}
+
+// score returns the combined score of the comparison, which is zero when the
+// names do not match.
+func (s toolCallScore) score() float64 {
+	if !s.nameMatch {
+		return 0
+	}
+	return (1 + s.coverage + s.accuracy) / 3
+}
+
+// compareToolCall compares the arguments of got against those of want.
+// A nil got is a missing call.
+func compareToolCall(want, got *ToolRequest) toolCallScore {
+	if got == nil || got.Name != want.Name {
+		return toolCallScore{}
+	}
+	s := toolCallScore{nameMatch: true, coverage: 1, accuracy: 1}
+	wantArgs, err1 := toolCallArgs(want.Input)
+	gotArgs, err2 := toolCallArgs(got.Input)
+	if err1 != nil || err2 != nil {
+		// At least one side does not take an object; compare them whole.
+		wv, err1 := jsonValue(want.Input)
+		gv, err2 := jsonValue(got.Input)
+		if err1 != nil || err2 != nil || !jsonValuesEqual(wv, gv) {
+			s.coverage, s.accuracy = 0, 0
+		}
+		return s
+	}
+	if len(wantArgs) == 0 {
+		return s
+	}
+	present, correct := 0, 0
+	for k, wv := range wantArgs {
+		gv, ok := gotArgs[k]
+		if !ok {
+			continue
+		}
+		present++
+		if jsonValuesEqual(wv, gv) {
+			correct++
+		}
+	}
+	s.coverage = float64(present) / float64(len(wantArgs))
+	s.accuracy = float64(correct) / float64(len(wantArgs))
+	return s
+}
+
+// parseToolCalls returns the tool calls held in output.
+func parseToolCalls(output any) ([]*ToolRequest, error) {
+	switch v := output.(type) {
+	case []*ToolRequest:
+		return v, nil
+	case []ToolRequest:
+		calls := make([]*ToolRequest, len(v))
+		for i := range v {
+			calls[i] = &v[i]
+		}
+		return calls, nil
+	case *ModelResponse:
+		var calls []*ToolRequest
+		if v.Message != nil {
+			for _, p := range v.Message.Content {
+				if p.IsToolRequest() {
+					calls = append(calls, p.ToolRequest)
+				}
+			}
+		}
+		return calls, nil
+	}
+	b, ok := output.(string)
+	var data []byte
+	if ok {
+		data = []byte(b)
+	} else {
+		var err error
+		if data, err = json.Marshal(output); err != nil {
+			return nil, err
+		}
+	}
+	var calls []*ToolRequest
+	if err := json.Unmarshal(data, &calls); err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
+
+// toolCallArgs returns the arguments of a tool call as a decoded JSON
+// object.
+func toolCallArgs(input any) (map[string]any, error) {
+	v, err := jsonValue(input)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	args, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("arguments are a %T, not an object", v)
+	}
+	return args, nil
+}
+
+// jsonValue returns v as it would be decoded from its JSON encoding.
+func jsonValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// jsonValuesEqual reports whether the decoded JSON values a and b are equal,
+// treating numbers that are within toolCallTolerance of each other as equal.
+func jsonValuesEqual(a, b any) bool {
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return false
+		}
+		return math.Abs(av-bv) <= toolCallTolerance*max(1, math.Abs(av), math.Abs(bv))
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			if w, ok := bv[k]; !ok || !jsonValuesEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonValuesEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// passStatus returns ScoreStatusPass if pass is true and ScoreStatusFail
+// otherwise.
+func passStatus(pass bool) ScoreStatus {
+	if pass {
+		return ScoreStatusPass
+	}
+	return ScoreStatusFail
+}
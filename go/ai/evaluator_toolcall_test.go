@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestToolCallAccuracyEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalAction, err := DefineToolCallAccuracyEvaluator(r, "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weather := &ToolRequest{Name: "getWeather", Input: map[string]any{"city": "Paris", "days": 3}}
+	ds := Dataset{
+		{
+			TestCaseId:        "exact",
+			Input:             "q",
+			Output:            []*ToolRequest{{Name: "getWeather", Input: map[string]any{"days": 3.0000000001, "city": "Paris"}}},
+			ExpectedToolCalls: []*ToolRequest{weather},
+		},
+		{
+			TestCaseId:        "wrongValue",
+			Input:             "q",
+			Output:            `[{"name": "getWeather", "input": {"city": "London", "days": 3}}]`,
+			ExpectedToolCalls: []*ToolRequest{weather},
+		},
+		{
+			TestCaseId:        "missingKey",
+			Input:             "q",
+			Output:            []ToolRequest{{Name: "getWeather", Input: map[string]any{"city": "Paris"}}},
+			ExpectedToolCalls: []*ToolRequest{weather},
+		},
+		{
+			TestCaseId:        "wrongName",
+			Input:             "q",
+			Output:            []*ToolRequest{{Name: "getTime", Input: map[string]any{"city": "Paris"}}},
+			ExpectedToolCalls: []*ToolRequest{weather},
+		},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		score, coverage, accuracy float64
+		status                    string
+	}{
+		{1, 1, 1, "pass"},
+		{(1 + 1 + 0.5) / 3, 1, 0.5, "fail"},
+		{(1 + 0.5 + 0.5) / 3, 0.5, 0.5, "fail"},
+		{0, 0, 0, "fail"},
+	}
+	for i, test := range tests {
+		res := (*resp)[i]
+		if got := res.Evaluation[0].Score.(float64); math.Abs(got-test.score) > 1e-9 {
+			t.Errorf("%s: got score %v, want %v", res.TestCaseId, got, test.score)
+		}
+		if got := res.Evaluation[0].Status; got != test.status {
+			t.Errorf("%s: got status %v, want %v", res.TestCaseId, got, test.status)
+		}
+		sub := res.Evaluation[1]
+		if got, want := sub.Id, "tool_call_0"; got != want {
+			t.Errorf("%s: got sub-score id %v, want %v", res.TestCaseId, got, want)
+		}
+		if got := sub.Details["argumentCoverage"]; got != test.coverage {
+			t.Errorf("%s: got coverage %v, want %v", res.TestCaseId, got, test.coverage)
+		}
+		if got := sub.Details["argumentAccuracy"]; got != test.accuracy {
+			t.Errorf("%s: got accuracy %v, want %v", res.TestCaseId, got, test.accuracy)
+		}
+	}
+}
+
+func TestParseToolCallsFromModelResponse(t *testing.T) {
+	want := []*ToolRequest{{Name: "a"}}
+	got, err := parseToolCalls(&ModelResponse{Message: &Message{Content: []*Part{
+		NewTextPart("calling tools"),
+		NewToolRequestPart(&ToolRequest{Name: "a"}),
+		NewToolRequestPart(&ToolRequest{Name: "b"}),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d tool calls, want 2", len(got))
+	}
+	if s := compareToolCall(want[0], got[0]).score(); s != 1 {
+		t.Errorf("got score %v, want 1", s)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/firebase/genkit/go/internal/base"
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// ToolSchema describes a tool that a model may call, for use with
+// [DefineToolCallValidityEvaluator].
+type ToolSchema struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// ArgumentSchema is the JSON Schema that the call's arguments must
+	// match. If it is nil, any arguments are accepted.
+	ArgumentSchema map[string]any `json:"argumentSchema,omitempty"`
+}
+
+// DefineToolCallValidityEvaluator registers an evaluator named
+// "tool_call_validity" that checks that the Output of each [Example] is a
+// well-formed tool call: that it parses as a [ToolRequest] or a list of
+// them, that each call names a known tool, and that its arguments match
+// the tool's ArgumentSchema. Tools are looked up in toolSchemas first and
+// then among the tools defined in r, whose input schema is used.
+//
+// The score is the fraction of calls that are valid, and an example passes
+// if there is at least one call and all of them are valid. The problems
+// found are reported in the "errors" key of [Score.Details]. If opts is nil,
+// default options are used.
+func DefineToolCallValidityEvaluator(r *registry.Registry, provider string, toolSchemas []ToolSchema, opts *EvaluatorOptions) (Evaluator, error) {
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Tool Call Validity",
+			Definition:  "Checks that the output is a valid call of a known tool",
+		}
+	}
+	schemas := map[string][]byte{}
+	for _, ts := range toolSchemas {
+		if ts.Name == "" {
+			return nil, errors.New("ai.DefineToolCallValidityEvaluator: tool schema has no name")
+		}
+		if _, ok := schemas[ts.Name]; ok {
+			return nil, fmt.Errorf("ai.DefineToolCallValidityEvaluator: duplicate tool %q", ts.Name)
+		}
+		var b []byte
+		if ts.ArgumentSchema != nil {
+			var err error
+			if b, err = json.Marshal(ts.ArgumentSchema); err != nil {
+				return nil, fmt.Errorf("ai.DefineToolCallValidityEvaluator: tool %q: %w", ts.Name, err)
+			}
+		}
+		schemas[ts.Name] = b
+	}
+
+	// argumentSchema returns the argument schema of the named tool, which
+	// is nil if the tool accepts any arguments.
+	argumentSchema := func(name string) ([]byte, error) {
+		if b, ok := schemas[name]; ok {
+			return b, nil
+		}
+		tool := LookupTool(r, name)
+		if tool == nil {
+			return nil, fmt.Errorf("unknown tool %q", name)
+		}
+		if s := tool.Definition().InputSchema; s != nil {
+			return json.Marshal(s)
+		}
+		return nil, nil
+	}
+
+	return DefineEvaluator(r, provider, "tool_call_validity", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		score := Score{
+			Id:      "tool_call_validity",
+			Details: map[string]any{},
+		}
+		calls, err := parseToolCallOutput(req.Input.Output)
+		if err != nil {
+			score.Score = 0.0
+			score.Status = ScoreStatusFail.String()
+			score.Details["errors"] = []string{fmt.Sprintf("output is not a tool call: %v", err)}
+			return &EvaluatorCallbackResponse{TestCaseId: req.Input.TestCaseId, Evaluation: []Score{score}}, nil
+		}
+
+		var problems []string
+		valid := 0
+		for i, call := range calls {
+			if err := validateToolCall(call, argumentSchema); err != nil {
+				problems = append(problems, fmt.Sprintf("call %d: %v", i, err))
+				continue
+			}
+			valid++
+		}
+		if len(calls) == 0 {
+			problems = append(problems, "output has no tool calls")
+			score.Score = 0.0
+		} else {
+			score.Score = float64(valid) / float64(len(calls))
+		}
+		score.Status = passStatus(len(problems) == 0).String()
+		score.Details["calls"] = len(calls)
+		if len(problems) > 0 {
+			score.Details["errors"] = problems
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// parseToolCallOutput returns the tool calls held in output, which may be a
+// single call or a list of them.
+func parseToolCallOutput(output any) ([]*ToolRequest, error) {
+	switch v := output.(type) {
+	case *ToolRequest:
+		return []*ToolRequest{v}, nil
+	case ToolRequest:
+		return []*ToolRequest{&v}, nil
+	}
+	parsed, err := parseJSONOutput(output)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := parsed.(map[string]any); !ok {
+		return parseToolCalls(output)
+	}
+	b, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	var call ToolRequest
+	if err := json.Unmarshal(b, &call); err != nil {
+		return nil, err
+	}
+	return []*ToolRequest{&call}, nil
+}
+
+// validateToolCall checks that call names a known tool and that its
+// arguments match the tool's schema.
+func validateToolCall(call *ToolRequest, argumentSchema func(string) ([]byte, error)) error {
+	if call == nil || call.Name == "" {
+		return errors.New("missing tool name")
+	}
+	schema, err := argumentSchema(call.Name)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+	args, err := json.Marshal(call.Input)
+	if err != nil {
+		return fmt.Errorf("tool %q: arguments are not JSON: %w", call.Name, err)
+	}
+	if err := base.ValidateRaw(args, schema); err != nil {
+		return fmt.Errorf("tool %q: %w", call.Name, err)
+	}
+	return nil
+}
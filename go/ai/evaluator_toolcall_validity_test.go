@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestToolCallValidityEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	type weatherInput struct {
+		City string `json:"city"`
+	}
+	DefineTool(r, "weather", "Gets the weather", func(ctx *ToolContext, in weatherInput) (string, error) {
+		return "sunny", nil
+	})
+
+	evalAction, err := DefineToolCallValidityEvaluator(r, "test", []ToolSchema{{
+		Name:        "search",
+		Description: "Searches the web",
+		ArgumentSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"query": map[string]any{"type": "string"}, "limit": map[string]any{"type": "integer", "minimum": 1}},
+			"required":   []any{"query"},
+		},
+	}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "valid", Output: `{"name": "search", "input": {"query": "genkit", "limit": 5}}`},
+		{TestCaseId: "struct", Output: &ToolRequest{Name: "search", Input: map[string]any{"query": "go"}}},
+		{TestCaseId: "registered", Output: map[string]any{"name": "weather", "input": map[string]any{"city": "Paris"}}},
+		{TestCaseId: "unknown", Output: `{"name": "book_flight", "input": {}}`},
+		{TestCaseId: "bad args", Output: `{"name": "search", "input": {"limit": 0}}`},
+		{TestCaseId: "list", Output: `[{"name": "search", "input": {"query": "a"}}, {"name": "search", "input": {}}]`},
+		{TestCaseId: "not json", Output: `search(query="genkit")`},
+		{TestCaseId: "empty", Output: `[]`},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct {
+		score  float64
+		status string
+	}{
+		"valid":      {1, "pass"},
+		"struct":     {1, "pass"},
+		"registered": {1, "pass"},
+		"unknown":    {0, "fail"},
+		"bad args":   {0, "fail"},
+		"list":       {0.5, "fail"},
+		"not json":   {0, "fail"},
+		"empty":      {0, "fail"},
+	}
+	for _, res := range *resp {
+		w := want[res.TestCaseId]
+		s := res.Evaluation[0]
+		if s.Score != w.score || s.Status != w.status {
+			t.Errorf("%s: got score %v, status %s, want %v, %s", res.TestCaseId, s.Score, s.Status, w.score, w.status)
+		}
+		if _, hasErrors := s.Details["errors"]; hasErrors != (w.status == "fail") {
+			t.Errorf("%s: got errors %v", res.TestCaseId, s.Details["errors"])
+		}
+	}
+
+	if _, err := DefineToolCallValidityEvaluator(r, "dup", []ToolSchema{{Name: "a"}, {Name: "a"}}, nil); err == nil {
+		t.Error("got nil error for duplicate tool, want error")
+	}
+}
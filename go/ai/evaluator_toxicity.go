@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// DefineToxicityEvaluator registers a rule-based evaluator named "toxicity"
+// that checks the Output of each [Example] against a keyword blocklist. It
+// makes no external calls, so it is suitable as a fast first-pass filter
+// before more expensive LLM judges.
+//
+// Blocklist entries are matched case-insensitively on word boundaries.
+// Entries wrapped in slashes, such as "/idiot(s)?/", are treated as regular
+// expressions. Entries prefixed with "!" are allow-list overrides: a match
+// whose text equals an allow-listed term is ignored.
+//
+// An example fails if any blocked term matches; the matched terms are
+// reported in the "matches" key of [Score.Details]. If opts is nil, default
+// options are used.
+func DefineToxicityEvaluator(r *registry.Registry, provider string, blocklist []string, opts *EvaluatorOptions) (Evaluator, error) {
+	var patterns []*regexp.Regexp
+	allowed := map[string]bool{}
+	for _, entry := range blocklist {
+		switch {
+		case strings.HasPrefix(entry, "!"):
+			allowed[strings.ToLower(entry[1:])] = true
+		case len(entry) > 1 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/"):
+			re, err := regexp.Compile(`(?i)\b(?:` + entry[1:len(entry)-1] + `)\b`)
+			if err != nil {
+				return nil, fmt.Errorf("ai.DefineToxicityEvaluator: invalid pattern %q: %w", entry, err)
+			}
+			patterns = append(patterns, re)
+		case entry != "":
+			patterns = append(patterns, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(entry)+`\b`))
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, errors.New("ai.DefineToxicityEvaluator: blocklist must contain at least one term")
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName: "Toxicity",
+			Definition:  "Checks output for terms from a configurable blocklist",
+		}
+	}
+
+	return DefineEvaluator(r, provider, "toxicity", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		text, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := []string{}
+		seen := map[string]bool{}
+		for _, re := range patterns {
+			for _, m := range re.FindAllString(text, -1) {
+				key := strings.ToLower(m)
+				if allowed[key] || seen[key] {
+					continue
+				}
+				seen[key] = true
+				matches = append(matches, m)
+			}
+		}
+
+		score := Score{
+			Id:      "toxicity",
+			Score:   1.0,
+			Status:  ScoreStatusPass.String(),
+			Details: map[string]any{"matches": matches},
+		}
+		if len(matches) > 0 {
+			score.Score = 0.0
+			score.Status = ScoreStatusFail.String()
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestToxicityEvaluator(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocklist := []string{"darn", "/heck(s)?/", "!hecks"}
+	evalAction, err := DefineToxicityEvaluator(r, "test", blocklist, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "clean", Input: "q", Output: "What a lovely day"},
+		{TestCaseId: "word", Input: "q", Output: "Well, DARN it"},
+		{TestCaseId: "boundary", Input: "q", Output: "darning socks"},
+		{TestCaseId: "regex", Input: "q", Output: "what the heck"},
+		{TestCaseId: "allowed", Input: "q", Output: "hecks are fine"},
+		{TestCaseId: "missing", Input: "q"},
+	}
+	resp, err := evalAction.Evaluate(context.Background(), &EvaluatorRequest{Dataset: &ds})
+	var evalErr EvaluatorError
+	if !errors.As(err, &evalErr) || evalErr.TestCaseId != "missing" {
+		t.Fatalf("got error %v, want EvaluatorError for the example without output", err)
+	}
+
+	wantStatus := []string{"pass", "fail", "pass", "fail", "pass", "fail"}
+	for i, res := range *resp {
+		if got, want := res.Evaluation[0].Status, wantStatus[i]; got != want {
+			t.Errorf("%s: got status %v, want %v", res.TestCaseId, got, want)
+		}
+	}
+	if got, want := (*resp)[1].Evaluation[0].Details["matches"], []string{"DARN"}; !slices.Equal(got.([]string), want) {
+		t.Errorf("got matches %v, want %v", got, want)
+	}
+	if got := (*resp)[5].Evaluation[0].Error; got == "" {
+		t.Errorf("got %q, want error", got)
+	}
+}
+
+func TestToxicityEvaluatorInvalidBlocklist(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := DefineToxicityEvaluator(r, "test", []string{"/(/"}, nil); err == nil {
+		t.Error("got nil, want error for invalid pattern")
+	}
+	if _, err := DefineToxicityEvaluator(r, "test", []string{"!only"}, nil); err == nil {
+		t.Error("got nil, want error for empty blocklist")
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/core/tracing"
+	"github.com/firebase/genkit/go/internal/registry"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEvaluateSpanNesting(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	r.RegisterSpanProcessor(recorder)
+
+	e, err := ai.DefineEvaluator(r, "test", "nested", &ai.EvaluatorOptions{DisplayName: "Nested"},
+		func(ctx context.Context, req *ai.EvaluatorCallbackRequest) (*ai.EvaluatorCallbackResponse, error) {
+			return &ai.EvaluatorCallbackResponse{
+				TestCaseId: req.Input.TestCaseId,
+				Evaluation: []ai.Score{{Score: true, Status: ai.ScoreStatusPass.String()}},
+			}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := ai.Dataset{{TestCaseId: "a", Input: "x"}, {TestCaseId: "b", Input: "y"}}
+	_, err = tracing.RunInNewSpan(context.Background(), r.TracingState(), "root", "flow", true, 0,
+		func(ctx context.Context, _ int) (*ai.EvaluatorResponse, error) {
+			return ai.Evaluate(ctx, e, ai.WithEvaluateDataset(&ds))
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	root, ok := spans["root"]
+	if !ok {
+		t.Fatal("root span was not recorded")
+	}
+	action, ok := spans["test/nested"]
+	if !ok {
+		t.Fatal("evaluator action span was not recorded")
+	}
+	if got, want := action.Parent().SpanID(), root.SpanContext().SpanID(); got != want {
+		t.Errorf("evaluator span parent: got %v, want %v", got, want)
+	}
+	for _, id := range []string{"a", "b"} {
+		s, ok := spans["TestCase "+id]
+		if !ok {
+			t.Errorf("span for test case %q was not recorded", id)
+			continue
+		}
+		if got, want := s.SpanContext().TraceID(), root.SpanContext().TraceID(); got != want {
+			t.Errorf("test case %q: got trace %v, want %v", id, got, want)
+		}
+		if got, want := s.Parent().SpanID(), action.SpanContext().SpanID(); got != want {
+			t.Errorf("test case %q: got parent %v, want %v", id, got, want)
+		}
+		if got, want := spanAttr(s, "genkit:type"), "evaluator"; got != want {
+			t.Errorf("test case %q: got genkit:type %q, want %q", id, got, want)
+		}
+	}
+}
+
+func spanAttr(s sdktrace.ReadOnlySpan, key string) string {
+	for _, kv := range s.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
+
+func TestSpanNameFormatter(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	r.RegisterSpanProcessor(recorder)
+
+	opts := ai.NewEvaluatorOptions(ai.WithDisplayName("Formatted"), ai.WithSpanNameFormatter(func(ex ai.Example) string {
+		return fmt.Sprintf("%s/%s", ex.Input.(map[string]any)["task"], ex.TestCaseId)
+	}))
+	e, err := ai.DefineEvaluator(r, "test", "formatted", opts,
+		func(ctx context.Context, req *ai.EvaluatorCallbackRequest) (*ai.EvaluatorCallbackResponse, error) {
+			return &ai.EvaluatorCallbackResponse{
+				TestCaseId: req.Input.TestCaseId,
+				Evaluation: []ai.Score{{Score: true, Status: ai.ScoreStatusPass.String()}},
+			}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := ai.Dataset{
+		{TestCaseId: "a", Input: map[string]any{"task": "summarize"}},
+		{TestCaseId: "b", Input: map[string]any{"task": "translate"}},
+	}
+	if _, err := ai.Evaluate(context.Background(), e, ai.WithEvaluateDataset(&ds)); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range recorder.Ended() {
+		if spanAttr(s, "genkit:type") == "evaluator" {
+			got = append(got, s.Name())
+		}
+	}
+	if want := []string{"summarize/a", "translate/b"}; !slices.Equal(got, want) {
+		t.Errorf("got example spans %v, want %v", got, want)
+	}
+}
+
+func TestSkipIf(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	r.RegisterSpanProcessor(recorder)
+
+	var evaluated []string
+	opts := ai.NewEvaluatorOptions(ai.WithDisplayName("Safety"), ai.WithSkipIf(func(ex ai.Example) bool {
+		return ex.Input.(map[string]any)["synthetic"] == true
+	}))
+	e, err := ai.DefineEvaluator(r, "test", "safety", opts,
+		func(ctx context.Context, req *ai.EvaluatorCallbackRequest) (*ai.EvaluatorCallbackResponse, error) {
+			evaluated = append(evaluated, req.Input.TestCaseId)
+			return &ai.EvaluatorCallbackResponse{
+				TestCaseId: req.Input.TestCaseId,
+				Evaluation: []ai.Score{{Score: true, Status: ai.ScoreStatusPass.String()}},
+			}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := ai.Dataset{
+		{TestCaseId: "real", Input: map[string]any{"synthetic": false}},
+		{TestCaseId: "adversarial", Input: map[string]any{"synthetic": true}},
+	}
+	resp, err := ai.EvaluateRun(context.Background(), e, ai.WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"real"}; !slices.Equal(evaluated, want) {
+		t.Errorf("evaluated %v, want %v", evaluated, want)
+	}
+	if got, want := resp.Summary.SkippedExamples, 1; got != want {
+		t.Errorf("got %d skipped examples, want %d", got, want)
+	}
+	skipped := resp.Results[1].Evaluation[0]
+	if skipped.Status != ai.ScoreStatusUnknown.String() || skipped.Details["reason"] != "skipped" {
+		t.Errorf("got score %+v, want unknown status with reason skipped", skipped)
+	}
+
+	for _, s := range recorder.Ended() {
+		var events []string
+		for _, ev := range s.Events() {
+			events = append(events, ev.Name)
+		}
+		switch s.Name() {
+		case "TestCase adversarial":
+			if !slices.Equal(events, []string{"skipped"}) {
+				t.Errorf("got events %v on the skipped example's span, want [skipped]", events)
+			}
+		case "TestCase real":
+			if len(events) > 0 {
+				t.Errorf("got events %v on the evaluated example's span, want none", events)
+			}
+		}
+	}
+}
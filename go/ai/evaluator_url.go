@@ -0,0 +1,259 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// URLCheck is the verification of one URL, reported by
+// [DefineURLVerificationEvaluator].
+type URLCheck struct {
+	URL string `json:"url"`
+	// StatusCode is the HTTP status of the response, or 0 if no response
+	// was received.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Valid reports whether the URL was reachable with a status below 400.
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// URLVerificationOption configures [DefineURLVerificationEvaluator].
+type URLVerificationOption func(opts *urlVerificationOptions)
+
+type urlVerificationOptions struct {
+	maxRedirects int
+	userAgent    string
+	client       *http.Client
+	allowedHosts []string
+}
+
+// WithURLMaxRedirects sets the number of redirects followed before a URL is
+// considered unreachable. It defaults to 10.
+func WithURLMaxRedirects(n int) URLVerificationOption {
+	return func(opts *urlVerificationOptions) {
+		opts.maxRedirects = n
+	}
+}
+
+// WithURLUserAgent sets the User-Agent header of the requests. It defaults to
+// [URLVerificationUserAgent].
+func WithURLUserAgent(userAgent string) URLVerificationOption {
+	return func(opts *urlVerificationOptions) {
+		opts.userAgent = userAgent
+	}
+}
+
+// WithURLHTTPClient sets the client used to make the requests. Its timeout
+// and redirect policy are replaced by those of the evaluator. To block
+// requests to internal addresses whatever their host name, give it a
+// transport whose dialer rejects them in its Control function.
+func WithURLHTTPClient(client *http.Client) URLVerificationOption {
+	return func(opts *urlVerificationOptions) {
+		opts.client = client
+	}
+}
+
+// WithURLAllowedHosts restricts the requests, including redirects, to the
+// given hosts and their subdomains. URLs on other hosts are reported as
+// invalid without being requested. Hosts are matched by name, not by
+// address.
+func WithURLAllowedHosts(hosts ...string) URLVerificationOption {
+	return func(opts *urlVerificationOptions) {
+		opts.allowedHosts = append(opts.allowedHosts, hosts...)
+	}
+}
+
+// allowed reports whether u may be requested.
+func (o *urlVerificationOptions) allowed(u *url.URL) bool {
+	if o.allowedHosts == nil {
+		return true
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range o.allowedHosts {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// URLVerificationUserAgent is the default User-Agent of the requests made by
+// [DefineURLVerificationEvaluator].
+const URLVerificationUserAgent = "genkit-url-verifier/1.0"
+
+// defaultURLTimeout is the time allowed for each URL if none is given.
+const defaultURLTimeout = 10 * time.Second
+
+var (
+	urlRe = regexp.MustCompile(`https?://[^\s<>"'()\[\]{}]+`)
+	// urlTrailingPunct is the punctuation that ends a sentence rather than
+	// a URL.
+	urlTrailingPunct = ".,;:!?"
+)
+
+// DefineURLVerificationEvaluator registers an evaluator named
+// "url_verification" that checks that the URLs in the Output of each
+// [Example] are reachable. Each distinct http or https URL is requested with
+// HEAD, falling back to GET if the server does not allow HEAD, and is valid
+// if it answers with a status below 400 within timeout. If timeout is zero,
+// each URL is allowed 10 seconds.
+//
+// The score is the fraction of valid URLs, and an example passes if all of
+// its URLs are valid, including when it has none. The result of each request
+// is reported as a [URLCheck] in the "urls" key of [Score.Details]. If opts
+// is nil, default options are used.
+//
+// The URLs come from model output, so by default the evaluator requests
+// whatever host they name, including internal services and cloud metadata
+// endpoints reachable from where it runs. When evaluating untrusted output,
+// restrict the hosts with [WithURLAllowedHosts], or block internal addresses
+// with a client given by [WithURLHTTPClient].
+func DefineURLVerificationEvaluator(r *registry.Registry, provider string, timeout time.Duration, opts *EvaluatorOptions, urlOpts ...URLVerificationOption) (Evaluator, error) {
+	if timeout < 0 {
+		return nil, fmt.Errorf("ai.DefineURLVerificationEvaluator: negative timeout %v", timeout)
+	}
+	if timeout == 0 {
+		timeout = defaultURLTimeout
+	}
+	o := &urlVerificationOptions{maxRedirects: 10, userAgent: URLVerificationUserAgent}
+	for _, opt := range urlOpts {
+		opt(o)
+	}
+	if o.maxRedirects < 0 {
+		return nil, fmt.Errorf("ai.DefineURLVerificationEvaluator: negative redirect limit %d", o.maxRedirects)
+	}
+	client := &http.Client{}
+	if o.client != nil {
+		*client = *o.client
+	}
+	client.Timeout = timeout
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > o.maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", o.maxRedirects)
+		}
+		if !o.allowed(req.URL) {
+			return fmt.Errorf("redirect to host %q is not allowed", req.URL.Hostname())
+		}
+		return nil
+	}
+	if opts == nil {
+		opts = &EvaluatorOptions{
+			DisplayName:    "URL Verification",
+			Definition:     "Checks that the URLs in the output are reachable",
+			RequiredFields: []string{"Output"},
+		}
+	}
+
+	return DefineEvaluator(r, provider, "url_verification", opts, func(ctx context.Context, req *EvaluatorCallbackRequest) (*EvaluatorCallbackResponse, error) {
+		if req.Input.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		output, err := exampleText(req.Input.Output)
+		if err != nil {
+			return nil, err
+		}
+
+		urls := extractURLs(output)
+		checks := make([]URLCheck, len(urls))
+		valid := 0
+		for i, u := range urls {
+			checks[i] = checkURL(ctx, client, o, u)
+			if checks[i].Valid {
+				valid++
+			}
+		}
+
+		score := Score{
+			Id:      "url_verification",
+			Score:   1.0,
+			Status:  passStatus(valid == len(urls)).String(),
+			Details: map[string]any{"urls": checks},
+		}
+		if len(urls) > 0 {
+			score.Score = float64(valid) / float64(len(urls))
+		}
+		return &EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []Score{score},
+		}, nil
+	})
+}
+
+// extractURLs returns the distinct http and https URLs in text, in order of
+// appearance.
+func extractURLs(text string) []string {
+	var urls []string
+	seen := map[string]bool{}
+	for _, u := range urlRe.FindAllString(text, -1) {
+		u = strings.TrimRight(u, urlTrailingPunct)
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// checkURL requests u with HEAD, or with GET if HEAD is not allowed.
+func checkURL(ctx context.Context, client *http.Client, o *urlVerificationOptions, u string) URLCheck {
+	check := URLCheck{URL: u}
+	status, err := requestURL(ctx, client, http.MethodHead, o, u)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = requestURL(ctx, client, http.MethodGet, o, u)
+	}
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	check.StatusCode = status
+	check.Valid = status < 400
+	if !check.Valid {
+		check.Error = http.StatusText(status)
+	}
+	return check
+}
+
+// requestURL makes a request without a body and returns its status.
+func requestURL(ctx context.Context, client *http.Client, method string, o *urlVerificationOptions, u string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	if !o.allowed(req.URL) {
+		return 0, fmt.Errorf("host %q is not allowed", req.URL.Hostname())
+	}
+	if o.userAgent != "" {
+		req.Header.Set("User-Agent", o.userAgent)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
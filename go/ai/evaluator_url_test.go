@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestExtractURLs(t *testing.T) {
+	got := extractURLs(`See https://example.com/a, (http://example.com/b) and "https://example.com/a".`)
+	want := []string{"https://example.com/a", "http://example.com/b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestURLVerificationEvaluator(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		userAgents []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents = append(userAgents, r.UserAgent())
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/ok":
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalAction, err := DefineURLVerificationEvaluator(r, "test", 50*time.Millisecond, nil, WithURLMaxRedirects(3), WithURLUserAgent("test-agent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Dataset{
+		{TestCaseId: "valid", Output: fmt.Sprintf("Read %s/ok and %s/get-only, or %s/redirect.", srv.URL, srv.URL, srv.URL)},
+		{TestCaseId: "invalid", Output: fmt.Sprintf("Try %s/ok, %s/missing, %s/loop and %s/slow.", srv.URL, srv.URL, srv.URL, srv.URL)},
+		{TestCaseId: "none", Output: "No links here."},
+	}
+	resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := indexResults(resp)
+	tests := []struct {
+		id     string
+		score  float64
+		status ScoreStatus
+	}{
+		{"valid", 1, ScoreStatusPass},
+		{"invalid", 0.25, ScoreStatusFail},
+		{"none", 1, ScoreStatusPass},
+	}
+	for _, test := range tests {
+		score := results[test.id].Evaluation[0]
+		if score.Score != test.score || score.Status != test.status.String() {
+			t.Errorf("%s: got score %v status %q, want %v %q", test.id, score.Score, score.Status, test.score, test.status)
+		}
+	}
+
+	checks := results["invalid"].Evaluation[0].Details["urls"].([]URLCheck)
+	if got := checks[1]; got.Valid || got.StatusCode != http.StatusNotFound {
+		t.Errorf("got %+v, want invalid with status 404", got)
+	}
+	for _, c := range checks[2:] {
+		if c.Valid || c.Error == "" {
+			t.Errorf("got %+v, want invalid with an error", c)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, ua := range userAgents {
+		if ua != "test-agent" {
+			t.Fatalf("got User-Agent %q, want test-agent", ua)
+		}
+	}
+}
+
+func TestURLVerificationAllowedHosts(t *testing.T) {
+	requested := map[string]bool{}
+	var mu sync.Mutex
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.Host+r.URL.Path] = true
+		mu.Unlock()
+		if r.URL.Path == "/external" {
+			// Redirect to the same server under a host that is not allowed.
+			http.Redirect(w, r, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)+"/ok", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+	if !strings.Contains(srv.URL, "127.0.0.1") {
+		t.Skipf("test server is not on 127.0.0.1: %s", srv.URL)
+	}
+
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalAction, err := DefineURLVerificationEvaluator(r, "test", 0, nil, WithURLAllowedHosts("127.0.0.1", "example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	ds := Dataset{{TestCaseId: "a", Output: fmt.Sprintf("See %s/ok, %s/ok and %s/external.", srv.URL, local, srv.URL)}}
+	resp, err := Evaluate(context.Background(), evalAction, WithEvaluateDataset(&ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := (*resp)[0].Evaluation[0].Details["urls"].([]URLCheck)
+	for i, want := range []bool{true, false, false} {
+		if checks[i].Valid != want {
+			t.Errorf("%s: got valid %v, want %v (error %q)", checks[i].URL, checks[i].Valid, want, checks[i].Error)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for host := range requested {
+		if strings.HasPrefix(host, "localhost") {
+			t.Errorf("requested %s, whose host is not allowed", host)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"fmt"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+// evaluatorVersionKey returns the registry value key under which version of
+// the evaluator provider/name is recorded.
+func evaluatorVersionKey(provider, name, version string) string {
+	return "evaluatorVersion/" + provider + "/" + name + "@" + version
+}
+
+// versionedEvaluatorName returns the action name under which to register
+// version of the evaluator provider/name. The first evaluator defined with a
+// provider and name keeps that name; later versions are registered as
+// "name@version". It is an error to define the same version twice.
+func versionedEvaluatorName(r *registry.Registry, provider, name, version string) (string, error) {
+	if version == "" {
+		return name, nil
+	}
+	if r.LookupValue(evaluatorVersionKey(provider, name, version)) != nil {
+		return "", fmt.Errorf("version %q of evaluator %q is already defined", version, provider+"/"+name)
+	}
+	if IsDefinedEvaluator(r, provider, name) {
+		return name + "@" + version, nil
+	}
+	return name, nil
+}
+
+// registerEvaluatorVersion records that version of the evaluator
+// provider/name is registered under actionName.
+func registerEvaluatorVersion(r *registry.Registry, provider, name, version, actionName string) {
+	if version != "" {
+		r.RegisterValue(evaluatorVersionKey(provider, name, version), actionName)
+	}
+}
+
+// stampEvaluatorVersion sets the EvaluatorVersion of every result in resp.
+func stampEvaluatorVersion(resp *EvaluatorResponse, version string) {
+	if resp == nil || version == "" {
+		return
+	}
+	for i := range *resp {
+		(*resp)[i].EvaluatorVersion = version
+	}
+}
+
+// LookupEvaluatorByVersion looks up version of an [Evaluator] defined with
+// [EvaluatorOptions.ActionVersion]. If version is empty, it is the same as
+// [LookupEvaluator]. It returns nil if no such version was defined.
+func LookupEvaluatorByVersion(r *registry.Registry, provider, name, version string) Evaluator {
+	if version == "" {
+		return LookupEvaluator(r, provider, name)
+	}
+	actionName, ok := r.LookupValue(evaluatorVersionKey(provider, name, version)).(string)
+	if !ok {
+		return nil
+	}
+	return LookupEvaluator(r, provider, actionName)
+}
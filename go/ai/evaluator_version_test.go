@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/internal/registry"
+)
+
+func TestEvaluatorVersions(t *testing.T) {
+	r, err := registry.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1opts, v2opts := evalOptions, evalOptions
+	v1opts.ActionVersion = "v1"
+	v2opts.ActionVersion = "v2"
+
+	v1, err := DefineEvaluator(r, "test", "versioned", &v1opts, testEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := DefineBatchEvaluator(r, "test", "versioned", &v2opts, testBatchEvalFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DefineEvaluator(r, "test", "versioned", &v2opts, testEvalFunc); err == nil {
+		t.Error("defining v2 twice: got nil error, want error")
+	}
+
+	if got, want := v1.Name(), "test/versioned"; got != want {
+		t.Errorf("v1 name: got %q, want %q", got, want)
+	}
+	if got, want := v2.Name(), "test/versioned@v2"; got != want {
+		t.Errorf("v2 name: got %q, want %q", got, want)
+	}
+	if got := LookupEvaluatorByVersion(r, "test", "versioned", "v1"); got != v1 {
+		t.Errorf("looking up v1: got %v, want %v", got, v1)
+	}
+	if got := LookupEvaluatorByVersion(r, "test", "versioned", "v2"); got != v2 {
+		t.Errorf("looking up v2: got %v, want %v", got, v2)
+	}
+	if got := LookupEvaluatorByVersion(r, "test", "versioned", ""); got != v1 {
+		t.Errorf("looking up no version: got %v, want %v", got, v1)
+	}
+	if got := LookupEvaluatorByVersion(r, "test", "versioned", "v3"); got != nil {
+		t.Errorf("looking up v3: got %v, want nil", got)
+	}
+
+	ctx := context.Background()
+	ds := Dataset{{TestCaseId: "a", Input: "hello"}, {TestCaseId: "b", Input: "world"}}
+	before, err := v1.Evaluate(ctx, &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := v2.Evaluate(ctx, &EvaluatorRequest{Dataset: &ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := (*before)[0].EvaluatorVersion, "v1"; got != want {
+		t.Errorf("got version %q, want %q", got, want)
+	}
+	if got, want := (*after)[0].EvaluatorVersion, "v2"; got != want {
+		t.Errorf("got version %q, want %q", got, want)
+	}
+
+	diffs := DiffEvaluatorResponses(before, after)
+	if got, want := len(diffs), len(ds); got != want {
+		t.Fatalf("got %d changes, want %d", got, want)
+	}
+	for _, d := range diffs {
+		if !d.VersionMismatch() {
+			t.Errorf("%s/%s: got no version mismatch, want one", d.TestCaseId, d.ScoreId)
+		}
+	}
+	if got := len(DiffEvaluatorResponses(before, before)); got != 0 {
+		t.Errorf("diffing a response with itself: got %d changes, want 0", got)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/firebase/genkit/go/ai"
+)
+
+// output is where and in what format a command writes its result.
+type output struct {
+	format string
+	path   string
+}
+
+// write writes v as JSON, or t as CSV or HTML, according to the format.
+func (o *output) write(v any, t *table) (err error) {
+	var render func(io.Writer) error
+	switch o.format {
+	case "json":
+		render = func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(v)
+		}
+	case "csv":
+		render = t.writeCSV
+	case "html":
+		render = t.writeHTML
+	default:
+		return fmt.Errorf("unknown format %q", o.format)
+	}
+
+	if o.path == "" {
+		return render(os.Stdout)
+	}
+	f, err := os.Create(o.path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return render(f)
+}
+
+// table is the tabular form of a command's result, used for the CSV and
+// HTML formats.
+type table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+func (t *table) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(t.Header)
+	cw.WriteAll(t.Rows)
+	return cw.Error()
+}
+
+var htmlTemplate = template.Must(template.New("table").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+func (t *table) writeHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, t)
+}
+
+// resultsTable returns a table with a row for each score in resp.
+func resultsTable(title string, resp *ai.EvaluatorResponse) *table {
+	t := &table{Title: title, Header: []string{"testCaseId", "scoreId", "score", "status", "error"}}
+	for _, res := range *resp {
+		for _, s := range res.Evaluation {
+			t.Rows = append(t.Rows, []string{res.TestCaseId, s.Id, scoreString(s), s.Status, s.Error})
+		}
+	}
+	return t
+}
+
+// changesTable returns a table with a row for each change.
+func changesTable(changes []ai.ScoreDiff) *table {
+	t := &table{Title: "Score changes", Header: []string{"testCaseId", "scoreId", "oldScore", "oldStatus", "newScore", "newStatus"}}
+	for _, c := range changes {
+		row := []string{c.TestCaseId, c.ScoreId}
+		for _, s := range []*ai.Score{c.Old, c.New} {
+			if s == nil {
+				row = append(row, "", "")
+			} else {
+				row = append(row, scoreString(*s), s.Status)
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// scoreString formats the value of s.
+func scoreString(s ai.Score) string {
+	if s.Score == nil {
+		return ""
+	}
+	if v, err := s.Normalize(); err == nil {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	data, err := json.Marshal(s.Score)
+	if err != nil {
+		return fmt.Sprint(s.Score)
+	}
+	return string(data)
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// genkiteval runs Genkit evaluations from the command line, for example in
+// CI.
+//
+// Usage:
+//
+//	genkiteval run [-format FORMAT] [-o FILE] CONFIG
+//	   Run the evaluation described by the YAML file CONFIG. See
+//	   ai.ParseEvalConfig for its format. Paths in CONFIG are relative to the
+//	   directory that contains it.
+//	genkiteval list [-format FORMAT] [-o FILE]
+//	   List the available evaluators.
+//	genkiteval diff [-format FORMAT] [-o FILE] OLD NEW
+//	   Compare the scores in two result files.
+//	genkiteval report [-format FORMAT] [-o FILE] RESULTS
+//	   Render a result file.
+//
+// FORMAT is one of json (the default), csv or html. Output is written to
+// standard output unless -o is given. Result files are JSON files written by
+// the run command or by ai.RunFromConfig.
+//
+// The available evaluators are the rule-based evaluators that need no model:
+// those of the evaluators plugin and some from the ai package, all with the
+// provider "genkitEval".
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/firebase/genkit/go/plugins/evaluators"
+)
+
+const provider = "genkitEval"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("genkiteval: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"run":    runCommand,
+		"list":   listCommand,
+		"diff":   diffCommand,
+		"report": reportCommand,
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		log.Printf("unknown command %q", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd(context.Background(), flag.Args()[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: genkiteval run|list|diff|report [-format json|csv|html] [-o FILE] ARGS...")
+}
+
+func runCommand(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("run")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("run: need a config file")
+	}
+	path := fs.Arg(0)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cfg, err := ai.ParseEvalConfig(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.DatasetPath = relativeTo(path, cfg.DatasetPath)
+	if cfg.OutputPath != "" {
+		cfg.OutputPath = relativeTo(path, cfg.OutputPath)
+	}
+
+	g, err := newGenkit(ctx)
+	if err != nil {
+		return err
+	}
+	resp, evalErr := genkit.RunFromConfig(ctx, g, cfg)
+	if resp == nil {
+		return evalErr
+	}
+	if err := out.write(resp, resultsTable(cfg.Evaluator, resp)); err != nil {
+		return err
+	}
+	return evalErr
+}
+
+func listCommand(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("list")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		return errors.New("list: takes no arguments")
+	}
+	g, err := newGenkit(ctx)
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	t := &table{Title: "Evaluators", Header: []string{"name"}}
+	for _, e := range genkit.ListEvaluators(g) {
+		names = append(names, e.Name())
+		t.Rows = append(t.Rows, []string{e.Name()})
+	}
+	return out.write(names, t)
+}
+
+func diffCommand(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("diff")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return errors.New("diff: need two result files")
+	}
+	before, err := readResults(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := readResults(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	changes := ai.DiffEvaluatorResponses(before, after)
+	return out.write(changes, changesTable(changes))
+}
+
+func reportCommand(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("report")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("report: need a result file")
+	}
+	resp, err := readResults(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return out.write(resp, resultsTable(filepath.Base(fs.Arg(0)), resp))
+}
+
+// newFlagSet returns a flag set for the named command with the flags that
+// select its output.
+func newFlagSet(name string) (*flag.FlagSet, *output) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	out := &output{}
+	fs.StringVar(&out.format, "format", "json", "output `format`: json, csv or html")
+	fs.StringVar(&out.path, "o", "", "write output to `file` instead of standard output")
+	return fs, out
+}
+
+// newGenkit returns a Genkit instance with the available evaluators
+// registered.
+func newGenkit(ctx context.Context) (*genkit.Genkit, error) {
+	g, err := genkit.Init(ctx, genkit.WithPlugins(&evaluators.GenkitEval{
+		Metrics: []evaluators.MetricConfig{
+			{MetricType: evaluators.EvaluatorDeepEqual},
+			{MetricType: evaluators.EvaluatorRegex},
+			{MetricType: evaluators.EvaluatorJsonata},
+		},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	for _, define := range []func(*genkit.Genkit, string, *ai.EvaluatorOptions) (ai.Evaluator, error){
+		genkit.DefineGoCodeEvaluator,
+		genkit.DefineJSONValidityEvaluator,
+		genkit.DefineToolCallAccuracyEvaluator,
+	} {
+		if _, err := define(g, provider, nil); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := genkit.DefinePIIDetectionEvaluator(g, provider, nil, nil); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// readResults reads a result file, migrating it from an earlier schema
+// version if necessary.
+func readResults(path string) (*ai.EvaluatorResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ai.MigrateEvaluatorResponse(data, "")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return resp, nil
+}
+
+// relativeTo resolves path relative to the directory containing file.
+func relativeTo(file, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(file), path)
+}
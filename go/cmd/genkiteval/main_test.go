@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+)
+
+// binary is the path of the genkiteval binary built by TestMain.
+var binary string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "genkiteval")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	binary = filepath.Join(dir, "genkiteval")
+	if out, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "building genkiteval: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// genkiteval runs the binary with args and returns its standard output.
+func genkiteval(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(binary, args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("genkiteval %s: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return string(out)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestList(t *testing.T) {
+	var names []string
+	if err := json.Unmarshal([]byte(genkiteval(t, "list")), &names); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"genkitEval/regex", "genkitEval/json_validity"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("%q missing from %v", want, names)
+		}
+	}
+}
+
+func TestRunReportDiff(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "data.json"), `[
+		{"testCaseId": "valid", "input": "q", "output": "{\"a\": 1}"},
+		{"testCaseId": "invalid", "input": "q", "output": "{\"a\": "}
+	]`)
+	writeFile(t, filepath.Join(dir, "eval.yaml"), `
+evaluator: genkitEval/json_validity
+datasetPath: data.json
+outputPath: out/results.json
+`)
+
+	csv := genkiteval(t, "run", "-format", "csv", filepath.Join(dir, "eval.yaml"))
+	lines := strings.Split(strings.TrimSpace(csv), "\n")
+	if got, want := lines[0], "testCaseId,scoreId,score,status,error"; got != want {
+		t.Errorf("got header %q, want %q", got, want)
+	}
+	if got, want := len(lines), 3; got != want {
+		t.Fatalf("got %d lines, want %d:\n%s", got, want, csv)
+	}
+	if !strings.HasPrefix(lines[1], "valid,") || !strings.Contains(lines[1], ",pass,") {
+		t.Errorf("got %q, want a passing row for test case valid", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "invalid,") || !strings.Contains(lines[2], ",fail,") {
+		t.Errorf("got %q, want a failing row for test case invalid", lines[2])
+	}
+
+	results := filepath.Join(dir, "out", "results.json")
+	report := filepath.Join(dir, "report.html")
+	genkiteval(t, "report", "-format", "html", "-o", report, results)
+	html, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), "<td>invalid</td>") {
+		t.Errorf("report does not contain the invalid test case:\n%s", html)
+	}
+
+	// The previous results are in the legacy format, with "invalid" passing.
+	previous := filepath.Join(dir, "previous.json")
+	writeFile(t, previous, `[
+		{"testCaseId": "valid", "evaluation": [{"id": "json_validity", "score": 1, "status": "pass"}]},
+		{"testCaseId": "invalid", "evaluation": [{"id": "json_validity", "score": 1, "status": "pass"}]}
+	]`)
+	var changes []ai.ScoreDiff
+	if err := json.Unmarshal([]byte(genkiteval(t, "diff", previous, results)), &changes); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].TestCaseId != "invalid" || changes[0].New.Status != "fail" {
+		t.Errorf("got changes %+v, want one change to test case invalid", changes)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if err := exec.Command(binary, "list", "-format", "xml").Run(); err == nil {
+		t.Error("got nil, want error for unknown format")
+	}
+}
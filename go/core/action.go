@@ -114,6 +114,24 @@ func DefineActionWithInputSchema[Out any](
 		})
 }
 
+// DefineTypedActionWithInputSchema creates a new non-streaming Action and
+// registers it. This differs from DefineAction in that inputSchema, if not
+// nil, replaces the schema inferred from In. It is used for actions whose
+// input has fields of static type "any" that hold values of a known type.
+func DefineTypedActionWithInputSchema[In, Out any](
+	r *registry.Registry,
+	provider, name string,
+	atype atype.ActionType,
+	metadata map[string]any,
+	inputSchema *jsonschema.Schema,
+	fn Func[In, Out],
+) *ActionDef[In, Out, struct{}] {
+	return defineAction(r, provider, name, atype, metadata, inputSchema,
+		func(ctx context.Context, in In, cb noStream) (Out, error) {
+			return fn(ctx, in)
+		})
+}
+
 // defineAction creates an action and registers it with the given Registry.
 func defineAction[In, Out, Stream any](
 	r *registry.Registry,
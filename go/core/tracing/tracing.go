@@ -23,8 +23,10 @@ import (
 
 	"github.com/firebase/genkit/go/core/logger"
 	"github.com/firebase/genkit/go/internal/base"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -33,6 +35,9 @@ import (
 type State struct {
 	tp     *sdktrace.TracerProvider // references Stores
 	tracer trace.Tracer             // returned from tp.Tracer(), cached
+
+	mu sync.Mutex
+	mp metric.MeterProvider // nil means the global provider
 }
 
 func NewState() *State {
@@ -47,6 +52,25 @@ func (ts *State) RegisterSpanProcessor(sp sdktrace.SpanProcessor) {
 	ts.tp.RegisterSpanProcessor(sp)
 }
 
+// SetMeterProvider sets the provider of the meter returned by Meter.
+// By default it is the global provider.
+func (ts *State) SetMeterProvider(mp metric.MeterProvider) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.mp = mp
+}
+
+// Meter returns the meter for recording Genkit metrics.
+func (ts *State) Meter() metric.Meter {
+	ts.mu.Lock()
+	mp := ts.mp
+	ts.mu.Unlock()
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	return mp.Meter("genkit")
+}
+
 // WriteTelemetryImmediate adds a telemetry server to the tracingState.
 // Traces are saved immediately as they are finshed.
 // Use this for a gtrace.Store with a fast Save method,
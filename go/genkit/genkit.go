@@ -21,18 +21,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/firebase/genkit/go/ai"
 	"github.com/firebase/genkit/go/core"
 	"github.com/firebase/genkit/go/internal/atype"
 	"github.com/firebase/genkit/go/internal/registry"
 
+	"go.opentelemetry.io/otel/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -547,6 +550,13 @@ func DefineEvaluator(g *Genkit, provider, name string, options *ai.EvaluatorOpti
 	return ai.DefineEvaluator(g.reg, provider, name, options, eval)
 }
 
+// DefineEvaluatorTyped is like [DefineEvaluator] for evaluators whose request
+// options have the concrete type O, which the evaluator's input schema
+// describes. See [ai.DefineEvaluatorTyped].
+func DefineEvaluatorTyped[O any](g *Genkit, provider, name string, options *ai.EvaluatorOptions, eval func(context.Context, *ai.EvaluatorCallbackRequest, O) (*ai.EvaluatorCallbackResponse, error)) (ai.Evaluator, error) {
+	return ai.DefineEvaluatorTyped(g.reg, provider, name, options, eval)
+}
+
 // DefineBatchEvaluator registers the given evaluator function as an action, and
 // returns a [ai.Evaluator] that runs it. This method provide the full
 // [ai.EvaluatorRequest] to the callback function, giving more flexibilty to the
@@ -555,12 +565,267 @@ func DefineBatchEvaluator(g *Genkit, provider, name string, options *ai.Evaluato
 	return ai.DefineBatchEvaluator(g.reg, provider, name, options, eval)
 }
 
+// DefineBatchEvaluatorStream registers an evaluator whose callback reads
+// examples from a channel and sends results on another. See
+// [ai.DefineBatchEvaluatorStream].
+func DefineBatchEvaluatorStream(g *Genkit, provider, name string, opts *ai.EvaluatorOptions, fn func(ctx context.Context, examples <-chan ai.Example, results chan<- *ai.EvaluationResult) error) (ai.Evaluator, error) {
+	return ai.DefineBatchEvaluatorStream(g.reg, provider, name, opts, fn)
+}
+
 // LookupEvaluator looks up a [ai.Evaluator] registered by [DefineEvaluator].
 // It returns nil if the evaluator was not defined.
 func LookupEvaluator(g *Genkit, provider, name string) ai.Evaluator {
 	return ai.LookupEvaluator(g.reg, provider, name)
 }
 
+// LookupEvaluatorByVersion looks up a version of an [ai.Evaluator] defined
+// with [ai.EvaluatorOptions.ActionVersion]. See [ai.LookupEvaluatorByVersion].
+func LookupEvaluatorByVersion(g *Genkit, provider, name, version string) ai.Evaluator {
+	return ai.LookupEvaluatorByVersion(g.reg, provider, name, version)
+}
+
+// ListEvaluators returns all evaluators registered in the Genkit instance,
+// sorted by name.
+func ListEvaluators(g *Genkit) []ai.Evaluator {
+	evals := []ai.Evaluator{}
+	for _, act := range g.reg.ListActions() {
+		name, ok := strings.CutPrefix(act.Key, "/"+string(atype.Evaluator)+"/")
+		if !ok {
+			continue
+		}
+		provider, name, _ := strings.Cut(name, "/")
+		evals = append(evals, ai.LookupEvaluator(g.reg, provider, name))
+	}
+	return evals
+}
+
+// DefineToxicityEvaluator registers a rule-based [ai.Evaluator] that fails
+// examples whose output contains a term from the given blocklist. See
+// [ai.DefineToxicityEvaluator] for the blocklist syntax.
+func DefineToxicityEvaluator(g *Genkit, provider string, blocklist []string, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineToxicityEvaluator(g.reg, provider, blocklist, opts)
+}
+
+// DefinePIIDetectionEvaluator registers an [ai.Evaluator] that fails examples
+// whose output contains personally identifiable information. If patterns is
+// empty, [ai.DefaultPIIPatterns] is used.
+func DefinePIIDetectionEvaluator(g *Genkit, provider string, patterns []ai.PIIPattern, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefinePIIDetectionEvaluator(g.reg, provider, patterns, opts)
+}
+
+// DefineLengthEvaluator registers an [ai.Evaluator] that checks that the
+// length of each output, measured according to mode, is within [min, max].
+// A max of zero means there is no upper bound. See [ai.DefineLengthEvaluator]
+// for how words in Chinese and Japanese text are counted.
+func DefineLengthEvaluator(g *Genkit, provider string, mode ai.LengthMode, min, max int) (ai.Evaluator, error) {
+	return ai.DefineLengthEvaluator(g.reg, provider, mode, min, max)
+}
+
+// DefineReadabilityEvaluator registers an [ai.Evaluator] that checks that the
+// readability grade level of each output, computed with formula, is within
+// targetRange.
+func DefineReadabilityEvaluator(g *Genkit, provider string, formula ai.ReadabilityFormula, targetRange [2]float64) (ai.Evaluator, error) {
+	return ai.DefineReadabilityEvaluator(g.reg, provider, formula, targetRange)
+}
+
+// DefineBERTScoreEvaluator registers an [ai.Evaluator] that approximates
+// BERTScore between each output and reference with sentence embeddings from
+// embedder. See [ai.DefineBERTScoreEvaluator].
+func DefineBERTScoreEvaluator(g *Genkit, provider string, embedder ai.Embedder, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineBERTScoreEvaluator(g.reg, provider, embedder, opts)
+}
+
+// NewEvaluationPipeline returns an empty [ai.EvaluationPipeline] with the
+// given name. The name is used for the pipeline's trace span.
+func NewEvaluationPipeline(g *Genkit, name string) *ai.EvaluationPipeline {
+	return ai.NewEvaluationPipeline(g.reg, name)
+}
+
+// RunKFoldEvaluation runs evals on each of k folds of ds and summarizes their
+// scores across folds. See [ai.RunKFoldEvaluation].
+func RunKFoldEvaluation(ctx context.Context, g *Genkit, evals []ai.Evaluator, ds ai.Dataset, k int, seed int64, opts ...ai.EvaluateOption) (*ai.KFoldReport, error) {
+	return ai.RunKFoldEvaluation(ctx, g.reg, evals, ds, k, seed, opts...)
+}
+
+// DefineToolCallAccuracyEvaluator registers an [ai.Evaluator] that compares
+// the tool calls in each output against the example's expected tool calls.
+func DefineToolCallAccuracyEvaluator(g *Genkit, provider string, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineToolCallAccuracyEvaluator(g.reg, provider, opts)
+}
+
+// DefineToolCallValidityEvaluator registers an [ai.Evaluator] that checks
+// that each example's output is a valid call of a known tool. See
+// [ai.DefineToolCallValidityEvaluator].
+func DefineToolCallValidityEvaluator(g *Genkit, provider string, toolSchemas []ai.ToolSchema, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineToolCallValidityEvaluator(g.reg, provider, toolSchemas, opts)
+}
+
+// DefineCrossLingualEvaluator registers an [ai.Evaluator] that translates
+// each example's output and reference into targetLang before evaluating it
+// with inner. See [ai.DefineCrossLingualEvaluator].
+func DefineCrossLingualEvaluator(g *Genkit, provider, name string, translator ai.TranslateFunc, inner ai.Evaluator, targetLang string, opts ...ai.CrossLingualOption) (ai.Evaluator, error) {
+	return ai.DefineCrossLingualEvaluator(g.reg, provider, name, translator, inner, targetLang, opts...)
+}
+
+// DefineConsistencyEvaluator registers an [ai.Evaluator] that runs inner n
+// times on each example and scores the consistency of its scores. See
+// [ai.DefineConsistencyEvaluator].
+func DefineConsistencyEvaluator(g *Genkit, provider, name string, inner ai.Evaluator, n int, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineConsistencyEvaluator(g.reg, provider, name, inner, n, opts)
+}
+
+// DefineURLVerificationEvaluator registers an [ai.Evaluator] that checks
+// that the URLs in each example's output are reachable. See
+// [ai.DefineURLVerificationEvaluator].
+func DefineURLVerificationEvaluator(g *Genkit, provider string, timeout time.Duration, opts *ai.EvaluatorOptions, urlOpts ...ai.URLVerificationOption) (ai.Evaluator, error) {
+	return ai.DefineURLVerificationEvaluator(g.reg, provider, timeout, opts, urlOpts...)
+}
+
+// DefineGoCodeEvaluator registers an [ai.Evaluator] that runs each example's
+// reference Go tests against the generated Go code in its output.
+func DefineGoCodeEvaluator(g *Genkit, provider string, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineGoCodeEvaluator(g.reg, provider, opts)
+}
+
+// DefineCodeStyleEvaluator registers an [ai.Evaluator] that checks that
+// each example's output is Go source formatted with gofmt. See
+// [ai.DefineCodeStyleEvaluator].
+func DefineCodeStyleEvaluator(g *Genkit, provider string, opts *ai.EvaluatorOptions, styleOpts ...ai.CodeStyleOption) (ai.Evaluator, error) {
+	return ai.DefineCodeStyleEvaluator(g.reg, provider, opts, styleOpts...)
+}
+
+// DefineJSONValidityEvaluator registers an [ai.Evaluator] that checks that
+// each output is valid JSON.
+func DefineJSONValidityEvaluator(g *Genkit, provider string, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineJSONValidityEvaluator(g.reg, provider, opts)
+}
+
+// DefineMarkdownEvaluator registers an [ai.Evaluator] that checks that each
+// output is Markdown satisfying rules.
+func DefineMarkdownEvaluator(g *Genkit, provider string, rules []ai.MarkdownRule, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineMarkdownEvaluator(g.reg, provider, rules, opts)
+}
+
+// DefineMultilingualEvaluator registers an [ai.Evaluator] that grades each
+// output with an LLM judge using the rubric for the example's language.
+func DefineMultilingualEvaluator(g *Genkit, provider, name string, rubrics map[string]string, model ai.Model, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineMultilingualEvaluator(g.reg, provider, name, rubrics, model, opts)
+}
+
+// DefineEntailmentEvaluator registers an [ai.Evaluator] that checks that
+// each output and its reference entail each other, using an NLI model.
+func DefineEntailmentEvaluator(g *Genkit, provider, name string, nliModel ai.Model, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineEntailmentEvaluator(g.reg, provider, name, nliModel, opts)
+}
+
+// DefineChildProcessEvaluator registers an [ai.Evaluator] implemented by the
+// program command, which exchanges newline-delimited JSON with Genkit over
+// its standard input and output. See [ai.DefineChildProcessEvaluator] for
+// the protocol. Call Close on the evaluator to stop the program.
+func DefineChildProcessEvaluator(g *Genkit, provider, name string, command []string, timeout time.Duration, opts *ai.EvaluatorOptions) (ai.ChildProcessEvaluator, error) {
+	return ai.DefineChildProcessEvaluator(g.reg, provider, name, command, timeout, opts)
+}
+
+// DefineCitationAccuracyEvaluator registers an [ai.Evaluator] that checks
+// that the citations in each output refer to context documents that support
+// the cited claims, using model to judge support.
+func DefineCitationAccuracyEvaluator(g *Genkit, provider, name string, model ai.Model, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineCitationAccuracyEvaluator(g.reg, provider, name, model, opts)
+}
+
+// DefineCitationFormatEvaluator registers an [ai.Evaluator] that checks that
+// the in-text citations in each output follow format. See
+// [ai.DefineCitationFormatEvaluator].
+func DefineCitationFormatEvaluator(g *Genkit, provider string, format ai.CitationFormat, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineCitationFormatEvaluator(g.reg, provider, format, opts)
+}
+
+// DefineRubricEvaluator registers an evaluator that asks model to grade each
+// example against every criterion of rubric. See [ai.DefineRubricEvaluator].
+func DefineRubricEvaluator(g *Genkit, provider, name string, model ai.Model, rubric ai.EvaluatorRubric, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineRubricEvaluator(g.reg, provider, name, model, rubric, opts)
+}
+
+// DefineMultiScoreEvaluator registers an [ai.Evaluator] that asks model for
+// several named scores of each example in a single call. See
+// [ai.DefineMultiScoreEvaluator].
+func DefineMultiScoreEvaluator(g *Genkit, provider, name string, model ai.Model, scoreNames []string, promptTemplate string, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineMultiScoreEvaluator(g.reg, provider, name, model, scoreNames, promptTemplate, opts)
+}
+
+// DefineInstructionFollowingEvaluator registers an evaluator that uses model
+// to check that each output follows the instructions in its input. See
+// [ai.DefineInstructionFollowingEvaluator].
+func DefineInstructionFollowingEvaluator(g *Genkit, provider, name string, model ai.Model, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineInstructionFollowingEvaluator(g.reg, provider, name, model, opts)
+}
+
+// DefineImageEvaluator registers an evaluator that uses model to judge image
+// outputs against their text references. See [ai.DefineImageEvaluator].
+func DefineImageEvaluator(g *Genkit, provider, name string, model ai.VisionModel, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	return ai.DefineImageEvaluator(g.reg, provider, name, model, opts)
+}
+
+// NewModelVisionModel returns an [ai.VisionModel] that asks model to grade
+// images. See [ai.NewModelVisionModel].
+func NewModelVisionModel(g *Genkit, model ai.Model) ai.VisionModel {
+	return ai.NewModelVisionModel(g.reg, model)
+}
+
+// RegisterScoreAggregator registers agg under name for use with
+// [WithAggregationStrategy]. See [ai.RegisterScoreAggregator].
+func RegisterScoreAggregator(g *Genkit, name string, agg ai.AggregatorPlugin) {
+	ai.RegisterScoreAggregator(g.reg, name, agg)
+}
+
+// WithAggregationStrategy makes [ai.AggregateScores] combine scores using the
+// aggregator registered under name.
+func WithAggregationStrategy(g *Genkit, name string) ai.AggregateOption {
+	return ai.WithAggregationStrategy(g.reg, name)
+}
+
+// RegisterBenchmarkDataset registers loader under name for use with
+// [LookupBenchmarkDataset]. See [ai.RegisterBenchmarkDataset].
+func RegisterBenchmarkDataset(g *Genkit, name string, loader ai.DatasetLoader) {
+	ai.RegisterBenchmarkDataset(g.reg, name, loader)
+}
+
+// LookupBenchmarkDataset returns the dataset loader registered under name,
+// or the built-in one with that name. See [ai.LookupBenchmarkDataset].
+func LookupBenchmarkDataset(g *Genkit, name string) ai.DatasetLoader {
+	return ai.LookupBenchmarkDataset(g.reg, name)
+}
+
+// RegisterResponseSerializer registers s under format for use with
+// [SerializeResponse] and [DeserializeResponse]. See
+// [ai.RegisterResponseSerializer].
+func RegisterResponseSerializer(g *Genkit, format string, s ai.ResponseSerializer) {
+	ai.RegisterResponseSerializer(g.reg, format, s)
+}
+
+// SerializeResponse writes resp to w in format. See [ai.SerializeResponse].
+func SerializeResponse(g *Genkit, resp *ai.EvaluatorResponse, format string, w io.Writer) error {
+	return ai.SerializeResponse(g.reg, resp, format, w)
+}
+
+// DeserializeResponse reads a response in format from r. See
+// [ai.DeserializeResponse].
+func DeserializeResponse(g *Genkit, r io.Reader, format string) (*ai.EvaluatorResponse, error) {
+	return ai.DeserializeResponse(g.reg, r, format)
+}
+
+// GenerateSyntheticDataset asks model to write n evaluation examples
+// matching spec. See [ai.GenerateSyntheticDataset].
+func GenerateSyntheticDataset(ctx context.Context, g *Genkit, model ai.Model, spec ai.DatasetSpec, n int) (ai.Dataset, error) {
+	return ai.GenerateSyntheticDataset(ctx, g.reg, model, spec, n)
+}
+
+// RunFromConfig runs the evaluation described by cfg, which is typically
+// read with [ai.ParseEvalConfig], using an evaluator registered in g.
+func RunFromConfig(ctx context.Context, g *Genkit, cfg *ai.EvalConfig) (*ai.EvaluatorResponse, error) {
+	return ai.RunFromConfig(ctx, g.reg, cfg)
+}
+
 // LoadPromptDir loads all prompts and partials from a given directory with the specified namespace.
 func LoadPromptDir(g *Genkit, dir string, namespace string) error {
 	return ai.LoadPromptDir(g.reg, dir, namespace)
@@ -596,3 +861,11 @@ func LoadPrompt(g *Genkit, path string, namespace string) (*ai.Prompt, error) {
 func RegisterSpanProcessor(g *Genkit, sp sdktrace.SpanProcessor) {
 	g.reg.RegisterSpanProcessor(sp)
 }
+
+// SetMeterProvider sets the OpenTelemetry MeterProvider for metrics recorded
+// by Genkit, such as those emitted by evaluators through
+// [ai.EvaluatorCallbackRequest.Metrics]. By default the global provider is
+// used.
+func SetMeterProvider(g *Genkit, mp metric.MeterProvider) {
+	g.reg.SetMeterProvider(mp)
+}
@@ -54,10 +54,28 @@ func ValidateJSON(dataBytes json.RawMessage, schema *jsonschema.Schema) error {
 // ValidateRaw will validate JSON data against the JSON schema.
 // It will return an error if it doesn't match the schema, otherwise it will return nil.
 func ValidateRaw(dataBytes json.RawMessage, schemaBytes json.RawMessage) error {
+	violations, err := SchemaViolations(dataBytes, schemaBytes)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		var errors []string
+		for _, v := range violations {
+			errors = append(errors, fmt.Sprintf("- %s", v))
+		}
+		return fmt.Errorf("data did not match expected schema:\n%s", strings.Join(errors, "\n"))
+	}
+	return nil
+}
+
+// SchemaViolations validates JSON data against the JSON schema and returns a
+// description of each way in which it does not match. It returns an error
+// only if the data or the schema could not be read.
+func SchemaViolations(dataBytes json.RawMessage, schemaBytes json.RawMessage) ([]string, error) {
 	var data any
 	// Do this check separately from below to get a better error message.
 	if err := json.Unmarshal(dataBytes, &data); err != nil {
-		return fmt.Errorf("data is not valid JSON: %w", err)
+		return nil, fmt.Errorf("data is not valid JSON: %w", err)
 	}
 
 	schemaLoader := gojsonschema.NewBytesLoader(schemaBytes)
@@ -65,16 +83,12 @@ func ValidateRaw(dataBytes json.RawMessage, schemaBytes json.RawMessage) error {
 
 	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
 	if err != nil {
-		return fmt.Errorf("failed to validate data against expected schema: %w", err)
+		return nil, fmt.Errorf("failed to validate data against expected schema: %w", err)
 	}
 
-	if !result.Valid() {
-		var errors []string
-		for _, err := range result.Errors() {
-			errors = append(errors, fmt.Sprintf("- %s", err))
-		}
-		return fmt.Errorf("data did not match expected schema:\n%s", strings.Join(errors, "\n"))
+	var violations []string
+	for _, err := range result.Errors() {
+		violations = append(violations, err.String())
 	}
-
-	return nil
+	return violations, nil
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/firebase/genkit/go/internal/action"
 	"github.com/firebase/genkit/go/internal/atype"
 	"github.com/google/dotprompt/go/dotprompt"
+	"go.opentelemetry.io/otel/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"golang.org/x/exp/maps"
 )
@@ -163,6 +164,12 @@ func (r *Registry) RegisterSpanProcessor(sp sdktrace.SpanProcessor) {
 	r.tstate.RegisterSpanProcessor(sp)
 }
 
+// SetMeterProvider sets the provider of the meter used for metrics recorded
+// through the registry's tracing state.
+func (r *Registry) SetMeterProvider(mp metric.MeterProvider) {
+	r.tstate.SetMeterProvider(mp)
+}
+
 // An Environment is the execution context in which the program is running.
 type Environment string
 
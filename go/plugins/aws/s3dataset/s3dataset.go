@@ -0,0 +1,266 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package s3dataset provides an [ai.DatasetStore] backed by Amazon S3.
+package s3dataset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/firebase/genkit/go/ai"
+)
+
+// partSize is the size of the parts of multipart uploads. Datasets smaller
+// than a part are uploaded with a single request.
+const partSize = 8 << 20
+
+// Option configures a [Store].
+type Option func(s *Store)
+
+// WithServerSideEncryption makes S3 encrypt saved datasets with keys it
+// manages (SSE-S3).
+func WithServerSideEncryption() Option {
+	return func(s *Store) {
+		s.sse = types.ServerSideEncryptionAes256
+	}
+}
+
+// WithKMSKey makes S3 encrypt saved datasets with the AWS KMS key keyID
+// (SSE-KMS). It takes precedence over [WithServerSideEncryption].
+func WithKMSKey(keyID string) Option {
+	return func(s *Store) {
+		s.kmsKeyID = keyID
+	}
+}
+
+// Store is an [ai.DatasetStore] that keeps each dataset in an object of an
+// S3 bucket. Datasets are streamed from S3 when loaded and uploaded in
+// parts when saved, so they are never held in memory in encoded form.
+type Store struct {
+	client    s3API
+	presigner presignAPI
+	bucket    string
+	prefix    string
+	sse       types.ServerSideEncryption
+	kmsKeyID  string
+	partSize  int
+}
+
+// NewS3DatasetStore returns a [Store] that keeps the dataset named name in
+// the object prefix+name of bucket, using a client created from awsCfg. As
+// described in [ai.DatasetStore], names ending in ".jsonl" are stored as
+// JSONL and others as JSON.
+func NewS3DatasetStore(bucket, prefix string, awsCfg aws.Config, opts ...Option) *Store {
+	client := s3.NewFromConfig(awsCfg)
+	return newStore(client, s3.NewPresignClient(client), bucket, prefix, opts)
+}
+
+func newStore(client s3API, presigner presignAPI, bucket, prefix string, opts []Option) *Store {
+	s := &Store{client: client, presigner: presigner, bucket: bucket, prefix: prefix, partSize: partSize}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Load implements [ai.DatasetStore.Load].
+func (s *Store) Load(ctx context.Context, name string) (ai.Dataset, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + name),
+	})
+	if nsk := (*types.NoSuchKey)(nil); errors.As(err, &nsk) {
+		return nil, fmt.Errorf("s3dataset: %w: %q", ai.ErrDatasetNotFound, s.prefix+name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("s3dataset: %w", err)
+	}
+	defer out.Body.Close()
+	ds, err := ai.ReadDataset(out.Body, name)
+	if err != nil {
+		return nil, fmt.Errorf("s3dataset: %w", err)
+	}
+	return ds, nil
+}
+
+// Save implements [ai.DatasetStore.Save]. If writing fails, the object is
+// left unchanged.
+func (s *Store) Save(ctx context.Context, name string, ds ai.Dataset) error {
+	contentType := "application/json"
+	if path.Ext(name) == ".jsonl" {
+		contentType = "application/jsonl"
+	}
+	w := &uploader{ctx: ctx, s: s, key: s.prefix + name, contentType: contentType}
+	if err := ai.WriteDataset(w, name, ds); err != nil {
+		w.abort()
+		return fmt.Errorf("s3dataset: %w", err)
+	}
+	if err := w.close(); err != nil {
+		w.abort()
+		return fmt.Errorf("s3dataset: %w", err)
+	}
+	return nil
+}
+
+// PresignedURL returns a URL that gives anyone holding it read access to
+// the dataset named name for the duration expires, for sharing the dataset
+// with tools that have no AWS credentials.
+func (s *Store) PresignedURL(ctx context.Context, name string, expires time.Duration) (string, error) {
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + name),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("s3dataset: %w", err)
+	}
+	return req.URL, nil
+}
+
+// uploader is an io.Writer that uploads what is written to it to S3 in
+// parts. Data is sent with a single PutObject request unless it exceeds a
+// part, in which case a multipart upload is used.
+type uploader struct {
+	ctx         context.Context
+	s           *Store
+	key         string
+	contentType string
+	buf         bytes.Buffer
+	uploadId    *string
+	parts       []types.CompletedPart
+}
+
+func (u *uploader) Write(p []byte) (int, error) {
+	n, _ := u.buf.Write(p)
+	for u.buf.Len() >= u.s.partSize {
+		if err := u.uploadPart(u.buf.Next(u.s.partSize)); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// uploadPart uploads part, starting a multipart upload if needed.
+func (u *uploader) uploadPart(part []byte) error {
+	if u.uploadId == nil {
+		out, err := u.s.client.CreateMultipartUpload(u.ctx, &s3.CreateMultipartUploadInput{
+			Bucket:               aws.String(u.s.bucket),
+			Key:                  aws.String(u.key),
+			ContentType:          aws.String(u.contentType),
+			ServerSideEncryption: u.s.encryption(),
+			SSEKMSKeyId:          u.s.kmsKey(),
+		})
+		if err != nil {
+			return err
+		}
+		u.uploadId = out.UploadId
+	}
+	num := aws.Int32(int32(len(u.parts) + 1))
+	out, err := u.s.client.UploadPart(u.ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(u.s.bucket),
+		Key:        aws.String(u.key),
+		UploadId:   u.uploadId,
+		PartNumber: num,
+		Body:       bytes.NewReader(part),
+	})
+	if err != nil {
+		return err
+	}
+	u.parts = append(u.parts, types.CompletedPart{ETag: out.ETag, PartNumber: num})
+	return nil
+}
+
+// close uploads the remaining data and commits the object.
+func (u *uploader) close() error {
+	if u.uploadId == nil {
+		_, err := u.s.client.PutObject(u.ctx, &s3.PutObjectInput{
+			Bucket:               aws.String(u.s.bucket),
+			Key:                  aws.String(u.key),
+			ContentType:          aws.String(u.contentType),
+			Body:                 bytes.NewReader(u.buf.Bytes()),
+			ServerSideEncryption: u.s.encryption(),
+			SSEKMSKeyId:          u.s.kmsKey(),
+		})
+		return err
+	}
+	if u.buf.Len() > 0 {
+		if err := u.uploadPart(u.buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	_, err := u.s.client.CompleteMultipartUpload(u.ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.s.bucket),
+		Key:             aws.String(u.key),
+		UploadId:        u.uploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: u.parts},
+	})
+	return err
+}
+
+// abort discards the parts of an unfinished multipart upload.
+func (u *uploader) abort() {
+	if u.uploadId == nil {
+		return
+	}
+	// The upload failed already; S3 eventually removes parts that are not
+	// aborted, so an error here is not reported.
+	u.s.client.AbortMultipartUpload(context.WithoutCancel(u.ctx), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.s.bucket),
+		Key:      aws.String(u.key),
+		UploadId: u.uploadId,
+	})
+	u.uploadId = nil
+}
+
+// encryption returns the server-side encryption of uploads.
+func (s *Store) encryption() types.ServerSideEncryption {
+	if s.kmsKeyID != "" {
+		return types.ServerSideEncryptionAwsKms
+	}
+	return s.sse
+}
+
+// kmsKey returns the KMS key of uploads, or nil.
+func (s *Store) kmsKey() *string {
+	if s.kmsKeyID == "" {
+		return nil
+	}
+	return aws.String(s.kmsKeyID)
+}
+
+// s3API is the part of the S3 API used by [Store]. *s3.Client implements it.
+type s3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// presignAPI is the part of the S3 presign API used by [Store].
+// *s3.PresignClient implements it.
+type presignAPI interface {
+	PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3dataset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/firebase/genkit/go/ai"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeS3 is an in-memory [s3API].
+type fakeS3 struct {
+	objects    map[string][]byte
+	encryption map[string]string
+	uploads    map[string][][]byte
+	aborted    int
+	requests   []string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}, encryption: map[string]string{}, uploads: map[string][][]byte{}}
+}
+
+func (f *fakeS3) GetObject(ctx context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	b, ok := f.objects[*in.Key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func (f *fakeS3) PutObject(ctx context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.requests = append(f.requests, "put")
+	b, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*in.Key] = b
+	f.encryption[*in.Key] = fmt.Sprintf("%s %s", in.ServerSideEncryption, aws.ToString(in.SSEKMSKeyId))
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) CreateMultipartUpload(ctx context.Context, in *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	f.requests = append(f.requests, "create")
+	id := fmt.Sprint(len(f.uploads))
+	f.uploads[id] = nil
+	f.encryption[*in.Key] = fmt.Sprintf("%s %s", in.ServerSideEncryption, aws.ToString(in.SSEKMSKeyId))
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+func (f *fakeS3) UploadPart(ctx context.Context, in *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	f.requests = append(f.requests, "part")
+	b, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.uploads[*in.UploadId] = append(f.uploads[*in.UploadId], b)
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprint(*in.PartNumber))}, nil
+}
+
+func (f *fakeS3) CompleteMultipartUpload(ctx context.Context, in *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	f.requests = append(f.requests, "complete")
+	parts := f.uploads[*in.UploadId]
+	if len(in.MultipartUpload.Parts) != len(parts) {
+		return nil, errors.New("part count mismatch")
+	}
+	f.objects[*in.Key] = bytes.Join(parts, nil)
+	delete(f.uploads, *in.UploadId)
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (f *fakeS3) AbortMultipartUpload(ctx context.Context, in *s3.AbortMultipartUploadInput, _ ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	f.aborted++
+	delete(f.uploads, *in.UploadId)
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func testStore(t *testing.T, opts ...Option) (*Store, *fakeS3) {
+	t.Helper()
+	cfg := aws.Config{
+		Region: "us-east-1",
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+		}),
+	}
+	s := NewS3DatasetStore("evals", "datasets/", cfg, opts...)
+	fake := newFakeS3()
+	s.client = fake
+	return s, fake
+}
+
+var dataset = ai.Dataset{
+	{TestCaseId: "a", Input: "question", Output: "answer"},
+	{TestCaseId: "b", Input: "other", Reference: "ref"},
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s, fake := testStore(t)
+
+	for _, name := range []string{"golden.json", "golden.jsonl"} {
+		if err := s.Save(ctx, name, dataset); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Load(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(dataset, got); diff != "" {
+			t.Errorf("%s: mismatch (-want +got):\n%s", name, diff)
+		}
+	}
+	if diff := cmp.Diff([]string{"put", "put"}, fake.requests); diff != "" {
+		t.Errorf("small datasets: requests mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := s.Load(ctx, "missing.json"); !errors.Is(err, ai.ErrDatasetNotFound) {
+		t.Errorf("got %v, want ErrDatasetNotFound", err)
+	}
+}
+
+func TestStoreMultipart(t *testing.T) {
+	ctx := context.Background()
+	s, fake := testStore(t)
+	s.partSize = 64
+
+	if err := s.Save(ctx, "golden.jsonl", dataset); err != nil {
+		t.Fatal(err)
+	}
+	if fake.requests[0] != "create" || fake.requests[len(fake.requests)-1] != "complete" || len(fake.requests) < 4 {
+		t.Errorf("got requests %v, want a multipart upload of several parts", fake.requests)
+	}
+	got, err := s.Load(ctx, "golden.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(dataset, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// A dataset that cannot be encoded aborts the upload and leaves the
+	// stored object unchanged.
+	before := string(fake.objects["datasets/golden.jsonl"])
+	bad := append(ai.Dataset{{Input: strings.Repeat("x", 200)}}, ai.Example{Input: math.NaN()})
+	if err := s.Save(ctx, "golden.jsonl", bad); err == nil {
+		t.Error("got nil, want error for a dataset that cannot be encoded")
+	}
+	if fake.aborted != 1 || len(fake.uploads) != 0 {
+		t.Errorf("got %d aborted and %d pending uploads, want 1 and 0", fake.aborted, len(fake.uploads))
+	}
+	if got := string(fake.objects["datasets/golden.jsonl"]); got != before {
+		t.Error("failed save modified the stored dataset")
+	}
+}
+
+func TestStoreEncryption(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		opts []Option
+		want string
+	}{
+		{nil, " "},
+		{[]Option{WithServerSideEncryption()}, "AES256 "},
+		{[]Option{WithServerSideEncryption(), WithKMSKey("key-1")}, "aws:kms key-1"},
+	} {
+		s, fake := testStore(t, test.opts...)
+		if err := s.Save(ctx, "golden.json", dataset); err != nil {
+			t.Fatal(err)
+		}
+		if got := fake.encryption["datasets/golden.json"]; got != test.want {
+			t.Errorf("got encryption %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestPresignedURL(t *testing.T) {
+	s, _ := testStore(t)
+	u, err := s.PresignedURL(context.Background(), "golden.json", 15*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"evals", "datasets/golden.json", "X-Amz-Expires=900", "X-Amz-Signature="} {
+		if !strings.Contains(u, want) {
+			t.Errorf("URL %q does not contain %q", u, want)
+		}
+	}
+}
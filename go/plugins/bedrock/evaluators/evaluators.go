@@ -0,0 +1,359 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package evaluators provides Genkit evaluators that use models on AWS
+// Bedrock as LLM judges.
+package evaluators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+)
+
+// InvokeModelAPI is the part of the Bedrock runtime API used by the
+// evaluators. *bedrockruntime.Client implements it.
+type InvokeModelAPI interface {
+	InvokeModel(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error)
+}
+
+// maxTokens is the largest number of tokens the judge may generate.
+const maxTokens = 1024
+
+// passThreshold is the lowest judge score that passes.
+const passThreshold = 0.5
+
+// DefineEvaluatorFromBedrockModel defines an evaluator that asks the Bedrock
+// model modelId, called through client, to grade each example against the
+// criteria in opts.Definition. The model replies with a score between 0 and
+// 1 and a reasoning; scores of at least 0.5 pass.
+//
+// modelId may be a base model ID such as "anthropic.claude-3-haiku-20240307-v1:0"
+// or an inference profile ID with a region prefix such as
+// "us.meta.llama3-1-70b-instruct-v1:0". The request and response bodies are
+// formatted for the model's family: Anthropic, Amazon Titan and Nova,
+// Mistral, Meta Llama, Cohere Command and AI21 Jamba.
+func DefineEvaluatorFromBedrockModel(g *genkit.Genkit, client InvokeModelAPI, provider, name, modelId string, opts *ai.EvaluatorOptions) (ai.Evaluator, error) {
+	if client == nil {
+		return nil, errors.New("bedrock: client is nil")
+	}
+	if opts == nil || opts.Definition == "" {
+		return nil, errors.New("bedrock: opts.Definition must describe the grading criteria")
+	}
+	f, err := familyOf(modelId)
+	if err != nil {
+		return nil, err
+	}
+	criteria := opts.Definition
+	return genkit.DefineEvaluator(g, provider, name, opts, func(ctx context.Context, req *ai.EvaluatorCallbackRequest) (*ai.EvaluatorCallbackResponse, error) {
+		prompt, err := judgePrompt(criteria, &req.Input)
+		if err != nil {
+			return nil, err
+		}
+		body, err := f.request(prompt)
+		if err != nil {
+			return nil, err
+		}
+		out, err := client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+			ModelId:     aws.String(modelId),
+			Body:        body,
+			ContentType: aws.String("application/json"),
+			Accept:      aws.String("application/json"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("bedrock: invoking %s: %w", modelId, err)
+		}
+		text, err := f.response(out.Body)
+		if err != nil {
+			return nil, fmt.Errorf("bedrock: response of %s: %w", modelId, err)
+		}
+		v, err := parseVerdict(text)
+		if err != nil {
+			return nil, fmt.Errorf("bedrock: response of %s: %w", modelId, err)
+		}
+		status := ai.ScoreStatusFail
+		if v.Score >= passThreshold {
+			status = ai.ScoreStatusPass
+		}
+		return &ai.EvaluatorCallbackResponse{
+			TestCaseId: req.Input.TestCaseId,
+			Evaluation: []ai.Score{{
+				Id:      name,
+				Score:   v.Score,
+				Status:  status.String(),
+				Details: map[string]any{"reasoning": v.Reasoning, "model": modelId},
+			}},
+		}, nil
+	})
+}
+
+// A family formats the request and response bodies of the InvokeModel API
+// for the models of one provider.
+type family struct {
+	// request returns the request body asking the model to complete prompt.
+	request func(prompt string) ([]byte, error)
+	// response returns the generated text in a response body.
+	response func(body []byte) (string, error)
+}
+
+// families maps the provider segment of a Bedrock model ID to its family.
+var families = map[string]family{
+	"anthropic": {
+		request: func(prompt string) ([]byte, error) {
+			return json.Marshal(map[string]any{
+				"anthropic_version": "bedrock-2023-05-31",
+				"max_tokens":        maxTokens,
+				"temperature":       0,
+				"messages": []map[string]any{{
+					"role":    "user",
+					"content": []map[string]any{{"type": "text", "text": prompt}},
+				}},
+			})
+		},
+		response: func(body []byte) (string, error) {
+			var resp struct {
+				Content []struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"content"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return "", err
+			}
+			var sb strings.Builder
+			for _, c := range resp.Content {
+				if c.Type == "text" {
+					sb.WriteString(c.Text)
+				}
+			}
+			return sb.String(), nil
+		},
+	},
+	"amazon.titan": {
+		request: func(prompt string) ([]byte, error) {
+			return json.Marshal(map[string]any{
+				"inputText":            prompt,
+				"textGenerationConfig": map[string]any{"maxTokenCount": maxTokens, "temperature": 0},
+			})
+		},
+		response: func(body []byte) (string, error) {
+			var resp struct {
+				Results []struct {
+					OutputText string `json:"outputText"`
+				} `json:"results"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return "", err
+			}
+			if len(resp.Results) == 0 {
+				return "", errors.New("no results")
+			}
+			return resp.Results[0].OutputText, nil
+		},
+	},
+	"amazon.nova": {
+		request: func(prompt string) ([]byte, error) {
+			return json.Marshal(map[string]any{
+				"messages":        []map[string]any{{"role": "user", "content": []map[string]any{{"text": prompt}}}},
+				"inferenceConfig": map[string]any{"maxTokens": maxTokens, "temperature": 0},
+			})
+		},
+		response: func(body []byte) (string, error) {
+			var resp struct {
+				Output struct {
+					Message struct {
+						Content []struct {
+							Text string `json:"text"`
+						} `json:"content"`
+					} `json:"message"`
+				} `json:"output"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return "", err
+			}
+			var sb strings.Builder
+			for _, c := range resp.Output.Message.Content {
+				sb.WriteString(c.Text)
+			}
+			return sb.String(), nil
+		},
+	},
+	"mistral": {
+		request: func(prompt string) ([]byte, error) {
+			return json.Marshal(map[string]any{
+				"prompt":      "<s>[INST] " + prompt + " [/INST]",
+				"max_tokens":  maxTokens,
+				"temperature": 0,
+			})
+		},
+		response: func(body []byte) (string, error) {
+			var resp struct {
+				Outputs []struct {
+					Text string `json:"text"`
+				} `json:"outputs"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return "", err
+			}
+			if len(resp.Outputs) == 0 {
+				return "", errors.New("no outputs")
+			}
+			return resp.Outputs[0].Text, nil
+		},
+	},
+	"meta": {
+		request: func(prompt string) ([]byte, error) {
+			return json.Marshal(map[string]any{
+				"prompt":      "<|begin_of_text|><|start_header_id|>user<|end_header_id|>\n\n" + prompt + "<|eot_id|><|start_header_id|>assistant<|end_header_id|>\n\n",
+				"max_gen_len": maxTokens,
+				"temperature": 0,
+			})
+		},
+		response: func(body []byte) (string, error) {
+			var resp struct {
+				Generation string `json:"generation"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return "", err
+			}
+			return resp.Generation, nil
+		},
+	},
+	"cohere": {
+		request: func(prompt string) ([]byte, error) {
+			return json.Marshal(map[string]any{
+				"message":     prompt,
+				"max_tokens":  maxTokens,
+				"temperature": 0,
+			})
+		},
+		response: func(body []byte) (string, error) {
+			var resp struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return "", err
+			}
+			return resp.Text, nil
+		},
+	},
+	"ai21": {
+		request: func(prompt string) ([]byte, error) {
+			return json.Marshal(map[string]any{
+				"messages":    []map[string]any{{"role": "user", "content": prompt}},
+				"max_tokens":  maxTokens,
+				"temperature": 0,
+			})
+		},
+		response: func(body []byte) (string, error) {
+			var resp struct {
+				Choices []struct {
+					Message struct {
+						Content string `json:"content"`
+					} `json:"message"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return "", err
+			}
+			if len(resp.Choices) == 0 {
+				return "", errors.New("no choices")
+			}
+			return resp.Choices[0].Message.Content, nil
+		},
+	},
+}
+
+// familyOf returns the family of the model modelId, ignoring the region
+// prefix of inference profile IDs.
+func familyOf(modelId string) (family, error) {
+	id := modelId
+	for _, region := range []string{"us.", "eu.", "apac.", "us-gov."} {
+		id = strings.TrimPrefix(id, region)
+	}
+	provider, rest, _ := strings.Cut(id, ".")
+	if provider == "amazon" {
+		if series, _, _ := strings.Cut(rest, "-"); series == "titan" || series == "nova" {
+			provider += "." + series
+		}
+	}
+	f, ok := families[provider]
+	if !ok {
+		return family{}, fmt.Errorf("bedrock: unsupported model %q", modelId)
+	}
+	return f, nil
+}
+
+// judgePrompt returns the prompt asking the judge to grade ex against
+// criteria. Unlike Anthropic's API, most Bedrock models have no structured
+// output mode, so the prompt asks for a bare JSON object.
+func judgePrompt(criteria string, ex *ai.Example) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("You are grading the output of an AI system.\n\n")
+	fmt.Fprintf(&sb, "Criteria:\n%s\n\n", criteria)
+	for _, f := range []struct {
+		label string
+		value any
+	}{
+		{"Input", ex.Input},
+		{"Output", ex.Output},
+		{"Reference", ex.Reference},
+	} {
+		if f.value == nil {
+			continue
+		}
+		text, ok := f.value.(string)
+		if !ok {
+			b, err := json.Marshal(f.value)
+			if err != nil {
+				return "", fmt.Errorf("bedrock: %s: %w", strings.ToLower(f.label), err)
+			}
+			text = string(b)
+		}
+		fmt.Fprintf(&sb, "%s:\n%s\n\n", f.label, text)
+	}
+	sb.WriteString(`Respond only with a JSON object of the form {"score": <number between 0 and 1>, "reasoning": "<short explanation>"}, where a score of 1 fully meets the criteria.`)
+	return sb.String(), nil
+}
+
+// verdict is the judge's reply.
+type verdict struct {
+	Score     float64 `json:"score"`
+	Reasoning string  `json:"reasoning"`
+}
+
+// parseVerdict extracts the JSON verdict from the judge's reply, which may
+// surround it with other text or a code fence. The score is clamped to
+// [0, 1].
+func parseVerdict(text string) (*verdict, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in %q", text)
+	}
+	var v verdict
+	if err := json.Unmarshal([]byte(text[start:end+1]), &v); err != nil {
+		return nil, fmt.Errorf("parsing verdict %q: %w", text, err)
+	}
+	v.Score = min(max(v.Score, 0), 1)
+	return &v, nil
+}
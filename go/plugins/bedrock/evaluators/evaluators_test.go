@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+)
+
+// fakeClient is an [InvokeModelAPI] that records the request and returns a
+// canned response body.
+type fakeClient struct {
+	body    []byte
+	err     error
+	modelId string
+	request map[string]any
+}
+
+func (c *fakeClient) InvokeModel(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+	c.modelId = *params.ModelId
+	if err := json.Unmarshal(params.Body, &c.request); err != nil {
+		return nil, err
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &bedrockruntime.InvokeModelOutput{Body: c.body}, nil
+}
+
+func TestDefineEvaluatorFromBedrockModel(t *testing.T) {
+	g, err := genkit.Init(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const reply = `Here is my grade: {"score": 0.8, "reasoning": "mostly correct"}`
+	quoted, _ := json.Marshal(reply)
+
+	tests := []struct {
+		modelId string
+		body    string
+		// promptKey is the request field that holds the prompt.
+		promptKey string
+	}{
+		{"anthropic.claude-3-haiku-20240307-v1:0", `{"content":[{"type":"text","text":` + string(quoted) + `}]}`, "messages"},
+		{"us.anthropic.claude-3-5-sonnet-20241022-v2:0", `{"content":[{"type":"text","text":` + string(quoted) + `}]}`, "messages"},
+		{"amazon.titan-text-express-v1", `{"results":[{"outputText":` + string(quoted) + `}]}`, "inputText"},
+		{"amazon.nova-pro-v1:0", `{"output":{"message":{"content":[{"text":` + string(quoted) + `}]}}}`, "messages"},
+		{"mistral.mistral-large-2402-v1:0", `{"outputs":[{"text":` + string(quoted) + `}]}`, "prompt"},
+		{"meta.llama3-70b-instruct-v1:0", `{"generation":` + string(quoted) + `}`, "prompt"},
+		{"cohere.command-r-plus-v1:0", `{"text":` + string(quoted) + `}`, "message"},
+		{"ai21.jamba-1-5-large-v1:0", `{"choices":[{"message":{"content":` + string(quoted) + `}}]}`, "messages"},
+	}
+	for i, test := range tests {
+		client := &fakeClient{body: []byte(test.body)}
+		opts := &ai.EvaluatorOptions{DisplayName: "Helpfulness", Definition: "The answer is helpful."}
+		e, err := DefineEvaluatorFromBedrockModel(g, client, "bedrock", fmt.Sprintf("helpful%d", i), test.modelId, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", test.modelId, err)
+		}
+		ds := ai.Dataset{{TestCaseId: "a", Input: "What is 2+2?", Output: "4"}}
+		resp, err := ai.Evaluate(context.Background(), e, ai.WithEvaluateDataset(&ds))
+		if err != nil {
+			t.Fatalf("%s: %v", test.modelId, err)
+		}
+		if client.modelId != test.modelId {
+			t.Errorf("%s: invoked model %q", test.modelId, client.modelId)
+		}
+		prompt, _ := json.Marshal(client.request[test.promptKey])
+		if !strings.Contains(string(prompt), "The answer is helpful.") {
+			t.Errorf("%s: request %v does not hold the prompt in %q", test.modelId, client.request, test.promptKey)
+		}
+		s := (*resp)[0].Evaluation[0]
+		if s.Score != 0.8 || s.Status != ai.ScoreStatusPass.String() || s.Details["reasoning"] != "mostly correct" {
+			t.Errorf("%s: got score %+v, want 0.8, pass, mostly correct", test.modelId, s)
+		}
+	}
+}
+
+func TestDefineEvaluatorFromBedrockModelErrors(t *testing.T) {
+	g, err := genkit.Init(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &ai.EvaluatorOptions{Definition: "The answer is helpful."}
+	if _, err := DefineEvaluatorFromBedrockModel(g, &fakeClient{}, "bedrock", "unknown", "stability.sd3-large-v1:0", opts); err == nil {
+		t.Error("got nil, want error for an unsupported model")
+	}
+	if _, err := DefineEvaluatorFromBedrockModel(g, &fakeClient{}, "bedrock", "nodef", "anthropic.claude-v2", &ai.EvaluatorOptions{}); err == nil {
+		t.Error("got nil, want error for missing criteria")
+	}
+
+	for name, client := range map[string]*fakeClient{
+		"invokeError": {err: errors.New("throttled")},
+		"noVerdict":   {body: []byte(`{"generation":"I cannot grade this."}`)},
+	} {
+		e, err := DefineEvaluatorFromBedrockModel(g, client, "bedrock", name, "meta.llama3-8b-instruct-v1:0", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds := ai.Dataset{{TestCaseId: "a", Input: "q", Output: "a"}}
+		resp, err := ai.Evaluate(context.Background(), e, ai.WithEvaluateDataset(&ds))
+		if err == nil {
+			t.Errorf("%s: got nil, want error", e.Name())
+		}
+		if resp == nil || (*resp)[0].Evaluation[0].Status != ai.ScoreStatusFail.String() {
+			t.Errorf("%s: got %v, want a failed score", e.Name(), resp)
+		}
+	}
+}
+
+func TestParseVerdict(t *testing.T) {
+	for _, test := range []struct {
+		text  string
+		score float64
+	}{
+		{`{"score": 0.3, "reasoning": "r"}`, 0.3},
+		{"```json\n{\"score\": 1.5, \"reasoning\": \"r\"}\n```", 1},
+		{`{"score": -1, "reasoning": "r"}`, 0},
+	} {
+		v, err := parseVerdict(test.text)
+		if err != nil {
+			t.Fatalf("%q: %v", test.text, err)
+		}
+		if v.Score != test.score {
+			t.Errorf("%q: got score %v, want %v", test.text, v.Score, test.score)
+		}
+	}
+	if _, err := parseVerdict("no verdict"); err == nil {
+		t.Error("got nil, want error")
+	}
+}
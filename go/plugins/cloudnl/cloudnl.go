@@ -0,0 +1,343 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cloudnl defines Genkit evaluators backed by the Google Cloud
+// Natural Language API.
+package cloudnl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	language "google.golang.org/api/language/v1"
+	"google.golang.org/api/option"
+)
+
+const provider = "cloudnl"
+
+// sentimentThreshold is the document sentiment score above which text is
+// labelled positive, and below whose negation it is labelled negative.
+const sentimentThreshold = 0.25
+
+// EvaluatorOptions configures the evaluators defined by this package.
+type EvaluatorOptions struct {
+	ai.EvaluatorOptions
+	// ProjectID is the Google Cloud project billed for Natural Language
+	// API requests. It is required.
+	ProjectID string
+	// ClientOptions are passed to the Natural Language API client after
+	// the project option.
+	ClientOptions []option.ClientOption
+}
+
+// DefineSentimentEvaluator defines an evaluator named "cloudnl/sentiment"
+// that compares the sentiment of each example's Output with its Reference.
+// The Reference is either a label ("positive", "negative" or "neutral") or
+// a number in [-1, 1]. Labels pass when the Output's sentiment has the
+// same label; numbers are scored by their closeness to the Output's
+// sentiment score and pass when it has the same label.
+func DefineSentimentEvaluator(ctx context.Context, g *genkit.Genkit, opts *EvaluatorOptions) (ai.Evaluator, error) {
+	svc, evalOpts, err := newService(ctx, opts, ai.EvaluatorOptions{
+		DisplayName: "Sentiment",
+		Definition:  "Compares the sentiment of the output with the reference sentiment",
+		IsBilled:    true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloudnl.DefineSentimentEvaluator: %w", err)
+	}
+	return genkit.DefineEvaluator(g, provider, "sentiment", evalOpts, func(ctx context.Context, req *ai.EvaluatorCallbackRequest) (*ai.EvaluatorCallbackResponse, error) {
+		ex := req.Input
+		if ex.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		if ex.Reference == nil {
+			return nil, errors.New("reference was not provided")
+		}
+		wantLabel, wantScore, numeric, err := referenceSentiment(ex.Reference)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := document(ex.Output)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := svc.Documents.AnalyzeSentiment(&language.AnalyzeSentimentRequest{Document: doc}).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		var got language.Sentiment
+		if resp.DocumentSentiment != nil {
+			got = *resp.DocumentSentiment
+		}
+		gotLabel := sentimentLabel(got.Score)
+		pass := gotLabel == wantLabel
+		var score any = pass
+		if numeric {
+			score = 1 - abs(got.Score-wantScore)/2
+		}
+		return &ai.EvaluatorCallbackResponse{
+			TestCaseId: ex.TestCaseId,
+			Evaluation: []ai.Score{{
+				Id:     "sentiment",
+				Score:  score,
+				Status: status(pass),
+				Details: map[string]any{
+					"label":     gotLabel,
+					"score":     got.Score,
+					"magnitude": got.Magnitude,
+				},
+			}},
+		}, nil
+	})
+}
+
+// DefineEntityCoverageEvaluator defines an evaluator named
+// "cloudnl/entity_coverage" that checks that each example's Output
+// mentions the entities listed in its Reference, given as a string or a
+// list of strings. Entities match case-insensitively against the names and
+// mentions of the entities found in the Output. The score is the fraction
+// of expected entities found, and the example passes when all of them are.
+func DefineEntityCoverageEvaluator(ctx context.Context, g *genkit.Genkit, opts *EvaluatorOptions) (ai.Evaluator, error) {
+	svc, evalOpts, err := newService(ctx, opts, ai.EvaluatorOptions{
+		DisplayName: "Entity Coverage",
+		Definition:  "Measures how many of the reference entities the output mentions",
+		IsBilled:    true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloudnl.DefineEntityCoverageEvaluator: %w", err)
+	}
+	return genkit.DefineEvaluator(g, provider, "entity_coverage", evalOpts, func(ctx context.Context, req *ai.EvaluatorCallbackRequest) (*ai.EvaluatorCallbackResponse, error) {
+		ex := req.Input
+		if ex.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		want, err := referenceEntities(ex.Reference)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := document(ex.Output)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := svc.Documents.AnalyzeEntities(&language.AnalyzeEntitiesRequest{Document: doc}).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		found := map[string]bool{}
+		for _, e := range resp.Entities {
+			found[strings.ToLower(e.Name)] = true
+			for _, m := range e.Mentions {
+				if m.Text != nil {
+					found[strings.ToLower(m.Text.Content)] = true
+				}
+			}
+		}
+		missing := []string{}
+		for _, name := range want {
+			if !found[strings.ToLower(name)] {
+				missing = append(missing, name)
+			}
+		}
+		covered := len(want) - len(missing)
+		return &ai.EvaluatorCallbackResponse{
+			TestCaseId: ex.TestCaseId,
+			Evaluation: []ai.Score{{
+				Id:     "entity_coverage",
+				Score:  float64(covered) / float64(len(want)),
+				Status: status(len(missing) == 0),
+				Details: map[string]any{
+					"missing": missing,
+				},
+			}},
+		}, nil
+	})
+}
+
+// DefineClassificationEvaluator defines an evaluator named
+// "cloudnl/classification" that checks that each example's Output is
+// classified under the content category in its Reference, such as
+// "/Science/Computer Science". A predicted category matches if it is the
+// Reference or one of its subcategories. The score is the confidence of
+// the best matching category, or 0 if none match.
+func DefineClassificationEvaluator(ctx context.Context, g *genkit.Genkit, opts *EvaluatorOptions) (ai.Evaluator, error) {
+	svc, evalOpts, err := newService(ctx, opts, ai.EvaluatorOptions{
+		DisplayName: "Classification",
+		Definition:  "Checks that the output is classified under the reference category",
+		IsBilled:    true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloudnl.DefineClassificationEvaluator: %w", err)
+	}
+	return genkit.DefineEvaluator(g, provider, "classification", evalOpts, func(ctx context.Context, req *ai.EvaluatorCallbackRequest) (*ai.EvaluatorCallbackResponse, error) {
+		ex := req.Input
+		if ex.Output == nil {
+			return nil, errors.New("output was not provided")
+		}
+		want, ok := ex.Reference.(string)
+		if !ok || want == "" {
+			return nil, errors.New("reference must be a category name")
+		}
+		doc, err := document(ex.Output)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := svc.Documents.ClassifyText(&language.ClassifyTextRequest{Document: doc}).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		var confidence float64
+		categories := make([]string, len(resp.Categories))
+		for i, c := range resp.Categories {
+			categories[i] = c.Name
+			if (c.Name == want || strings.HasPrefix(c.Name, strings.TrimSuffix(want, "/")+"/")) && c.Confidence > confidence {
+				confidence = c.Confidence
+			}
+		}
+		return &ai.EvaluatorCallbackResponse{
+			TestCaseId: ex.TestCaseId,
+			Evaluation: []ai.Score{{
+				Id:     "classification",
+				Score:  confidence,
+				Status: status(confidence > 0),
+				Details: map[string]any{
+					"categories": categories,
+				},
+			}},
+		}, nil
+	})
+}
+
+// newService returns a Natural Language API client configured by opts and
+// the evaluator options to define the evaluator with, using defaults for
+// any that opts leaves unset.
+func newService(ctx context.Context, opts *EvaluatorOptions, defaults ai.EvaluatorOptions) (*language.Service, *ai.EvaluatorOptions, error) {
+	if opts == nil || opts.ProjectID == "" {
+		return nil, nil, errors.New("project ID is required")
+	}
+	evalOpts := opts.EvaluatorOptions
+	if evalOpts.DisplayName == "" {
+		evalOpts.DisplayName = defaults.DisplayName
+	}
+	if evalOpts.Definition == "" {
+		evalOpts.Definition = defaults.Definition
+	}
+	evalOpts.IsBilled = evalOpts.IsBilled || defaults.IsBilled
+	clientOpts := append([]option.ClientOption{option.WithQuotaProject(opts.ProjectID)}, opts.ClientOptions...)
+	svc, err := language.NewService(ctx, clientOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return svc, &evalOpts, nil
+}
+
+// document returns a plain text document holding v, which is used as is
+// if it is a string and encoded as JSON otherwise.
+func document(v any) (*language.Document, error) {
+	text, ok := v.(string)
+	if !ok {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("output cannot be encoded as text: %w", err)
+		}
+		text = string(b)
+	}
+	return &language.Document{Content: text, Type: "PLAIN_TEXT"}, nil
+}
+
+// referenceSentiment parses a sentiment Reference, returning its label and,
+// for numeric references, its score.
+func referenceSentiment(ref any) (label string, score float64, numeric bool, err error) {
+	switch v := ref.(type) {
+	case float64:
+		score, numeric = v, true
+	case int:
+		score, numeric = float64(v), true
+	case string:
+		switch l := strings.ToLower(strings.TrimSpace(v)); l {
+		case "positive", "negative", "neutral":
+			return l, 0, false, nil
+		}
+		if score, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+			return "", 0, false, fmt.Errorf("reference %q is not a sentiment label or score", v)
+		}
+		numeric = true
+	default:
+		return "", 0, false, fmt.Errorf("reference of type %T is not a sentiment label or score", ref)
+	}
+	if score < -1 || score > 1 {
+		return "", 0, false, fmt.Errorf("reference sentiment score %v is outside [-1, 1]", score)
+	}
+	return sentimentLabel(score), score, true, nil
+}
+
+// sentimentLabel returns the label for a sentiment score.
+func sentimentLabel(score float64) string {
+	switch {
+	case score >= sentimentThreshold:
+		return "positive"
+	case score <= -sentimentThreshold:
+		return "negative"
+	default:
+		return "neutral"
+	}
+}
+
+// referenceEntities parses an entity coverage Reference.
+func referenceEntities(ref any) ([]string, error) {
+	var names []string
+	switch v := ref.(type) {
+	case string:
+		names = []string{v}
+	case []string:
+		names = v
+	case []any:
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("reference entity of type %T is not a string", e)
+			}
+			names = append(names, s)
+		}
+	case nil:
+		return nil, errors.New("reference was not provided")
+	default:
+		return nil, fmt.Errorf("reference of type %T is not an entity list", ref)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("reference lists no entities")
+	}
+	return names, nil
+}
+
+func status(pass bool) string {
+	if pass {
+		return ai.ScoreStatusPass.String()
+	}
+	return ai.ScoreStatusFail.String()
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudnl_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/firebase/genkit/go/plugins/cloudnl"
+	"google.golang.org/api/option"
+)
+
+// fakeLanguageAPI serves canned Natural Language API responses keyed by
+// method and document content.
+func fakeLanguageAPI(t *testing.T, responses map[string]map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Document struct {
+				Content string `json:"content"`
+			} `json:"document"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, ":")+1:]
+		resp, ok := responses[method][req.Document.Content]
+		if !ok {
+			http.Error(w, "unexpected request", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func evaluate(t *testing.T, define func(context.Context, *genkit.Genkit, *cloudnl.EvaluatorOptions) (ai.Evaluator, error), srv *httptest.Server, dataset ai.Dataset) []ai.EvaluationResult {
+	t.Helper()
+	ctx := context.Background()
+	g, err := genkit.Init(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eval, err := define(ctx, g, &cloudnl.EvaluatorOptions{
+		ProjectID:     "test-project",
+		ClientOptions: []option.ClientOption{option.WithEndpoint(srv.URL + "/"), option.WithoutAuthentication()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, _ := eval.Evaluate(ctx, &ai.EvaluatorRequest{Dataset: &dataset, EvaluationId: "testrun"})
+	if resp == nil {
+		t.Fatal("got nil response")
+	}
+	return *resp
+}
+
+func TestSentimentEvaluator(t *testing.T) {
+	srv := fakeLanguageAPI(t, map[string]map[string]any{
+		"analyzeSentiment": {
+			"I love it":  map[string]any{"documentSentiment": map[string]any{"score": 0.9, "magnitude": 0.9}},
+			"I hate it":  map[string]any{"documentSentiment": map[string]any{"score": -0.8, "magnitude": 0.8}},
+			"It is blue": map[string]any{"documentSentiment": map[string]any{"score": 0.1, "magnitude": 0.1}},
+		},
+	})
+	results := evaluate(t, cloudnl.DefineSentimentEvaluator, srv, ai.Dataset{
+		{TestCaseId: "label", Output: "I love it", Reference: "positive"},
+		{TestCaseId: "mismatch", Output: "I hate it", Reference: "neutral"},
+		{TestCaseId: "numeric", Output: "It is blue", Reference: 0.5},
+		{TestCaseId: "bad", Output: "I love it", Reference: "happy"},
+	})
+	if got := results[0].Evaluation[0]; got.Score != true || got.Status != ai.ScoreStatusPass.String() {
+		t.Errorf("label: got %+v, want pass", got)
+	}
+	if got := results[1].Evaluation[0]; got.Status != ai.ScoreStatusFail.String() || got.Details["label"] != "negative" {
+		t.Errorf("mismatch: got %+v, want negative fail", got)
+	}
+	if got := results[2].Evaluation[0]; got.Score != 0.8 || got.Status != ai.ScoreStatusFail.String() {
+		t.Errorf("numeric: got %+v, want score 0.8 and fail", got)
+	}
+	if got := results[3].Evaluation[0]; got.Error == "" {
+		t.Errorf("bad reference: got %+v, want error", got)
+	}
+}
+
+func TestEntityCoverageEvaluator(t *testing.T) {
+	srv := fakeLanguageAPI(t, map[string]map[string]any{
+		"analyzeEntities": {
+			"Larry Page founded Google in California.": map[string]any{"entities": []any{
+				map[string]any{"name": "Larry Page", "mentions": []any{map[string]any{"text": map[string]any{"content": "Larry Page"}}}},
+				map[string]any{"name": "Google", "mentions": []any{map[string]any{"text": map[string]any{"content": "Google"}}}},
+				map[string]any{"name": "California"},
+			}},
+		},
+	})
+	results := evaluate(t, cloudnl.DefineEntityCoverageEvaluator, srv, ai.Dataset{
+		{TestCaseId: "all", Output: "Larry Page founded Google in California.", Reference: []any{"google", "California"}},
+		{TestCaseId: "some", Output: "Larry Page founded Google in California.", Reference: []any{"Google", "Sergey Brin"}},
+		{TestCaseId: "none", Output: "Larry Page founded Google in California."},
+	})
+	if got := results[0].Evaluation[0]; got.Score != 1.0 || got.Status != ai.ScoreStatusPass.String() {
+		t.Errorf("all: got %+v, want full coverage", got)
+	}
+	got := results[1].Evaluation[0]
+	if got.Score != 0.5 || got.Status != ai.ScoreStatusFail.String() {
+		t.Errorf("some: got %+v, want half coverage", got)
+	}
+	if missing, _ := got.Details["missing"].([]string); len(missing) != 1 || missing[0] != "Sergey Brin" {
+		t.Errorf("some: got missing %v, want [Sergey Brin]", got.Details["missing"])
+	}
+	if got := results[2].Evaluation[0]; got.Error == "" {
+		t.Errorf("none: got %+v, want error", got)
+	}
+}
+
+func TestClassificationEvaluator(t *testing.T) {
+	srv := fakeLanguageAPI(t, map[string]map[string]any{
+		"classifyText": {
+			"Go is a programming language.": map[string]any{"categories": []any{
+				map[string]any{"name": "/Computers & Electronics/Programming", "confidence": 0.9},
+				map[string]any{"name": "/Science", "confidence": 0.2},
+			}},
+		},
+	})
+	results := evaluate(t, cloudnl.DefineClassificationEvaluator, srv, ai.Dataset{
+		{TestCaseId: "exact", Output: "Go is a programming language.", Reference: "/Computers & Electronics/Programming"},
+		{TestCaseId: "parent", Output: "Go is a programming language.", Reference: "/Computers & Electronics"},
+		{TestCaseId: "other", Output: "Go is a programming language.", Reference: "/Arts & Entertainment"},
+	})
+	for i, want := range []float64{0.9, 0.9, 0} {
+		if got := results[i].Evaluation[0].Score; got != want {
+			t.Errorf("%s: got score %v, want %v", results[i].TestCaseId, got, want)
+		}
+	}
+	if got := results[2].Evaluation[0].Status; got != ai.ScoreStatusFail.String() {
+		t.Errorf("other: got status %q, want fail", got)
+	}
+}
+
+func TestProjectIDRequired(t *testing.T) {
+	ctx := context.Background()
+	g, err := genkit.Init(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cloudnl.DefineSentimentEvaluator(ctx, g, &cloudnl.EvaluatorOptions{}); err == nil {
+		t.Error("got nil error, want error for missing project ID")
+	}
+}
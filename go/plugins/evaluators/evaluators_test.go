@@ -16,6 +16,7 @@ package evaluators_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/firebase/genkit/go/ai"
@@ -66,8 +67,8 @@ func TestEvaluators(t *testing.T) {
 
 		evalAction := genkit.LookupEvaluator(g, "genkitEval", "deep_equal")
 		resp, err := evalAction.Evaluate(ctx, &testRequest)
-		if err != nil {
-			t.Fatal(err)
+		if !errors.As(err, new(ai.EvaluatorError)) {
+			t.Fatalf("got error %v, want ai.EvaluatorError for the missing reference", err)
 		}
 		if got, want := (*resp)[0].Evaluation[0].Score, true; got != want {
 			t.Errorf("got %v, want %v", got, want)
@@ -105,8 +106,8 @@ func TestEvaluators(t *testing.T) {
 
 		evalAction := genkit.LookupEvaluator(g, "genkitEval", "regex")
 		resp, err := evalAction.Evaluate(ctx, &testRequest)
-		if err != nil {
-			t.Fatal(err)
+		if !errors.As(err, new(ai.EvaluatorError)) {
+			t.Fatalf("got error %v, want ai.EvaluatorError for the non-string reference", err)
 		}
 		if got, want := (*resp)[0].Evaluation[0].Score, true; got != want {
 			t.Errorf("got %v, want %v", got, want)
@@ -153,8 +154,8 @@ func TestEvaluators(t *testing.T) {
 
 		evalAction := genkit.LookupEvaluator(g, "genkitEval", "jsonata")
 		resp, err := evalAction.Evaluate(ctx, &testRequest)
-		if err != nil {
-			t.Fatal(err)
+		if !errors.As(err, new(ai.EvaluatorError)) {
+			t.Fatalf("got error %v, want ai.EvaluatorError for the non-string reference", err)
 		}
 		if got, want := (*resp)[0].Evaluation[0].Score, true; got != want {
 			t.Errorf("got %v, want %v", got, want)
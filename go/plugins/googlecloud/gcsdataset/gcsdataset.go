@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gcsdataset provides an [ai.DatasetStore] backed by Google Cloud
+// Storage.
+package gcsdataset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"cloud.google.com/go/storage"
+	"github.com/firebase/genkit/go/ai"
+)
+
+// Store is an [ai.DatasetStore] that keeps each dataset in an object of a
+// Cloud Storage bucket. Datasets are streamed to and from the objects, so
+// they are never held in memory in encoded form.
+type Store struct {
+	objects objectStore
+	prefix  string
+}
+
+// NewGCSDatasetStore returns a [Store] that keeps the dataset named name in
+// the object prefix+name of bucket, using client. As described in
+// [ai.DatasetStore], names ending in ".jsonl" are stored as JSONL and
+// others as JSON.
+func NewGCSDatasetStore(client *storage.Client, bucket, prefix string) *Store {
+	return &Store{objects: gcsObjects{client.Bucket(bucket)}, prefix: prefix}
+}
+
+// Load implements [ai.DatasetStore.Load].
+func (s *Store) Load(ctx context.Context, name string) (ai.Dataset, error) {
+	object := s.prefix + name
+	r, err := s.objects.NewReader(ctx, object)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, fmt.Errorf("gcsdataset: %w: %q", ai.ErrDatasetNotFound, object)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gcsdataset: %w", err)
+	}
+	defer r.Close()
+	ds, err := ai.ReadDataset(r, name)
+	if err != nil {
+		return nil, fmt.Errorf("gcsdataset: %w", err)
+	}
+	return ds, nil
+}
+
+// Save implements [ai.DatasetStore.Save]. If writing fails, the object is
+// left unchanged.
+func (s *Store) Save(ctx context.Context, name string, ds ai.Dataset) error {
+	// Canceling the context aborts the upload instead of committing a
+	// partial object.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	contentType := "application/json"
+	if path.Ext(name) == ".jsonl" {
+		contentType = "application/jsonl"
+	}
+	w := s.objects.NewWriter(ctx, s.prefix+name, contentType)
+	if err := ai.WriteDataset(w, name, ds); err != nil {
+		cancel()
+		w.Close()
+		return fmt.Errorf("gcsdataset: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("gcsdataset: %w", err)
+	}
+	return nil
+}
+
+// objectStore is the part of a Cloud Storage bucket used by [Store].
+type objectStore interface {
+	// NewReader returns a reader for the contents of the object, or an
+	// error wrapping [storage.ErrObjectNotExist].
+	NewReader(ctx context.Context, object string) (io.ReadCloser, error)
+	// NewWriter returns a writer that replaces the contents of the object
+	// when it is closed, unless ctx was canceled.
+	NewWriter(ctx context.Context, object, contentType string) io.WriteCloser
+}
+
+// gcsObjects is an [objectStore] for a Cloud Storage bucket.
+type gcsObjects struct {
+	bucket *storage.BucketHandle
+}
+
+func (g gcsObjects) NewReader(ctx context.Context, object string) (io.ReadCloser, error) {
+	return g.bucket.Object(object).NewReader(ctx)
+}
+
+func (g gcsObjects) NewWriter(ctx context.Context, object, contentType string) io.WriteCloser {
+	w := g.bucket.Object(object).NewWriter(ctx)
+	w.ContentType = contentType
+	return w
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsdataset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/firebase/genkit/go/ai"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeObjects is an in-memory [objectStore].
+type fakeObjects struct {
+	objects      map[string][]byte
+	contentTypes map[string]string
+}
+
+func newFakeObjects() *fakeObjects {
+	return &fakeObjects{objects: map[string][]byte{}, contentTypes: map[string]string{}}
+}
+
+func (f *fakeObjects) NewReader(ctx context.Context, object string) (io.ReadCloser, error) {
+	b, ok := f.objects[object]
+	if !ok {
+		return nil, storage.ErrObjectNotExist
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (f *fakeObjects) NewWriter(ctx context.Context, object, contentType string) io.WriteCloser {
+	return &fakeWriter{ctx: ctx, f: f, object: object, contentType: contentType}
+}
+
+// fakeWriter commits its contents on Close unless its context is done,
+// like a [storage.Writer].
+type fakeWriter struct {
+	bytes.Buffer
+	ctx         context.Context
+	f           *fakeObjects
+	object      string
+	contentType string
+}
+
+func (w *fakeWriter) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	w.f.objects[w.object] = w.Bytes()
+	w.f.contentTypes[w.object] = w.contentType
+	return nil
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	objects := newFakeObjects()
+	s := &Store{objects: objects, prefix: "evals/"}
+	ds := ai.Dataset{
+		{TestCaseId: "a", Input: "question", Output: "answer"},
+		{TestCaseId: "b", Input: "other", Reference: "ref"},
+	}
+
+	for _, test := range []struct {
+		name, contentType string
+	}{
+		{"golden.json", "application/json"},
+		{"golden.jsonl", "application/jsonl"},
+	} {
+		if err := s.Save(ctx, test.name, ds); err != nil {
+			t.Fatal(err)
+		}
+		if got := objects.contentTypes["evals/"+test.name]; got != test.contentType {
+			t.Errorf("%s: got content type %q, want %q", test.name, got, test.contentType)
+		}
+		got, err := s.Load(ctx, test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ds, got); diff != "" {
+			t.Errorf("%s: mismatch (-want +got):\n%s", test.name, diff)
+		}
+	}
+	if got := strings.Count(string(objects.objects["evals/golden.jsonl"]), "\n"); got != len(ds) {
+		t.Errorf("got %d JSONL lines, want %d", got, len(ds))
+	}
+
+	if _, err := s.Load(ctx, "missing.json"); !errors.Is(err, ai.ErrDatasetNotFound) {
+		t.Errorf("got %v, want ErrDatasetNotFound", err)
+	}
+
+	// A dataset that cannot be encoded leaves the stored object unchanged.
+	before := string(objects.objects["evals/golden.json"])
+	if err := s.Save(ctx, "golden.json", ai.Dataset{{Input: math.NaN()}}); err == nil {
+		t.Error("got nil, want error for a dataset that cannot be encoded")
+	}
+	if got := string(objects.objects["evals/golden.json"]); got != before {
+		t.Error("failed save modified the stored dataset")
+	}
+}
@@ -0,0 +1,87 @@
+# Copyright 2025 Google LLC
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# SPDX-License-Identifier: Apache-2.0
+
+"""Reference implementation of the Genkit Go child process evaluator protocol.
+
+A program that calls serve() can be registered as an evaluator with
+ai.DefineChildProcessEvaluator. See README.md for the protocol.
+
+Example:
+
+    from genkit_eval import serve
+
+    def exact_match(request):
+        example = request['input']
+        ok = example.get('output') == example.get('reference')
+        return {
+            'evaluation': [{
+                'id': 'exact_match',
+                'score': 1.0 if ok else 0.0,
+                'status': 'pass' if ok else 'fail',
+            }],
+        }
+
+    if __name__ == '__main__':
+        serve(exact_match)
+"""
+
+import json
+import sys
+from collections.abc import Callable
+from typing import Any, TextIO
+
+EvaluateFn = Callable[[dict[str, Any]], dict[str, Any]]
+
+
+def handle(evaluate: EvaluateFn, line: str) -> dict[str, Any]:
+    """Evaluates the request in line and returns the response to send.
+
+    Args:
+        evaluate: The evaluator function. It receives the decoded
+            EvaluatorCallbackRequest and returns an EvaluationResult.
+        line: A line read from standard input.
+
+    Returns:
+        The EvaluationResult, with its testCaseId filled in from the request
+        if missing, or an object with an "error" field if evaluate raised.
+    """
+    try:
+        request = json.loads(line)
+        result = dict(evaluate(request))
+        result.setdefault('testCaseId', request.get('input', {}).get('testCaseId', ''))
+        return result
+    except Exception as e:  # noqa: BLE001 - every failure is reported to Go.
+        return {'error': f'{type(e).__name__}: {e}'}
+
+
+def serve(
+    evaluate: EvaluateFn,
+    stdin: TextIO = sys.stdin,
+    stdout: TextIO = sys.stdout,
+) -> None:
+    """Answers evaluation requests until stdin is closed.
+
+    Args:
+        evaluate: The evaluator function; see handle.
+        stdin: Where requests are read from.
+        stdout: Where responses are written to. Nothing else may be written
+            to it; use standard error for logging.
+    """
+    for line in stdin:
+        if not line.strip():
+            continue
+        stdout.write(json.dumps(handle(evaluate, line)) + '\n')
+        stdout.flush()